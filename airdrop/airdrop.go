@@ -0,0 +1,390 @@
+// Package airdrop delivers ERB amounts and NFTs to a list of recipients from one account.
+//
+// Transactions are sent in batches with consecutive nonces and throttled. The state of every
+// item is saved to a checkpoint after each batch, so an interrupted airdrop resumes where it
+// stopped without delivering an item twice.
+package airdrop
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/erbieio/erb-client/v2/client"
+	"github.com/erbieio/erb-client/v2/tools"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"golang.org/x/xerrors"
+)
+
+const (
+	transferGas         = 21000
+	defaultBatchSize    = 50
+	defaultInterval     = 100 * time.Millisecond
+	defaultPollInterval = time.Second
+)
+
+// Item is a recipient of the airdrop, it receives either Amount wei or the NFT at NFTAddress.
+type Item struct {
+	To         common.Address `json:"to"`
+	Amount     *big.Int       `json:"amount,omitempty"`
+	NFTAddress string         `json:"nft_address,omitempty"`
+}
+
+// Status is the delivery status of an item.
+type Status string
+
+const (
+	Pending   Status = "pending"
+	Sent      Status = "sent"
+	Delivered Status = "delivered"
+	Failed    Status = "failed"
+)
+
+// Result is the delivery state of an item.
+type Result struct {
+	Item   *Item       `json:"item"`
+	Status Status      `json:"status"`
+	TxHash common.Hash `json:"tx_hash,omitempty"`
+	Nonce  uint64      `json:"nonce,omitempty"`
+	// Error is the last send error of a pending item or the reason of a failed one.
+	Error string `json:"error,omitempty"`
+}
+
+// Checkpoint persists the results of an airdrop.
+type Checkpoint interface {
+	// Load returns the saved results, ok is false when nothing has been saved yet.
+	Load(ctx context.Context) (results []*Result, ok bool, err error)
+	Save(ctx context.Context, results []*Result) error
+}
+
+// MemoryCheckpoint keeps the results in memory only.
+type MemoryCheckpoint struct {
+	mu      sync.Mutex
+	results []byte
+}
+
+func (c *MemoryCheckpoint) Load(ctx context.Context) ([]*Result, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.results == nil {
+		return nil, false, nil
+	}
+	var results []*Result
+	if err := json.Unmarshal(c.results, &results); err != nil {
+		return nil, false, err
+	}
+	return results, true, nil
+}
+
+func (c *MemoryCheckpoint) Save(ctx context.Context, results []*Result) error {
+	data, err := json.Marshal(results)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.results = data
+	return nil
+}
+
+// FileCheckpoint keeps the results as JSON in a file.
+// The file is replaced atomically, so a crash never leaves a partial checkpoint behind.
+type FileCheckpoint struct {
+	Path string
+}
+
+func (c *FileCheckpoint) Load(ctx context.Context) ([]*Result, bool, error) {
+	data, err := ioutil.ReadFile(c.Path)
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, xerrors.Errorf("read checkpoint %s fail. %v", c.Path, err)
+	}
+	var results []*Result
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, false, xerrors.Errorf("invalid checkpoint %s. %v", c.Path, err)
+	}
+	return results, true, nil
+}
+
+func (c *FileCheckpoint) Save(ctx context.Context, results []*Result) error {
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(c.Path), filepath.Base(c.Path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), c.Path)
+}
+
+// Config configures an Airdrop. Zero values select the defaults.
+type Config struct {
+	// BatchSize is the number of transactions sent before waiting for their receipts, 50 by default.
+	BatchSize int
+	// Interval is the minimum time between two sent transactions, 100ms by default.
+	Interval time.Duration
+	// PollInterval is the interval between receipt polls, 1s by default.
+	PollInterval time.Duration
+	// GasPrice is the gas price of the transactions, suggested by the node when nil.
+	GasPrice *big.Int
+	// Checkpoint stores the results, in memory by default.
+	Checkpoint Checkpoint
+}
+
+// Report reconciles the items of an airdrop after a run.
+type Report struct {
+	Delivered []*Result
+	Failed    []*Result
+	// Pending are the items not delivered yet, sent or not, e.g. after a send error.
+	Pending []*Result
+	// DeliveredAmount is the total wei of the delivered ERB items.
+	DeliveredAmount *big.Int
+}
+
+// Airdrop delivers a list of items. It must not be used concurrently.
+type Airdrop struct {
+	worm    *client.Wormholes
+	account common.Address
+	items   []*Item
+	cfg     Config
+}
+
+// New creates an airdrop of items sent by the account of priKey through worm.
+func New(worm *client.Wormholes, priKey string, items []*Item, cfg Config) (*Airdrop, error) {
+	account, _, err := tools.PriKeyToAddress(priKey)
+	if err != nil {
+		return nil, err
+	}
+	for i, item := range items {
+		if (item.Amount == nil) == (item.NFTAddress == "") {
+			return nil, xerrors.Errorf("airdrop item %d: exactly one of amount and nft address must be set", i)
+		}
+		if item.Amount != nil && item.Amount.Sign() <= 0 {
+			return nil, xerrors.Errorf("airdrop item %d: amount must be positive", i)
+		}
+		if item.NFTAddress != "" {
+			if err := tools.CheckHex("airdrop item nft address", item.NFTAddress); err != nil {
+				return nil, xerrors.Errorf("airdrop item %d: %v", i, err)
+			}
+		}
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultBatchSize
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultInterval
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = defaultPollInterval
+	}
+	if cfg.Checkpoint == nil {
+		cfg.Checkpoint = &MemoryCheckpoint{}
+	}
+	return &Airdrop{worm: worm.WithPriKey(priKey), account: account, items: items, cfg: cfg}, nil
+}
+
+// Run delivers the items that are not delivered or failed yet and returns the report.
+// On error or cancellation of ctx the results reached so far are saved, and calling Run
+// again, also from a new Airdrop with the same items and checkpoint, resumes the delivery.
+func (a *Airdrop) Run(ctx context.Context) (*Report, error) {
+	results, err := a.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// Settle the transactions sent before an interruption first, their nonces are taken.
+	if err := a.settle(ctx, results); err != nil {
+		return nil, err
+	}
+	gasPrice := a.cfg.GasPrice
+	if gasPrice == nil {
+		if gasPrice, err = a.worm.SuggestGasPrice(ctx); err != nil {
+			return nil, err
+		}
+	}
+	for {
+		var batch []*Result
+		for _, result := range results {
+			if result.Status == Pending {
+				batch = append(batch, result)
+				if len(batch) == a.cfg.BatchSize {
+					break
+				}
+			}
+		}
+		if len(batch) == 0 {
+			return NewReport(results), nil
+		}
+		sendErr := a.send(ctx, batch, gasPrice)
+		if err := a.save(ctx, results); err != nil {
+			return nil, err
+		}
+		if err := a.settle(ctx, results); err != nil {
+			return nil, err
+		}
+		if sendErr != nil {
+			return NewReport(results), sendErr
+		}
+	}
+}
+
+func (a *Airdrop) load(ctx context.Context) ([]*Result, error) {
+	results, ok, err := a.cfg.Checkpoint.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		results = make([]*Result, len(a.items))
+		for i, item := range a.items {
+			results[i] = &Result{Item: item, Status: Pending}
+		}
+		return results, nil
+	}
+	if len(results) != len(a.items) {
+		return nil, xerrors.Errorf("checkpoint has %d items, airdrop has %d", len(results), len(a.items))
+	}
+	for i, result := range results {
+		if result.Item.To != a.items[i].To || result.Item.NFTAddress != a.items[i].NFTAddress {
+			return nil, xerrors.Errorf("checkpoint item %d does not match the airdrop", i)
+		}
+	}
+	return results, nil
+}
+
+// send sends the batch with consecutive nonces. It stops at the first send error, leaving
+// the item and the rest of the batch pending.
+func (a *Airdrop) send(ctx context.Context, batch []*Result, gasPrice *big.Int) error {
+	nonce, err := a.worm.PendingNonceAt(ctx, a.account)
+	if err != nil {
+		return err
+	}
+	for i, result := range batch {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(a.cfg.Interval):
+			}
+		}
+		tx, err := a.build(ctx, result.Item, nonce, gasPrice)
+		if err == nil {
+			err = a.worm.SendTransaction(ctx, tx)
+		}
+		if err != nil {
+			result.Error = err.Error()
+			return xerrors.Errorf("airdrop to %s: %w", result.Item.To.Hex(), err)
+		}
+		result.Status, result.TxHash, result.Nonce, result.Error = Sent, tx.Hash(), nonce, ""
+		nonce++
+	}
+	return nil
+}
+
+func (a *Airdrop) build(ctx context.Context, item *Item, nonce uint64, gasPrice *big.Int) (*types.Transaction, error) {
+	if item.NFTAddress != "" {
+		return a.worm.Tx().Transfer(item.NFTAddress).To(item.To.Hex()).Nonce(nonce).GasPrice(gasPrice).Build(ctx)
+	}
+	return a.worm.Tx().Pay(item.To.Hex(), item.Amount).Nonce(nonce).GasPrice(gasPrice).GasLimit(transferGas).Build(ctx)
+}
+
+// settle waits for the receipts of the sent items and saves their outcome. A sent transaction
+// whose nonce was used by another transaction was dropped, its item is pending again.
+func (a *Airdrop) settle(ctx context.Context, results []*Result) error {
+	for _, result := range results {
+		if result.Status != Sent {
+			continue
+		}
+		receipt, err := a.waitMined(ctx, result)
+		if err != nil {
+			return err
+		}
+		switch {
+		case receipt == nil:
+			result.Status, result.TxHash, result.Nonce = Pending, common.Hash{}, 0
+		case receipt.Status == types.ReceiptStatusSuccessful:
+			result.Status = Delivered
+		default:
+			result.Status, result.Error = Failed, "transaction failed"
+		}
+	}
+	return a.save(ctx, results)
+}
+
+// save checkpoints the results also when ctx was cancelled, so the transactions sent are
+// not sent again by the next run.
+func (a *Airdrop) save(ctx context.Context, results []*Result) error {
+	ctx, cancel := tools.FlushContext(ctx)
+	defer cancel()
+	return a.cfg.Checkpoint.Save(ctx, results)
+}
+
+// waitMined polls the receipt of a sent item until it is available or ctx is done. It returns
+// a nil receipt when the nonce of the item was used without mining its transaction.
+func (a *Airdrop) waitMined(ctx context.Context, result *Result) (*types.Receipt, error) {
+	ticker := time.NewTicker(a.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		receipt, err := a.worm.TransactionReceipt(ctx, result.TxHash.Hex())
+		if err == nil {
+			return receipt, nil
+		}
+		if !errors.Is(err, ethereum.NotFound) {
+			return nil, err
+		}
+		nonce, err := a.worm.NonceAt(ctx, a.account, nil)
+		if err != nil {
+			return nil, err
+		}
+		if nonce > result.Nonce {
+			// The nonce may have been mined since the receipt poll, check once more.
+			receipt, err := a.worm.TransactionReceipt(ctx, result.TxHash.Hex())
+			if errors.Is(err, ethereum.NotFound) {
+				return nil, nil
+			}
+			return receipt, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// NewReport reconciles a list of results.
+func NewReport(results []*Result) *Report {
+	report := &Report{DeliveredAmount: new(big.Int)}
+	for _, result := range results {
+		switch result.Status {
+		case Delivered:
+			report.Delivered = append(report.Delivered, result)
+			if result.Item.Amount != nil {
+				report.DeliveredAmount.Add(report.DeliveredAmount, result.Item.Amount)
+			}
+		case Failed:
+			report.Failed = append(report.Failed, result)
+		default:
+			report.Pending = append(report.Pending, result)
+		}
+	}
+	return report
+}
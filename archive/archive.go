@@ -0,0 +1,262 @@
+// Package archive exports the blocks and trades of a scanner to object storage as gzipped JSON
+// lines, for analytics over the history of the chain with tools reading partitioned files,
+// such as Athena, BigQuery, Spark or DuckDB.
+//
+// A file holds the rows of one table for a range of blocks of one UTC day, at most BlockSpan
+// blocks aligned on multiples of BlockSpan, under a Hive-style key:
+//
+//	<prefix>blocks/date=2024-05-01/000001234000-000001234999.jsonl.gz
+//	<prefix>trades/date=2024-05-01/000001234000-000001234999.jsonl.gz
+//
+// The scanner progress is checkpointed when the files of the blocks are stored, so after a
+// crash the blocks since the last files are scanned again and their files are written under
+// the same keys, without duplicate rows.
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/erbieio/erb-client/v2/scanner"
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"golang.org/x/xerrors"
+)
+
+// The tables, the first part of the keys.
+const (
+	TableBlocks = "blocks"
+	TableTrades = "trades"
+)
+
+// Block is a row of the blocks table.
+type Block struct {
+	Number       uint64         `json:"number"`
+	Hash         common.Hash    `json:"hash"`
+	ParentHash   common.Hash    `json:"parent_hash"`
+	Time         uint64         `json:"time"`
+	Miner        common.Address `json:"miner"`
+	GasUsed      uint64         `json:"gas_used"`
+	GasLimit     uint64         `json:"gas_limit"`
+	Transactions int            `json:"transactions"`
+}
+
+// Trade is a row of the trades table.
+type Trade struct {
+	Block   uint64      `json:"block"`
+	Time    uint64      `json:"time"`
+	TxHash  common.Hash `json:"tx_hash"`
+	TxIndex int         `json:"tx_index"`
+	// Type is the name of the wormholes transaction type.
+	Type       string         `json:"type"`
+	NFTAddress string         `json:"nft_address,omitempty"`
+	Exchanger  string         `json:"exchanger,omitempty"`
+	Buyer      common.Address `json:"buyer"`
+	From       common.Address `json:"from"`
+	// Price is in wei as a decimal string.
+	Price string `json:"price"`
+}
+
+const defaultBlockSpan = 1000
+
+// Config configures an Exporter. Zero values select the defaults.
+type Config struct {
+	// Store stores the files.
+	Store Store
+	// Prefix is prepended to the keys, e.g. "erb/mainnet/".
+	Prefix string
+	// BlockSpan is the largest number of blocks of a file, 1000 by default.
+	BlockSpan uint64
+}
+
+// Exporter writes the files of the blocks delivered by a scanner.
+type Exporter struct {
+	cfg Config
+
+	mu sync.Mutex
+	// from and last are the first and last block of the current files, date their day.
+	from, last uint64
+	date       string
+	empty      bool
+	rows       map[string]*bytes.Buffer
+	// inner is the checkpoint of the scanner, saved when the files are stored.
+	inner scanner.Checkpoint
+	next  uint64
+	saved bool
+}
+
+// New creates an exporter.
+func New(cfg Config) *Exporter {
+	if cfg.BlockSpan == 0 {
+		cfg.BlockSpan = defaultBlockSpan
+	}
+	return &Exporter{cfg: cfg, empty: true, rows: make(map[string]*bytes.Buffer)}
+}
+
+// Checkpoint returns the checkpoint of the scanner, inner saved only up to the stored files.
+// Configure the scanner with it, the exporter stores the files of a block range and then saves
+// inner.
+func (e *Exporter) Checkpoint(inner scanner.Checkpoint) scanner.Checkpoint {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.inner = inner
+	return (*exporterCheckpoint)(e)
+}
+
+type exporterCheckpoint Exporter
+
+func (c *exporterCheckpoint) Load(ctx context.Context) (uint64, bool, error) {
+	return c.inner.Load(ctx)
+}
+
+// Save records the progress of the scanner, saved with the next files.
+func (c *exporterCheckpoint) Save(ctx context.Context, next uint64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.next, c.saved = next, true
+	return nil
+}
+
+// Attach registers the exporter on a scanner configured with Checkpoint. A block starting a new
+// range or day stores the files of the previous blocks, a failure stops the scanner.
+func (e *Exporter) Attach(s *scanner.Scanner) {
+	s.OnBlock(func(ctx context.Context, block *types.Block) error {
+		return e.addBlock(ctx, block)
+	})
+	s.OnTrade(func(ctx context.Context, trade *scanner.Trade) error {
+		tx := trade.Transaction
+		row := &Trade{
+			Block:      tx.Block.NumberU64(),
+			Time:       tx.Block.Time(),
+			TxHash:     tx.Tx.Hash(),
+			TxIndex:    tx.Index,
+			Type:       types2.TypeName(trade.Type),
+			NFTAddress: trade.NFTAddress,
+			Exchanger:  trade.Exchanger,
+			Buyer:      trade.Buyer,
+			From:       tx.From,
+			Price:      "0",
+		}
+		if trade.Price != nil {
+			row.Price = trade.Price.String()
+		}
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		return e.add(TableTrades, row)
+	})
+}
+
+func (e *Exporter) addBlock(ctx context.Context, block *types.Block) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	number := block.NumberU64()
+	date := time.Unix(int64(block.Time()), 0).UTC().Format("2006-01-02")
+	if !e.empty {
+		switch {
+		case number != e.last+1:
+			// The scanner restarted from the checkpoint, the rows since are scanned again.
+			e.reset()
+		case number/e.cfg.BlockSpan != e.from/e.cfg.BlockSpan || date != e.date:
+			if err := e.flush(ctx); err != nil {
+				return err
+			}
+		}
+	}
+	if e.empty {
+		e.from, e.date, e.empty = number, date, false
+	}
+	e.last = number
+	return e.add(TableBlocks, &Block{
+		Number:       number,
+		Hash:         block.Hash(),
+		ParentHash:   block.ParentHash(),
+		Time:         block.Time(),
+		Miner:        block.Coinbase(),
+		GasUsed:      block.GasUsed(),
+		GasLimit:     block.GasLimit(),
+		Transactions: len(block.Transactions()),
+	})
+}
+
+// add appends a row to the current file of table.
+func (e *Exporter) add(table string, row interface{}) error {
+	data, err := json.Marshal(row)
+	if err != nil {
+		return err
+	}
+	buf, ok := e.rows[table]
+	if !ok {
+		buf = new(bytes.Buffer)
+		e.rows[table] = buf
+	}
+	buf.Write(data)
+	buf.WriteByte('\n')
+	return nil
+}
+
+func (e *Exporter) reset() {
+	e.rows = make(map[string]*bytes.Buffer)
+	e.empty = true
+}
+
+// Flush stores the files of the blocks delivered since the last files and saves the checkpoint
+// of the scanner. Call it once the scanner stopped, the blocks of the current range are
+// otherwise scanned again by the next run.
+func (e *Exporter) Flush(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.empty {
+		return e.save(ctx)
+	}
+	return e.flush(ctx)
+}
+
+func (e *Exporter) flush(ctx context.Context) error {
+	for _, table := range []string{TableBlocks, TableTrades} {
+		rows, ok := e.rows[table]
+		if !ok {
+			continue
+		}
+		data, err := compress(rows.Bytes())
+		if err != nil {
+			return err
+		}
+		key := e.Key(table, e.date, e.from, e.last)
+		if err := e.cfg.Store.Put(ctx, key, data); err != nil {
+			return xerrors.Errorf("store %s: %w", key, err)
+		}
+	}
+	e.reset()
+	return e.save(ctx)
+}
+
+// save saves the progress of the scanner up to the stored files.
+func (e *Exporter) save(ctx context.Context) error {
+	if e.inner == nil || !e.saved {
+		return nil
+	}
+	return e.inner.Save(ctx, e.next)
+}
+
+// Key returns the key of the file of table with the blocks from..to of date.
+func (e *Exporter) Key(table, date string, from, to uint64) string {
+	return fmt.Sprintf("%s%s/date=%s/%012d-%012d.jsonl.gz", e.cfg.Prefix, table, date, from, to)
+}
+
+func compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
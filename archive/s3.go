@@ -0,0 +1,137 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// S3Config configures an S3 store.
+type S3Config struct {
+	// Endpoint is the URL of the service, e.g. https://s3.eu-west-1.amazonaws.com, or
+	// https://storage.googleapis.com for Google Cloud Storage with HMAC keys, or a MinIO server.
+	Endpoint string
+	// Region is the region of the bucket, "auto" for Google Cloud Storage.
+	Region string
+	Bucket string
+	// AccessKey and SecretKey sign the requests.
+	AccessKey string
+	SecretKey string
+	// Client sends the requests, an http.Client with a 60s timeout by default.
+	Client *http.Client
+}
+
+// S3 stores the files in a bucket of an S3 compatible service, addressed in path style:
+// Endpoint/Bucket/key. Requests are signed with AWS Signature Version 4.
+type S3 struct {
+	cfg S3Config
+}
+
+// NewS3 creates an S3 store.
+func NewS3(cfg S3Config) *S3 {
+	cfg.Endpoint = strings.TrimRight(cfg.Endpoint, "/")
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: 60 * time.Second}
+	}
+	return &S3{cfg: cfg}
+}
+
+// Put uploads the object key.
+func (s *S3) Put(ctx context.Context, key string, data []byte) error {
+	endpoint, err := url.Parse(s.cfg.Endpoint)
+	if err != nil || endpoint.Host == "" {
+		return xerrors.Errorf("invalid S3 endpoint %q", s.cfg.Endpoint)
+	}
+	path := endpoint.Path + "/" + s.cfg.Bucket + "/" + key
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.cfg.Endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.URL.Path, req.URL.RawPath = path, uriEncode(path)
+	req.Header.Set("Content-Type", "application/gzip")
+	s.sign(req, data)
+	resp, err := s.cfg.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return xerrors.Errorf("put s3://%s/%s: %s: %s", s.cfg.Bucket, key, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// sign adds the Authorization header of Signature Version 4, signing the host, the content
+// type and the payload hash.
+func (s *S3) sign(req *http.Request, payload []byte) {
+	now := time.Now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+	payloadHash := sha256Hex(payload)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signed := []string{"content-type", "host", "x-amz-content-sha256", "x-amz-date"}
+	var headers strings.Builder
+	for _, name := range signed {
+		value := req.Header.Get(name)
+		if name == "host" {
+			value = req.URL.Host
+		}
+		headers.WriteString(name + ":" + strings.TrimSpace(value) + "\n")
+	}
+	signedHeaders := strings.Join(signed, ";")
+	canonical := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		headers.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := day + "/" + s.cfg.Region + "/s3/aws4_request"
+	toSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonical))
+	key := hmacSHA256([]byte("AWS4"+s.cfg.SecretKey), day)
+	for _, part := range []string{s.cfg.Region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, toSign))
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+s.cfg.AccessKey+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// uriEncode encodes a path the way Signature Version 4 does: all but the unreserved characters
+// and the slashes.
+func uriEncode(path string) string {
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || strings.IndexByte("-_.~/", c) >= 0 {
+			b.WriteByte(c)
+		} else {
+			b.WriteString("%" + strings.ToUpper(hex.EncodeToString([]byte{c})))
+		}
+	}
+	return b.String()
+}
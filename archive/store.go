@@ -0,0 +1,39 @@
+package archive
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// Store stores the files of an exporter. Put replaces the object of an existing key.
+type Store interface {
+	Put(ctx context.Context, key string, data []byte) error
+}
+
+// Dir stores the files in a local directory, the keys being paths relative to it, e.g. for a
+// file system mounted from object storage.
+type Dir string
+
+// Put writes the file atomically, creating its directories.
+func (d Dir) Put(ctx context.Context, key string, data []byte) error {
+	path := filepath.Join(string(d), filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
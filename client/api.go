@@ -1,37 +1,87 @@
 package client
 
-import "github.com/ethereum/go-ethereum/common"
+import (
+	"context"
 
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// APIs is the contract of the wormholes transactions of a client, implemented by Wormholes.
+// Mocks of the client for the tests of its users are generated from it.
 type APIs interface {
 	NormalTransaction(to string, value int64, data string) (string, error)
+	NormalTransactionCtx(ctx context.Context, to string, value types2.Amount, data string) (string, error)
 	Mint(royalty uint32, metaURL string, exchanger string) (string, error)
+	MintCtx(ctx context.Context, royalty uint32, metaURL string, exchanger string) (string, error)
 	Transfer(nftAddress, to string) (string, error)
+	TransferCtx(ctx context.Context, nftAddress, to string) (string, error)
 	Author(nftAddress, to string) (string, error)
+	AuthorCtx(ctx context.Context, nftAddress, to string) (string, error)
 	AuthorRevoke(nftAddress, to string) (string, error)
-	AccountAuthor(to string) (string, error)
-	AccountAuthorRevoke(to string) (string, error)
+	AuthorRevokeCtx(ctx context.Context, nftAddress, to string) (string, error)
+	AuthorizeAll(to string) (string, error)
+	AuthorizeAllCtx(ctx context.Context, to string) (string, error)
+	RevokeAuthorizeAll(to string) (string, error)
+	RevokeAuthorizeAllCtx(ctx context.Context, to string) (string, error)
 	SNFTToERB(nftAddress string) (string, error)
+	SNFTToERBCtx(ctx context.Context, nftAddress string) (string, error)
 	//SNFTPledge(snftAddress string) (string, error)
 	//SNFTRevokesPledge(snftAddress string) (string, error)
 	TokenPledge(toaddress common.Address, proxyAddress, name, url string, value int64, feerate int) (string, error)
+	TokenPledgeCtx(ctx context.Context, toaddress common.Address, proxyAddress, name, url string, value types2.Amount, feerate int) (string, error)
 	TokenRevokesPledge(toaddress common.Address, value int64) (string, error)
+	TokenRevokesPledgeCtx(ctx context.Context, toaddress common.Address, value types2.Amount) (string, error)
 	//Open(feeRate uint32, name, url string) (string, error)
 	//Close() (string, error)
 	TransactionNFT(buyer []byte, to string) (string, error)
+	TransactionNFTCtx(ctx context.Context, buyer []byte, to string) (string, error)
 	BuyerInitiatingTransaction(seller1 []byte) (string, error)
+	BuyerInitiatingTransactionCtx(ctx context.Context, seller1 []byte) (string, error)
 	FoundryTradeBuyer(seller2 []byte) (string, error)
+	FoundryTradeBuyerCtx(ctx context.Context, seller2 []byte) (string, error)
 	FoundryExchange(buyer, seller2 []byte, to string) (string, error)
+	FoundryExchangeCtx(ctx context.Context, buyer, seller2 []byte, to string) (string, error)
 	NftExchangeMatch(buyer, seller, exchangerAuth []byte, to string) (string, error)
+	NftExchangeMatchCtx(ctx context.Context, buyer, seller, exchangerAuth []byte, to string) (string, error)
 	FoundryExchangeInitiated(buyer, seller2, exchangerAuthor []byte, to string) (string, error)
+	FoundryExchangeInitiatedCtx(ctx context.Context, buyer, seller2, exchangerAuth []byte, to string) (string, error)
 	NFTDoesNotAuthorizeExchanges(buyer, seller1 []byte, to string) (string, error)
+	NFTDoesNotAuthorizeExchangesCtx(ctx context.Context, buyer, seller1 []byte, to string) (string, error)
 	AdditionalPledgeAmount(value int64) (string, error)
+	AdditionalPledgeAmountCtx(ctx context.Context, value types2.Amount) (string, error)
 	RevokesPledgeAmount(value int64) (string, error)
-	VoteOfficialNFT(dir, startIndex string, number uint64, royalty uint32, creator string) (string, error)                                          //23
+	RevokesPledgeAmountCtx(ctx context.Context, value types2.Amount) (string, error)
+	VoteOfficialNFT(dir, startIndex string, number uint64, royalty uint32, creator string) (string, error) //23
+	VoteOfficialNFTCtx(ctx context.Context, dir, startIndex string, number uint64, royalty uint32, creator string) (string, error)
 	VoteOfficialNFTByApprovedExchanger(dir, startIndex string, number uint64, royalty uint32, creator string, exchangerAuth []byte) (string, error) //24
-	UnforzenAccount() (string, error)                                                                                                               //25
-	WeightRedemption() (string, error)                                                                                                              //26
-	BatchSellTransfer(buyer, seller, buyerAuth, sellerAuth, exchangerAuth []byte, to string) (string, error)                                        //27
-	ForceBuyingTransfer(buyer, buyerAuth, exchangerAuth []byte, to string) (string, error)                                                          //28
-	ExtractERB() (string, error)                                                                                                                    //29
-	AccountDelegate(proxySign []byte, proxyAddress string) (string, error)                                                                          //31
+	VoteOfficialNFTByApprovedExchangerCtx(ctx context.Context, dir, startIndex string, number uint64, royalty uint32, creator string, exchangerAuth []byte) (string, error)
+	UnfreezeAccount() (string, error) //25
+	UnfreezeAccountCtx(ctx context.Context) (string, error)
+	WeightRedemption() (string, error) //26
+	WeightRedemptionCtx(ctx context.Context) (string, error)
+	BatchSellTransfer(buyer, seller, buyerAuth, sellerAuth, exchangerAuth []byte, to string) (string, error) //27
+	BatchSellTransferCtx(ctx context.Context, buyer, seller, buyerAuth, sellerAuth, exchangerAuth []byte, to string) (string, error)
+	ForceBuyingTransfer(buyer, buyerAuth, exchangerAuth []byte, to string) (string, error) //28
+	ForceBuyingTransferCtx(ctx context.Context, buyer, buyerAuth, exchangerAuth []byte, to string) (string, error)
+	ExtractERB() (string, error) //29
+	ExtractERBCtx(ctx context.Context) (string, error)
+	AccountDelegate(proxySign []byte, proxyAddress string) (string, error) //31
+	AccountDelegateCtx(ctx context.Context, proxySign []byte, proxyAddress string) (string, error)
+
+	// Deprecated: use AuthorizeAll.
+	AccountAuthor(to string) (string, error)
+	// Deprecated: use AuthorizeAllCtx.
+	AccountAuthorCtx(ctx context.Context, to string) (string, error)
+	// Deprecated: use RevokeAuthorizeAll.
+	AccountAuthorRevoke(to string) (string, error)
+	// Deprecated: use RevokeAuthorizeAllCtx.
+	AccountAuthorRevokeCtx(ctx context.Context, to string) (string, error)
+	// Deprecated: use UnfreezeAccount.
+	UnforzenAccount() (string, error)
+	// Deprecated: use UnfreezeAccountCtx.
+	UnforzenAccountCtx(ctx context.Context) (string, error)
 }
+
+// The build fails when the methods of Wormholes no longer match APIs.
+var _ APIs = (*Wormholes)(nil)
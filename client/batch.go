@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+	"golang.org/x/xerrors"
+)
+
+// accountBatchSize is the number of account requests sent in one JSON-RPC batch, nodes limit
+// the size of batches.
+const accountBatchSize = 100
+
+// AccountResult is the state of an account fetched by GetAccountInfoBatch.
+// Exactly one of Account and Err is set.
+type AccountResult struct {
+	Address common.Address
+	Account *types2.Account
+	Err     error
+}
+
+// GetAccountInfoBatch returns the state of the accounts addrs at the given block height, e.g.
+// of the SNFT fragments of a period, in JSON-RPC batches of 100 requests instead of one round
+// trip per account. The results are in the order of addrs, one per address; an account that
+// could not be fetched has Err set, an error wrapping ethereum.NotFound for an unknown block.
+// The error reports a batch that could not be sent, the results are then nil.
+func (worm *Wormholes) GetAccountInfoBatch(ctx context.Context, addrs []string, block int64) ([]*AccountResult, error) {
+	return worm.GetAccountInfoBatchAt(ctx, addrs, blockTagWithNumber(rpc.BlockNumber(block)))
+}
+
+// GetAccountInfoBatchAt is GetAccountInfoBatch at the given block tag.
+func (worm *Wormholes) GetAccountInfoBatchAt(ctx context.Context, addrs []string, tag BlockTag) ([]*AccountResult, error) {
+	results := make([]*AccountResult, len(addrs))
+	for start := 0; start < len(addrs); start += accountBatchSize {
+		end := start + accountBatchSize
+		if end > len(addrs) {
+			end = len(addrs)
+		}
+		accounts := make([]*types2.Account, end-start)
+		reqs := make([]rpc.BatchElem, end-start)
+		for i := range reqs {
+			address := common.HexToAddress(worm.resolve(ctx, addrs[start+i]))
+			results[start+i] = &AccountResult{Address: address}
+			reqs[i] = rpc.BatchElem{
+				Method: "eth_getAccountInfo",
+				Args:   []interface{}{address, tag},
+				Result: &accounts[i],
+			}
+		}
+		if err := worm.batchCall(ctx, reqs); err != nil {
+			return nil, err
+		}
+		for i, req := range reqs {
+			result := results[start+i]
+			switch {
+			case req.Error != nil:
+				result.Err = req.Error
+			case accounts[i] == nil:
+				result.Err = xerrors.Errorf("account %s at block %s: %w", result.Address.Hex(), tag, ethereum.NotFound)
+			default:
+				result.Account = accounts[i]
+			}
+		}
+	}
+	return results, nil
+}
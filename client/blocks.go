@@ -0,0 +1,120 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// BlockResult is the outcome of fetching a single block of a range.
+// Exactly one of Block and Err is set.
+type BlockResult struct {
+	Number uint64
+	Block  *types.Block
+	Err    error
+}
+
+// RangeError reports the blocks of a range that could not be fetched.
+type RangeError struct {
+	Failed []uint64
+}
+
+func (e *RangeError) Error() string {
+	return fmt.Sprintf("failed to fetch %d block(s), first failed block %d", len(e.Failed), e.Failed[0])
+}
+
+// GetBlocksByRange fetches the blocks from..to (inclusive) using up to concurrency parallel requests.
+// The results are returned in block order, one per block. When some blocks could not be fetched
+// the successful results are still returned together with a *RangeError listing the failed numbers.
+func (worm *Wormholes) GetBlocksByRange(ctx context.Context, from, to uint64, concurrency int) ([]*BlockResult, error) {
+	return worm.GetBlocks(ctx, fixedFetchPool(concurrency), from, to)
+}
+
+// GetBlocks is GetBlocksByRange with the parallel requests of pool, e.g. a pool adapting to
+// the node shared by the historical scans of a service.
+func (worm *Wormholes) GetBlocks(ctx context.Context, pool *FetchPool, from, to uint64) ([]*BlockResult, error) {
+	if from > to {
+		return nil, fmt.Errorf("invalid block range %d..%d", from, to)
+	}
+	results := make([]*BlockResult, to-from+1)
+	errs := pool.Run(ctx, len(results), func(ctx context.Context, i int) error {
+		number := from + uint64(i)
+		block, err := worm.BlockByNumber(ctx, new(big.Int).SetUint64(number))
+		results[i] = &BlockResult{Number: number, Block: block, Err: err}
+		return err
+	})
+
+	var failed []uint64
+	for i, err := range errs {
+		if err != nil {
+			results[i] = &BlockResult{Number: from + uint64(i), Err: err}
+			failed = append(failed, from+uint64(i))
+		}
+	}
+	if len(failed) > 0 {
+		return results, &RangeError{Failed: failed}
+	}
+	return results, nil
+}
+
+// BeneficiaryResult is a reward beneficiary of a block delivered by StreamBeneficiaries.
+// When the beneficiaries of a block could not be fetched, Err is set and Beneficiary is nil.
+type BeneficiaryResult struct {
+	Block       uint64
+	Beneficiary *types2.BeneficiaryAddress
+	Err         error
+}
+
+// StreamBeneficiaries fetches the reward beneficiaries of the blocks from..to (inclusive)
+// using up to concurrency parallel requests and delivers one result per beneficiary.
+// Results of different blocks may arrive out of order. The channel is closed when all blocks
+// have been processed or ctx is cancelled.
+func (worm *Wormholes) StreamBeneficiaries(ctx context.Context, from, to uint64, concurrency int) <-chan *BeneficiaryResult {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	out := make(chan *BeneficiaryResult, concurrency)
+	numbers := make(chan uint64)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for number := range numbers {
+				list, err := worm.GetBlockBeneficiaryAddressByNumber(ctx, int64(number))
+				if err != nil {
+					select {
+					case out <- &BeneficiaryResult{Block: number, Err: err}:
+					case <-ctx.Done():
+					}
+					continue
+				}
+				for _, beneficiary := range *list {
+					select {
+					case out <- &BeneficiaryResult{Block: number, Beneficiary: beneficiary}:
+					case <-ctx.Done():
+					}
+				}
+			}
+		}()
+	}
+	go func() {
+		defer close(out)
+		for number := from; number <= to && ctx.Err() == nil; number++ {
+			select {
+			case numbers <- number:
+			case <-ctx.Done():
+			}
+			if number == to {
+				break
+			}
+		}
+		close(numbers)
+		wg.Wait()
+	}()
+	return out
+}
@@ -0,0 +1,88 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// BlockTag selects the block whose state a query is answered from.
+// Use one of the predefined tags or the AtBlockNumber and AtBlockHash constructors. The zero
+// value is Latest.
+type BlockTag struct {
+	number *rpc.BlockNumber
+	hash   *common.Hash
+}
+
+var (
+	Latest    = blockTagWithNumber(rpc.LatestBlockNumber)
+	Pending   = blockTagWithNumber(rpc.PendingBlockNumber)
+	Earliest  = blockTagWithNumber(rpc.EarliestBlockNumber)
+	Finalized = blockTagWithNumber(rpc.FinalizedBlockNumber)
+)
+
+var errHashTagNotSupported = errors.New("block hash tag not supported by this query")
+
+func blockTagWithNumber(number rpc.BlockNumber) BlockTag {
+	return BlockTag{number: &number}
+}
+
+// blockTagOf returns the tag of a block number argument of the ethclient style: nil is the
+// latest block and the negative rpc.BlockNumber constants select their tags.
+func blockTagOf(number *big.Int) BlockTag {
+	if number == nil {
+		return Latest
+	}
+	return blockTagWithNumber(rpc.BlockNumber(number.Int64()))
+}
+
+// AtBlockNumber returns the tag of the block at the given height.
+func AtBlockNumber(n uint64) BlockTag {
+	return blockTagWithNumber(rpc.BlockNumber(n))
+}
+
+// AtBlockHash returns the tag of the block with the given hash. The node rejects the query
+// if the block is not part of the canonical chain.
+func AtBlockHash(h common.Hash) BlockTag {
+	return BlockTag{hash: &h}
+}
+
+// BlockNumber returns the block number of the tag, ok is false for hash tags.
+func (t BlockTag) BlockNumber() (rpc.BlockNumber, bool) {
+	if t.number == nil {
+		if t.hash == nil {
+			return rpc.LatestBlockNumber, true
+		}
+		return 0, false
+	}
+	return *t.number, true
+}
+
+// BlockHash returns the block hash of the tag, ok is false for number tags.
+func (t BlockTag) BlockHash() (common.Hash, bool) {
+	if t.hash == nil {
+		return common.Hash{}, false
+	}
+	return *t.hash, true
+}
+
+func (t BlockTag) String() string {
+	if hash, ok := t.BlockHash(); ok {
+		return hash.Hex()
+	}
+	number, _ := t.BlockNumber()
+	return number.String()
+}
+
+// MarshalJSON encodes number tags as the usual block parameter string and hash tags
+// as a canonical block hash object, as accepted by the state query RPCs.
+func (t BlockTag) MarshalJSON() ([]byte, error) {
+	if hash, ok := t.BlockHash(); ok {
+		return json.Marshal(rpc.BlockNumberOrHashWithHash(hash, true))
+	}
+	number, _ := t.BlockNumber()
+	return json.Marshal(number.String())
+}
@@ -0,0 +1,377 @@
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"math/big"
+	"strings"
+
+	"github.com/erbieio/erb-client/v2/tools"
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"golang.org/x/xerrors"
+)
+
+// TxBuilder starts a fluent wormholes transaction, e.g.
+//
+//	hash, err := worm.Tx().Mint().Royalty(1000).MetaURL(u).Via(exchanger).GasPrice(p).Send(ctx)
+//
+// Every transaction kind only has the option methods of its own payload fields. The nonce,
+// gas price and gas limit are taken from the node and the gas limits of the client unless set.
+type TxBuilder struct {
+	worm *Wormholes
+}
+
+// Tx starts a transaction sent by the account of worm.
+func (worm *Wormholes) Tx() *TxBuilder {
+	return &TxBuilder{worm: worm}
+}
+
+// Mint starts a Mint transaction.
+func (b *TxBuilder) Mint() *MintTx {
+	tx := &MintTx{}
+	tx.init(b.worm, tx)
+	return tx
+}
+
+// Transfer starts a Transfer of an NFT or SNFT.
+func (b *TxBuilder) Transfer(nftAddress string) *TransferTx {
+	tx := &TransferTx{nftAddress: nftAddress}
+	tx.init(b.worm, tx)
+	return tx
+}
+
+// Author starts the authorization of an NFT to an exchanger.
+func (b *TxBuilder) Author(nftAddress string) *AuthorTx {
+	tx := &AuthorTx{nftAddress: nftAddress, txType: types2.Author}
+	tx.init(b.worm, tx)
+	return tx
+}
+
+// AuthorRevoke starts the revocation of the authorization of an NFT.
+func (b *TxBuilder) AuthorRevoke(nftAddress string) *AuthorTx {
+	tx := &AuthorTx{nftAddress: nftAddress, txType: types2.AuthorRevoke}
+	tx.init(b.worm, tx)
+	return tx
+}
+
+// SNFTToERB starts the conversion of an SNFT to ERB.
+func (b *TxBuilder) SNFTToERB(nftAddress string) *SNFTToERBTx {
+	tx := &SNFTToERBTx{nftAddress: nftAddress}
+	tx.init(b.worm, tx)
+	return tx
+}
+
+// Pay starts a plain ERB transfer of value wei.
+func (b *TxBuilder) Pay(to string, value *big.Int) *PayTx {
+	tx := &PayTx{to: to, value: value}
+	tx.init(b.worm, tx)
+	return tx
+}
+
+// txOptions holds the options shared by all transaction kinds. Its methods return the
+// kind T so that calls can be chained.
+type txOptions[T any] struct {
+	worm     *Wormholes
+	self     T
+	nonce    *uint64
+	gasPrice *big.Int
+	gasLimit uint64
+}
+
+func (o *txOptions[T]) init(worm *Wormholes, self T) {
+	o.worm = worm
+	o.self = self
+}
+
+// Nonce sets the nonce instead of the pending nonce of the account.
+func (o *txOptions[T]) Nonce(nonce uint64) T {
+	o.nonce = &nonce
+	return o.self
+}
+
+// GasPrice sets the gas price instead of the price suggested by the node.
+func (o *txOptions[T]) GasPrice(price *big.Int) T {
+	o.gasPrice = price
+	return o.self
+}
+
+// GasLimit sets the gas limit instead of the limit of the client for the transaction kind,
+// see SetGasLimit.
+func (o *txOptions[T]) GasLimit(limit uint64) T {
+	o.gasLimit = limit
+	return o.self
+}
+
+// sign builds and signs a transaction to the recipient with the given value and wormholes payload,
+// a nil payload sends data as is.
+func (o *txOptions[T]) sign(ctx context.Context, to string, value *big.Int, payload *types2.Transaction, data []byte) (*types.Transaction, error) {
+	account, fromKey, err := o.worm.signingKey()
+	if err != nil {
+		return nil, err
+	}
+	if to == "" {
+		to = account.Hex()
+	}
+	if payload != nil {
+		payload.Version = types2.WormHolesVersion
+		encoded, err := json.Marshal(payload)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to format wormholes data: %v", err)
+		}
+		data = append([]byte(TranPrefix), encoded...)
+	}
+	if value == nil {
+		value = new(big.Int)
+	}
+	toAddr := common.HexToAddress(to)
+	gasLimit := o.gasLimit
+	if gasLimit == 0 {
+		if payload == nil {
+			gasLimit = payGasLimit
+		} else if gasLimit, err = o.worm.gasLimit(ctx, payload.Type, account, toAddr, value, data); err != nil {
+			return nil, err
+		}
+	}
+	return o.signWith(ctx, fromKey, account, &toAddr, value, gasLimit, data)
+}
+
+// signWith signs a transaction of account to to, nil for a contract creation, with the nonce
+// and gas price of the options or of the node.
+func (o *txOptions[T]) signWith(ctx context.Context, fromKey *ecdsa.PrivateKey, account common.Address, to *common.Address, value *big.Int, gasLimit uint64, data []byte) (*types.Transaction, error) {
+	var nonce uint64
+	var err error
+	if o.nonce != nil {
+		nonce = *o.nonce
+	} else if nonce, err = o.worm.PendingNonceAt(ctx, account); err != nil {
+		return nil, err
+	}
+	gasPrice := o.gasPrice
+	if gasPrice == nil {
+		if gasPrice, err = o.worm.SuggestGasPrice(ctx); err != nil {
+			return nil, err
+		}
+	}
+	chainID, err := o.worm.NetworkID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tx := types.NewTx(&types.LegacyTx{Nonce: nonce, GasPrice: gasPrice, Gas: gasLimit, To: to, Value: value, Data: data})
+	return o.worm.signTx(tx, chainID, fromKey)
+}
+
+func (o *txOptions[T]) send(ctx context.Context, tx *types.Transaction) (string, error) {
+	if err := o.worm.SendTransaction(ctx, tx); err != nil {
+		return "", err
+	}
+	return strings.ToLower(tx.Hash().String()), nil
+}
+
+// MintTx is a Mint transaction being built.
+type MintTx struct {
+	txOptions[*MintTx]
+	royalty   uint32
+	metaURL   string
+	exchanger string
+}
+
+// Royalty sets the royalty rate of the NFT in ten-thousandths.
+func (t *MintTx) Royalty(royalty uint32) *MintTx {
+	t.royalty = royalty
+	return t
+}
+
+// MetaURL sets the metadata address of the NFT.
+func (t *MintTx) MetaURL(metaURL string) *MintTx {
+	t.metaURL = metaURL
+	return t
+}
+
+// Via mints the NFT exclusively for an exchanger.
+func (t *MintTx) Via(exchanger string) *MintTx {
+	t.exchanger = exchanger
+	return t
+}
+
+// Build returns the signed transaction without sending it.
+func (t *MintTx) Build(ctx context.Context) (*types.Transaction, error) {
+	if err := tools.CheckRoyalty("Mint() royalty", uint64(t.royalty)); err != nil {
+		return nil, err
+	}
+	if err := tools.CheckMetaURL("Mint() metaURL", t.metaURL); err != nil {
+		return nil, err
+	}
+	exchanger, err := checkOptionalExchanger("Mint() exchanger", t.worm.resolve(ctx, t.exchanger))
+	if err != nil {
+		return nil, err
+	}
+	return t.sign(ctx, "", nil, &types2.Transaction{
+		Type:      types2.Mint,
+		Royalty:   t.royalty,
+		MetaURL:   t.metaURL,
+		Exchanger: exchanger,
+	}, nil)
+}
+
+// Send signs and sends the transaction and returns its hash.
+func (t *MintTx) Send(ctx context.Context) (string, error) {
+	unlock, err := t.worm.lockKeySender(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+	tx, err := t.Build(ctx)
+	if err != nil {
+		return "", err
+	}
+	return t.send(ctx, tx)
+}
+
+// TransferTx is a Transfer transaction being built.
+type TransferTx struct {
+	txOptions[*TransferTx]
+	nftAddress string
+	to         string
+}
+
+// To sets the new owner.
+func (t *TransferTx) To(to string) *TransferTx {
+	t.to = to
+	return t
+}
+
+// Build returns the signed transaction without sending it.
+func (t *TransferTx) Build(ctx context.Context) (*types.Transaction, error) {
+	if err := tools.CheckNFTAddress("Transfer() wormAddress", t.nftAddress); err != nil {
+		return nil, err
+	}
+	to := t.worm.resolve(ctx, t.to)
+	if err := tools.CheckAddress("Transfer() to", to); err != nil {
+		return nil, err
+	}
+	return t.sign(ctx, to, nil, &types2.Transaction{Type: types2.Transfer, NFTAddress: t.nftAddress}, nil)
+}
+
+// Send signs and sends the transaction and returns its hash.
+func (t *TransferTx) Send(ctx context.Context) (string, error) {
+	unlock, err := t.worm.lockKeySender(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+	tx, err := t.Build(ctx)
+	if err != nil {
+		return "", err
+	}
+	return t.send(ctx, tx)
+}
+
+// AuthorTx is an Author or AuthorRevoke transaction being built.
+type AuthorTx struct {
+	txOptions[*AuthorTx]
+	txType     uint8
+	nftAddress string
+	exchanger  string
+}
+
+// Via sets the exchanger the authorization is granted to or revoked from.
+func (t *AuthorTx) Via(exchanger string) *AuthorTx {
+	t.exchanger = exchanger
+	return t
+}
+
+// Build returns the signed transaction without sending it.
+func (t *AuthorTx) Build(ctx context.Context) (*types.Transaction, error) {
+	if err := tools.CheckNFTAddress("Author() wormAddress", t.nftAddress); err != nil {
+		return nil, err
+	}
+	exchanger, err := checkExchanger("Author() exchanger", t.worm.resolve(ctx, t.exchanger))
+	if err != nil {
+		return nil, err
+	}
+	return t.sign(ctx, exchanger, nil, &types2.Transaction{Type: t.txType, NFTAddress: t.nftAddress}, nil)
+}
+
+// Send signs and sends the transaction and returns its hash.
+func (t *AuthorTx) Send(ctx context.Context) (string, error) {
+	unlock, err := t.worm.lockKeySender(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+	tx, err := t.Build(ctx)
+	if err != nil {
+		return "", err
+	}
+	return t.send(ctx, tx)
+}
+
+// SNFTToERBTx is an SNFTToERB transaction being built.
+type SNFTToERBTx struct {
+	txOptions[*SNFTToERBTx]
+	nftAddress string
+}
+
+// Build returns the signed transaction without sending it.
+func (t *SNFTToERBTx) Build(ctx context.Context) (*types.Transaction, error) {
+	if err := tools.CheckNFTAddress("SNFTToERB() wormAddress", t.nftAddress); err != nil {
+		return nil, err
+	}
+	return t.sign(ctx, "", nil, &types2.Transaction{Type: types2.SNFTToERB, NFTAddress: t.nftAddress}, nil)
+}
+
+// Send signs and sends the transaction and returns its hash.
+func (t *SNFTToERBTx) Send(ctx context.Context) (string, error) {
+	unlock, err := t.worm.lockKeySender(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+	tx, err := t.Build(ctx)
+	if err != nil {
+		return "", err
+	}
+	return t.send(ctx, tx)
+}
+
+// PayTx is a plain ERB transfer being built.
+type PayTx struct {
+	txOptions[*PayTx]
+	to    string
+	value *big.Int
+	data  []byte
+}
+
+// Data sets the data of the transaction.
+func (t *PayTx) Data(data []byte) *PayTx {
+	t.data = data
+	return t
+}
+
+// Build returns the signed transaction without sending it.
+func (t *PayTx) Build(ctx context.Context) (*types.Transaction, error) {
+	to := t.worm.resolve(ctx, t.to)
+	if err := tools.CheckAddress("Pay() to", to); err != nil {
+		return nil, err
+	}
+	if t.value != nil && t.value.Sign() < 0 {
+		return nil, xerrors.Errorf("Pay() value must not be negative: %s wei", t.value)
+	}
+	return t.sign(ctx, to, t.value, nil, t.data)
+}
+
+// Send signs and sends the transaction and returns its hash.
+func (t *PayTx) Send(ctx context.Context) (string, error) {
+	unlock, err := t.worm.lockKeySender(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+	tx, err := t.Build(ctx)
+	if err != nil {
+		return "", err
+	}
+	return t.send(ctx, tx)
+}
@@ -0,0 +1,203 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	defaultCacheSize          = 1024
+	defaultCacheConfirmations = 12
+)
+
+// CacheConfig configures a CachedClient. Zero values select the defaults.
+type CacheConfig struct {
+	// Size is the maximum number of entries of each cache, 1024 by default.
+	Size int
+	// TTL bounds how long an entry is served, entries never expire by default.
+	TTL time.Duration
+	// Confirmations is the number of blocks below the head whose receipts and account states
+	// are not cached, as a reorg can still replace them. 12 by default, negative caches them
+	// at any depth.
+	Confirmations int
+}
+
+// CachedClient wraps a Wormholes client and memoizes the reads whose result can not change:
+// blocks and headers by hash, and transaction receipts and account state at an explicit block
+// number once the block is confirmed.
+//
+// Only the methods below are cached. All other methods, including the promoted ones that look
+// up blocks or accounts internally such as DecodeTrade, call the wrapped client directly.
+type CachedClient struct {
+	*Wormholes
+
+	confirmations uint64
+
+	blocks   *ttlCache[common.Hash, *types.Block]
+	headers  *ttlCache[common.Hash, *types2.Header]
+	receipts *ttlCache[common.Hash, *types.Receipt]
+	accounts *ttlCache[accountKey, *types2.Account]
+}
+
+type accountKey struct {
+	address common.Address
+	block   int64
+}
+
+// NewCachedClient wraps worm with LRU caches.
+func NewCachedClient(worm *Wormholes, cfg CacheConfig) *CachedClient {
+	if cfg.Size <= 0 {
+		cfg.Size = defaultCacheSize
+	}
+	if cfg.Confirmations == 0 {
+		cfg.Confirmations = defaultCacheConfirmations
+	} else if cfg.Confirmations < 0 {
+		cfg.Confirmations = 0
+	}
+	return &CachedClient{
+		Wormholes:     worm,
+		confirmations: uint64(cfg.Confirmations),
+		blocks:        newTTLCache[common.Hash, *types.Block](cfg.Size, cfg.TTL),
+		headers:       newTTLCache[common.Hash, *types2.Header](cfg.Size, cfg.TTL),
+		receipts:      newTTLCache[common.Hash, *types.Receipt](cfg.Size, cfg.TTL),
+		accounts:      newTTLCache[accountKey, *types2.Account](cfg.Size, cfg.TTL),
+	}
+}
+
+// BlockByHash returns the given full block, from the cache if possible.
+func (c *CachedClient) BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error) {
+	if block, ok := c.blocks.get(hash); ok {
+		return block, nil
+	}
+	block, err := c.Wormholes.BlockByHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	c.blocks.add(hash, block)
+	return block, nil
+}
+
+// HeaderByHash returns the header of the given block, from the cache if possible.
+func (c *CachedClient) HeaderByHash(ctx context.Context, hash common.Hash) (*types2.Header, error) {
+	if header, ok := c.headers.get(hash); ok {
+		return header, nil
+	}
+	header, err := c.Wormholes.HeaderByHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	c.headers.add(hash, header)
+	return header, nil
+}
+
+// TransactionReceipt returns the receipt of a transaction, from the cache if possible.
+// Receipts are only cached once their block is confirmed.
+func (c *CachedClient) TransactionReceipt(ctx context.Context, txHash string) (*types.Receipt, error) {
+	hash := common.HexToHash(txHash)
+	if receipt, ok := c.receipts.get(hash); ok {
+		return receipt, nil
+	}
+	receipt, err := c.Wormholes.TransactionReceipt(ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
+	if receipt.BlockNumber != nil && c.confirmed(ctx, receipt.BlockNumber.Uint64()) {
+		c.receipts.add(hash, receipt)
+	}
+	return receipt, nil
+}
+
+// GetAccountInfo returns the account state at the given block, from the cache if possible.
+// Only explicit numbers of confirmed blocks are cached, the negative block tags are always
+// forwarded.
+func (c *CachedClient) GetAccountInfo(ctx context.Context, address string, block int64) (*types2.Account, error) {
+	address = c.resolve(ctx, address)
+	if block < 0 {
+		return c.Wormholes.GetAccountInfo(ctx, address, block)
+	}
+	key := accountKey{address: common.HexToAddress(address), block: block}
+	if account, ok := c.accounts.get(key); ok {
+		return account, nil
+	}
+	account, err := c.Wormholes.GetAccountInfo(ctx, address, block)
+	if err != nil {
+		return nil, err
+	}
+	if c.confirmed(ctx, uint64(block)) {
+		c.accounts.add(key, account)
+	}
+	return account, nil
+}
+
+// confirmed reports whether the block number is at least Confirmations blocks below the head.
+// It asks the node for the head unless nothing is left unconfirmed, a failure counts as not
+// confirmed.
+func (c *CachedClient) confirmed(ctx context.Context, number uint64) bool {
+	if c.confirmations == 0 {
+		return true
+	}
+	head, err := c.Wormholes.BlockNumber(ctx)
+	return err == nil && number+c.confirmations <= head
+}
+
+// Purge drops all cached entries.
+func (c *CachedClient) Purge() {
+	c.blocks.purge()
+	c.headers.purge()
+	c.receipts.purge()
+	c.accounts.purge()
+}
+
+// ttlCache is an LRU cache whose entries expire after ttl, a zero ttl never expires.
+type ttlCache[K comparable, V any] struct {
+	mu    sync.Mutex
+	cache lru.BasicLRU[K, ttlEntry[V]]
+	ttl   time.Duration
+}
+
+type ttlEntry[V any] struct {
+	value   V
+	expires time.Time
+}
+
+func newTTLCache[K comparable, V any](size int, ttl time.Duration) *ttlCache[K, V] {
+	return &ttlCache[K, V]{cache: lru.NewBasicLRU[K, ttlEntry[V]](size), ttl: ttl}
+}
+
+func (c *ttlCache[K, V]) get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.cache.Get(key)
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	if c.ttl > 0 && time.Now().After(entry.expires) {
+		c.cache.Remove(key)
+		var zero V
+		return zero, false
+	}
+	return entry.value, true
+}
+
+func (c *ttlCache[K, V]) add(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry := ttlEntry[V]{value: value}
+	if c.ttl > 0 {
+		entry.expires = time.Now().Add(c.ttl)
+	}
+	c.cache.Add(key, entry)
+}
+
+func (c *ttlCache[K, V]) purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cache.Purge()
+}
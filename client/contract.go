@@ -0,0 +1,291 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+
+	"github.com/erbieio/erb-client/v2/tools"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/xerrors"
+)
+
+var errBlockHashWithRange = errors.New("cannot specify both BlockHash and FromBlock/ToBlock")
+
+var (
+	_ bind.ContractBackend = (*ContractBackend)(nil)
+	_ bind.DeployBackend   = (*ContractBackend)(nil)
+)
+
+// ContractBackend runs contract bindings generated by abigen over the connection of a client,
+// it is a bind.ContractBackend and a bind.DeployBackend:
+//
+//	token, err := NewToken(address, worm.ContractBackend())
+//
+// It has the methods of the client, HeaderByNumber and TransactionReceipt take and return the
+// go-ethereum types expected by the bindings.
+type ContractBackend struct {
+	*Wormholes
+}
+
+// ContractBackend returns worm as a backend of contract bindings. Transactions sent by the
+// bindings take their nonces from the node, do not send them concurrently with transactions of
+// the client from the same account.
+func (worm *Wormholes) ContractBackend() *ContractBackend {
+	return &ContractBackend{worm}
+}
+
+// HeaderByNumber returns the go-ethereum header of a block from the current canonical chain.
+// If number is nil, the latest known header is returned.
+func (b *ContractBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	var head *types.Header
+	err := b.call(ctx, &head, "eth_getBlockByNumber", toBlockNumArg(number), false)
+	if err == nil && head == nil {
+		return nil, ethereum.NotFound
+	}
+	return head, err
+}
+
+// TransactionReceipt returns the receipt of the transaction txHash, the error wraps
+// ethereum.NotFound while it is pending.
+func (b *ContractBackend) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	return b.Wormholes.TransactionReceipt(ctx, txHash.Hex())
+}
+
+// CodeAt returns the contract code of the given account at the given block height.
+// If blockNumber is nil, the latest known block is used.
+func (worm *Wormholes) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
+	var result hexutil.Bytes
+	err := worm.call(ctx, &result, "eth_getCode", account, toBlockNumArg(blockNumber))
+	return result, err
+}
+
+// PendingCodeAt returns the contract code of the given account in the pending state.
+func (worm *Wormholes) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	var result hexutil.Bytes
+	err := worm.call(ctx, &result, "eth_getCode", account, "pending")
+	return result, err
+}
+
+// CallContract executes a message call transaction, which is directly executed in the VM
+// of the node, but never mined into the blockchain. If blockNumber is nil, the latest known
+// block is used.
+func (worm *Wormholes) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	var hex hexutil.Bytes
+	err := worm.call(ctx, &hex, "eth_call", toCallArg(msg), toBlockNumArg(blockNumber))
+	if err != nil {
+		return nil, err
+	}
+	return hex, nil
+}
+
+// PendingCallContract executes a message call transaction using the pending state.
+func (worm *Wormholes) PendingCallContract(ctx context.Context, msg ethereum.CallMsg) ([]byte, error) {
+	var hex hexutil.Bytes
+	err := worm.call(ctx, &hex, "eth_call", toCallArg(msg), "pending")
+	if err != nil {
+		return nil, err
+	}
+	return hex, nil
+}
+
+// FilterLogs executes a filter query.
+func (worm *Wormholes) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	var result []types.Log
+	arg, err := toFilterArg(q)
+	if err != nil {
+		return nil, err
+	}
+	err = worm.call(ctx, &result, "eth_getLogs", arg)
+	return result, err
+}
+
+// SubscribeFilterLogs subscribes to the results of a streaming filter query. Subscriptions
+// need a websocket or IPC connection to the node.
+func (worm *Wormholes) SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	if worm.c == nil {
+		return nil, worm.notConnected()
+	}
+	arg, err := toFilterArg(q)
+	if err != nil {
+		return nil, err
+	}
+	sub, err := worm.c.EthSubscribe(ctx, ch, "logs", arg)
+	if err != nil {
+		return nil, wrapRPCError("eth_subscribe", err)
+	}
+	return sub, nil
+}
+
+func toFilterArg(q ethereum.FilterQuery) (interface{}, error) {
+	arg := map[string]interface{}{
+		"address": q.Addresses,
+		"topics":  q.Topics,
+	}
+	if q.BlockHash != nil {
+		arg["blockHash"] = *q.BlockHash
+		if q.FromBlock != nil || q.ToBlock != nil {
+			return nil, errBlockHashWithRange
+		}
+	} else {
+		if q.FromBlock == nil {
+			arg["fromBlock"] = "0x0"
+		} else {
+			arg["fromBlock"] = toBlockNumArg(q.FromBlock)
+		}
+		arg["toBlock"] = toBlockNumArg(q.ToBlock)
+	}
+	return arg, nil
+}
+
+// Deploy starts the deployment of a contract with the given creation bytecode, args are the
+// arguments of its constructor in parsed.
+func (b *TxBuilder) Deploy(parsed abi.ABI, bytecode []byte, args ...interface{}) *ContractTx {
+	input, err := parsed.Pack("", args...)
+	tx := &ContractTx{data: append(append([]byte{}, bytecode...), input...), err: err}
+	tx.init(b.worm, tx)
+	return tx
+}
+
+// Contract starts a call of method of the contract at address that changes its state, args
+// are the arguments of the method in parsed.
+func (b *TxBuilder) Contract(address string, parsed abi.ABI, method string, args ...interface{}) *ContractTx {
+	data, err := parsed.Pack(method, args...)
+	tx := &ContractTx{to: address, data: data, err: err}
+	tx.init(b.worm, tx)
+	return tx
+}
+
+// ContractTx is a contract deployment or method call being built. Without a gas limit the
+// node estimates the gas of the transaction.
+type ContractTx struct {
+	txOptions[*ContractTx]
+	// to is the contract, empty for a deployment.
+	to    string
+	value *big.Int
+	data  []byte
+	// err is the error of encoding the arguments.
+	err error
+}
+
+// Value sets the ERB sent to a payable method or constructor, in wei.
+func (t *ContractTx) Value(value *big.Int) *ContractTx {
+	t.value = value
+	return t
+}
+
+// Build returns the signed transaction without sending it.
+func (t *ContractTx) Build(ctx context.Context) (*types.Transaction, error) {
+	if t.err != nil {
+		return nil, xerrors.Errorf("failed to encode the contract arguments: %w", t.err)
+	}
+	account, fromKey, err := t.worm.signingKey()
+	if err != nil {
+		return nil, err
+	}
+	var to *common.Address
+	if t.to != "" {
+		address := t.worm.resolve(ctx, t.to)
+		if err := tools.CheckAddress("Contract() address", address); err != nil {
+			return nil, err
+		}
+		contract := common.HexToAddress(address)
+		to = &contract
+	}
+	value := t.value
+	if value == nil {
+		value = new(big.Int)
+	} else if value.Sign() < 0 {
+		return nil, xerrors.Errorf("Contract() value must not be negative: %s wei", value)
+	}
+	gasLimit := t.gasLimit
+	if gasLimit == 0 {
+		msg := ethereum.CallMsg{From: account, To: to, Value: value, Data: t.data}
+		if gasLimit, err = t.worm.EstimateGas(ctx, msg); err != nil {
+			return nil, xerrors.Errorf("failed to estimate the gas of the contract transaction: %w", err)
+		}
+	}
+	return t.signWith(ctx, fromKey, account, to, value, gasLimit, t.data)
+}
+
+// Send signs and sends the transaction and returns its hash.
+func (t *ContractTx) Send(ctx context.Context) (string, error) {
+	unlock, err := t.worm.lockKeySender(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+	tx, err := t.Build(ctx)
+	if err != nil {
+		return "", err
+	}
+	return t.send(ctx, tx)
+}
+
+// DeployContract deploys a contract with the given creation bytecode and constructor arguments
+// and returns its address and the hash of the transaction. The contract exists once the
+// transaction is mined, see WaitMined.
+func (worm *Wormholes) DeployContract(ctx context.Context, parsed abi.ABI, bytecode []byte, args ...interface{}) (common.Address, string, error) {
+	t := worm.Tx().Deploy(parsed, bytecode, args...)
+	unlock, err := worm.lockKeySender(ctx)
+	if err != nil {
+		return common.Address{}, "", err
+	}
+	defer unlock()
+	tx, err := t.Build(ctx)
+	if err != nil {
+		return common.Address{}, "", err
+	}
+	hash, err := t.send(ctx, tx)
+	if err != nil {
+		return common.Address{}, "", err
+	}
+	account, _, _ := worm.signingKey()
+	return crypto.CreateAddress(account, tx.Nonce()), hash, nil
+}
+
+// TransactContract calls method of the contract at address in a transaction and returns its
+// hash. Use Tx().Contract to set the value, nonce or gas.
+func (worm *Wormholes) TransactContract(ctx context.Context, address string, parsed abi.ABI, method string, args ...interface{}) (string, error) {
+	return worm.Tx().Contract(address, parsed, method, args...).Send(ctx)
+}
+
+// CallContractMethod calls the read-only method of the contract at address at the latest block,
+// from the account of worm if it has a key, and returns the decoded results.
+func (worm *Wormholes) CallContractMethod(ctx context.Context, address string, parsed abi.ABI, method string, args ...interface{}) ([]interface{}, error) {
+	address = worm.resolve(ctx, address)
+	if err := tools.CheckAddress("CallContractMethod() address", address); err != nil {
+		return nil, err
+	}
+	data, err := parsed.Pack(method, args...)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to encode the arguments of %s: %w", method, err)
+	}
+	contract := common.HexToAddress(address)
+	msg := ethereum.CallMsg{To: &contract, Data: data}
+	if account, _, err := worm.signingKey(); err == nil {
+		msg.From = account
+	}
+	output, err := worm.CallContract(ctx, msg, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(output) == 0 && len(parsed.Methods[method].Outputs) > 0 {
+		if code, err := worm.CodeAt(ctx, contract, nil); err == nil && len(code) == 0 {
+			return nil, xerrors.Errorf("no contract at %s: %w", contract.Hex(), bind.ErrNoCode)
+		}
+	}
+	return parsed.Unpack(method, output)
+}
+
+// ParseABI parses the JSON ABI of a contract, e.g. the output of solc --abi.
+func ParseABI(abiJSON string) (abi.ABI, error) {
+	return abi.JSON(strings.NewReader(abiJSON))
+}
@@ -0,0 +1,48 @@
+package client
+
+import "context"
+
+// The methods below are the former names of methods renamed for their typos or unclear meaning.
+// They are kept so that code can move to the new names one call at a time.
+
+// AccountAuthor authorizes to to trade all NFTs of the account.
+//
+// Deprecated: use AuthorizeAll.
+func (worm *Wormholes) AccountAuthor(to string) (string, error) {
+	return worm.AuthorizeAll(to)
+}
+
+// AccountAuthorCtx is AccountAuthor with a context for the requests to the node.
+//
+// Deprecated: use AuthorizeAllCtx.
+func (worm *Wormholes) AccountAuthorCtx(ctx context.Context, to string) (string, error) {
+	return worm.AuthorizeAllCtx(ctx, to)
+}
+
+// AccountAuthorRevoke revokes an authorization of AccountAuthor.
+//
+// Deprecated: use RevokeAuthorizeAll.
+func (worm *Wormholes) AccountAuthorRevoke(to string) (string, error) {
+	return worm.RevokeAuthorizeAll(to)
+}
+
+// AccountAuthorRevokeCtx is AccountAuthorRevoke with a context for the requests to the node.
+//
+// Deprecated: use RevokeAuthorizeAllCtx.
+func (worm *Wormholes) AccountAuthorRevokeCtx(ctx context.Context, to string) (string, error) {
+	return worm.RevokeAuthorizeAllCtx(ctx, to)
+}
+
+// UnforzenAccount unfreezes the account.
+//
+// Deprecated: use UnfreezeAccount.
+func (worm *Wormholes) UnforzenAccount() (string, error) {
+	return worm.UnfreezeAccount()
+}
+
+// UnforzenAccountCtx is UnforzenAccount with a context for the requests to the node.
+//
+// Deprecated: use UnfreezeAccountCtx.
+func (worm *Wormholes) UnforzenAccountCtx(ctx context.Context) (string, error) {
+	return worm.UnfreezeAccountCtx(ctx)
+}
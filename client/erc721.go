@@ -0,0 +1,144 @@
+package client
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/erbieio/erb-client/v2/tools"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"golang.org/x/xerrors"
+)
+
+// erc721ABI is the part of the ERC-721 interface used by the client.
+const erc721ABI = `[
+	{"type":"function","name":"ownerOf","stateMutability":"view","inputs":[{"name":"tokenId","type":"uint256"}],"outputs":[{"type":"address"}]},
+	{"type":"function","name":"tokenURI","stateMutability":"view","inputs":[{"name":"tokenId","type":"uint256"}],"outputs":[{"type":"string"}]},
+	{"type":"function","name":"balanceOf","stateMutability":"view","inputs":[{"name":"owner","type":"address"}],"outputs":[{"type":"uint256"}]},
+	{"type":"function","name":"safeTransferFrom","stateMutability":"nonpayable","inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"tokenId","type":"uint256"}],"outputs":[]}
+]`
+
+// ERC721 is the parsed ABI of the ERC-721 methods used by the client.
+var ERC721, _ = ParseABI(erc721ABI)
+
+// AssetKind is the kind of an NFT listed by Asset.
+type AssetKind string
+
+const (
+	// NativeNFT is a wormholes NFT, an account of the chain.
+	NativeNFT AssetKind = "native"
+	// ERC721Token is a token of an ERC-721 contract.
+	ERC721Token AssetKind = "erc721"
+)
+
+// Asset describes a native NFT or an ERC-721 token the same way, so both kinds can be listed
+// together. The chain has no bridge between the two kinds, an ERC-721 token can not be turned
+// into a native NFT or back.
+type Asset struct {
+	Kind AssetKind `json:"kind"`
+	// Address is the address of a native NFT or of the ERC-721 contract.
+	Address common.Address `json:"address"`
+	// TokenID is the id of an ERC-721 token, nil for a native NFT.
+	TokenID *big.Int       `json:"tokenId,omitempty"`
+	Owner   common.Address `json:"owner"`
+	// MetaURL is the metadata URL of a native NFT or the tokenURI of an ERC-721 token.
+	MetaURL string `json:"metaUrl"`
+}
+
+// NativeAsset returns the native NFT at address as an Asset. The error wraps ethereum.NotFound
+// if address is not an NFT.
+func (worm *Wormholes) NativeAsset(ctx context.Context, address string) (*Asset, error) {
+	address = worm.resolve(ctx, address)
+	if err := tools.CheckAddress("NativeAsset() address", address); err != nil {
+		return nil, err
+	}
+	info, err := worm.GetAccountInfoAt(ctx, address, Latest)
+	if err != nil {
+		return nil, err
+	}
+	if info.Nft.Owner == (common.Address{}) {
+		return nil, xerrors.Errorf("nft %s: %w", address, ethereum.NotFound)
+	}
+	return &Asset{
+		Kind:    NativeNFT,
+		Address: common.HexToAddress(address),
+		Owner:   info.Nft.Owner,
+		MetaURL: info.Nft.MetaURL,
+	}, nil
+}
+
+// ERC721Asset returns the token tokenID of the ERC-721 contract at address as an Asset.
+func (worm *Wormholes) ERC721Asset(ctx context.Context, contract string, tokenID *big.Int) (*Asset, error) {
+	owner, err := worm.ERC721OwnerOf(ctx, contract, tokenID)
+	if err != nil {
+		return nil, err
+	}
+	uri, err := worm.ERC721TokenURI(ctx, contract, tokenID)
+	if err != nil {
+		return nil, err
+	}
+	return &Asset{
+		Kind:    ERC721Token,
+		Address: common.HexToAddress(worm.resolve(ctx, contract)),
+		TokenID: new(big.Int).Set(tokenID),
+		Owner:   owner,
+		MetaURL: uri,
+	}, nil
+}
+
+// ERC721OwnerOf returns the owner of the token tokenID of the ERC-721 contract.
+func (worm *Wormholes) ERC721OwnerOf(ctx context.Context, contract string, tokenID *big.Int) (common.Address, error) {
+	out, err := worm.callERC721(ctx, contract, "ownerOf", tokenID)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return *abi.ConvertType(out[0], new(common.Address)).(*common.Address), nil
+}
+
+// ERC721TokenURI returns the metadata URI of the token tokenID of the ERC-721 contract.
+func (worm *Wormholes) ERC721TokenURI(ctx context.Context, contract string, tokenID *big.Int) (string, error) {
+	out, err := worm.callERC721(ctx, contract, "tokenURI", tokenID)
+	if err != nil {
+		return "", err
+	}
+	return *abi.ConvertType(out[0], new(string)).(*string), nil
+}
+
+// ERC721BalanceOf returns the number of tokens of the ERC-721 contract owned by owner.
+func (worm *Wormholes) ERC721BalanceOf(ctx context.Context, contract, owner string) (*big.Int, error) {
+	owner = worm.resolve(ctx, owner)
+	if err := tools.CheckAddress("ERC721BalanceOf() owner", owner); err != nil {
+		return nil, err
+	}
+	out, err := worm.callERC721(ctx, contract, "balanceOf", common.HexToAddress(owner))
+	if err != nil {
+		return nil, err
+	}
+	return abi.ConvertType(out[0], new(big.Int)).(*big.Int), nil
+}
+
+// TransferERC721 transfers the token tokenID of the ERC-721 contract from the account of worm
+// to the address to with safeTransferFrom, and returns the hash of the transaction.
+func (worm *Wormholes) TransferERC721(ctx context.Context, contract, to string, tokenID *big.Int) (string, error) {
+	account, _, err := worm.signingKey()
+	if err != nil {
+		return "", err
+	}
+	to = worm.resolve(ctx, to)
+	if err := tools.CheckAddress("TransferERC721() to", to); err != nil {
+		return "", err
+	}
+	return worm.TransactContract(ctx, contract, ERC721, "safeTransferFrom", account, common.HexToAddress(to), tokenID)
+}
+
+func (worm *Wormholes) callERC721(ctx context.Context, contract, method string, args ...interface{}) ([]interface{}, error) {
+	out, err := worm.CallContractMethod(ctx, contract, ERC721, method, args...)
+	if err != nil {
+		return nil, xerrors.Errorf("erc721 %s of %s: %w", method, contract, err)
+	}
+	if len(out) != 1 {
+		return nil, xerrors.Errorf("erc721 %s of %s returned %d values", method, contract, len(out))
+	}
+	return out, nil
+}
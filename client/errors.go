@@ -0,0 +1,94 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Errors of common failures. The transaction methods and the pre-checks return them wrapped,
+// test for them with errors.Is.
+var (
+	// ErrInsufficientBalance reports that an account can not pay an amount and the gas.
+	ErrInsufficientBalance = errors.New("insufficient balance")
+	// ErrNotNFTOwner reports that an account neither owns an NFT nor is authorized by its owner.
+	ErrNotNFTOwner = errors.New("not the owner of the nft")
+	// ErrExchangerNotOpen reports that the exchanger of an order has not opened an exchange.
+	ErrExchangerNotOpen = errors.New("exchanger not open")
+	// ErrOrderExpired reports that the block height a signed order is valid before was reached.
+	ErrOrderExpired = errors.New("order expired")
+	// ErrUnauthorizedExchange reports that an exchanger is not authorized to settle an order.
+	ErrUnauthorizedExchange = errors.New("exchange not authorized")
+	// ErrNotConnected reports a request of a client created without a node, or whose node URL
+	// could not be dialed.
+	ErrNotConnected = errors.New("client is not connected to a node")
+	// ErrWrongNetwork reports a transaction refused because the node, or the transaction, is on
+	// another chain than the one the client expects.
+	ErrWrongNetwork = errors.New("wrong network")
+	// ErrUnknownNetwork reports a network name that is not in a Registry.
+	ErrUnknownNetwork = errors.New("unknown network")
+)
+
+// RPCError is an error response of the node to a JSON-RPC request, get it with errors.As to
+// handle the errors of the node by their code instead of their message:
+//
+//	var rpcErr *client.RPCError
+//	if errors.As(err, &rpcErr) && rpcErr.Code == -32000 { ... }
+type RPCError struct {
+	// Method is the JSON-RPC method of the request, e.g. "eth_sendRawTransaction".
+	Method string
+	// Code is the error code of the response, -32000 for most errors of transactions.
+	Code int
+	// Data is the data of the response, nil if it has none.
+	Data interface{}
+	err  error
+}
+
+// Error returns the message of the node.
+func (e *RPCError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the error of the rpc package, an rpc.Error.
+func (e *RPCError) Unwrap() error {
+	return e.err
+}
+
+// wrapRPCError wraps an error response of the node to a request of method in an RPCError.
+// Other errors, e.g. of the connection, are returned as they are.
+func wrapRPCError(method string, err error) error {
+	var rpcErr rpc.Error
+	if err == nil || !errors.As(err, &rpcErr) {
+		return err
+	}
+	wrapped := &RPCError{Method: method, Code: rpcErr.ErrorCode(), err: err}
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		wrapped.Data = dataErr.ErrorData()
+	}
+	return wrapped
+}
+
+// nodeErrors are the errors of the messages with which the node rejects transactions.
+var nodeErrors = []struct {
+	msg string
+	err error
+}{
+	{"insufficient funds", ErrInsufficientBalance},
+}
+
+// wrapNodeError wraps the error of a transaction rejected by the node with the matching error
+// of the package, keeping the error of the node.
+func wrapNodeError(err error) error {
+	if err == nil {
+		return nil
+	}
+	for _, e := range nodeErrors {
+		if strings.Contains(err.Error(), e.msg) {
+			return fmt.Errorf("%w: %w", e.err, err)
+		}
+	}
+	return err
+}
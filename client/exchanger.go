@@ -0,0 +1,203 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// exchangerScanBatch is the number of blocks fetched per GetBlocksByRange call while indexing.
+	exchangerScanBatch          = 500
+	defaultExchangerConcurrency = 8
+)
+
+// ExchangerIndexConfig configures an ExchangerIndex. Zero values select the defaults.
+type ExchangerIndexConfig struct {
+	// Start is the first block scanned, e.g. the deployment block of the chain when the earlier
+	// blocks are known to hold no pledges. It is ignored when resuming from Checkpoint.
+	Start uint64
+	// Checkpoint resumes the index from the progress saved with ExchangerIndex.Checkpoint.
+	Checkpoint *ExchangerCheckpoint
+	// Concurrency is the largest number of parallel block requests, 8 by default.
+	Concurrency int
+}
+
+// ExchangerCheckpoint is the progress of an ExchangerIndex. It can be stored, e.g. as JSON,
+// to resume indexing after a restart without scanning the chain again.
+type ExchangerCheckpoint struct {
+	// Next is the number of the next block to be scanned.
+	Next uint64 `json:"next"`
+	// Candidates are the accounts of the pledges found in the blocks before Next.
+	Candidates []common.Address `json:"candidates"`
+}
+
+// ExchangerIndex discovers exchangers by scanning the chain for TokenPledge transactions.
+// The node has no RPC to enumerate accounts, so every account that ever sent or received
+// a pledge is remembered as a candidate and its ExchangerFlag is checked when listing.
+//
+// This is the way for services to list exchangers repeatedly: keep the index, call Update
+// with the new head before List and save its Checkpoint to resume after a restart.
+type ExchangerIndex struct {
+	worm        *Wormholes
+	concurrency int
+
+	mu         sync.Mutex
+	next       uint64
+	candidates map[common.Address]struct{}
+}
+
+// NewExchangerIndex creates an index that scans from cfg.Start or resumes from cfg.Checkpoint.
+func NewExchangerIndex(worm *Wormholes, cfg ExchangerIndexConfig) *ExchangerIndex {
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = defaultExchangerConcurrency
+	}
+	idx := &ExchangerIndex{
+		worm:        worm,
+		concurrency: cfg.Concurrency,
+		next:        cfg.Start,
+		candidates:  make(map[common.Address]struct{}),
+	}
+	if cfg.Checkpoint != nil {
+		idx.next = cfg.Checkpoint.Next
+		for _, addr := range cfg.Checkpoint.Candidates {
+			idx.candidates[addr] = struct{}{}
+		}
+	}
+	return idx
+}
+
+// Update scans the blocks after the last scanned one up to and including block to.
+// Calling it again later only scans the new blocks. The blocks are scanned in batches
+// and the progress of the finished batches is kept when a later batch fails.
+func (idx *ExchangerIndex) Update(ctx context.Context, to uint64) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	for idx.next <= to {
+		end := idx.next + exchangerScanBatch - 1
+		if end > to || end < idx.next {
+			end = to
+		}
+		results, err := idx.worm.GetBlocksByRange(ctx, idx.next, end, idx.concurrency)
+		if err != nil {
+			return err
+		}
+		for _, result := range results {
+			idx.collect(result.Block)
+		}
+		idx.next = end + 1
+	}
+	return nil
+}
+
+// Checkpoint returns the progress of the index, with the candidates ordered by address.
+func (idx *ExchangerIndex) Checkpoint() *ExchangerCheckpoint {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	return &ExchangerCheckpoint{Next: idx.next, Candidates: idx.sortedCandidates()}
+}
+
+// sortedCandidates returns the candidates ordered by address, idx.mu must be held.
+func (idx *ExchangerIndex) sortedCandidates() []common.Address {
+	candidates := make([]common.Address, 0, len(idx.candidates))
+	for addr := range idx.candidates {
+		candidates = append(candidates, addr)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return strings.Compare(candidates[i].Hex(), candidates[j].Hex()) < 0
+	})
+	return candidates
+}
+
+func (idx *ExchangerIndex) collect(block *types.Block) {
+	for _, tx := range block.Transactions() {
+		transaction, ok := ParseWormholesData(tx.Data())
+		if !ok || transaction.Type != types2.TokenPledge {
+			continue
+		}
+		if tx.To() != nil {
+			idx.candidates[*tx.To()] = struct{}{}
+		}
+		if from, err := TransactionSender(block, tx); err == nil {
+			idx.candidates[from] = struct{}{}
+		}
+	}
+}
+
+// List returns the candidates that have ExchangerFlag set at the given block, ordered by address.
+// Only the pledges of the scanned blocks are known, call Update up to block first.
+func (idx *ExchangerIndex) List(ctx context.Context, block int64) ([]*types2.Exchanger, error) {
+	idx.mu.Lock()
+	candidates := idx.sortedCandidates()
+	idx.mu.Unlock()
+
+	var exchangers []*types2.Exchanger
+	for _, addr := range candidates {
+		account, err := idx.worm.GetAccountInfo(ctx, addr.Hex(), block)
+		if err != nil {
+			return nil, err
+		}
+		if account.Worm == nil || !account.Worm.ExchangerFlag {
+			continue
+		}
+		exchangers = append(exchangers, &types2.Exchanger{
+			Address:        addr,
+			Name:           account.Worm.ExchangerName,
+			URL:            account.Worm.ExchangerURL,
+			FeeRate:        account.Worm.FeeRate,
+			PledgedBalance: account.Worm.PledgedBalance,
+			BlockNumber:    account.Worm.BlockNumber,
+		})
+	}
+	return exchangers, nil
+}
+
+// ListExchangers scans the whole chain up to block and returns all accounts that are exchangers at
+// that block. A negative block (rpc.LatestBlockNumber) scans up to the current head.
+// Every call scans from the genesis block, which is only suitable for one-off queries. Services that
+// list exchangers repeatedly should keep an ExchangerIndex instead, which only scans new blocks.
+func (worm *Wormholes) ListExchangers(ctx context.Context, block int64) ([]*types2.Exchanger, error) {
+	if block < 0 {
+		latest, err := worm.BlockNumber(ctx)
+		if err != nil {
+			return nil, err
+		}
+		block = int64(latest)
+	}
+	idx := NewExchangerIndex(worm, ExchangerIndexConfig{})
+	if err := idx.Update(ctx, uint64(block)); err != nil {
+		return nil, err
+	}
+	return idx.List(ctx, block)
+}
+
+// ParseWormholesData decodes the payload of a wormholes special transaction.
+// ok is false when data is not a wormholes payload, e.g. for plain ERB transfers.
+func ParseWormholesData(data []byte) (*types2.Transaction, bool) {
+	if !strings.HasPrefix(string(data), TranPrefix) {
+		return nil, false
+	}
+	var transaction types2.Transaction
+	if err := json.Unmarshal(data[len(TranPrefix):], &transaction); err != nil {
+		return nil, false
+	}
+	return &transaction, true
+}
+
+// DecodeWormholesData decodes the payload of a wormholes special transaction into the typed
+// payload of its type, holding only the fields the type uses.
+func DecodeWormholesData(data []byte) (types2.Payload, error) {
+	transaction, ok := ParseWormholesData(data)
+	if !ok {
+		return nil, xerrors.New("not a wormholes transaction payload")
+	}
+	return types2.DecodePayload(transaction)
+}
@@ -0,0 +1,173 @@
+package client
+
+import (
+	"encoding/json"
+
+	"github.com/erbieio/erb-client/v2/tools"
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"golang.org/x/xerrors"
+)
+
+// The kinds of orders of an UnsignedOrder.
+const (
+	OrderBuyer         = "buyer"
+	OrderSeller1       = "seller1"
+	OrderSeller2       = "seller2"
+	OrderExchangerAuth = "exchanger_auth"
+)
+
+// UnsignedOrder is an order exported to be signed by a browser wallet, so the key of a seller or
+// buyer stays in the wallet. The chain checks orders signed as personal messages, which is what
+// personal_sign of MetaMask compatible wallets does: the page calls
+//
+//	ethereum.request({method: "personal_sign", params: [order.data, account]})
+//
+// and returns the signature to ImportOrder. EIP-712 typed data signatures are not accepted by
+// the chain, orders can not be signed with eth_signTypedData.
+type UnsignedOrder struct {
+	// Kind is the kind of Order, OrderBuyer, OrderSeller1, OrderSeller2 or OrderExchangerAuth.
+	Kind string `json:"kind"`
+	// Order is the order without a signature, as JSON.
+	Order json.RawMessage `json:"order"`
+	// Signer is the account that must sign the order, empty for any.
+	Signer string `json:"signer,omitempty"`
+	// Message is the text signed, shown by the wallet.
+	Message string `json:"message"`
+	// Data is Message as hex, the first parameter of personal_sign.
+	Data string `json:"data"`
+	// Hash is the EIP-191 hash signed.
+	Hash common.Hash `json:"hash"`
+}
+
+// signedOrder is an order of an UnsignedOrder.
+type signedOrder interface {
+	Message() string
+	SigningHash() common.Hash
+	Validate() error
+}
+
+// ExportOrder exports an order built with the constructors of the types package, a
+// *types.Buyer, *types.Seller1, *types.Seller2 or *types.ExchangerAuth, to be signed by the
+// account signer, which may be empty.
+func ExportOrder(order interface{}, signer string) (*UnsignedOrder, error) {
+	var kind string
+	// The copies are exported without their signatures.
+	switch o := order.(type) {
+	case *types2.Buyer:
+		b := *o
+		b.Sig = ""
+		kind, order = OrderBuyer, &b
+	case *types2.Seller1:
+		s := *o
+		s.Sig = ""
+		kind, order = OrderSeller1, &s
+	case *types2.Seller2:
+		s := *o
+		s.Sig = ""
+		kind, order = OrderSeller2, &s
+	case *types2.ExchangerAuth:
+		a := *o
+		a.Sig = ""
+		kind, order = OrderExchangerAuth, &a
+	default:
+		return nil, xerrors.Errorf("can not export orders of type %T", order)
+	}
+	if signer != "" {
+		if err := tools.CheckAddress("ExportOrder() signer", signer); err != nil {
+			return nil, err
+		}
+		signer = common.HexToAddress(signer).Hex()
+	}
+	data, err := json.Marshal(order)
+	if err != nil {
+		return nil, err
+	}
+	o, _, err := decodeOrder(kind, data)
+	if err != nil {
+		return nil, err
+	}
+	message := o.Message()
+	return &UnsignedOrder{
+		Kind:    kind,
+		Order:   data,
+		Signer:  signer,
+		Message: message,
+		Data:    hexutil.Encode([]byte(message)),
+		Hash:    o.SigningHash(),
+	}, nil
+}
+
+// ImportOrder attaches the signature sig of a browser wallet to the exported order u and
+// returns the signed order as JSON, as signed by the Sign methods of the wallet, and its signer.
+// The signature is checked against the order rather than against the Message and Hash of u,
+// and must be by the Signer of u if it is set. V may be 27 or 28 or 0 or 1, the order carries
+// the 27 or 28 the chain expects.
+func ImportOrder(u *UnsignedOrder, sig string) ([]byte, common.Address, error) {
+	o, sigField, err := decodeOrder(u.Kind, u.Order)
+	if err != nil {
+		return nil, common.Address{}, err
+	}
+	signer, err := tools.RecoverAddress(o.Message(), sig)
+	if err != nil {
+		return nil, common.Address{}, xerrors.Errorf("invalid %s signature: %w", u.Kind, err)
+	}
+	if u.Signer != "" && !tools.SameAddress(u.Signer, signer.Hex()) {
+		return nil, common.Address{}, xerrors.Errorf("%s signed by %s, want %s", u.Kind, signer.Hex(), u.Signer)
+	}
+	if *sigField, err = tools.ConvertSignatureV(sig, tools.SignatureVOffset); err != nil {
+		return nil, common.Address{}, err
+	}
+	signed, err := json.Marshal(o)
+	if err != nil {
+		return nil, common.Address{}, err
+	}
+	return signed, signer, nil
+}
+
+// VerifyOrder checks the fields of the signed order of the given kind, as JSON, and returns the
+// account that signed it, whether a wallet of the client or a browser wallet signed it.
+func VerifyOrder(kind string, signed []byte) (common.Address, error) {
+	o, sigField, err := decodeOrder(kind, signed)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if *sigField == "" {
+		return common.Address{}, xerrors.Errorf("%s is not signed", kind)
+	}
+	signer, err := tools.RecoverAddress(o.Message(), *sigField)
+	if err != nil {
+		return common.Address{}, xerrors.Errorf("invalid %s signature: %w", kind, err)
+	}
+	return signer, nil
+}
+
+// decodeOrder decodes and validates an order of kind, and returns it with its signature field.
+func decodeOrder(kind string, data []byte) (signedOrder, *string, error) {
+	var o signedOrder
+	var sig *string
+	switch kind {
+	case OrderBuyer:
+		b := new(types2.Buyer)
+		o, sig = b, &b.Sig
+	case OrderSeller1:
+		s := new(types2.Seller1)
+		o, sig = s, &s.Sig
+	case OrderSeller2:
+		s := new(types2.Seller2)
+		o, sig = s, &s.Sig
+	case OrderExchangerAuth:
+		a := new(types2.ExchangerAuth)
+		o, sig = a, &a.Sig
+	default:
+		return nil, nil, xerrors.Errorf("unknown order kind %q", kind)
+	}
+	if err := json.Unmarshal(data, o); err != nil {
+		return nil, nil, xerrors.Errorf("invalid %s order: %w", kind, err)
+	}
+	if err := o.Validate(); err != nil {
+		return nil, nil, err
+	}
+	return o, sig, nil
+}
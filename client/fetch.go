@@ -0,0 +1,213 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"golang.org/x/xerrors"
+)
+
+// methodNotFound is the JSON-RPC error code of a method the node does not have.
+const methodNotFound = -32601
+
+const (
+	defaultMaxConcurrency = 16
+	defaultTargetLatency  = time.Second
+)
+
+// FetchConfig configures a FetchPool. Zero values select the defaults.
+type FetchConfig struct {
+	// MinConcurrency is the number of parallel requests the pool starts with and never goes
+	// below, 1 by default.
+	MinConcurrency int
+	// MaxConcurrency is the largest number of parallel requests, 16 by default.
+	MaxConcurrency int
+	// TargetLatency is the request duration above which the node is considered overloaded,
+	// 1s by default.
+	TargetLatency time.Duration
+}
+
+// FetchPool runs read requests to a node in parallel and adapts their number to the node:
+// it adds a request after as many fast successes as requests run, and halves them when a
+// request fails or is slower than the target latency. Share one pool between the users of a
+// node so they back off together. It is safe for concurrent use.
+type FetchPool struct {
+	cfg FetchConfig
+
+	mu        sync.Mutex
+	limit     int
+	active    int
+	successes int
+	// wake is closed and replaced when a request finishes or the limit grows.
+	wake chan struct{}
+}
+
+// NewFetchPool creates a pool of MinConcurrency parallel requests.
+func NewFetchPool(cfg FetchConfig) *FetchPool {
+	if cfg.MinConcurrency <= 0 {
+		cfg.MinConcurrency = 1
+	}
+	if cfg.MaxConcurrency <= 0 {
+		cfg.MaxConcurrency = defaultMaxConcurrency
+	}
+	if cfg.MaxConcurrency < cfg.MinConcurrency {
+		cfg.MaxConcurrency = cfg.MinConcurrency
+	}
+	if cfg.TargetLatency <= 0 {
+		cfg.TargetLatency = defaultTargetLatency
+	}
+	return &FetchPool{cfg: cfg, limit: cfg.MinConcurrency, wake: make(chan struct{})}
+}
+
+// fixedFetchPool returns a pool of always concurrency parallel requests.
+func fixedFetchPool(concurrency int) *FetchPool {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	return NewFetchPool(FetchConfig{MinConcurrency: concurrency, MaxConcurrency: concurrency})
+}
+
+// Concurrency returns the current number of parallel requests.
+func (p *FetchPool) Concurrency() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.limit
+}
+
+// Run calls fetch for the indexes 0..n-1 in parallel and returns the error of every call,
+// nil if all succeeded. The calls not started when ctx is done fail with ctx.Err().
+func (p *FetchPool) Run(ctx context.Context, n int, fetch func(ctx context.Context, i int) error) []error {
+	errs := make([]error, n)
+	failed := false
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		if err := p.acquire(ctx); err != nil {
+			for ; i < n; i++ {
+				errs[i] = err
+			}
+			failed = true
+			break
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			start := time.Now()
+			err := fetch(ctx, i)
+			p.release(ctx, time.Since(start), err)
+			if err != nil {
+				mu.Lock()
+				errs[i], failed = err, true
+				mu.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+	if !failed {
+		return nil
+	}
+	return errs
+}
+
+// acquire waits until fewer requests than the limit run.
+func (p *FetchPool) acquire(ctx context.Context) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		p.mu.Lock()
+		if p.active < p.limit {
+			p.active++
+			p.mu.Unlock()
+			return nil
+		}
+		wake := p.wake
+		p.mu.Unlock()
+		select {
+		case <-wake:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// release adapts the limit to the outcome of a finished request.
+func (p *FetchPool) release(ctx context.Context, latency time.Duration, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.active--
+	switch {
+	case ctx.Err() != nil || errors.Is(err, ethereum.NotFound):
+		// Neither a cancellation nor a missing object says anything about the node.
+	case err != nil || latency > p.cfg.TargetLatency:
+		p.successes = 0
+		if p.limit /= 2; p.limit < p.cfg.MinConcurrency {
+			p.limit = p.cfg.MinConcurrency
+		}
+	default:
+		if p.successes++; p.successes >= p.limit && p.limit < p.cfg.MaxConcurrency {
+			p.successes = 0
+			p.limit++
+		}
+	}
+	close(p.wake)
+	p.wake = make(chan struct{})
+}
+
+// GetReceipts fetches the receipts of the transactions hashes with pool, in the order of the
+// hashes. It fails if a receipt could not be fetched.
+func (worm *Wormholes) GetReceipts(ctx context.Context, pool *FetchPool, hashes []common.Hash) ([]*types.Receipt, error) {
+	receipts := make([]*types.Receipt, len(hashes))
+	errs := pool.Run(ctx, len(hashes), func(ctx context.Context, i int) (err error) {
+		receipts[i], err = worm.TransactionReceipt(ctx, hashes[i].Hex())
+		return err
+	})
+	for i, err := range errs {
+		if err != nil {
+			return nil, xerrors.Errorf("receipt of %s: %w", hashes[i].Hex(), err)
+		}
+	}
+	return receipts, nil
+}
+
+// FetchReceipts returns the receipts of the transactions of block, in their order. It asks the
+// node for all of them with eth_getBlockReceipts; nodes without it, remembered by the clients
+// sharing the connection, are asked for the receipts one by one with concurrency parallel
+// requests.
+func (worm *Wormholes) FetchReceipts(ctx context.Context, block *types.Block, concurrency int) ([]*types.Receipt, error) {
+	txs := block.Transactions()
+	if len(txs) == 0 {
+		return []*types.Receipt{}, nil
+	}
+	if !worm.network.noBlockReceipts.Load() {
+		var receipts []*types.Receipt
+		err := worm.call(ctx, &receipts, "eth_getBlockReceipts", rpc.BlockNumberOrHashWithHash(block.Hash(), false))
+		var rpcErr *RPCError
+		switch {
+		case errors.As(err, &rpcErr) && rpcErr.Code == methodNotFound:
+			worm.network.noBlockReceipts.Store(true)
+		case err != nil:
+			return nil, xerrors.Errorf("receipts of block %d: %w", block.NumberU64(), err)
+		case len(receipts) != len(txs):
+			return nil, xerrors.Errorf("block %d has %d transactions, got %d receipts", block.NumberU64(), len(txs), len(receipts))
+		default:
+			for i, receipt := range receipts {
+				if receipt == nil || receipt.TxHash != txs[i].Hash() {
+					return nil, xerrors.Errorf("receipt %d of block %d is not of transaction %s", i, block.NumberU64(), txs[i].Hash().Hex())
+				}
+			}
+			return receipts, nil
+		}
+	}
+	hashes := make([]common.Hash, len(txs))
+	for i, tx := range txs {
+		hashes[i] = tx.Hash()
+	}
+	return worm.GetReceipts(ctx, fixedFetchPool(concurrency), hashes)
+}
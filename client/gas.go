@@ -0,0 +1,95 @@
+package client
+
+import (
+	"context"
+	"math/big"
+	"sync"
+
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// defaultGasLimits are the gas limits of the wormholes transaction types. The settlements
+// decode and verify signed orders, the batch trades several of them, so they need more.
+var defaultGasLimits = map[uint8]uint64{
+	types2.Mint:                               60000,
+	types2.Transfer:                           50000,
+	types2.Author:                             50000,
+	types2.AuthorRevoke:                       50000,
+	types2.AccountAuthor:                      50000,
+	types2.AccountAuthorRevoke:                50000,
+	types2.SNFTToERB:                          50000,
+	types2.TokenPledge:                        70000,
+	types2.TokenRevokesPledge:                 50000,
+	types2.TransactionNFT:                     100000,
+	types2.BuyerInitiatingTransaction:         100000,
+	types2.FoundryTradeBuyer:                  101000,
+	types2.FoundryExchange:                    140000,
+	types2.NftExchangeMatch:                   140000,
+	types2.FoundryExchangeInitiated:           170000,
+	types2.FtDoesNotAuthorizeExchanges:        130000,
+	types2.AdditionalPledgeAmount:             55000,
+	types2.RevokesPledgeAmount:                55000,
+	types2.VoteOfficialNFT:                    60000,
+	types2.VoteOfficialNFTByApprovedExchanger: 60000,
+	types2.UnfreezeAccount:                    50000,
+	types2.WeightRedemption:                   50000,
+	types2.BatchSellTransfer:                  200000,
+	types2.ForceBuyingTransfer:                200000,
+	types2.ExtractERB:                         50000,
+	types2.AccountDelegate:                    70000,
+}
+
+// payGasLimit is the gas limit of plain ERB transfers.
+const payGasLimit = 51000
+
+// DefaultGasLimit returns the gas limit the client uses for the transactions of txType unless
+// it is overridden with SetGasLimit. It returns false for types without a default, whose gas
+// is estimated by the node.
+func DefaultGasLimit(txType uint8) (uint64, bool) {
+	limit, ok := defaultGasLimits[txType]
+	return limit, ok
+}
+
+// gasLimits holds the gas limits set with SetGasLimit, shared by the clients of a connection.
+type gasLimits struct {
+	mu     sync.RWMutex
+	limits map[uint8]uint64
+}
+
+func newGasLimits() *gasLimits {
+	return &gasLimits{limits: make(map[uint8]uint64)}
+}
+
+// SetGasLimit sets the gas limit of the transactions of txType sent by worm and the clients
+// sharing its connection, instead of the default. A limit of 0 has the node estimate the gas of
+// every transaction of the type, e.g. for VoteOfficialNFT injecting many NFTs.
+func (worm *Wormholes) SetGasLimit(txType uint8, limit uint64) {
+	worm.gas.mu.Lock()
+	defer worm.gas.mu.Unlock()
+	worm.gas.limits[txType] = limit
+}
+
+// gasLimit returns the gas limit of a transaction of txType: the limit set with SetGasLimit,
+// otherwise the default of the type, otherwise the estimate of the node with a margin.
+func (worm *Wormholes) gasLimit(ctx context.Context, txType uint8, from, to common.Address, value *big.Int, data []byte) (uint64, error) {
+	limit, ok := uint64(0), false
+	if worm.gas != nil {
+		worm.gas.mu.RLock()
+		limit, ok = worm.gas.limits[txType]
+		worm.gas.mu.RUnlock()
+	}
+	if !ok {
+		limit, ok = defaultGasLimits[txType]
+	}
+	if ok && limit != 0 {
+		return limit, nil
+	}
+	gas, err := worm.EstimateGas(ctx, ethereum.CallMsg{From: from, To: &to, Value: value, Data: data})
+	if err != nil {
+		return 0, err
+	}
+	// The state may change before the transaction is mined.
+	return gas + gas/5, nil
+}
@@ -0,0 +1,152 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/rpc"
+	"golang.org/x/xerrors"
+)
+
+// ListIterator decodes the entries of a list returned by the node one at a time, so a list of
+// thousands of validators or miners is not unmarshaled as a whole before the first entry is
+// used. The response is kept as raw JSON. Use it like sql.Rows:
+//
+//	for it.Next() {
+//		v := it.Value()
+//	}
+//	if err := it.Err(); err != nil { ... }
+type ListIterator[T any] struct {
+	dec     *json.Decoder
+	field   string
+	started bool
+	done    bool
+	value   *T
+	err     error
+}
+
+// newListIterator iterates the list in the field of the JSON object raw. The field name is
+// matched case-insensitively, like encoding/json does, the node writes it capitalized.
+func newListIterator[T any](raw json.RawMessage, field string) *ListIterator[T] {
+	return &ListIterator[T]{dec: json.NewDecoder(bytes.NewReader(raw)), field: field}
+}
+
+// Next decodes the next entry and reports whether there is one. It returns false at the end of
+// the list or on an error, see Err.
+func (it *ListIterator[T]) Next() bool {
+	if it.done {
+		return false
+	}
+	if !it.started {
+		it.started = true
+		if err := it.open(); err != nil {
+			return it.fail(err)
+		}
+		if it.done {
+			return false
+		}
+	}
+	if !it.dec.More() {
+		it.done, it.value = true, nil
+		return false
+	}
+	value := new(T)
+	if err := it.dec.Decode(value); err != nil {
+		return it.fail(err)
+	}
+	it.value = value
+	return true
+}
+
+// Value returns the entry decoded by the last call to Next.
+func (it *ListIterator[T]) Value() *T {
+	return it.value
+}
+
+// Err returns the error that stopped the iteration, nil at the end of the list.
+func (it *ListIterator[T]) Err() error {
+	return it.err
+}
+
+// open moves the decoder to the first entry of the list, a missing or null list is empty.
+func (it *ListIterator[T]) open() error {
+	if tok, err := it.dec.Token(); err != nil {
+		return err
+	} else if tok != json.Delim('{') {
+		return fmt.Errorf("got %v, want an object with %s", tok, it.field)
+	}
+	for it.dec.More() {
+		tok, err := it.dec.Token()
+		if err != nil {
+			return err
+		}
+		if key, _ := tok.(string); !strings.EqualFold(key, it.field) {
+			var skip json.RawMessage
+			if err := it.dec.Decode(&skip); err != nil {
+				return err
+			}
+			continue
+		}
+		switch tok, err := it.dec.Token(); {
+		case err != nil:
+			return err
+		case tok == nil:
+			it.done = true
+			return nil
+		case tok != json.Delim('['):
+			return fmt.Errorf("got %v, want the list %s", tok, it.field)
+		}
+		return nil
+	}
+	it.done = true
+	return nil
+}
+
+func (it *ListIterator[T]) fail(err error) bool {
+	it.done, it.value = true, nil
+	it.err = xerrors.Errorf("decode %s: %w", it.field, err)
+	return false
+}
+
+// IterateValidators is GetValidators returning an iterator over the validators instead of the
+// decoded list.
+func (worm *Wormholes) IterateValidators(ctx context.Context, blockNumber int64) (*ListIterator[types2.Validator], error) {
+	return worm.IterateValidatorsAt(ctx, blockTagWithNumber(rpc.BlockNumber(blockNumber)))
+}
+
+// IterateValidatorsAt is GetValidatorsAt returning an iterator over the validators.
+func (worm *Wormholes) IterateValidatorsAt(ctx context.Context, tag BlockTag) (*ListIterator[types2.Validator], error) {
+	blockNumber, ok := tag.BlockNumber()
+	if !ok {
+		return nil, errHashTagNotSupported
+	}
+	var raw json.RawMessage
+	if err := worm.call(ctx, &raw, "eth_getValidator", blockNumber); err != nil {
+		return nil, err
+	}
+	if isNull(raw) {
+		return nil, xerrors.Errorf("validators at block %s: %w", tag, ethereum.NotFound)
+	}
+	return newListIterator[types2.Validator](raw, "validators"), nil
+}
+
+// IterateActiveLivePool is GetActiveLivePool returning an iterator over the active miners.
+func (worm *Wormholes) IterateActiveLivePool(ctx context.Context, number uint64) (*ListIterator[types2.ActiveMiner], error) {
+	var raw json.RawMessage
+	if err := worm.call(ctx, &raw, "eth_getActiveLivePool", rpc.BlockNumber(number)); err != nil {
+		return nil, err
+	}
+	if isNull(raw) {
+		return nil, xerrors.Errorf("active miners at block %d: %w", number, ethereum.NotFound)
+	}
+	return newListIterator[types2.ActiveMiner](raw, "activeMiners"), nil
+}
+
+func isNull(raw json.RawMessage) bool {
+	return len(raw) == 0 || bytes.Equal(raw, []byte("null"))
+}
@@ -0,0 +1,91 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// MinerPoolEvent is a miner joining or leaving the active live pool between two samples.
+type MinerPoolEvent struct {
+	Block   uint64
+	Address common.Address
+	Joined  bool
+}
+
+// MinerAvailability is the availability of a single miner over the sampled range.
+type MinerAvailability struct {
+	Address common.Address
+	// Active is the number of samples in which the miner was in the active pool.
+	Active int
+	// Uptime is Active as a percentage of all samples.
+	Uptime float64
+}
+
+// ActivePoolReport is the result of AnalyzeActiveMiners.
+type ActivePoolReport struct {
+	From    uint64
+	To      uint64
+	Samples int
+	Miners  map[common.Address]*MinerAvailability
+	Events  []*MinerPoolEvent
+}
+
+// AnalyzeActiveMiners samples GetActiveLivePool every step blocks from..to (inclusive, the last
+// block is always sampled) and reports when miners joined or left the pool and their uptime.
+// A miner present in the first sample is not reported as joining.
+func (worm *Wormholes) AnalyzeActiveMiners(ctx context.Context, from, to, step uint64) (*ActivePoolReport, error) {
+	if from > to {
+		return nil, fmt.Errorf("invalid block range %d..%d", from, to)
+	}
+	if step == 0 {
+		step = 1
+	}
+	report := &ActivePoolReport{
+		From:   from,
+		To:     to,
+		Miners: make(map[common.Address]*MinerAvailability),
+	}
+	var previous map[common.Address]bool
+	for number := from; ; number += step {
+		if number > to || number < from {
+			number = to
+		}
+		pool, err := worm.IterateActiveLivePool(ctx, number)
+		if err != nil {
+			return nil, err
+		}
+		current := make(map[common.Address]bool, len(previous))
+		for pool.Next() {
+			miner := pool.Value()
+			current[miner.Address] = true
+			availability, ok := report.Miners[miner.Address]
+			if !ok {
+				availability = &MinerAvailability{Address: miner.Address}
+				report.Miners[miner.Address] = availability
+			}
+			availability.Active++
+			if previous != nil && !previous[miner.Address] {
+				report.Events = append(report.Events, &MinerPoolEvent{Block: number, Address: miner.Address, Joined: true})
+			}
+		}
+		if err := pool.Err(); err != nil {
+			return nil, err
+		}
+		for addr := range previous {
+			if !current[addr] {
+				report.Events = append(report.Events, &MinerPoolEvent{Block: number, Address: addr, Joined: false})
+			}
+		}
+		previous = current
+		report.Samples++
+		if number == to {
+			break
+		}
+	}
+	for _, availability := range report.Miners {
+		availability.Uptime = float64(availability.Active) * 100 / float64(report.Samples)
+	}
+	return report, nil
+}
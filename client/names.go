@@ -0,0 +1,161 @@
+package client
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/erbieio/erb-client/v2/tools"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/xerrors"
+)
+
+// resolveTimeout bounds the lookup of a name passed as an address parameter.
+const resolveTimeout = 10 * time.Second
+
+// isName tells if value may be a name rather than a hex address.
+func isName(value string) bool {
+	return value != "" && !strings.HasPrefix(value, "0x") && !strings.HasPrefix(value, "0X")
+}
+
+// Resolve returns the address of value: a hex address, an alias of the address book or a name
+// of the resolver set with SetNameResolver. The error wraps tools.ErrNameNotFound for an
+// unknown name.
+func (worm *Wormholes) Resolve(ctx context.Context, value string) (common.Address, error) {
+	if common.IsHexAddress(value) {
+		return common.HexToAddress(value), nil
+	}
+	worm.mu.RLock()
+	book, names := worm.book, worm.names
+	worm.mu.RUnlock()
+	if book != nil {
+		if address, ok := book.Lookup(value); ok {
+			return address, nil
+		}
+	}
+	if names == nil || !isName(value) {
+		return common.Address{}, xerrors.Errorf("resolve %q: %w", value, tools.ErrNameNotFound)
+	}
+	address, err := names.LookupName(ctx, value)
+	if err != nil {
+		return common.Address{}, xerrors.Errorf("resolve %q: %w", value, err)
+	}
+	return address, nil
+}
+
+// ReverseLookup returns the name of address from the resolver set with SetNameResolver, or
+// else its alias in the address book. The error wraps tools.ErrNameNotFound if it has none.
+func (worm *Wormholes) ReverseLookup(ctx context.Context, address common.Address) (string, error) {
+	worm.mu.RLock()
+	book, names := worm.book, worm.names
+	worm.mu.RUnlock()
+	if names != nil {
+		name, err := names.ReverseLookup(ctx, address)
+		if err == nil || !xerrors.Is(err, tools.ErrNameNotFound) {
+			return name, err
+		}
+	}
+	if book != nil {
+		return book.ReverseLookup(ctx, address)
+	}
+	return "", xerrors.Errorf("name of %s: %w", address.Hex(), tools.ErrNameNotFound)
+}
+
+const ensABI = `[
+	{"type":"function","name":"resolver","stateMutability":"view","inputs":[{"name":"node","type":"bytes32"}],"outputs":[{"type":"address"}]},
+	{"type":"function","name":"addr","stateMutability":"view","inputs":[{"name":"node","type":"bytes32"}],"outputs":[{"type":"address"}]},
+	{"type":"function","name":"name","stateMutability":"view","inputs":[{"name":"node","type":"bytes32"}],"outputs":[{"type":"string"}]}
+]`
+
+// ens is the parsed ABI of the methods of ENS registries and resolvers used by ENSResolver.
+var ens, _ = ParseABI(ensABI)
+
+// ENSResolver resolves the names of an ENS-style registry deployed on the chain: the registry
+// returns the resolver of a name with resolver(namehash), which returns its address with
+// addr(namehash). Reverse lookups ask name(namehash) of <address>.addr.reverse and are checked
+// against the forward resolution.
+type ENSResolver struct {
+	worm     *Wormholes
+	registry string
+}
+
+var _ tools.NameResolver = (*ENSResolver)(nil)
+
+// NewENSResolver creates a resolver of the registry at address registry queried through worm.
+func NewENSResolver(worm *Wormholes, registry string) *ENSResolver {
+	return &ENSResolver{worm: worm, registry: registry}
+}
+
+// LookupName returns the address of name.
+func (r *ENSResolver) LookupName(ctx context.Context, name string) (common.Address, error) {
+	node := NameHash(name)
+	resolver, err := r.resolver(ctx, node)
+	if err != nil {
+		return common.Address{}, xerrors.Errorf("resolve %s: %w", name, err)
+	}
+	address, err := r.address(ctx, resolver, "addr", node)
+	if err != nil {
+		return common.Address{}, xerrors.Errorf("resolve %s: %w", name, err)
+	}
+	if address == (common.Address{}) {
+		return common.Address{}, xerrors.Errorf("resolve %s: %w", name, tools.ErrNameNotFound)
+	}
+	return address, nil
+}
+
+// ReverseLookup returns the name of address, if it resolves back to address.
+func (r *ENSResolver) ReverseLookup(ctx context.Context, address common.Address) (string, error) {
+	node := NameHash(strings.ToLower(address.Hex()[2:]) + ".addr.reverse")
+	resolver, err := r.resolver(ctx, node)
+	if err != nil {
+		return "", xerrors.Errorf("name of %s: %w", address.Hex(), err)
+	}
+	out, err := r.worm.CallContractMethod(ctx, resolver.Hex(), ens, "name", node)
+	if err != nil {
+		return "", xerrors.Errorf("name of %s: %w", address.Hex(), err)
+	}
+	name := *abi.ConvertType(out[0], new(string)).(*string)
+	if name == "" {
+		return "", xerrors.Errorf("name of %s: %w", address.Hex(), tools.ErrNameNotFound)
+	}
+	// Anyone can claim any name in reverse records, only names resolving back count.
+	if forward, err := r.LookupName(ctx, name); err != nil || forward != address {
+		return "", xerrors.Errorf("name of %s: %s does not resolve back: %w", address.Hex(), name, tools.ErrNameNotFound)
+	}
+	return name, nil
+}
+
+// resolver returns the resolver of node from the registry.
+func (r *ENSResolver) resolver(ctx context.Context, node common.Hash) (common.Address, error) {
+	resolver, err := r.address(ctx, common.HexToAddress(r.registry), "resolver", node)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if resolver == (common.Address{}) {
+		return common.Address{}, tools.ErrNameNotFound
+	}
+	return resolver, nil
+}
+
+func (r *ENSResolver) address(ctx context.Context, contract common.Address, method string, node common.Hash) (common.Address, error) {
+	out, err := r.worm.CallContractMethod(ctx, contract.Hex(), ens, method, node)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return *abi.ConvertType(out[0], new(common.Address)).(*common.Address), nil
+}
+
+// NameHash returns the ENS namehash of name, whose labels are compared in lower case.
+func NameHash(name string) common.Hash {
+	var node common.Hash
+	if name == "" {
+		return node
+	}
+	labels := strings.Split(strings.ToLower(name), ".")
+	for i := len(labels) - 1; i >= 0; i-- {
+		node = crypto.Keccak256Hash(node.Bytes(), crypto.Keccak256([]byte(labels[i])))
+	}
+	return node
+}
@@ -0,0 +1,139 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	defaultChainIDTTL  = time.Minute
+	defaultGasPriceTTL = 3 * time.Second
+	// defaultPollInterval is the interval of WaitMined between two requests to the node.
+	defaultPollInterval = time.Second
+)
+
+// Keys of the cached chain metadata.
+const (
+	metaChainID   = "eth_chainId"
+	metaNetworkID = "net_version"
+	metaGasPrice  = "eth_gasPrice"
+)
+
+// network holds the chain ID a client expects and the cached chain metadata, shared by the
+// clients of a connection.
+type network struct {
+	mu      sync.RWMutex
+	chainID *big.Int
+
+	metaMu      sync.Mutex
+	meta        map[string]cachedBig
+	chainIDTTL  time.Duration
+	gasPriceTTL time.Duration
+
+	// pollInterval is the interval of WaitMined, in nanoseconds.
+	pollInterval atomic.Int64
+
+	// noBlockReceipts is set once the node answered that it has no eth_getBlockReceipts.
+	noBlockReceipts atomic.Bool
+}
+
+type cachedBig struct {
+	value   *big.Int
+	expires time.Time
+}
+
+func newNetwork() *network {
+	n := &network{
+		meta:        make(map[string]cachedBig),
+		chainIDTTL:  defaultChainIDTTL,
+		gasPriceTTL: defaultGasPriceTTL,
+	}
+	n.pollInterval.Store(int64(defaultPollInterval))
+	return n
+}
+
+// SetMetadataTTL sets how long worm and the clients sharing its connection reuse the chain ID
+// and network ID, 1 minute by default, and the suggested gas price, 3s by default, instead of
+// asking the node for every transaction. A zero TTL asks the node on every call.
+func (worm *Wormholes) SetMetadataTTL(chainID, gasPrice time.Duration) {
+	worm.network.metaMu.Lock()
+	defer worm.network.metaMu.Unlock()
+	worm.network.chainIDTTL, worm.network.gasPriceTTL = chainID, gasPrice
+	worm.network.meta = make(map[string]cachedBig)
+}
+
+// cachedMetadata returns the cached value of key, fetching it once it expired. Failures are
+// not cached. The value is copied, callers may modify it.
+func (worm *Wormholes) cachedMetadata(ctx context.Context, key string, fetch func(ctx context.Context) (*big.Int, error)) (*big.Int, error) {
+	n := worm.network
+	n.metaMu.Lock()
+	ttl := n.chainIDTTL
+	if key == metaGasPrice {
+		ttl = n.gasPriceTTL
+	}
+	entry, ok := n.meta[key]
+	n.metaMu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return new(big.Int).Set(entry.value), nil
+	}
+	value, err := fetch(ctx)
+	if err != nil || ttl <= 0 {
+		return value, err
+	}
+	n.metaMu.Lock()
+	n.meta[key] = cachedBig{value: new(big.Int).Set(value), expires: time.Now().Add(ttl)}
+	n.metaMu.Unlock()
+	return value, nil
+}
+
+// ExpectChainID makes worm and the clients sharing its connection refuse to send transactions
+// with ErrWrongNetwork unless the node is on the chain chainID, e.g. so that a mainnet key is not
+// used on a test node by mistake. A nil chainID sends to any chain.
+func (worm *Wormholes) ExpectChainID(chainID *big.Int) {
+	if chainID != nil {
+		chainID = new(big.Int).Set(chainID)
+	}
+	worm.network.mu.Lock()
+	defer worm.network.mu.Unlock()
+	worm.network.chainID = chainID
+}
+
+// ExpectedChainID returns the chain ID set with ExpectChainID, nil if none.
+func (worm *Wormholes) ExpectedChainID() *big.Int {
+	if worm.network == nil {
+		return nil
+	}
+	worm.network.mu.RLock()
+	defer worm.network.mu.RUnlock()
+	if worm.network.chainID == nil {
+		return nil
+	}
+	return new(big.Int).Set(worm.network.chainID)
+}
+
+// checkNetwork returns ErrWrongNetwork if a chain is expected and the node, or the chain tx was
+// signed for, is another one. The chain of the node is read again once its cached value expires,
+// a node behind a load balancer may change.
+func (worm *Wormholes) checkNetwork(ctx context.Context, tx *types.Transaction) error {
+	expected := worm.ExpectedChainID()
+	if expected == nil {
+		return nil
+	}
+	chainID, err := worm.ChainID(ctx)
+	if err != nil {
+		return err
+	}
+	if chainID.Cmp(expected) != 0 {
+		return fmt.Errorf("%w: node is on chain %s, client expects %s", ErrWrongNetwork, chainID, expected)
+	}
+	if tx.Protected() && tx.ChainId().Cmp(expected) != 0 {
+		return fmt.Errorf("%w: transaction signed for chain %s, client expects %s", ErrWrongNetwork, tx.ChainId(), expected)
+	}
+	return nil
+}
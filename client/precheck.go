@@ -0,0 +1,196 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/erbieio/erb-client/v2/tools"
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum/common"
+	"golang.org/x/xerrors"
+)
+
+// The pre-checks test the state of the latest block before a transaction is sent, so a
+// settlement that would fail on chain is refused without paying for the failed transaction.
+// They return the errors of the package wrapped, other errors are failures to read the state.
+
+// CheckBalance returns ErrInsufficientBalance if account holds less than amount in the pending state.
+func (worm *Wormholes) CheckBalance(ctx context.Context, account string, amount types2.Amount) error {
+	balance, err := worm.Balance(ctx, account)
+	if err != nil {
+		return err
+	}
+	if balance.Cmp(amount.Wei()) < 0 {
+		return fmt.Errorf("%w: %s holds %s ERB, needs %s ERB", ErrInsufficientBalance, worm.resolve(ctx, account), types2.Wei(balance), amount)
+	}
+	return nil
+}
+
+// CheckNFTOwner returns ErrNotNFTOwner unless account owns the NFT, or was authorized by its
+// owner for the NFT or for all of the NFTs of the owner.
+func (worm *Wormholes) CheckNFTOwner(ctx context.Context, nftAddress, account string) error {
+	nft, err := worm.GetAccountInfoAt(ctx, nftAddress, Latest)
+	if err != nil {
+		return err
+	}
+	owner := nft.Nft.Owner
+	if owner == (common.Address{}) {
+		return fmt.Errorf("%w: nft %s has no owner", ErrNotNFTOwner, nftAddress)
+	}
+	operator := common.HexToAddress(worm.resolve(ctx, account))
+	if operator == owner || operator == nft.Nft.NFTApproveAddressList {
+		return nil
+	}
+	info, err := worm.GetAccountInfoAt(ctx, owner.Hex(), Latest)
+	if err != nil {
+		return err
+	}
+	if info.Worm != nil {
+		for _, approved := range info.Worm.ApproveAddressList {
+			if approved == operator {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("%w: nft %s is owned by %s", ErrNotNFTOwner, nftAddress, owner.Hex())
+}
+
+// CheckExchanger returns ErrExchangerNotOpen unless exchanger has opened an exchange.
+func (worm *Wormholes) CheckExchanger(ctx context.Context, exchanger string) error {
+	exchanger, err := checkExchanger("CheckExchanger() exchanger", worm.resolve(ctx, exchanger))
+	if err != nil {
+		return err
+	}
+	info, err := worm.GetAccountInfoAt(ctx, exchanger, Latest)
+	if err != nil {
+		return err
+	}
+	if info.Worm == nil || !info.Worm.ExchangerFlag {
+		return fmt.Errorf("%w: %s", ErrExchangerNotOpen, exchanger)
+	}
+	return nil
+}
+
+// CheckOrderExpiry returns ErrOrderExpired if the next block reaches blockNumber, the hex block
+// height a signed order is valid before.
+func (worm *Wormholes) CheckOrderExpiry(ctx context.Context, blockNumber string) error {
+	expiry, err := tools.DecodeBlockNumber(blockNumber)
+	if err != nil {
+		return err
+	}
+	head, err := worm.BlockNumber(ctx)
+	if err != nil {
+		return err
+	}
+	if head+1 >= expiry {
+		return fmt.Errorf("%w: valid before block %d, head is %d", ErrOrderExpired, expiry, head)
+	}
+	return nil
+}
+
+// CheckBuyer checks a buy order signed with SignBuyer before it is settled: the order has not
+// expired, its exchanger is open, the buyer can pay the amount and, for a minted NFT, the seller
+// of the order, or the account of the client if the order names none, can sell the NFT.
+func (worm *Wormholes) CheckBuyer(ctx context.Context, buyer []byte) error {
+	var order types2.Buyer
+	if err := json.Unmarshal(buyer, &order); err != nil {
+		return xerrors.New("the formate of buyer is wrong")
+	}
+	if err := worm.checkOrder(ctx, order.BlockNumber, order.Exchanger); err != nil {
+		return err
+	}
+	amount, err := types2.ParseAmount(order.Amount)
+	if err != nil {
+		return err
+	}
+	signer, err := tools.RecoverAddress(order.Message(), order.Sig)
+	if err != nil {
+		return err
+	}
+	if err := worm.CheckBalance(ctx, signer.Hex(), amount); err != nil {
+		return err
+	}
+	if order.NFTAddress == "" {
+		return nil
+	}
+	seller := order.Seller
+	if seller == "" {
+		account, _, err := worm.signingKey()
+		if err != nil {
+			return err
+		}
+		seller = account.Hex()
+	}
+	return worm.CheckNFTOwner(ctx, order.NFTAddress, seller)
+}
+
+// CheckSeller1 checks a sell order of a minted NFT signed with SignSeller1 before it is settled:
+// the order has not expired, its exchanger is open and its signer can sell the NFT.
+func (worm *Wormholes) CheckSeller1(ctx context.Context, seller1 []byte) error {
+	var order types2.Seller1
+	if err := json.Unmarshal(seller1, &order); err != nil {
+		return xerrors.New("the formate of seller1 is wrong")
+	}
+	if err := worm.checkOrder(ctx, order.BlockNumber, order.Exchanger); err != nil {
+		return err
+	}
+	signer, err := tools.RecoverAddress(order.Message(), order.Sig)
+	if err != nil {
+		return err
+	}
+	return worm.CheckNFTOwner(ctx, order.NFTAddress, signer.Hex())
+}
+
+// CheckSeller2 checks a sell order of an unminted NFT signed with SignSeller2 before it is
+// settled: the order has not expired and its exchanger is open.
+func (worm *Wormholes) CheckSeller2(ctx context.Context, seller2 []byte) error {
+	var order types2.Seller2
+	if err := json.Unmarshal(seller2, &order); err != nil {
+		return xerrors.New("the formate of seller2 is wrong")
+	}
+	return worm.checkOrder(ctx, order.BlockNumber, order.Exchanger)
+}
+
+// CheckExchangerAuth checks an authorization signed with SignExchanger before the client settles
+// orders of exchanger with it. It returns ErrUnauthorizedExchange unless the authorization was
+// signed by exchanger for the account of the client, ErrOrderExpired if it expired and
+// ErrExchangerNotOpen if exchanger is not open.
+func (worm *Wormholes) CheckExchangerAuth(ctx context.Context, exchangerAuth []byte, exchanger string) error {
+	var auth types2.ExchangerAuth
+	if err := json.Unmarshal(exchangerAuth, &auth); err != nil {
+		return xerrors.New("the formate of exchangerAuth is wrong")
+	}
+	exchanger, err := checkExchanger("CheckExchangerAuth() exchanger", worm.resolve(ctx, exchanger))
+	if err != nil {
+		return err
+	}
+	account, _, err := worm.signingKey()
+	if err != nil {
+		return err
+	}
+	signer, err := tools.RecoverAddress(auth.Message(), auth.Sig)
+	if err != nil {
+		return err
+	}
+	switch {
+	case !tools.SameAddress(auth.ExchangerOwner, exchanger):
+		return fmt.Errorf("%w: authorization of %s, orders of %s", ErrUnauthorizedExchange, auth.ExchangerOwner, exchanger)
+	case signer != common.HexToAddress(auth.ExchangerOwner):
+		return fmt.Errorf("%w: authorization of %s signed by %s", ErrUnauthorizedExchange, auth.ExchangerOwner, signer.Hex())
+	case common.HexToAddress(auth.To) != account:
+		return fmt.Errorf("%w: authorization for %s, client is %s", ErrUnauthorizedExchange, auth.To, account.Hex())
+	}
+	return worm.checkOrder(ctx, auth.BlockNumber, exchanger)
+}
+
+// checkOrder checks the expiry and the exchanger of an order.
+func (worm *Wormholes) checkOrder(ctx context.Context, blockNumber, exchanger string) error {
+	if err := worm.CheckOrderExpiry(ctx, blockNumber); err != nil {
+		return err
+	}
+	if exchanger == "" {
+		return nil
+	}
+	return worm.CheckExchanger(ctx, exchanger)
+}
@@ -0,0 +1,167 @@
+package client
+
+import (
+	"context"
+	"math/big"
+	"sort"
+	"sync"
+
+	"golang.org/x/xerrors"
+)
+
+// NetworkConfig configures a network of a Registry.
+type NetworkConfig struct {
+	// URL is the node of the network.
+	URL string
+	// PriKey is the key signing on the network, empty for a client that does not sign.
+	PriKey string
+	// ChainID is the chain the node must be on to send transactions, see ExpectChainID. Set it
+	// so that the key of a network is never used on another one; nil sends to any chain.
+	ChainID *big.Int
+	// Transport tunes the HTTP connections to the node, see DialWithTransport. nil dials with
+	// the defaults of Dial.
+	Transport *TransportConfig
+}
+
+// Registry holds the clients of several named networks, e.g. "mainnet", "testnet" and private
+// devnets, for a service operating on all of them. It is safe for concurrent use.
+type Registry struct {
+	mu      sync.RWMutex
+	clients map[string]*Wormholes
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{clients: make(map[string]*Wormholes)}
+}
+
+// Dial connects to the network configured by cfg and registers its client as name.
+func (r *Registry) Dial(name string, cfg NetworkConfig) (*Wormholes, error) {
+	var worm *Wormholes
+	var err error
+	if cfg.Transport != nil {
+		worm, err = DialWithTransport(cfg.PriKey, cfg.URL, *cfg.Transport)
+	} else {
+		worm, err = Dial(cfg.PriKey, cfg.URL)
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("network %s: %w", name, err)
+	}
+	worm.ExpectChainID(cfg.ChainID)
+	if err := r.Add(name, worm); err != nil {
+		worm.CloseConnect()
+		return nil, err
+	}
+	return worm, nil
+}
+
+// Add registers worm as the client of the network name, which must not be registered yet.
+func (r *Registry) Add(name string, worm *Wormholes) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.clients[name]; ok {
+		return xerrors.Errorf("network %s is already registered", name)
+	}
+	r.clients[name] = worm
+	return nil
+}
+
+// Get returns the client of the network name, ErrUnknownNetwork if it is not registered.
+func (r *Registry) Get(name string) (*Wormholes, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	worm, ok := r.clients[name]
+	if !ok {
+		return nil, xerrors.Errorf("%w: %s", ErrUnknownNetwork, name)
+	}
+	return worm, nil
+}
+
+// Names returns the registered networks in sorted order.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.clients))
+	for name := range r.clients {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Remove unregisters the network name and closes the connection of its client.
+func (r *Registry) Remove(name string) {
+	r.mu.Lock()
+	worm, ok := r.clients[name]
+	delete(r.clients, name)
+	r.mu.Unlock()
+	if ok {
+		worm.CloseConnect()
+	}
+}
+
+// Close closes the connections of all the clients and empties the registry.
+func (r *Registry) Close() {
+	r.mu.Lock()
+	clients := r.clients
+	r.clients = make(map[string]*Wormholes)
+	r.mu.Unlock()
+	for _, worm := range clients {
+		worm.CloseConnect()
+	}
+}
+
+// Each calls fn with the client of every network in parallel, or of the networks names if
+// given, and returns the errors by network, nil if all calls succeeded. An unregistered name
+// fails with ErrUnknownNetwork, the other networks are still called.
+func (r *Registry) Each(ctx context.Context, fn func(ctx context.Context, name string, worm *Wormholes) error, names ...string) map[string]error {
+	_, errs := EachNetwork(ctx, r, func(ctx context.Context, name string, worm *Wormholes) (struct{}, error) {
+		return struct{}{}, fn(ctx, name, worm)
+	}, names...)
+	return errs
+}
+
+// EachNetwork is Registry.Each for an operation returning a value, it returns the values of the
+// calls that succeeded by network, e.g. the balance of an account on every network:
+//
+//	balances, errs := client.EachNetwork(ctx, registry, func(ctx context.Context, name string, worm *client.Wormholes) (*big.Int, error) {
+//		return worm.Balance(ctx, account)
+//	})
+func EachNetwork[T any](ctx context.Context, r *Registry, fn func(ctx context.Context, name string, worm *Wormholes) (T, error), names ...string) (map[string]T, map[string]error) {
+	if len(names) == 0 {
+		names = r.Names()
+	}
+	values := make(map[string]T, len(names))
+	var errs map[string]error
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, name := range names {
+		worm, err := r.Get(name)
+		if err != nil {
+			mu.Lock()
+			if errs == nil {
+				errs = make(map[string]error)
+			}
+			errs[name] = err
+			mu.Unlock()
+			continue
+		}
+		wg.Add(1)
+		go func(name string, worm *Wormholes) {
+			defer wg.Done()
+			value, err := fn(ctx, name, worm)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if errs == nil {
+					errs = make(map[string]error)
+				}
+				errs[name] = xerrors.Errorf("network %s: %w", name, err)
+				return
+			}
+			values[name] = value
+		}(name, worm)
+	}
+	wg.Wait()
+	return values, errs
+}
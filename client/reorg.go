@@ -0,0 +1,111 @@
+package client
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ReorgEvent describes a change of the canonical chain detected by a CanonicalChecker.
+// The remembered blocks From..To (inclusive) are no longer canonical.
+type ReorgEvent struct {
+	From    uint64
+	To      uint64
+	OldHead common.Hash
+	NewHead common.Hash
+	// Deep is set when the common ancestor is older than the remembered blocks,
+	// so the reorg may start before From.
+	Deep bool
+}
+
+// CanonicalChecker remembers the (number, hash) pairs of the most recent blocks
+// and reports when the chain reorganizes underneath them.
+type CanonicalChecker struct {
+	worm   *Wormholes
+	depth  uint64
+	mu     sync.Mutex
+	hashes map[uint64]common.Hash
+	head   uint64
+}
+
+// NewCanonicalChecker creates a checker that remembers up to depth recent blocks.
+func NewCanonicalChecker(worm *Wormholes, depth uint64) *CanonicalChecker {
+	if depth == 0 {
+		depth = 1
+	}
+	return &CanonicalChecker{
+		worm:   worm,
+		depth:  depth,
+		hashes: make(map[uint64]common.Hash),
+	}
+}
+
+// CheckHead fetches the latest block and compares its ancestry with the remembered blocks.
+// It returns nil when the remembered blocks are still canonical, otherwise the diverged range.
+func (c *CanonicalChecker) CheckHead(ctx context.Context) (*ReorgEvent, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	head, err := c.worm.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(c.hashes) == 0 {
+		c.head = head.Number
+		c.remember(map[uint64]common.Hash{head.Number: head.Hash})
+		return nil, nil
+	}
+
+	// Walk back along the new chain until a remembered block matches.
+	oldest := c.head + 1 - uint64(len(c.hashes))
+	seen := make(map[uint64]common.Hash)
+	cur := head
+	ancestor, found := uint64(0), false
+	for {
+		number := cur.Number
+		seen[number] = cur.Hash
+		if old, ok := c.hashes[number]; ok && old == cur.Hash {
+			ancestor, found = number, true
+			break
+		}
+		if number == 0 || number <= oldest {
+			break
+		}
+		if cur, err = c.worm.HeaderByHash(ctx, cur.ParentHash); err != nil {
+			return nil, err
+		}
+	}
+
+	var event *ReorgEvent
+	if !found || ancestor < c.head {
+		event = &ReorgEvent{
+			From:    ancestor + 1,
+			To:      c.head,
+			OldHead: c.hashes[c.head],
+			NewHead: head.Hash,
+			Deep:    !found,
+		}
+		if !found {
+			event.From = oldest
+		}
+		for number := event.From; number <= c.head; number++ {
+			delete(c.hashes, number)
+		}
+	}
+	c.head = head.Number
+	c.remember(seen)
+	return event, nil
+}
+
+// remember stores the given hashes and forgets the blocks older than the checker depth.
+func (c *CanonicalChecker) remember(hashes map[uint64]common.Hash) {
+	for number, hash := range hashes {
+		c.hashes[number] = hash
+	}
+	for number := range c.hashes {
+		if number+c.depth <= c.head || number > c.head {
+			delete(c.hashes, number)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package client
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// senderLocks serializes the transactions of every account sent through a client and the
+// clients sharing its connection. A transaction holds the lock of its sender from reading the
+// pending nonce until the node accepted it, so the next one reads the following nonce.
+type senderLocks struct {
+	mu    sync.Mutex
+	locks map[common.Address]chan struct{}
+}
+
+func newSenderLocks() *senderLocks {
+	return &senderLocks{locks: make(map[common.Address]chan struct{})}
+}
+
+// lockSender waits until no other transaction of account is being sent and returns the
+// function releasing the lock.
+func (worm *Wormholes) lockSender(ctx context.Context, account common.Address) (func(), error) {
+	if worm.senders == nil {
+		return func() {}, nil
+	}
+	worm.senders.mu.Lock()
+	lock, ok := worm.senders.locks[account]
+	if !ok {
+		lock = make(chan struct{}, 1)
+		worm.senders.locks[account] = lock
+	}
+	worm.senders.mu.Unlock()
+	select {
+	case lock <- struct{}{}:
+		return func() { <-lock }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// lockKeySender is lockSender for the account of the key of the client. An invalid key is
+// reported when the transaction is signed, it takes no lock.
+func (worm *Wormholes) lockKeySender(ctx context.Context) (func(), error) {
+	account, _, err := worm.signingKey()
+	if err != nil {
+		return func() {}, nil
+	}
+	return worm.lockSender(ctx, account)
+}
@@ -0,0 +1,67 @@
+package client
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/xerrors"
+)
+
+// Signer signs with a key held outside the process, e.g. by the HSM signer of the pkcs11
+// package, for exchanger and validator keys that must not be exported.
+type Signer interface {
+	// Address returns the account of the key.
+	Address() common.Address
+	// SignHash signs a 32 byte hash and returns the signature as [R || S || V] with the
+	// recovery id V of 0 or 1 and a low S, as crypto.Sign does.
+	SignHash(hash []byte) ([]byte, error)
+}
+
+// NewWalletWithSigner creates a wallet that signs with s without connecting to a node.
+func NewWalletWithSigner(s Signer) *Wallet {
+	return &Wallet{signer: &walletKey{account: s.Address(), external: s}}
+}
+
+// SetSigner makes the wallet sign its transactions, orders and authorizations with s instead of
+// its private key. Clients created with WithPriKey sign with their own key.
+func (w *Wallet) SetSigner(s Signer) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.signer = &walletKey{account: s.Address(), external: s}
+}
+
+// signHash signs hash with the key or the Signer of the wallet, with a V of 0 or 1.
+func (w *Wallet) signHash(hash []byte) ([]byte, error) {
+	signer := w.walletKey()
+	if signer.err != nil {
+		return nil, signer.err
+	}
+	if signer.external == nil {
+		return crypto.Sign(hash, signer.key)
+	}
+	signature, err := signer.external.SignHash(hash)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to sign with the signer of %s: %w", signer.account.Hex(), err)
+	}
+	if len(signature) != crypto.SignatureLength || signature[64] > 1 {
+		return nil, xerrors.Errorf("signer of %s returned an invalid signature %x", signer.account.Hex(), signature)
+	}
+	return signature, nil
+}
+
+// signTx signs tx for the chain chainID with key, or with the Signer of the wallet when key is
+// nil.
+func (w *Wallet) signTx(tx *types.Transaction, chainID *big.Int, key *ecdsa.PrivateKey) (*types.Transaction, error) {
+	signer := types.NewEIP155Signer(chainID)
+	if key != nil {
+		return types.SignTx(tx, signer, key)
+	}
+	signature, err := w.signHash(signer.Hash(tx).Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return tx.WithSignature(signer, signature)
+}
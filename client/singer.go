@@ -19,6 +19,12 @@ func setSenderFromServer(tx *types.Transaction, addr common.Address, block commo
 	types.Sender(&senderFromServer{addr, block}, tx)
 }
 
+// TransactionSender returns the sender of a transaction of a block fetched through this client,
+// as reported by the node. No chain ID or signature recovery is needed.
+func TransactionSender(block *types.Block, tx *types.Transaction) (common.Address, error) {
+	return types.Sender(&senderFromServer{blockhash: block.Hash()}, tx)
+}
+
 func (s *senderFromServer) Equal(other types.Signer) bool {
 	os, ok := other.(*senderFromServer)
 	return ok && os.blockhash == s.blockhash
@@ -31,12 +37,16 @@ func (s *senderFromServer) Sender(tx *types.Transaction) (common.Address, error)
 	return s.addr, nil
 }
 
+var errCannotSign = errors.New("can't sign with senderFromServer")
+
+// The sender of the node can not sign, its signing methods return no chain ID, no hash and an error.
+
 func (s *senderFromServer) ChainID() *big.Int {
-	panic("can't sign with senderFromServer")
+	return nil
 }
 func (s *senderFromServer) Hash(tx *types.Transaction) common.Hash {
-	panic("can't sign with senderFromServer")
+	return common.Hash{}
 }
 func (s *senderFromServer) SignatureValues(tx *types.Transaction, sig []byte) (R, S, V *big.Int, err error) {
-	panic("can't sign with senderFromServer")
+	return nil, nil, nil, errCannotSign
 }
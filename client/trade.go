@@ -0,0 +1,119 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/erbieio/erb-client/v2/tools"
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"golang.org/x/xerrors"
+)
+
+var (
+	ErrNotTrade    = errors.New("transaction is not an NFT trade")
+	ErrTradeFailed = errors.New("trade transaction failed")
+)
+
+// DecodeTrade decodes a mined settlement transaction into a normalized Trade.
+//
+// Buyer and seller are recovered from the signed orders in the payload. Without a buy order
+// the buyer is the sender, or the recipient of the trades sent by the seller or an exchanger
+// (see types.SenderIsBuyer). The price is the value of the transaction. For minted NFTs the
+// seller, creator and royalty are taken from the NFT state of the block before the trade, the
+// exchanger fee rate from the exchanger account at the same block. ErrNotTrade is returned for
+// transactions that do not settle a sale and ErrTradeFailed if the receipt status is failed.
+func (worm *Wormholes) DecodeTrade(ctx context.Context, tx *types.Transaction, receipt *types.Receipt) (*types2.Trade, error) {
+	payload, ok := ParseWormholesData(tx.Data())
+	if !ok || !types2.IsTrade(payload.Type) {
+		return nil, ErrNotTrade
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return nil, ErrTradeFailed
+	}
+	sender, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return nil, err
+	}
+	number := receipt.BlockNumber.Uint64()
+	var parent int64
+	if number > 0 {
+		parent = int64(number - 1)
+	}
+
+	trade := &types2.Trade{
+		TxHash:      tx.Hash(),
+		BlockNumber: number,
+		Type:        payload.Type,
+		Buyer:       sender,
+		Price:       tx.Value(),
+		RoyaltyPaid: new(big.Int),
+		FeePaid:     new(big.Int),
+	}
+	if trade.Price == nil {
+		trade.Price = new(big.Int)
+	}
+	if !types2.SenderIsBuyer(payload.Type) && tx.To() != nil {
+		trade.Buyer = *tx.To()
+	}
+	var exchanger string
+	switch {
+	case payload.Buyer != nil:
+		trade.NFTAddress = payload.Buyer.NFTAddress
+		exchanger = payload.Buyer.Exchanger
+		if trade.Buyer, err = tools.RecoverAddress(payload.Buyer.Message(), payload.Buyer.Sig); err != nil {
+			return nil, xerrors.Errorf("recover buyer: %v", err)
+		}
+	case payload.Seller1 != nil:
+		trade.NFTAddress = payload.Seller1.NFTAddress
+		exchanger = payload.Seller1.Exchanger
+	case payload.Seller2 != nil:
+		exchanger = payload.Seller2.Exchanger
+	}
+
+	trade.NFTAddress = tools.ChecksumAddress(trade.NFTAddress)
+
+	var royalty uint64
+	if payload.Seller2 != nil {
+		// The NFT is minted by the trade, the seller is its creator.
+		trade.MetaURL = payload.Seller2.MetaURL
+		if trade.Seller, err = tools.RecoverAddress(payload.Seller2.Message(), payload.Seller2.Sig); err != nil {
+			return nil, xerrors.Errorf("recover seller: %v", err)
+		}
+		trade.Creator = trade.Seller
+		if royalty, err = hexutil.DecodeUint64(payload.Seller2.Royalty); err != nil {
+			return nil, xerrors.Errorf("invalid royalty %q: %v", payload.Seller2.Royalty, err)
+		}
+	} else if trade.NFTAddress != "" {
+		nft, err := worm.GetAccountInfo(ctx, trade.NFTAddress, parent)
+		if err != nil {
+			return nil, err
+		}
+		trade.Seller = nft.Nft.Owner
+		trade.Creator = nft.Nft.Creator
+		trade.MetaURL = nft.Nft.MetaURL
+		royalty = uint64(nft.Nft.Royalty)
+	}
+	trade.RoyaltyPaid = shareOf(trade.Price, royalty)
+
+	if exchanger != "" {
+		trade.Exchanger = common.HexToAddress(exchanger)
+		account, err := worm.GetAccountInfo(ctx, exchanger, parent)
+		if err != nil {
+			return nil, err
+		}
+		if account.Worm != nil {
+			trade.FeePaid = shareOf(trade.Price, uint64(account.Worm.FeeRate))
+		}
+	}
+	return trade, nil
+}
+
+// shareOf returns amount * rate / types2.RateDenominator.
+func shareOf(amount *big.Int, rate uint64) *big.Int {
+	share := new(big.Int).Mul(amount, new(big.Int).SetUint64(rate))
+	return share.Div(share, big.NewInt(types2.RateDenominator))
+}
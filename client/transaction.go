@@ -4,10 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"github.com/erbieio/erb-client/tools"
-	types2 "github.com/erbieio/erb-client/types"
+	"github.com/erbieio/erb-client/v2/tools"
+	types2 "github.com/erbieio/erb-client/v2/types"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"golang.org/x/xerrors"
 	"log"
@@ -21,36 +20,55 @@ const TranPrefix = "erbie:"
 //
 //		Parameter Description
 //	 to 			Account address
-//	 value		transaction amount
+//	 value		transaction amount in ERB
 //	 data
 func (worm *Wormholes) NormalTransaction(to string, value int64, data string) (string, error) {
-	ctx := context.Background()
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
+	return worm.NormalTransactionCtx(context.Background(), to, types2.ERB(value), data)
+}
+
+// NormalTransactionCtx is NormalTransaction with a context for the requests to the node and
+// the amount as a types.Amount.
+func (worm *Wormholes) NormalTransactionCtx(ctx context.Context, to string, value types2.Amount, data string) (string, error) {
+	if err := checkAmount("NormalTransaction() value", value); err != nil {
+		return "", err
+	}
+	to = worm.resolve(ctx, to)
+	if err := tools.CheckAddress("NormalTransaction() to", to); err != nil {
+		return "", err
+	}
+	account, fromKey, err := worm.signingKey()
 	if err != nil {
 		log.Println("NormalTransaction() priKeyToAddress err ", err)
 		return "", err
 	}
 
 	toAddr := common.HexToAddress(to)
+	unlock, err := worm.lockSender(ctx, account)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
 	nonce, err := worm.PendingNonceAt(ctx, account)
+	if err != nil {
+		log.Println("NormalTransaction() pendingNonceAt err ", err)
+		return "", err
+	}
 
-	gasLimit := uint64(51000)
+	gasLimit := uint64(payGasLimit)
 	gasPrice, err := worm.SuggestGasPrice(ctx)
 	if err != nil {
 		log.Println("NormalTransaction() suggestGasPrice err ", err)
 		return "", err
 	}
 
-	wei, _ := new(big.Int).SetString("1000000000000000000", 10)
-	charge := new(big.Int).Mul(big.NewInt(value), wei)
-	tx := types.NewTransaction(nonce, toAddr, charge, gasLimit, gasPrice, []byte(data))
+	tx := types.NewTransaction(nonce, toAddr, value.Wei(), gasLimit, gasPrice, []byte(data))
 	chainID, err := worm.NetworkID(ctx)
 	if err != nil {
 		log.Println("NormalTransaction() networkID err=", err)
 		return "", err
 	}
 	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
+	signedTx, err := worm.signTx(tx, chainID, fromKey)
 	if err != nil {
 		log.Println("NormalTransaction() signTx err ", err)
 		return "", err
@@ -68,26 +86,42 @@ func (worm *Wormholes) NormalTransaction(to string, value int64, data string) (s
 //	Users can use this transaction to create an NFT on the wormholes chain
 //
 //	Parameter Description
-//	royalty: 10,																					Royalty, formatted as an integer
+//	royalty: 10,																					Royalty in basis points, at most 10000
 //	metaURL: "/ipfs/ddfd90be9408b4",	NFT metadata address
 //	exchanger:"0xe61e5Bbe724B8F449B5C7BB4a09F99A057253eB4",							The exchange when the NFT is minted, the format is a string. When this field is filled, the exchange will exclusively own the NFT. If it is not filled in, no exchange will exclusively own the NFT
 func (worm *Wormholes) Mint(royalty uint32, metaURL string, exchanger string) (string, error) {
-	if exchanger != "" {
-		err := tools.CheckAddress("Mint() exchanger", exchanger)
-		if err != nil {
-			return "", err
-		}
+	return worm.MintCtx(context.Background(), royalty, metaURL, exchanger)
+}
+
+// MintCtx is Mint with a context for the requests to the node.
+func (worm *Wormholes) MintCtx(ctx context.Context, royalty uint32, metaURL string, exchanger string) (string, error) {
+	if err := tools.CheckRoyalty("Mint() royalty", uint64(royalty)); err != nil {
+		return "", err
+	}
+	if err := tools.CheckMetaURL("Mint() metaURL", metaURL); err != nil {
+		return "", err
+	}
+	exchanger, err := checkOptionalExchanger("Mint() exchanger", worm.resolve(ctx, exchanger))
+	if err != nil {
+		return "", err
 	}
 
-	ctx := context.Background()
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
+	account, fromKey, err := worm.signingKey()
 	if err != nil {
 		return "", err
 	}
 
+	unlock, err := worm.lockSender(ctx, account)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
 	nonce, err := worm.PendingNonceAt(ctx, account)
+	if err != nil {
+		log.Println("Mint() pendingNonceAt err ", err)
+		return "", err
+	}
 
-	gasLimit := uint64(60000)
 	gasPrice, err := worm.SuggestGasPrice(ctx)
 	if err != nil {
 		log.Println("Mint() suggestGasPrice err ", err)
@@ -110,6 +144,10 @@ func (worm *Wormholes) Mint(royalty uint32, metaURL string, exchanger string) (s
 
 	tx_data := append([]byte(TranPrefix), data...)
 
+	gasLimit, err := worm.gasLimit(ctx, types2.Mint, account, account, nil, tx_data)
+	if err != nil {
+		return "", err
+	}
 	tx := types.NewTransaction(nonce, account, big.NewInt(0), gasLimit, gasPrice, tx_data)
 	chainID, err := worm.NetworkID(ctx)
 	if err != nil {
@@ -117,7 +155,7 @@ func (worm *Wormholes) Mint(royalty uint32, metaURL string, exchanger string) (s
 		return "", err
 	}
 	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
+	signedTx, err := worm.signTx(tx, chainID, fromKey)
 	if err != nil {
 		log.Println("Mint() signTx err ", err)
 		return "", err
@@ -138,7 +176,13 @@ func (worm *Wormholes) Mint(royalty uint32, metaURL string, exchanger string) (s
 //	wormAddress: "0x8000000000000000000000000000000000000001",  worm address, the format is a decimal string, when it is SNFT, the length can be less than 42 (including 0x), representing the synthesized SNFT
 //	to:         "0x814920c33b1a037F91a16B126282155c6F92A10F",  Target NFT user address
 func (worm *Wormholes) Transfer(wormAddress, to string) (string, error) {
-	err := tools.CheckHex("Transfer() wormAddress", wormAddress)
+	return worm.TransferCtx(context.Background(), wormAddress, to)
+}
+
+// TransferCtx is Transfer with a context for the requests to the node.
+func (worm *Wormholes) TransferCtx(ctx context.Context, wormAddress, to string) (string, error) {
+	to = worm.resolve(ctx, to)
+	err := tools.CheckNFTAddress("Transfer() wormAddress", wormAddress)
 	if err != nil {
 		return "", err
 	}
@@ -146,17 +190,24 @@ func (worm *Wormholes) Transfer(wormAddress, to string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	ctx := context.Background()
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
+	account, fromKey, err := worm.signingKey()
 	if err != nil {
 		return "", err
 	}
 
 	toAddr := common.HexToAddress(to)
 
+	unlock, err := worm.lockSender(ctx, account)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
 	nonce, err := worm.PendingNonceAt(ctx, account)
+	if err != nil {
+		log.Println("Transfer() pendingNonceAt err ", err)
+		return "", err
+	}
 
-	gasLimit := uint64(50000)
 	gasPrice, err := worm.SuggestGasPrice(ctx)
 	if err != nil {
 		log.Println("Transfer() suggestGasPrice err ", err)
@@ -179,6 +230,10 @@ func (worm *Wormholes) Transfer(wormAddress, to string) (string, error) {
 
 	fmt.Println(string(tx_data))
 
+	gasLimit, err := worm.gasLimit(ctx, types2.Transfer, account, toAddr, nil, tx_data)
+	if err != nil {
+		return "", err
+	}
 	tx := types.NewTransaction(nonce, toAddr, big.NewInt(0), gasLimit, gasPrice, tx_data)
 	chainID, err := worm.NetworkID(ctx)
 	if err != nil {
@@ -186,7 +241,7 @@ func (worm *Wormholes) Transfer(wormAddress, to string) (string, error) {
 		return "", err
 	}
 	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
+	signedTx, err := worm.signTx(tx, chainID, fromKey)
 	if err != nil {
 		log.Println("Transfer() signTx err ", err)
 		return "", err
@@ -205,7 +260,13 @@ func (worm *Wormholes) Transfer(wormAddress, to string) (string, error) {
 //	wormAddress: "0x0000000000000000000000000000000000000001",	Authorized worm address, the format is a decimal string, when it is SNFT, the length can be less than 42 (including 0x), representing the synthesized SNFT
 //	to:         "0x814920c33b1a037F91a16B126282155c6F92A10F",	Licensee's address
 func (worm *Wormholes) Author(wormAddress, to string) (string, error) {
-	err := tools.CheckHex("Author() wormAddress", wormAddress)
+	return worm.AuthorCtx(context.Background(), wormAddress, to)
+}
+
+// AuthorCtx is Author with a context for the requests to the node.
+func (worm *Wormholes) AuthorCtx(ctx context.Context, wormAddress, to string) (string, error) {
+	to = worm.resolve(ctx, to)
+	err := tools.CheckNFTAddress("Author() wormAddress", wormAddress)
 	if err != nil {
 		return "", err
 	}
@@ -213,17 +274,24 @@ func (worm *Wormholes) Author(wormAddress, to string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	ctx := context.Background()
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
+	account, fromKey, err := worm.signingKey()
 	if err != nil {
 		return "", err
 	}
 
 	toAddr := common.HexToAddress(to)
 
+	unlock, err := worm.lockSender(ctx, account)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
 	nonce, err := worm.PendingNonceAt(ctx, account)
+	if err != nil {
+		log.Println("Author() pendingNonceAt err ", err)
+		return "", err
+	}
 
-	gasLimit := uint64(50000)
 	gasPrice, err := worm.SuggestGasPrice(ctx)
 	if err != nil {
 		log.Println("Author() suggestGasPrice err ", err)
@@ -246,6 +314,10 @@ func (worm *Wormholes) Author(wormAddress, to string) (string, error) {
 
 	fmt.Println(string(tx_data))
 
+	gasLimit, err := worm.gasLimit(ctx, types2.Author, account, toAddr, nil, tx_data)
+	if err != nil {
+		return "", err
+	}
 	tx := types.NewTransaction(nonce, toAddr, big.NewInt(0), gasLimit, gasPrice, tx_data)
 	chainID, err := worm.NetworkID(ctx)
 	if err != nil {
@@ -253,7 +325,7 @@ func (worm *Wormholes) Author(wormAddress, to string) (string, error) {
 		return "", err
 	}
 	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
+	signedTx, err := worm.signTx(tx, chainID, fromKey)
 	if err != nil {
 		log.Println("Author signTx err ", err)
 		return "", err
@@ -272,7 +344,13 @@ func (worm *Wormholes) Author(wormAddress, to string) (string, error) {
 //	wormAddress: "0x0000000000000000000000000000000000000002",	Authorized worm address, the format is a decimal string, when it is SNFT, the length can be less than 42 (including 0x), representing the synthesized SNFT
 //	to:         "0x814920c33b1a037F91a16B126282155c6F92A10F",	Licensee's address
 func (worm *Wormholes) AuthorRevoke(wormAddress, to string) (string, error) {
-	err := tools.CheckHex("AuthorRevoke() wormAddress", wormAddress)
+	return worm.AuthorRevokeCtx(context.Background(), wormAddress, to)
+}
+
+// AuthorRevokeCtx is AuthorRevoke with a context for the requests to the node.
+func (worm *Wormholes) AuthorRevokeCtx(ctx context.Context, wormAddress, to string) (string, error) {
+	to = worm.resolve(ctx, to)
+	err := tools.CheckNFTAddress("AuthorRevoke() wormAddress", wormAddress)
 	if err != nil {
 		return "", err
 	}
@@ -280,17 +358,24 @@ func (worm *Wormholes) AuthorRevoke(wormAddress, to string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	ctx := context.Background()
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
+	account, fromKey, err := worm.signingKey()
 	if err != nil {
 		return "", err
 	}
 
 	toAddr := common.HexToAddress(to)
 
+	unlock, err := worm.lockSender(ctx, account)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
 	nonce, err := worm.PendingNonceAt(ctx, account)
+	if err != nil {
+		log.Println("AuthorRevoke() pendingNonceAt err ", err)
+		return "", err
+	}
 
-	gasLimit := uint64(50000)
 	gasPrice, err := worm.SuggestGasPrice(ctx)
 	if err != nil {
 		log.Println("AuthorRevoke suggestGasPrice err ", err)
@@ -312,6 +397,10 @@ func (worm *Wormholes) AuthorRevoke(wormAddress, to string) (string, error) {
 	tx_data := append([]byte(TranPrefix), data...)
 	fmt.Println(string(tx_data))
 
+	gasLimit, err := worm.gasLimit(ctx, types2.AuthorRevoke, account, toAddr, nil, tx_data)
+	if err != nil {
+		return "", err
+	}
 	tx := types.NewTransaction(nonce, toAddr, big.NewInt(0), gasLimit, gasPrice, tx_data)
 	chainID, err := worm.NetworkID(ctx)
 	if err != nil {
@@ -319,7 +408,7 @@ func (worm *Wormholes) AuthorRevoke(wormAddress, to string) (string, error) {
 		return "", err
 	}
 	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
+	signedTx, err := worm.signTx(tx, chainID, fromKey)
 	if err != nil {
 		log.Println("AuthorRevoke() signTx err ", err)
 		return "", err
@@ -332,30 +421,43 @@ func (worm *Wormholes) AuthorRevoke(wormAddress, to string) (string, error) {
 	return strings.ToLower(signedTx.Hash().String()), nil
 }
 
-// AccountAuthor
+// AuthorizeAll
 //
 //	Authorize all NFTs under an account to the exchange
 //	Parameter Description
 //	to:     "0x814920c33b1a037F91a16B126282155c6F92A10F",							Licensee's address
-func (worm *Wormholes) AccountAuthor(to string) (string, error) {
-	err := tools.CheckAddress("AccountAuthor() to", to)
+func (worm *Wormholes) AuthorizeAll(to string) (string, error) {
+	return worm.AuthorizeAllCtx(context.Background(), to)
+}
+
+// AuthorizeAllCtx is AuthorizeAll with a context for the requests to the node.
+func (worm *Wormholes) AuthorizeAllCtx(ctx context.Context, to string) (string, error) {
+	to = worm.resolve(ctx, to)
+	err := tools.CheckAddress("AuthorizeAll() to", to)
 	if err != nil {
 		return "", err
 	}
-	ctx := context.Background()
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
+	account, fromKey, err := worm.signingKey()
 	if err != nil {
 		return "", err
 	}
 
 	toAddr := common.HexToAddress(to)
 
+	unlock, err := worm.lockSender(ctx, account)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
 	nonce, err := worm.PendingNonceAt(ctx, account)
+	if err != nil {
+		log.Println("AuthorizeAll() pendingNonceAt err ", err)
+		return "", err
+	}
 
-	gasLimit := uint64(50000)
 	gasPrice, err := worm.SuggestGasPrice(ctx)
 	if err != nil {
-		log.Println("AccountAuthor() suggestGasPrice err ", err)
+		log.Println("AuthorizeAll() suggestGasPrice err ", err)
 		return "", err
 	}
 
@@ -366,58 +468,75 @@ func (worm *Wormholes) AccountAuthor(to string) (string, error) {
 
 	data, err := json.Marshal(transaction)
 	if err != nil {
-		log.Println("AccountAuthor() ailed to format wormholes data")
+		log.Println("AuthorizeAll() failed to format wormholes data")
 		return "", err
 	}
 
 	tx_data := append([]byte(TranPrefix), data...)
 	fmt.Println(string(tx_data))
 
+	gasLimit, err := worm.gasLimit(ctx, types2.AccountAuthor, account, toAddr, nil, tx_data)
+	if err != nil {
+		return "", err
+	}
 	tx := types.NewTransaction(nonce, toAddr, big.NewInt(0), gasLimit, gasPrice, tx_data)
 	chainID, err := worm.NetworkID(ctx)
 	if err != nil {
-		log.Println("AccountAuthor() networkID err ", err)
+		log.Println("AuthorizeAll() networkID err ", err)
 		return "", err
 	}
 	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
+	signedTx, err := worm.signTx(tx, chainID, fromKey)
 	if err != nil {
-		log.Println("AccountAuthor() signTx err ", err)
+		log.Println("AuthorizeAll() signTx err ", err)
 		return "", err
 	}
 	err = worm.SendTransaction(ctx, signedTx)
 	if err != nil {
-		log.Println("AccountAuthor sendTransaction err ", err)
+		log.Println("AuthorizeAll sendTransaction err ", err)
 		return "", err
 	}
 	return strings.ToLower(signedTx.Hash().String()), nil
 }
 
-// AccountAuthorRevoke
+// RevokeAuthorizeAll
 //
 //	Cancel all NFT authorizations under an account
 //
 //	Parameter Description
 //	to:     "0x814920c33b1a037F91a16B126282155c6F92A10F",							Licensee's address
-func (worm *Wormholes) AccountAuthorRevoke(to string) (string, error) {
-	err := tools.CheckAddress("AccountAuthorRevoke() to", to)
+func (worm *Wormholes) RevokeAuthorizeAll(to string) (string, error) {
+	return worm.RevokeAuthorizeAllCtx(context.Background(), to)
+}
+
+// RevokeAuthorizeAllCtx is RevokeAuthorizeAll with a context for the requests to the node.
+func (worm *Wormholes) RevokeAuthorizeAllCtx(ctx context.Context, to string) (string, error) {
+	to = worm.resolve(ctx, to)
+	err := tools.CheckAddress("RevokeAuthorizeAll() to", to)
 	if err != nil {
 		return "", err
 	}
-	ctx := context.Background()
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
+	account, fromKey, err := worm.signingKey()
 	if err != nil {
 		return "", err
 	}
 
 	toAddr := common.HexToAddress(to)
 
+	unlock, err := worm.lockSender(ctx, account)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
 	nonce, err := worm.PendingNonceAt(ctx, account)
+	if err != nil {
+		log.Println("RevokeAuthorizeAll() pendingNonceAt err ", err)
+		return "", err
+	}
 
-	gasLimit := uint64(50000)
 	gasPrice, err := worm.SuggestGasPrice(ctx)
 	if err != nil {
-		log.Println("AccountAuthorRevoke() suggestGasPrice err ", err)
+		log.Println("RevokeAuthorizeAll() suggestGasPrice err ", err)
 		return "", err
 	}
 
@@ -428,28 +547,32 @@ func (worm *Wormholes) AccountAuthorRevoke(to string) (string, error) {
 
 	data, err := json.Marshal(transaction)
 	if err != nil {
-		log.Println("AccountAuthorRevoke() failed to format wormholes data")
+		log.Println("RevokeAuthorizeAll() failed to format wormholes data")
 		return "", err
 	}
 
 	tx_data := append([]byte(TranPrefix), data...)
 	fmt.Println(string(tx_data))
 
+	gasLimit, err := worm.gasLimit(ctx, types2.AccountAuthorRevoke, account, toAddr, nil, tx_data)
+	if err != nil {
+		return "", err
+	}
 	tx := types.NewTransaction(nonce, toAddr, big.NewInt(0), gasLimit, gasPrice, tx_data)
 	chainID, err := worm.NetworkID(ctx)
 	if err != nil {
-		log.Println("AccountAuthorRevoke() networkID err ", err)
+		log.Println("RevokeAuthorizeAll() networkID err ", err)
 		return "", err
 	}
 	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
+	signedTx, err := worm.signTx(tx, chainID, fromKey)
 	if err != nil {
-		log.Println("AccountAuthorRevoke() signTx err ", err)
+		log.Println("RevokeAuthorizeAll() signTx err ", err)
 		return "", err
 	}
 	err = worm.SendTransaction(ctx, signedTx)
 	if err != nil {
-		log.Println("AccountAuthorRevoke() sendTransaction err ", err)
+		log.Println("RevokeAuthorizeAll() sendTransaction err ", err)
 		return "", err
 	}
 	return strings.ToLower(signedTx.Hash().String()), nil
@@ -468,20 +591,32 @@ func (worm *Wormholes) AccountAuthorRevoke(to string) (string, error) {
 //	2: 225000000000000000
 //	3: 300000000000000000
 func (worm *Wormholes) SNFTToERB(wormAddress string) (string, error) {
-	err := tools.CheckHex("SNFTToERB() wormAddress", wormAddress)
+	return worm.SNFTToERBCtx(context.Background(), wormAddress)
+}
+
+// SNFTToERBCtx is SNFTToERB with a context for the requests to the node.
+func (worm *Wormholes) SNFTToERBCtx(ctx context.Context, wormAddress string) (string, error) {
+	err := tools.CheckNFTAddress("SNFTToERB() wormAddress", wormAddress)
 	if err != nil {
 		return "", err
 	}
 
-	ctx := context.Background()
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
+	account, fromKey, err := worm.signingKey()
 	if err != nil {
 		return "", err
 	}
 
+	unlock, err := worm.lockSender(ctx, account)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
 	nonce, err := worm.PendingNonceAt(ctx, account)
+	if err != nil {
+		log.Println("SNFTToERB() pendingNonceAt err ", err)
+		return "", err
+	}
 
-	gasLimit := uint64(50000)
 	gasPrice, err := worm.SuggestGasPrice(ctx)
 	if err != nil {
 		log.Println("SNFTToERB() suggestGasPrice err ", err)
@@ -503,6 +638,10 @@ func (worm *Wormholes) SNFTToERB(wormAddress string) (string, error) {
 	tx_data := append([]byte(TranPrefix), data...)
 	fmt.Println(string(tx_data))
 
+	gasLimit, err := worm.gasLimit(ctx, types2.SNFTToERB, account, account, nil, tx_data)
+	if err != nil {
+		return "", err
+	}
 	tx := types.NewTransaction(nonce, account, big.NewInt(0), gasLimit, gasPrice, tx_data)
 	chainID, err := worm.NetworkID(ctx)
 	if err != nil {
@@ -510,7 +649,7 @@ func (worm *Wormholes) SNFTToERB(wormAddress string) (string, error) {
 		return "", err
 	}
 	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
+	signedTx, err := worm.signTx(tx, chainID, fromKey)
 	if err != nil {
 		log.Println("SNFTToERB() signTx err ", err)
 		return "", err
@@ -528,7 +667,7 @@ func (worm *Wormholes) SNFTToERB(wormAddress string) (string, error) {
 //	When a user wants to become a miner, he needs to do an ERB pledge transaction first to pledge the ERB needed to become a miner
 //func (worm *Wormholes) SNFTPledge(snftAddress string) (string, error) {
 //	ctx := context.Background()
-//	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
+//	account, fromKey, err := worm.signingKey()
 //	if err != nil {
 //		log.Println("TokenPledge() priKeyToAddress err ", err)
 //		return "", err
@@ -567,7 +706,7 @@ func (worm *Wormholes) SNFTToERB(wormAddress string) (string, error) {
 //		return "", err
 //	}
 //	log.Println("chainID=", chainID)
-//	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
+//	signedTx, err := worm.signTx(tx, chainID, fromKey)
 //	if err != nil {
 //		log.Println("TokenPledge() signTx err ", err)
 //		return "", err
@@ -585,7 +724,7 @@ func (worm *Wormholes) SNFTToERB(wormAddress string) (string, error) {
 //	When the user does not want to be a miner, or no longer wants to pledge so much ERB, he can do ERB to revoke the pledge
 //func (worm *Wormholes) SNFTRevokesPledge(snftaAddress string) (string, error) {
 //	ctx := context.Background()
-//	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
+//	account, fromKey, err := worm.signingKey()
 //	if err != nil {
 //		log.Println("TokenRevokesPledge() priKeyToAddress err ", err)
 //		return "", err
@@ -625,7 +764,7 @@ func (worm *Wormholes) SNFTToERB(wormAddress string) (string, error) {
 //		return "", err
 //	}
 //	log.Println("chainID=", chainID)
-//	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
+//	signedTx, err := worm.signTx(tx, chainID, fromKey)
 //	if err != nil {
 //		log.Println("TokenRevokesPledge() signTx err ", err)
 //		return "", err
@@ -641,17 +780,41 @@ func (worm *Wormholes) SNFTToERB(wormAddress string) (string, error) {
 // TokenPledge
 //
 //	When a user wants to become a miner, he needs to do an ERB pledge transaction first to pledge the ERB needed to become a miner
+//	feerate is the fee of the exchanger in basis points of the price, at most 10000
 func (worm *Wormholes) TokenPledge(toaddress common.Address, proxyAddress, name, url string, value int64, feerate int) (string, error) {
-	ctx := context.Background()
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
+	return worm.TokenPledgeCtx(context.Background(), toaddress, proxyAddress, name, url, types2.ERB(value), feerate)
+}
+
+// TokenPledgeCtx is TokenPledge with a context for the requests to the node and
+// the amount as a types.Amount.
+func (worm *Wormholes) TokenPledgeCtx(ctx context.Context, toaddress common.Address, proxyAddress, name, url string, value types2.Amount, feerate int) (string, error) {
+	if err := checkAmount("TokenPledge() value", value); err != nil {
+		return "", err
+	}
+	if err := checkFeeRate("TokenPledge() feerate", feerate); err != nil {
+		return "", err
+	}
+	proxyAddress = worm.resolve(ctx, proxyAddress)
+	if err := checkOptionalAddress("TokenPledge() proxyAddress", proxyAddress); err != nil {
+		return "", err
+	}
+	account, fromKey, err := worm.signingKey()
 	if err != nil {
 		log.Println("TokenPledge() priKeyToAddress err ", err)
 		return "", err
 	}
 
+	unlock, err := worm.lockSender(ctx, account)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
 	nonce, err := worm.PendingNonceAt(ctx, account)
+	if err != nil {
+		log.Println("TokenPledge() pendingNonceAt err ", err)
+		return "", err
+	}
 
-	gasLimit := uint64(70000)
 	gasPrice, err := worm.SuggestGasPrice(ctx)
 	if err != nil {
 		log.Println("TokenPledge() suggestGasPrice err ", err)
@@ -676,16 +839,18 @@ func (worm *Wormholes) TokenPledge(toaddress common.Address, proxyAddress, name,
 	tx_data := append([]byte(TranPrefix), data...)
 	fmt.Println(string(tx_data))
 
-	wei, _ := new(big.Int).SetString("1000000000000000000", 10)
-	pledge := new(big.Int).Mul(big.NewInt(value), wei)
-	tx := types.NewTransaction(nonce, toaddress, pledge, gasLimit, gasPrice, tx_data)
+	gasLimit, err := worm.gasLimit(ctx, types2.TokenPledge, account, toaddress, value.Wei(), tx_data)
+	if err != nil {
+		return "", err
+	}
+	tx := types.NewTransaction(nonce, toaddress, value.Wei(), gasLimit, gasPrice, tx_data)
 	chainID, err := worm.NetworkID(ctx)
 	if err != nil {
 		log.Println("TokenPledge() networkID err=", err)
 		return "", err
 	}
 	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
+	signedTx, err := worm.signTx(tx, chainID, fromKey)
 	if err != nil {
 		log.Println("TokenPledge() signTx err ", err)
 		return "", err
@@ -702,16 +867,32 @@ func (worm *Wormholes) TokenPledge(toaddress common.Address, proxyAddress, name,
 //
 //	When the user does not want to be a miner, or no longer wants to pledge so much ERB, he can do ERB to revoke the pledge
 func (worm *Wormholes) TokenRevokesPledge(toaddress common.Address, value int64) (string, error) {
-	ctx := context.Background()
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
+	return worm.TokenRevokesPledgeCtx(context.Background(), toaddress, types2.ERB(value))
+}
+
+// TokenRevokesPledgeCtx is TokenRevokesPledge with a context for the requests to the node and
+// the amount as a types.Amount.
+func (worm *Wormholes) TokenRevokesPledgeCtx(ctx context.Context, toaddress common.Address, value types2.Amount) (string, error) {
+	if err := checkAmount("TokenRevokesPledge() value", value); err != nil {
+		return "", err
+	}
+	account, fromKey, err := worm.signingKey()
 	if err != nil {
 		log.Println("TokenRevokesPledge() priKeyToAddress err ", err)
 		return "", err
 	}
 
+	unlock, err := worm.lockSender(ctx, account)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
 	nonce, err := worm.PendingNonceAt(ctx, account)
+	if err != nil {
+		log.Println("TokenRevokesPledge() pendingNonceAt err ", err)
+		return "", err
+	}
 
-	gasLimit := uint64(50000)
 	gasPrice, err := worm.SuggestGasPrice(ctx)
 	if err != nil {
 		log.Println("TokenRevokesPledge() suggestGasPrice err ", err)
@@ -732,17 +913,18 @@ func (worm *Wormholes) TokenRevokesPledge(toaddress common.Address, value int64)
 	tx_data := append([]byte(TranPrefix), data...)
 	fmt.Println(string(tx_data))
 
-	wei, _ := new(big.Int).SetString("1000000000000000000", 10)
-	pledge := new(big.Int).Mul(big.NewInt(value), wei)
-
-	tx := types.NewTransaction(nonce, toaddress, pledge, gasLimit, gasPrice, tx_data)
+	gasLimit, err := worm.gasLimit(ctx, types2.TokenRevokesPledge, account, toaddress, value.Wei(), tx_data)
+	if err != nil {
+		return "", err
+	}
+	tx := types.NewTransaction(nonce, toaddress, value.Wei(), gasLimit, gasPrice, tx_data)
 	chainID, err := worm.NetworkID(ctx)
 	if err != nil {
 		log.Println("TokenRevokesPledge() networkID err=", err)
 		return "", err
 	}
 	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
+	signedTx, err := worm.signTx(tx, chainID, fromKey)
 	if err != nil {
 		log.Println("TokenRevokesPledge() signTx err ", err)
 		return "", err
@@ -765,7 +947,7 @@ func (worm *Wormholes) TokenRevokesPledge(toaddress common.Address, value int64)
 //	url:       "www.kang123456.com",		Exchange server address, formatted as a string
 //func (worm *Wormholes) Open(feeRate uint32, name, url string) (string, error) {
 //	ctx := context.Background()
-//	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
+//	account, fromKey, err := worm.signingKey()
 //	if err != nil {
 //		log.Println("Open() priKeyToAddress err ", err)
 //		return "", err
@@ -807,7 +989,7 @@ func (worm *Wormholes) TokenRevokesPledge(toaddress common.Address, value int64)
 //		return "", err
 //	}
 //	log.Println("chainID=", chainID)
-//	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
+//	signedTx, err := worm.signTx(tx, chainID, fromKey)
 //	if err != nil {
 //		log.Println("open() signTx err ", err)
 //		return "", err
@@ -825,7 +1007,7 @@ func (worm *Wormholes) TokenRevokesPledge(toaddress common.Address, value int64)
 //	When the user does not want to continue to open an exchange, he can initiate this transaction to close the opened exchange
 //func (worm *Wormholes) Close() (string, error) {
 //	ctx := context.Background()
-//	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
+//	account, fromKey, err := worm.signingKey()
 //	if err != nil {
 //		log.Println("close() priKeyToAddress err ", err)
 //		return "", err
@@ -861,7 +1043,7 @@ func (worm *Wormholes) TokenRevokesPledge(toaddress common.Address, value int64)
 //		return "", err
 //	}
 //	log.Println("chainID=", chainID)
-//	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
+//	signedTx, err := worm.signTx(tx, chainID, fromKey)
 //	if err != nil {
 //		log.Println("close() signTx err ", err)
 //		return "", err
@@ -882,6 +1064,12 @@ func (worm *Wormholes) TokenRevokesPledge(toaddress common.Address, value int64)
 //	buyer: { "price":"0xde0b6b3a7640000", "worm_address":"0x0000000000000000000000000000000000000002", "exchanger":"0xe61e5Bbe724B8F449B5C7BB4a09F99A057253eB4", "block_number":"0x487", "sig":"0x24355436e991443b8ed3fb83e8c2fa02f8e2bfc0f716c320f836ee7d756e3c712e7e2510b994d1cb7be85d6643233abc81c23929ce7c1c1effd93db261aac5211b" }																				buyer
 //	to:     "0x5051B76579BC966A9480dd6E72B39A4C89c1154C",				Buyer's address
 func (worm *Wormholes) TransactionNFT(buyer []byte, to string) (string, error) {
+	return worm.TransactionNFTCtx(context.Background(), buyer, to)
+}
+
+// TransactionNFTCtx is TransactionNFT with a context for the requests to the node.
+func (worm *Wormholes) TransactionNFTCtx(ctx context.Context, buyer []byte, to string) (string, error) {
+	to = worm.resolve(ctx, to)
 	err := tools.CheckAddress("TransactionNFT() to", to)
 	if err != nil {
 		return "", err
@@ -893,23 +1081,30 @@ func (worm *Wormholes) TransactionNFT(buyer []byte, to string) (string, error) {
 		return "", xerrors.New("the formate of buyer is wrong")
 	}
 
-	err = tools.CheckHex("buyers.BlockNumber", buyers.BlockNumber)
+	err = buyers.Validate()
 	if err != nil {
 		return "", err
 	}
 
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
+	account, fromKey, err := worm.signingKey()
 	if err != nil {
 		log.Println("TransactionNFT() priKeyToAddress err ", err)
 		return "", err
 	}
 
-	ctx := context.Background()
+	unlock, err := worm.lockSender(ctx, account)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
 	nonce, err := worm.PendingNonceAt(ctx, account)
+	if err != nil {
+		log.Println("TransactionNFT() pendingNonceAt err ", err)
+		return "", err
+	}
 
 	toAddr := common.HexToAddress(to)
 
-	gasLimit := uint64(100000)
 	gasPrice, err := worm.SuggestGasPrice(ctx)
 	if err != nil {
 		log.Println("TransactionNFT() suggestGasPrice err ", err)
@@ -939,8 +1134,12 @@ func (worm *Wormholes) TransactionNFT(buyer []byte, to string) (string, error) {
 	tx_data := append([]byte(TranPrefix), data...)
 	fmt.Println(string(tx_data))
 
-	value, _ := hexutil.DecodeBig(buyers.Amount)
+	value, _ := tools.DecodeAmount(buyers.Amount)
 	fmt.Println(value)
+	gasLimit, err := worm.gasLimit(ctx, types2.TransactionNFT, account, toAddr, value, tx_data)
+	if err != nil {
+		return "", err
+	}
 	tx := types.NewTransaction(nonce, toAddr, value, gasLimit, gasPrice, tx_data)
 	chainID, err := worm.NetworkID(ctx)
 	if err != nil {
@@ -948,7 +1147,7 @@ func (worm *Wormholes) TransactionNFT(buyer []byte, to string) (string, error) {
 		return "", err
 	}
 	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
+	signedTx, err := worm.signTx(tx, chainID, fromKey)
 	if err != nil {
 		log.Println("TransactionNFT() signTx err ", err)
 		return "", err
@@ -968,26 +1167,39 @@ func (worm *Wormholes) TransactionNFT(buyer []byte, to string) (string, error) {
 //	Parameter Description
 //	seller1: { "price":"0x38D7EA4C68000", "worm_address":"0x0000000000000000000000000000000000000003", "exchanger":"0xe61e5Bbe724B8F449B5C7BB4a09F99A057253eB4", "block_number":"0x65d", "sig":"0x94e88fb5686551dfc3006c608423983a248df8502cbbcaeb2c3352f267a25e531d5fc745bea5f7f564b7399fb70d87026bbf9952f1403e9d4dae4aa14b091cff1c" }
 func (worm *Wormholes) BuyerInitiatingTransaction(seller1 []byte) (string, error) {
+	return worm.BuyerInitiatingTransactionCtx(context.Background(), seller1)
+}
+
+// BuyerInitiatingTransactionCtx is BuyerInitiatingTransaction with a context for the requests to the node.
+func (worm *Wormholes) BuyerInitiatingTransactionCtx(ctx context.Context, seller1 []byte) (string, error) {
 	var seller1s types2.Seller1
 	err := json.Unmarshal(seller1, &seller1s)
 	if err != nil {
 		return "", xerrors.New("the formate of seller1 is wrong")
 	}
 
-	err = tools.CheckHex("seller1s.BlockNumber", seller1s.BlockNumber)
+	err = seller1s.Validate()
 	if err != nil {
 		return "", err
 	}
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
+
+	account, fromKey, err := worm.signingKey()
 	if err != nil {
 		log.Println("BuyerInitiatingTransaction() priKeyToAddress err ", err)
 		return "", err
 	}
 
-	ctx := context.Background()
+	unlock, err := worm.lockSender(ctx, account)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
 	nonce, err := worm.PendingNonceAt(ctx, account)
+	if err != nil {
+		log.Println("BuyerInitiatingTransaction() pendingNonceAt err ", err)
+		return "", err
+	}
 
-	gasLimit := uint64(100000)
 	gasPrice, err := worm.SuggestGasPrice(ctx)
 	if err != nil {
 		log.Println("BuyerInitiatingTransaction() suggestGasPrice err ", err)
@@ -1009,7 +1221,11 @@ func (worm *Wormholes) BuyerInitiatingTransaction(seller1 []byte) (string, error
 	tx_data := append([]byte(TranPrefix), data...)
 	fmt.Println(string(tx_data))
 
-	value, _ := hexutil.DecodeBig(seller1s.Amount)
+	value, _ := tools.DecodeAmount(seller1s.Amount)
+	gasLimit, err := worm.gasLimit(ctx, types2.BuyerInitiatingTransaction, account, account, value, tx_data)
+	if err != nil {
+		return "", err
+	}
 	tx := types.NewTransaction(nonce, account, value, gasLimit, gasPrice, tx_data)
 	chainID, err := worm.NetworkID(ctx)
 	if err != nil {
@@ -1017,7 +1233,7 @@ func (worm *Wormholes) BuyerInitiatingTransaction(seller1 []byte) (string, error
 		return "", err
 	}
 	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
+	signedTx, err := worm.signTx(tx, chainID, fromKey)
 	if err != nil {
 		log.Println("BuyerInitiatingTransaction signTx err ", err)
 		return "", err
@@ -1037,32 +1253,39 @@ func (worm *Wormholes) BuyerInitiatingTransaction(seller1 []byte) (string, error
 //	Parameter Description
 //	seller2: { "price":"0x38D7EA4C68000", "royalty":"0xa", "meta_url":"/ipfs/qqqqqqqqqq", "exclusive_flag":"0", "exchanger":"0xe61e5Bbe724B8F449B5C7BB4a09F99A057253eB4", "block_number":"0x703", "sig":"0xb08cf8b2f2d4b2635a85d1c7a816f01c24ac2a90ab49bdbe0e52e0a8f07eea5521eb80554df2c403423bdf49f412a7811b10a16005832a1bc171f5dfd3c983121c" }
 func (worm *Wormholes) FoundryTradeBuyer(seller2 []byte) (string, error) {
+	return worm.FoundryTradeBuyerCtx(context.Background(), seller2)
+}
+
+// FoundryTradeBuyerCtx is FoundryTradeBuyer with a context for the requests to the node.
+func (worm *Wormholes) FoundryTradeBuyerCtx(ctx context.Context, seller2 []byte) (string, error) {
 	var seller2s types2.Seller2
 	err := json.Unmarshal([]byte(seller2), &seller2s)
 	if err != nil {
 		return "", xerrors.New("the formate of seller2 is wrong")
 	}
 
-	err = tools.CheckFlag("seller2s.ExclusiveFlag", seller2s.ExclusiveFlag)
+	err = seller2s.Validate()
 	if err != nil {
 		return "", err
 	}
 
-	err = tools.CheckHex("seller2s.BlockNumber", seller2s.BlockNumber)
+	account, fromKey, err := worm.signingKey()
 	if err != nil {
+		log.Println("FoundryTradeBuyer() priKeyToAddress err ", err)
 		return "", err
 	}
 
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
+	unlock, err := worm.lockSender(ctx, account)
 	if err != nil {
-		log.Println("FoundryTradeBuyer() priKeyToAddress err ", err)
 		return "", err
 	}
-
-	ctx := context.Background()
+	defer unlock()
 	nonce, err := worm.PendingNonceAt(ctx, account)
+	if err != nil {
+		log.Println("FoundryTradeBuyer() pendingNonceAt err ", err)
+		return "", err
+	}
 
-	gasLimit := uint64(101000)
 	gasPrice, err := worm.SuggestGasPrice(ctx)
 	if err != nil {
 		log.Println("FoundryTradeBuyer() suggestGasPrice err ", err)
@@ -1084,7 +1307,11 @@ func (worm *Wormholes) FoundryTradeBuyer(seller2 []byte) (string, error) {
 	tx_data := append([]byte(TranPrefix), data...)
 	fmt.Println(string(tx_data))
 
-	value, _ := hexutil.DecodeBig(seller2s.Amount)
+	value, _ := tools.DecodeAmount(seller2s.Amount)
+	gasLimit, err := worm.gasLimit(ctx, types2.FoundryTradeBuyer, account, account, value, tx_data)
+	if err != nil {
+		return "", err
+	}
 	tx := types.NewTransaction(nonce, account, value, gasLimit, gasPrice, tx_data)
 	chainID, err := worm.NetworkID(ctx)
 	if err != nil {
@@ -1092,7 +1319,7 @@ func (worm *Wormholes) FoundryTradeBuyer(seller2 []byte) (string, error) {
 		return "", err
 	}
 	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
+	signedTx, err := worm.signTx(tx, chainID, fromKey)
 	if err != nil {
 		log.Println("FoundryTradeBuyer() signTx err ", err)
 		return "", err
@@ -1114,6 +1341,12 @@ func (worm *Wormholes) FoundryTradeBuyer(seller2 []byte) (string, error) {
 //	seller2: {"price":"0x38D7EA4C68000","royalty":"0xa","meta_url":"/ipfs/qqqqqqqqqq","exclusive_flag":"0","exchanger":"0xe61e5Bbe724B8F449B5C7BB4a09F99A057253eB4","block_number":"0x7be","sig":"0x84c0c293298557e38fa5064a6fb3b9e6930fa46b234fcd0a923cd677369f5aad3f014a164b21077f713e25b4e986673f614f6ce824561fbda2b4e67e018fac6f1b"}
 //	to:      "0x5051B76579BC966A9480dd6E72B39A4C89c1154C",  Buyer's address
 func (worm *Wormholes) FoundryExchange(buyer, seller2 []byte, to string) (string, error) {
+	return worm.FoundryExchangeCtx(context.Background(), buyer, seller2, to)
+}
+
+// FoundryExchangeCtx is FoundryExchange with a context for the requests to the node.
+func (worm *Wormholes) FoundryExchangeCtx(ctx context.Context, buyer, seller2 []byte, to string) (string, error) {
+	to = worm.resolve(ctx, to)
 	err := tools.CheckAddress("to", to)
 	if err != nil {
 		return "", err
@@ -1125,7 +1358,7 @@ func (worm *Wormholes) FoundryExchange(buyer, seller2 []byte, to string) (string
 		return "", xerrors.New("the formate of buyer is wrong")
 	}
 
-	err = tools.CheckHex("buyers.BlockNumber", buyers.BlockNumber)
+	err = buyers.Validate()
 	if err != nil {
 		return "", err
 	}
@@ -1136,25 +1369,19 @@ func (worm *Wormholes) FoundryExchange(buyer, seller2 []byte, to string) (string
 		return "", xerrors.New("the formate of seller2 is wrong")
 	}
 
-	err = tools.CheckFlag("seller2s.ExclusiveFlag", seller2s.ExclusiveFlag)
-	if err != nil {
-		return "", err
-	}
-
-	err = tools.CheckHex("seller2s.BlockNumber", seller2s.BlockNumber)
+	err = seller2s.Validate()
 	if err != nil {
 		return "", err
 	}
 
-	if buyers.Amount < seller2s.Amount {
+	if hexLess(buyers.Amount, seller2s.Amount) {
 		return "", xerrors.New("buyer`s amount must be greater then seller`s amount")
 	}
-	if seller2s.Exchanger != buyers.Exchanger {
-		return "", xerrors.New("buyer`s exchanger and seller`s exchanger and transaction`s exchanger aren`t same")
+	if !tools.SameAddress(seller2s.Exchanger, buyers.Exchanger) {
+		return "", fmt.Errorf("%w: buyer`s exchanger and seller`s exchanger and transaction`s exchanger aren`t same", ErrUnauthorizedExchange)
 	}
 
-	ctx := context.Background()
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
+	account, fromKey, err := worm.signingKey()
 	if err != nil {
 		log.Println("FoundryExchange() priKeyToAddress err ", err)
 		return "", err
@@ -1162,9 +1389,17 @@ func (worm *Wormholes) FoundryExchange(buyer, seller2 []byte, to string) (string
 
 	toAddr := common.HexToAddress(to)
 
+	unlock, err := worm.lockSender(ctx, account)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
 	nonce, err := worm.PendingNonceAt(ctx, account)
+	if err != nil {
+		log.Println("FoundryExchange() pendingNonceAt err ", err)
+		return "", err
+	}
 
-	gasLimit := uint64(140000)
 	gasPrice, err := worm.SuggestGasPrice(ctx)
 	if err != nil {
 		log.Println("FoundryExchange() suggestGasPrice err ", err)
@@ -1186,7 +1421,11 @@ func (worm *Wormholes) FoundryExchange(buyer, seller2 []byte, to string) (string
 	tx_data := append([]byte(TranPrefix), data...)
 	fmt.Println(string(tx_data))
 
-	value, _ := hexutil.DecodeBig(buyers.Amount)
+	value, _ := tools.DecodeAmount(buyers.Amount)
+	gasLimit, err := worm.gasLimit(ctx, types2.FoundryExchange, account, toAddr, value, tx_data)
+	if err != nil {
+		return "", err
+	}
 	tx := types.NewTransaction(nonce, toAddr, value, gasLimit, gasPrice, tx_data)
 	chainID, err := worm.NetworkID(ctx)
 	if err != nil {
@@ -1194,7 +1433,7 @@ func (worm *Wormholes) FoundryExchange(buyer, seller2 []byte, to string) (string
 		return "", err
 	}
 	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
+	signedTx, err := worm.signTx(tx, chainID, fromKey)
 	if err != nil {
 		log.Println("FoundryExchange() signTx err ", err)
 		return "", err
@@ -1216,6 +1455,12 @@ func (worm *Wormholes) FoundryExchange(buyer, seller2 []byte, to string) (string
 //	{"exchanger_owner":"0xe61e5Bbe724B8F449B5C7BB4a09F99A057253eB4","to":"0xEaE404DCa7c22A15A59f63002Df54BBb8D90c5FB","block_number":"0x92b","sig":"0x972099c287a8da54bb13e7134fcd7edcf96122f1dc949ab987961072011e57662ccb9482ed3738fcdefa613a4d7f58b02fffdf4702943e48bc93af3be7af34191c"}
 //	to            "0x5051B76579BC966A9480dd6E72B39A4C89c1154C",	Buyer's address
 func (worm *Wormholes) NftExchangeMatch(buyer, seller, exchangerAuth []byte, to string) (string, error) {
+	return worm.NftExchangeMatchCtx(context.Background(), buyer, seller, exchangerAuth, to)
+}
+
+// NftExchangeMatchCtx is NftExchangeMatch with a context for the requests to the node.
+func (worm *Wormholes) NftExchangeMatchCtx(ctx context.Context, buyer, seller, exchangerAuth []byte, to string) (string, error) {
+	to = worm.resolve(ctx, to)
 	err := tools.CheckAddress("NftExchangeMatch() to", to)
 	if err != nil {
 		return "", err
@@ -1228,7 +1473,7 @@ func (worm *Wormholes) NftExchangeMatch(buyer, seller, exchangerAuth []byte, to
 		return "", xerrors.New("the formate of buyer is wrong")
 	}
 
-	err = tools.CheckHex("buyers.BlockNumber", buyers.BlockNumber)
+	err = buyers.Validate()
 	if err != nil {
 		return "", err
 	}
@@ -1239,7 +1484,7 @@ func (worm *Wormholes) NftExchangeMatch(buyer, seller, exchangerAuth []byte, to
 		return "", xerrors.New("the formate of sellers is wrong")
 	}
 
-	err = tools.CheckHex("sellers.BlockNumber", sellers.BlockNumber)
+	err = sellers.Validate()
 	if err != nil {
 		return "", err
 	}
@@ -1250,21 +1495,28 @@ func (worm *Wormholes) NftExchangeMatch(buyer, seller, exchangerAuth []byte, to
 		return "", xerrors.New("the formate of exchangerAuth is wrong")
 	}
 
-	err = tools.CheckHex("exchangeAuths.BlockNumber", exchangeAuths.BlockNumber)
+	err = exchangeAuths.Validate()
 	if err != nil {
 		return "", err
 	}
 
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
+	account, fromKey, err := worm.signingKey()
 	if err != nil {
 		log.Println("NftExchangeMatch() priKeyToAddress err ", err)
 		return "", err
 	}
 
-	ctx := context.Background()
+	unlock, err := worm.lockSender(ctx, account)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
 	nonce, err := worm.PendingNonceAt(ctx, account)
+	if err != nil {
+		log.Println("NftExchangeMatch() pendingNonceAt err ", err)
+		return "", err
+	}
 
-	gasLimit := uint64(140000)
 	gasPrice, err := worm.SuggestGasPrice(ctx)
 	if err != nil {
 		log.Println("NftExchangeMatch() suggestGasPrice err ", err)
@@ -1287,7 +1539,11 @@ func (worm *Wormholes) NftExchangeMatch(buyer, seller, exchangerAuth []byte, to
 	tx_data := append([]byte(TranPrefix), data...)
 	fmt.Println(string(tx_data))
 
-	value, _ := hexutil.DecodeBig(buyers.Amount)
+	value, _ := tools.DecodeAmount(buyers.Amount)
+	gasLimit, err := worm.gasLimit(ctx, types2.NftExchangeMatch, account, toAddr, value, tx_data)
+	if err != nil {
+		return "", err
+	}
 	tx := types.NewTransaction(nonce, toAddr, value, gasLimit, gasPrice, tx_data)
 	chainID, err := worm.NetworkID(ctx)
 	if err != nil {
@@ -1295,7 +1551,7 @@ func (worm *Wormholes) NftExchangeMatch(buyer, seller, exchangerAuth []byte, to
 		return "", err
 	}
 	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
+	signedTx, err := worm.signTx(tx, chainID, fromKey)
 	if err != nil {
 		log.Println("NftExchangeMatch signTx err ", err)
 		return "", err
@@ -1318,6 +1574,12 @@ func (worm *Wormholes) NftExchangeMatch(buyer, seller, exchangerAuth []byte, to
 //	exchangerAuth:	{"exchanger_owner":"0xe61e5Bbe724B8F449B5C7BB4a09F99A057253eB4","to":"0xEaE404DCa7c22A15A59f63002Df54BBb8D90c5FB","block_number":"0x26","sig":"0x8c1706b407f50ed5cec8a392eac5f66f0338e9cf4eb71a465dc264ac7e315d2068f6061dfec02ee6b6f7f1150d1594c829436c36bc49c806ee5f5b4ad04e43631c"}
 //	to:            "0x5051B76579BC966A9480dd6E72B39A4C89c1154C",	Buyer's address
 func (worm *Wormholes) FoundryExchangeInitiated(buyer, seller2, exchangerAuth []byte, to string) (string, error) {
+	return worm.FoundryExchangeInitiatedCtx(context.Background(), buyer, seller2, exchangerAuth, to)
+}
+
+// FoundryExchangeInitiatedCtx is FoundryExchangeInitiated with a context for the requests to the node.
+func (worm *Wormholes) FoundryExchangeInitiatedCtx(ctx context.Context, buyer, seller2, exchangerAuth []byte, to string) (string, error) {
+	to = worm.resolve(ctx, to)
 	err := tools.CheckAddress("FoundryExchangeInitiated() to", to)
 	if err != nil {
 		return "", err
@@ -1329,7 +1591,7 @@ func (worm *Wormholes) FoundryExchangeInitiated(buyer, seller2, exchangerAuth []
 		return "", xerrors.New("the formate of buyer is wrong")
 	}
 
-	err = tools.CheckHex("buyers.BlockNumber", buyers.BlockNumber)
+	err = buyers.Validate()
 	if err != nil {
 		return "", err
 	}
@@ -1340,12 +1602,7 @@ func (worm *Wormholes) FoundryExchangeInitiated(buyer, seller2, exchangerAuth []
 		return "", xerrors.New("the formate of seller2 is wrong")
 	}
 
-	err = tools.CheckFlag("seller2s.ExclusiveFlag", seller2s.ExclusiveFlag)
-	if err != nil {
-		return "", err
-	}
-
-	err = tools.CheckHex("seller2s.BlockNumber", seller2s.BlockNumber)
+	err = seller2s.Validate()
 	if err != nil {
 		return "", err
 	}
@@ -1360,11 +1617,11 @@ func (worm *Wormholes) FoundryExchangeInitiated(buyer, seller2, exchangerAuth []
 	//addr, _ := tools.RecoverAddress(sellerMsg, seller2s.Sig)
 	//fmt.Println("---------------seller", addr.String())
 
-	if buyers.Amount < seller2s.Amount {
+	if hexLess(buyers.Amount, seller2s.Amount) {
 		return "", xerrors.New("buyer`s amount must be greater then seller`s amount")
 	}
-	if seller2s.Exchanger != buyers.Exchanger {
-		return "", xerrors.New("buyer`s exchanger and seller`s exchanger and transaction`s exchanger aren`t same")
+	if !tools.SameAddress(seller2s.Exchanger, buyers.Exchanger) {
+		return "", fmt.Errorf("%w: buyer`s exchanger and seller`s exchanger and transaction`s exchanger aren`t same", ErrUnauthorizedExchange)
 	}
 
 	var exchangerAuths types2.ExchangerAuth
@@ -1373,12 +1630,12 @@ func (worm *Wormholes) FoundryExchangeInitiated(buyer, seller2, exchangerAuth []
 		return "", xerrors.New("the formate of exchangerAuthor is wrong")
 	}
 
-	err = tools.CheckHex("exchangeAuths.BlockNumber", exchangerAuths.BlockNumber)
+	err = exchangerAuths.Validate()
 	if err != nil {
 		return "", err
 	}
 
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
+	account, fromKey, err := worm.signingKey()
 	if err != nil {
 		log.Println("FoundryExchangeInitiated() priKeyToAddress err ", err)
 		return "", err
@@ -1386,11 +1643,17 @@ func (worm *Wormholes) FoundryExchangeInitiated(buyer, seller2, exchangerAuth []
 
 	toAddr := common.HexToAddress(to)
 
-	ctx := context.Background()
-
+	unlock, err := worm.lockSender(ctx, account)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
 	nonce, err := worm.PendingNonceAt(ctx, account)
+	if err != nil {
+		log.Println("FoundryExchangeInitiated() pendingNonceAt err ", err)
+		return "", err
+	}
 
-	gasLimit := uint64(170000)
 	gasPrice, err := worm.SuggestGasPrice(ctx)
 	if err != nil {
 		log.Println("FoundryExchangeInitiated() suggestGasPrice err ", err)
@@ -1413,7 +1676,11 @@ func (worm *Wormholes) FoundryExchangeInitiated(buyer, seller2, exchangerAuth []
 	tx_data := append([]byte(TranPrefix), data...)
 	fmt.Println(string(tx_data))
 
-	value, _ := hexutil.DecodeBig(buyers.Amount)
+	value, _ := tools.DecodeAmount(buyers.Amount)
+	gasLimit, err := worm.gasLimit(ctx, types2.FoundryExchangeInitiated, account, toAddr, value, tx_data)
+	if err != nil {
+		return "", err
+	}
 	tx := types.NewTransaction(nonce, toAddr, value, gasLimit, gasPrice, tx_data)
 	chainID, err := worm.NetworkID(ctx)
 	if err != nil {
@@ -1421,7 +1688,7 @@ func (worm *Wormholes) FoundryExchangeInitiated(buyer, seller2, exchangerAuth []
 		return "", err
 	}
 	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
+	signedTx, err := worm.signTx(tx, chainID, fromKey)
 	if err != nil {
 		log.Println("FoundryExchangeInitiated() signTx err ", err)
 		return "", err
@@ -1443,6 +1710,12 @@ func (worm *Wormholes) FoundryExchangeInitiated(buyer, seller2, exchangerAuth []
 //	seller1: {"price":"0xde0b6b3a7640000","worm_address":"0x0000000000000000000000000000000000000002","exchanger":"0x5051B76579BC966A9480dd6E72B39A4C89c1154C","block_number":"0x113","sig":"0x1c8559524220b49e6b9548be405331228d8f26ced8ce12e81b672443fe28067327eef62ce2b3826e2e9ec10f8b2cf5d8a2b2519a0e95f288ea3f098fdea6ab6b1c"}
 //	to:      "0xe61e5Bbe724B8F449B5C7BB4a09F99A057253eB4",		Buyer's address
 func (worm *Wormholes) NFTDoesNotAuthorizeExchanges(buyer, seller1 []byte, to string) (string, error) {
+	return worm.NFTDoesNotAuthorizeExchangesCtx(context.Background(), buyer, seller1, to)
+}
+
+// NFTDoesNotAuthorizeExchangesCtx is NFTDoesNotAuthorizeExchanges with a context for the requests to the node.
+func (worm *Wormholes) NFTDoesNotAuthorizeExchangesCtx(ctx context.Context, buyer, seller1 []byte, to string) (string, error) {
+	to = worm.resolve(ctx, to)
 	err := tools.CheckAddress("FtDoesNotAuthorizeExchanges() to", to)
 	if err != nil {
 		return "", err
@@ -1453,7 +1726,7 @@ func (worm *Wormholes) NFTDoesNotAuthorizeExchanges(buyer, seller1 []byte, to st
 		return "", xerrors.New("the formate of buyer is wrong")
 	}
 
-	err = tools.CheckHex("buyers.BlockNumber", buyers.BlockNumber)
+	err = buyers.Validate()
 	if err != nil {
 		return "", err
 	}
@@ -1464,19 +1737,19 @@ func (worm *Wormholes) NFTDoesNotAuthorizeExchanges(buyer, seller1 []byte, to st
 		return "", xerrors.New("the formate of buyer is wrong")
 	}
 
-	err = tools.CheckHex("seller1s.BlockNumber", seller1s.BlockNumber)
+	err = seller1s.Validate()
 	if err != nil {
 		return "", err
 	}
 
-	if buyers.Amount < seller1s.Amount {
+	if hexLess(buyers.Amount, seller1s.Amount) {
 		return "", xerrors.New("buyer`s amount must be greater then seller`s amount")
 	}
-	if seller1s.Exchanger != buyers.Exchanger {
-		return "", xerrors.New("buyer`s exchanger and seller`s exchanger and transaction`s exchanger aren`t same")
+	if !tools.SameAddress(seller1s.Exchanger, buyers.Exchanger) {
+		return "", fmt.Errorf("%w: buyer`s exchanger and seller`s exchanger and transaction`s exchanger aren`t same", ErrUnauthorizedExchange)
 	}
 
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
+	account, fromKey, err := worm.signingKey()
 	if err != nil {
 		log.Println("FtDoesNotAuthorizeExchanges() priKeyToAddress err ", err)
 		return "", err
@@ -1484,10 +1757,17 @@ func (worm *Wormholes) NFTDoesNotAuthorizeExchanges(buyer, seller1 []byte, to st
 
 	toAddr := common.HexToAddress(to)
 
-	ctx := context.Background()
+	unlock, err := worm.lockSender(ctx, account)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
 	nonce, err := worm.PendingNonceAt(ctx, account)
+	if err != nil {
+		log.Println("FtDoesNotAuthorizeExchanges() pendingNonceAt err ", err)
+		return "", err
+	}
 
-	gasLimit := uint64(130000)
 	gasPrice, err := worm.SuggestGasPrice(ctx)
 	if err != nil {
 		log.Println("FtDoesNotAuthorizeExchanges() suggestGasPrice err ", err)
@@ -1509,7 +1789,11 @@ func (worm *Wormholes) NFTDoesNotAuthorizeExchanges(buyer, seller1 []byte, to st
 	tx_data := append([]byte(TranPrefix), data...)
 	fmt.Println(string(tx_data))
 
-	value, _ := hexutil.DecodeBig(buyers.Amount)
+	value, _ := tools.DecodeAmount(buyers.Amount)
+	gasLimit, err := worm.gasLimit(ctx, types2.FtDoesNotAuthorizeExchanges, account, toAddr, value, tx_data)
+	if err != nil {
+		return "", err
+	}
 	tx := types.NewTransaction(nonce, toAddr, value, gasLimit, gasPrice, tx_data)
 	chainID, err := worm.NetworkID(ctx)
 	if err != nil {
@@ -1517,7 +1801,7 @@ func (worm *Wormholes) NFTDoesNotAuthorizeExchanges(buyer, seller1 []byte, to st
 		return "", err
 	}
 	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
+	signedTx, err := worm.signTx(tx, chainID, fromKey)
 	if err != nil {
 		log.Println("FtDoesNotAuthorizeExchanges() signTx err ", err)
 		return "", err
@@ -1535,18 +1819,35 @@ func (worm *Wormholes) NFTDoesNotAuthorizeExchanges(buyer, seller1 []byte, to st
 //	The amount used by the exchange to increase the pledged ERB
 //
 //	Parameter Description
-//	value:  100,		Append amount, format is hex string
+//	value:  100,		Append amount in wei, an int64 holds at most about 9.2 ERB, pass larger
+//	amounts to AdditionalPledgeAmountCtx as types.Wei of a big.Int
 func (worm *Wormholes) AdditionalPledgeAmount(value int64) (string, error) {
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
+	return worm.AdditionalPledgeAmountCtx(context.Background(), types2.Wei(big.NewInt(value)))
+}
+
+// AdditionalPledgeAmountCtx is AdditionalPledgeAmount with a context for the requests to the node and
+// the amount as a types.Amount, of any size but not negative.
+func (worm *Wormholes) AdditionalPledgeAmountCtx(ctx context.Context, value types2.Amount) (string, error) {
+	if err := checkAmount("AdditionalPledgeAmount() value", value); err != nil {
+		return "", err
+	}
+	account, fromKey, err := worm.signingKey()
 	if err != nil {
 		log.Println("AdditionalPledgeAmount() priKeyToAddress err ", err)
 		return "", err
 	}
 
-	ctx := context.Background()
+	unlock, err := worm.lockSender(ctx, account)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
 	nonce, err := worm.PendingNonceAt(ctx, account)
+	if err != nil {
+		log.Println("AdditionalPledgeAmount() pendingNonceAt err ", err)
+		return "", err
+	}
 
-	gasLimit := uint64(55000)
 	gasPrice, err := worm.SuggestGasPrice(ctx)
 	if err != nil {
 		log.Println("AdditionalPledgeAmount() suggestGasPrice err ", err)
@@ -1566,15 +1867,18 @@ func (worm *Wormholes) AdditionalPledgeAmount(value int64) (string, error) {
 	tx_data := append([]byte(TranPrefix), data...)
 	fmt.Println(string(tx_data))
 
-	additional := big.NewInt(value)
-	tx := types.NewTransaction(nonce, account, additional, gasLimit, gasPrice, tx_data)
+	gasLimit, err := worm.gasLimit(ctx, types2.AdditionalPledgeAmount, account, account, value.Wei(), tx_data)
+	if err != nil {
+		return "", err
+	}
+	tx := types.NewTransaction(nonce, account, value.Wei(), gasLimit, gasPrice, tx_data)
 	chainID, err := worm.NetworkID(ctx)
 	if err != nil {
 		log.Println("AdditionalPledgeAmount() networkID err=", err)
 		return "", err
 	}
 	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
+	signedTx, err := worm.signTx(tx, chainID, fromKey)
 	if err != nil {
 		log.Println("AdditionalPledgeAmount() signTx err ", err)
 		return "", err
@@ -1592,18 +1896,35 @@ func (worm *Wormholes) AdditionalPledgeAmount(value int64) (string, error) {
 //	Amount used for exchanges to reduce the amount of staked ERB
 //
 //	Parameter Description
-//	value:  100,		Amount to decrease, format is hexadecimal string
+//	value:  100,		Amount to decrease in wei, an int64 holds at most about 9.2 ERB, pass larger
+//	amounts to RevokesPledgeAmountCtx as types.Wei of a big.Int
 func (worm *Wormholes) RevokesPledgeAmount(value int64) (string, error) {
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
+	return worm.RevokesPledgeAmountCtx(context.Background(), types2.Wei(big.NewInt(value)))
+}
+
+// RevokesPledgeAmountCtx is RevokesPledgeAmount with a context for the requests to the node and
+// the amount as a types.Amount, of any size but not negative.
+func (worm *Wormholes) RevokesPledgeAmountCtx(ctx context.Context, value types2.Amount) (string, error) {
+	if err := checkAmount("RevokesPledgeAmount() value", value); err != nil {
+		return "", err
+	}
+	account, fromKey, err := worm.signingKey()
 	if err != nil {
 		log.Println("RevokesPledgeAmount() priKeyToAddress err ", err)
 		return "", err
 	}
 
-	ctx := context.Background()
+	unlock, err := worm.lockSender(ctx, account)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
 	nonce, err := worm.PendingNonceAt(ctx, account)
+	if err != nil {
+		log.Println("RevokesPledgeAmount() pendingNonceAt err ", err)
+		return "", err
+	}
 
-	gasLimit := uint64(55000)
 	gasPrice, err := worm.SuggestGasPrice(ctx)
 	if err != nil {
 		log.Println("RevokesPledgeAmount() suggestGasPrice err ", err)
@@ -1623,15 +1944,18 @@ func (worm *Wormholes) RevokesPledgeAmount(value int64) (string, error) {
 	tx_data := append([]byte(TranPrefix), data...)
 	fmt.Println(string(tx_data))
 
-	revokes := big.NewInt(value)
-	tx := types.NewTransaction(nonce, account, revokes, gasLimit, gasPrice, tx_data)
+	gasLimit, err := worm.gasLimit(ctx, types2.RevokesPledgeAmount, account, account, value.Wei(), tx_data)
+	if err != nil {
+		return "", err
+	}
+	tx := types.NewTransaction(nonce, account, value.Wei(), gasLimit, gasPrice, tx_data)
 	chainID, err := worm.NetworkID(ctx)
 	if err != nil {
 		log.Println("RevokesPledgeAmount() networkID err=", err)
 		return "", err
 	}
 	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
+	signedTx, err := worm.signTx(tx, chainID, fromKey)
 	if err != nil {
 		log.Println("RevokesPledgeAmount() signTx err ", err)
 		return "", err
@@ -1652,23 +1976,44 @@ func (worm *Wormholes) RevokesPledgeAmount(value int64) (string, error) {
 //	dir:        "wormholes",  													The path address where sworm is located, the format is a string
 //	startIndex: "0x640001",	 														The start number of the sworm fragment, formatted as a hexadecimal string
 //	number:     6553600,														The number of sworm shards injected, formatted as a decimal string
-//	royalty:    20,																			Royalty, formatted as an integer
+//	royalty:    20,																			Royalty in basis points, at most 10000
 //	creator:    "0xab7624f47fd7dadb6b8e255d06a2f10af55990fe",	creator, format is a hex string
 func (worm *Wormholes) VoteOfficialNFT(dir, startIndex string, number uint64, royalty uint32, creator string) (string, error) {
+	return worm.VoteOfficialNFTCtx(context.Background(), dir, startIndex, number, royalty, creator)
+}
+
+// VoteOfficialNFTCtx is VoteOfficialNFT with a context for the requests to the node.
+func (worm *Wormholes) VoteOfficialNFTCtx(ctx context.Context, dir, startIndex string, number uint64, royalty uint32, creator string) (string, error) {
+	creator = worm.resolve(ctx, creator)
 	err := tools.CheckAddress("VoteOfficialNFT() creator", creator)
 	if err != nil {
 		return "", err
 	}
-	ctx := context.Background()
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
+	err = tools.CheckHex("VoteOfficialNFT() startIndex", startIndex)
+	if err != nil {
+		return "", err
+	}
+	err = tools.CheckRoyalty("VoteOfficialNFT() royalty", uint64(royalty))
+	if err != nil {
+		return "", err
+	}
+	account, fromKey, err := worm.signingKey()
 	if err != nil {
 		log.Println("VoteOfficialNFT() priKeyToAddress err ", err)
 		return "", err
 	}
 
+	unlock, err := worm.lockSender(ctx, account)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
 	nonce, err := worm.PendingNonceAt(ctx, account)
+	if err != nil {
+		log.Println("VoteOfficialNFT() pendingNonceAt err ", err)
+		return "", err
+	}
 
-	gasLimit := uint64(60000)
 	gasPrice, err := worm.SuggestGasPrice(ctx)
 	if err != nil {
 		log.Println("VoteOfficialNFT() suggestGasPrice err ", err)
@@ -1694,6 +2039,10 @@ func (worm *Wormholes) VoteOfficialNFT(dir, startIndex string, number uint64, ro
 	tx_data := append([]byte(TranPrefix), data...)
 	fmt.Println(string(tx_data))
 
+	gasLimit, err := worm.gasLimit(ctx, types2.VoteOfficialNFT, account, account, nil, tx_data)
+	if err != nil {
+		return "", err
+	}
 	tx := types.NewTransaction(nonce, account, big.NewInt(0), gasLimit, gasPrice, tx_data)
 	chainID, err := worm.NetworkID(ctx)
 	if err != nil {
@@ -1701,7 +2050,7 @@ func (worm *Wormholes) VoteOfficialNFT(dir, startIndex string, number uint64, ro
 		return "", err
 	}
 	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
+	signedTx, err := worm.signTx(tx, chainID, fromKey)
 	if err != nil {
 		log.Println("VoteOfficialNFT() signTx err ", err)
 		return "", err
@@ -1722,14 +2071,28 @@ func (worm *Wormholes) VoteOfficialNFT(dir, startIndex string, number uint64, ro
 //		dir:        "wormholes",  													The path address where sworm is located, the format is a string
 //		startIndex: "0x640001",	 														The start number of the sworm fragment, formatted as a hexadecimal string
 //		number:     6553600,														The number of sworm shards injected, formatted as a decimal string
-//		royalty:    20,																			Royalty, formatted as an integer
+//		royalty:    20,																			Royalty in basis points, at most 10000
 //	 exchanger:	{"exchanger_owner":"0x83c43f6F7bB4d8E429b21FF303a16b4c99A59b05","to":"0xB685EB3226d5F0D549607D2cC18672b756fd090c","block_number":"0x0","sig":"0xae18a165e51e322d04d2862b6e2760d0493b58870f9afe3c6d15b6e44145c293075662043611501c89d3e4b299a21fe1f8581def86cce4dd43b20c47960ac2481c"}
 //		creator:    "0xab7624f47fd7dadb6b8e255d06a2f10af55990fe",	creator, format is a hex string
 func (worm *Wormholes) VoteOfficialNFTByApprovedExchanger(dir, startIndex string, number uint64, royalty uint32, creator string, exchangerAuth []byte) (string, error) {
+	return worm.VoteOfficialNFTByApprovedExchangerCtx(context.Background(), dir, startIndex, number, royalty, creator, exchangerAuth)
+}
+
+// VoteOfficialNFTByApprovedExchangerCtx is VoteOfficialNFTByApprovedExchanger with a context for the requests to the node.
+func (worm *Wormholes) VoteOfficialNFTByApprovedExchangerCtx(ctx context.Context, dir, startIndex string, number uint64, royalty uint32, creator string, exchangerAuth []byte) (string, error) {
+	creator = worm.resolve(ctx, creator)
 	err := tools.CheckAddress("VoteOfficialNFTByApprovedExchanger() creator", creator)
 	if err != nil {
 		return "", err
 	}
+	err = tools.CheckHex("VoteOfficialNFTByApprovedExchanger() startIndex", startIndex)
+	if err != nil {
+		return "", err
+	}
+	err = tools.CheckRoyalty("VoteOfficialNFTByApprovedExchanger() royalty", uint64(royalty))
+	if err != nil {
+		return "", err
+	}
 
 	var exchangeAuths types2.ExchangerAuth
 	err = json.Unmarshal(exchangerAuth, &exchangeAuths)
@@ -1737,16 +2100,28 @@ func (worm *Wormholes) VoteOfficialNFTByApprovedExchanger(dir, startIndex string
 		return "", xerrors.New("the formate of exchangerAuth is wrong")
 	}
 
-	ctx := context.Background()
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
+	err = exchangeAuths.Validate()
+	if err != nil {
+		return "", err
+	}
+
+	account, fromKey, err := worm.signingKey()
 	if err != nil {
 		log.Println("VoteOfficialNFTByApprovedExchanger() priKeyToAddress err ", err)
 		return "", err
 	}
 
+	unlock, err := worm.lockSender(ctx, account)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
 	nonce, err := worm.PendingNonceAt(ctx, account)
+	if err != nil {
+		log.Println("VoteOfficialNFTByApprovedExchanger() pendingNonceAt err ", err)
+		return "", err
+	}
 
-	gasLimit := uint64(60000)
 	gasPrice, err := worm.SuggestGasPrice(ctx)
 	if err != nil {
 		log.Println("VoteOfficialNFTByApprovedExchanger() suggestGasPrice err ", err)
@@ -1773,6 +2148,10 @@ func (worm *Wormholes) VoteOfficialNFTByApprovedExchanger(dir, startIndex string
 	tx_data := append([]byte(TranPrefix), data...)
 	fmt.Println(string(tx_data))
 
+	gasLimit, err := worm.gasLimit(ctx, types2.VoteOfficialNFTByApprovedExchanger, account, account, nil, tx_data)
+	if err != nil {
+		return "", err
+	}
 	tx := types.NewTransaction(nonce, account, big.NewInt(0), gasLimit, gasPrice, tx_data)
 	chainID, err := worm.NetworkID(ctx)
 	if err != nil {
@@ -1780,7 +2159,7 @@ func (worm *Wormholes) VoteOfficialNFTByApprovedExchanger(dir, startIndex string
 		return "", err
 	}
 	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
+	signedTx, err := worm.signTx(tx, chainID, fromKey)
 	if err != nil {
 		log.Println("VoteOfficialNFTByApprovedExchanger() signTx err ", err)
 		return "", err
@@ -1793,20 +2172,32 @@ func (worm *Wormholes) VoteOfficialNFTByApprovedExchanger(dir, startIndex string
 	return strings.ToLower(signedTx.Hash().String()), nil
 }
 
-// UnforzenAccount
+// UnfreezeAccount
 //
 //	change revenue model
-func (worm *Wormholes) UnforzenAccount() (string, error) {
-	ctx := context.Background()
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
+func (worm *Wormholes) UnfreezeAccount() (string, error) {
+	return worm.UnfreezeAccountCtx(context.Background())
+}
+
+// UnfreezeAccountCtx is UnfreezeAccount with a context for the requests to the node.
+func (worm *Wormholes) UnfreezeAccountCtx(ctx context.Context) (string, error) {
+	account, fromKey, err := worm.signingKey()
 	if err != nil {
 		log.Println("VoteOfficialNFTByApprovedExchanger() priKeyToAddress err ", err)
 		return "", err
 	}
 
+	unlock, err := worm.lockSender(ctx, account)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
 	nonce, err := worm.PendingNonceAt(ctx, account)
+	if err != nil {
+		log.Println("UnfreezeAccount() pendingNonceAt err ", err)
+		return "", err
+	}
 
-	gasLimit := uint64(50000)
 	gasPrice, err := worm.SuggestGasPrice(ctx)
 	if err != nil {
 		log.Println("ASuggestGasPrice err ", err)
@@ -1814,7 +2205,7 @@ func (worm *Wormholes) UnforzenAccount() (string, error) {
 	}
 
 	transaction := types2.Transaction{
-		Type:    types2.UnforzenAccount,
+		Type:    types2.UnfreezeAccount,
 		Version: types2.WormHolesVersion,
 	}
 
@@ -1827,6 +2218,10 @@ func (worm *Wormholes) UnforzenAccount() (string, error) {
 	tx_data := append([]byte(TranPrefix), data...)
 	fmt.Println(string(tx_data))
 
+	gasLimit, err := worm.gasLimit(ctx, types2.UnfreezeAccount, account, account, nil, tx_data)
+	if err != nil {
+		return "", err
+	}
 	tx := types.NewTransaction(nonce, account, nil, gasLimit, gasPrice, tx_data)
 	chainID, err := worm.NetworkID(ctx)
 	if err != nil {
@@ -1834,7 +2229,7 @@ func (worm *Wormholes) UnforzenAccount() (string, error) {
 		return "", err
 	}
 	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
+	signedTx, err := worm.signTx(tx, chainID, fromKey)
 	if err != nil {
 		log.Println("VoteOfficialNFTByApprovedExchanger() signTx err ", err)
 		return "", err
@@ -1851,16 +2246,28 @@ func (worm *Wormholes) UnforzenAccount() (string, error) {
 //
 // When the user's weight is lower than 70, this transaction can be sent to restore the weight
 func (worm *Wormholes) WeightRedemption() (string, error) {
-	ctx := context.Background()
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
+	return worm.WeightRedemptionCtx(context.Background())
+}
+
+// WeightRedemptionCtx is WeightRedemption with a context for the requests to the node.
+func (worm *Wormholes) WeightRedemptionCtx(ctx context.Context) (string, error) {
+	account, fromKey, err := worm.signingKey()
 	if err != nil {
 		log.Println("WeightRedemption() priKeyToAddress err ", err)
 		return "", err
 	}
 
+	unlock, err := worm.lockSender(ctx, account)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
 	nonce, err := worm.PendingNonceAt(ctx, account)
+	if err != nil {
+		log.Println("WeightRedemption() pendingNonceAt err ", err)
+		return "", err
+	}
 
-	gasLimit := uint64(50000)
 	gasPrice, err := worm.SuggestGasPrice(ctx)
 	if err != nil {
 		log.Println("ASuggestGasPrice err ", err)
@@ -1881,6 +2288,10 @@ func (worm *Wormholes) WeightRedemption() (string, error) {
 	tx_data := append([]byte(TranPrefix), data...)
 	fmt.Println(string(tx_data))
 
+	gasLimit, err := worm.gasLimit(ctx, types2.WeightRedemption, account, account, nil, tx_data)
+	if err != nil {
+		return "", err
+	}
 	tx := types.NewTransaction(nonce, account, nil, gasLimit, gasPrice, tx_data)
 	chainID, err := worm.NetworkID(ctx)
 	if err != nil {
@@ -1888,7 +2299,7 @@ func (worm *Wormholes) WeightRedemption() (string, error) {
 		return "", err
 	}
 	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
+	signedTx, err := worm.signTx(tx, chainID, fromKey)
 	if err != nil {
 		log.Println("WeightRedemption() signTx err ", err)
 		return "", err
@@ -1905,6 +2316,12 @@ func (worm *Wormholes) WeightRedemption() (string, error) {
 //
 // Batch buying and selling of minted NFT or S-Nft
 func (worm *Wormholes) BatchSellTransfer(buyer, seller, buyerAuth, sellerAuth, exchangerAuth []byte, to string) (string, error) {
+	return worm.BatchSellTransferCtx(context.Background(), buyer, seller, buyerAuth, sellerAuth, exchangerAuth, to)
+}
+
+// BatchSellTransferCtx is BatchSellTransfer with a context for the requests to the node.
+func (worm *Wormholes) BatchSellTransferCtx(ctx context.Context, buyer, seller, buyerAuth, sellerAuth, exchangerAuth []byte, to string) (string, error) {
+	to = worm.resolve(ctx, to)
 	err := tools.CheckAddress("BatchSellTransfer() to", to)
 	if err != nil {
 		return "", err
@@ -1916,7 +2333,8 @@ func (worm *Wormholes) BatchSellTransfer(buyer, seller, buyerAuth, sellerAuth, e
 	if err != nil {
 		return "", xerrors.New("the formate of buyer is wrong")
 	}
-	err = tools.CheckHex("buyers.BlockNumber", buyers.BlockNumber)
+
+	err = buyers.Validate()
 	if err != nil {
 		return "", err
 	}
@@ -1926,7 +2344,8 @@ func (worm *Wormholes) BatchSellTransfer(buyer, seller, buyerAuth, sellerAuth, e
 	if err != nil {
 		return "", xerrors.New("the formate of sellers is wrong")
 	}
-	err = tools.CheckHex("sellers.BlockNumber", sellers.BlockNumber)
+
+	err = sellers.Validate()
 	if err != nil {
 		return "", err
 	}
@@ -1936,7 +2355,8 @@ func (worm *Wormholes) BatchSellTransfer(buyer, seller, buyerAuth, sellerAuth, e
 	if err != nil {
 		return "", xerrors.New("the formate of buyerAuths is wrong")
 	}
-	err = tools.CheckHex("buyerAuths.BlockNumber", buyerAuths.BlockNumber)
+
+	err = buyerAuths.Validate()
 	if err != nil {
 		return "", err
 	}
@@ -1946,7 +2366,8 @@ func (worm *Wormholes) BatchSellTransfer(buyer, seller, buyerAuth, sellerAuth, e
 	if err != nil {
 		return "", xerrors.New("the formate of sellerAuths is wrong")
 	}
-	err = tools.CheckHex("sellerAuths.BlockNumber", sellerAuths.BlockNumber)
+
+	err = sellerAuths.Validate()
 	if err != nil {
 		return "", err
 	}
@@ -1956,21 +2377,29 @@ func (worm *Wormholes) BatchSellTransfer(buyer, seller, buyerAuth, sellerAuth, e
 	if err != nil {
 		return "", xerrors.New("BatchSellTransfer() the formate of exchangerAuth is wrong")
 	}
-	err = tools.CheckHex("exchangeAuths.BlockNumber", exchangeAuths.BlockNumber)
+
+	err = exchangeAuths.Validate()
 	if err != nil {
 		return "", err
 	}
 
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
+	account, fromKey, err := worm.signingKey()
 	if err != nil {
 		log.Println("BatchSellTransfer() priKeyToAddress err ", err)
 		return "", err
 	}
 
-	ctx := context.Background()
+	unlock, err := worm.lockSender(ctx, account)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
 	nonce, err := worm.PendingNonceAt(ctx, account)
+	if err != nil {
+		log.Println("BatchSellTransfer() pendingNonceAt err ", err)
+		return "", err
+	}
 
-	gasLimit := uint64(200000)
 	gasPrice, err := worm.SuggestGasPrice(ctx)
 	if err != nil {
 		log.Println("BatchSellTransfer() suggestGasPrice err ", err)
@@ -1995,7 +2424,11 @@ func (worm *Wormholes) BatchSellTransfer(buyer, seller, buyerAuth, sellerAuth, e
 	tx_data := append([]byte(TranPrefix), data...)
 	fmt.Println(string(tx_data))
 
-	value, _ := hexutil.DecodeBig(buyers.Amount)
+	value, _ := tools.DecodeAmount(buyers.Amount)
+	gasLimit, err := worm.gasLimit(ctx, types2.BatchSellTransfer, account, toAddr, value, tx_data)
+	if err != nil {
+		return "", err
+	}
 	tx := types.NewTransaction(nonce, toAddr, value, gasLimit, gasPrice, tx_data)
 	chainID, err := worm.NetworkID(ctx)
 	if err != nil {
@@ -2003,7 +2436,7 @@ func (worm *Wormholes) BatchSellTransfer(buyer, seller, buyerAuth, sellerAuth, e
 		return "", err
 	}
 	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
+	signedTx, err := worm.signTx(tx, chainID, fromKey)
 	if err != nil {
 		log.Println("BatchSellTransfer signTx err ", err)
 		return "", err
@@ -2020,6 +2453,12 @@ func (worm *Wormholes) BatchSellTransfer(buyer, seller, buyerAuth, sellerAuth, e
 //
 // Compulsory purchase of S-Nft
 func (worm *Wormholes) ForceBuyingTransfer(buyer, buyerAuth, exchangerAuth []byte, to string) (string, error) {
+	return worm.ForceBuyingTransferCtx(context.Background(), buyer, buyerAuth, exchangerAuth, to)
+}
+
+// ForceBuyingTransferCtx is ForceBuyingTransfer with a context for the requests to the node.
+func (worm *Wormholes) ForceBuyingTransferCtx(ctx context.Context, buyer, buyerAuth, exchangerAuth []byte, to string) (string, error) {
+	to = worm.resolve(ctx, to)
 	err := tools.CheckAddress("ForceBuyingTransfer() to", to)
 	if err != nil {
 		return "", err
@@ -2031,7 +2470,8 @@ func (worm *Wormholes) ForceBuyingTransfer(buyer, buyerAuth, exchangerAuth []byt
 	if err != nil {
 		return "", xerrors.New("the formate of buyer is wrong")
 	}
-	err = tools.CheckHex("buyers.BlockNumber", buyers.BlockNumber)
+
+	err = buyers.Validate()
 	if err != nil {
 		return "", err
 	}
@@ -2041,7 +2481,8 @@ func (worm *Wormholes) ForceBuyingTransfer(buyer, buyerAuth, exchangerAuth []byt
 	if err != nil {
 		return "", xerrors.New("the formate of buyerAuths is wrong")
 	}
-	err = tools.CheckHex("buyerAuths.BlockNumber", buyerAuths.BlockNumber)
+
+	err = buyerAuths.Validate()
 	if err != nil {
 		return "", err
 	}
@@ -2051,21 +2492,29 @@ func (worm *Wormholes) ForceBuyingTransfer(buyer, buyerAuth, exchangerAuth []byt
 	if err != nil {
 		return "", xerrors.New("ForceBuyingTransfer() the formate of exchangerAuth is wrong")
 	}
-	err = tools.CheckHex("exchangeAuths.BlockNumber", exchangeAuths.BlockNumber)
+
+	err = exchangeAuths.Validate()
 	if err != nil {
 		return "", err
 	}
 
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
+	account, fromKey, err := worm.signingKey()
 	if err != nil {
 		log.Println("ForceBuyingTransfer() priKeyToAddress err ", err)
 		return "", err
 	}
 
-	ctx := context.Background()
+	unlock, err := worm.lockSender(ctx, account)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
 	nonce, err := worm.PendingNonceAt(ctx, account)
+	if err != nil {
+		log.Println("ForceBuyingTransfer() pendingNonceAt err ", err)
+		return "", err
+	}
 
-	gasLimit := uint64(200000)
 	gasPrice, err := worm.SuggestGasPrice(ctx)
 	if err != nil {
 		log.Println("ForceBuyingTransfer() suggestGasPrice err ", err)
@@ -2088,7 +2537,11 @@ func (worm *Wormholes) ForceBuyingTransfer(buyer, buyerAuth, exchangerAuth []byt
 	tx_data := append([]byte(TranPrefix), data...)
 	fmt.Println(string(tx_data))
 
-	value, _ := hexutil.DecodeBig(buyers.Amount)
+	value, _ := tools.DecodeAmount(buyers.Amount)
+	gasLimit, err := worm.gasLimit(ctx, types2.ForceBuyingTransfer, account, toAddr, value, tx_data)
+	if err != nil {
+		return "", err
+	}
 	tx := types.NewTransaction(nonce, toAddr, value, gasLimit, gasPrice, tx_data)
 	chainID, err := worm.NetworkID(ctx)
 	if err != nil {
@@ -2096,7 +2549,7 @@ func (worm *Wormholes) ForceBuyingTransfer(buyer, buyerAuth, exchangerAuth []byt
 		return "", err
 	}
 	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
+	signedTx, err := worm.signTx(tx, chainID, fromKey)
 	if err != nil {
 		log.Println("ForceBuyingTransfer signTx err ", err)
 		return "", err
@@ -2113,16 +2566,28 @@ func (worm *Wormholes) ForceBuyingTransfer(buyer, buyerAuth, exchangerAuth []byt
 //
 // Addresses with L3 can initiate this transaction to withdraw ERB
 func (worm *Wormholes) ExtractERB() (string, error) {
-	ctx := context.Background()
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
+	return worm.ExtractERBCtx(context.Background())
+}
+
+// ExtractERBCtx is ExtractERB with a context for the requests to the node.
+func (worm *Wormholes) ExtractERBCtx(ctx context.Context) (string, error) {
+	account, fromKey, err := worm.signingKey()
 	if err != nil {
 		log.Println("ExtractERB() priKeyToAddress err ", err)
 		return "", err
 	}
 
+	unlock, err := worm.lockSender(ctx, account)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
 	nonce, err := worm.PendingNonceAt(ctx, account)
+	if err != nil {
+		log.Println("ExtractERB() pendingNonceAt err ", err)
+		return "", err
+	}
 
-	gasLimit := uint64(50000)
 	gasPrice, err := worm.SuggestGasPrice(ctx)
 	if err != nil {
 		log.Println("ASuggestGasPrice err ", err)
@@ -2143,6 +2608,10 @@ func (worm *Wormholes) ExtractERB() (string, error) {
 	tx_data := append([]byte(TranPrefix), data...)
 	fmt.Println(string(tx_data))
 
+	gasLimit, err := worm.gasLimit(ctx, types2.ExtractERB, account, account, nil, tx_data)
+	if err != nil {
+		return "", err
+	}
 	tx := types.NewTransaction(nonce, account, nil, gasLimit, gasPrice, tx_data)
 	chainID, err := worm.NetworkID(ctx)
 	if err != nil {
@@ -2150,7 +2619,7 @@ func (worm *Wormholes) ExtractERB() (string, error) {
 		return "", err
 	}
 	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
+	signedTx, err := worm.signTx(tx, chainID, fromKey)
 	if err != nil {
 		log.Println("ExtractERB() signTx err ", err)
 		return "", err
@@ -2168,16 +2637,32 @@ func (worm *Wormholes) ExtractERB() (string, error) {
 // Parameter Description
 // proxyAddress:		0xe61e5Bbe724B8F449B5C7BB4a09F99A057253eB4
 func (worm *Wormholes) AccountDelegate(proxySign []byte, proxyAddress string) (string, error) {
-	ctx := context.Background()
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
+	return worm.AccountDelegateCtx(context.Background(), proxySign, proxyAddress)
+}
+
+// AccountDelegateCtx is AccountDelegate with a context for the requests to the node.
+func (worm *Wormholes) AccountDelegateCtx(ctx context.Context, proxySign []byte, proxyAddress string) (string, error) {
+	proxyAddress = worm.resolve(ctx, proxyAddress)
+	if err := tools.CheckAddress("AccountDelegate() proxyAddress", proxyAddress); err != nil {
+		return "", err
+	}
+	account, fromKey, err := worm.signingKey()
 	if err != nil {
 		log.Println("AccountDelegate() priKeyToAddress err ", err)
 		return "", err
 	}
 
+	unlock, err := worm.lockSender(ctx, account)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
 	nonce, err := worm.PendingNonceAt(ctx, account)
+	if err != nil {
+		log.Println("AccountDelegate() pendingNonceAt err ", err)
+		return "", err
+	}
 
-	gasLimit := uint64(70000)
 	gasPrice, err := worm.SuggestGasPrice(ctx)
 	if err != nil {
 		log.Println("AccountDelegate() suggestGasPrice err ", err)
@@ -2200,6 +2685,10 @@ func (worm *Wormholes) AccountDelegate(proxySign []byte, proxyAddress string) (s
 	tx_data := append([]byte(TranPrefix), data...)
 	fmt.Println(string(tx_data))
 
+	gasLimit, err := worm.gasLimit(ctx, types2.AccountDelegate, account, account, nil, tx_data)
+	if err != nil {
+		return "", err
+	}
 	tx := types.NewTransaction(nonce, account, big.NewInt(0), gasLimit, gasPrice, tx_data)
 	chainID, err := worm.NetworkID(ctx)
 	if err != nil {
@@ -2207,7 +2696,7 @@ func (worm *Wormholes) AccountDelegate(proxySign []byte, proxyAddress string) (s
 		return "", err
 	}
 	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
+	signedTx, err := worm.signTx(tx, chainID, fromKey)
 	if err != nil {
 		log.Println("AccountDelegate() signTx err ", err)
 		return "", err
@@ -2219,5 +2708,3 @@ func (worm *Wormholes) AccountDelegate(proxySign []byte, proxyAddress string) (s
 	}
 	return strings.ToLower(signedTx.Hash().String()), nil
 }
-
-var _ APIs = &Wormholes{}
@@ -0,0 +1,82 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/erbieio/erb-client/v2/tools"
+	"github.com/ethereum/go-ethereum/rpc"
+	"golang.org/x/xerrors"
+)
+
+const (
+	defaultMaxIdleConns    = 100
+	defaultIdleConnTimeout = 90 * time.Second
+)
+
+// TransportConfig tunes the HTTP connections to the node of a client created with
+// DialWithTransport. Zero values select the defaults. net/http keeps only 2 idle connections
+// per host by default, so a burst of parallel requests opens connections that are closed right
+// after, exhausting the ephemeral ports and handshaking TLS again on every burst.
+type TransportConfig struct {
+	// MaxIdleConns is the number of idle connections kept open to the node for reuse, 100 by
+	// default. Set it to the number of parallel requests, e.g. FetchConfig.MaxConcurrency.
+	MaxIdleConns int
+	// MaxConnsPerHost limits the connections to the node, idle or not, 0 for no limit.
+	MaxConnsPerHost int
+	// IdleConnTimeout is how long an idle connection is kept, 90s by default.
+	IdleConnTimeout time.Duration
+	// DisableHTTP2 speaks HTTP/1.1 to https nodes. HTTP/2, used by default when the node
+	// supports it, multiplexes the requests on one connection.
+	DisableHTTP2 bool
+	// DisableCompression stops asking for gzip responses, which saves CPU on a fast local
+	// network at the cost of bandwidth.
+	DisableCompression bool
+}
+
+// Transport returns the HTTP transport configured by cfg.
+func (cfg TransportConfig) Transport() *http.Transport {
+	if cfg.MaxIdleConns <= 0 {
+		cfg.MaxIdleConns = defaultMaxIdleConns
+	}
+	if cfg.IdleConnTimeout <= 0 {
+		cfg.IdleConnTimeout = defaultIdleConnTimeout
+	}
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	t := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     !cfg.DisableHTTP2,
+		MaxIdleConns:          cfg.MaxIdleConns,
+		MaxIdleConnsPerHost:   cfg.MaxIdleConns,
+		MaxConnsPerHost:       cfg.MaxConnsPerHost,
+		IdleConnTimeout:       cfg.IdleConnTimeout,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: time.Second,
+		DisableCompression:    cfg.DisableCompression,
+	}
+	if cfg.DisableHTTP2 {
+		// A non-nil empty map turns off the HTTP/2 upgrade of TLS connections.
+		t.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+	return t
+}
+
+// DialWithTransport is Dial with the HTTP connections to the node tuned by cfg, for services
+// sending many requests in parallel. cfg only applies to http and https URLs.
+func DialWithTransport(priKey, rawurl string, cfg TransportConfig) (*Wormholes, error) {
+	if priKey != "" {
+		if _, err := tools.ParsePriKey(priKey); err != nil {
+			return nil, err
+		}
+	}
+	httpClient := &http.Client{Transport: cfg.Transport()}
+	c, err := rpc.DialOptions(context.Background(), rawurl, rpc.WithHTTPClient(httpClient))
+	if err != nil {
+		return nil, xerrors.Errorf("failed to connect to node %s: %w", rawurl, err)
+	}
+	return NewClientWithRPC(priKey, c), nil
+}
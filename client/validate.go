@@ -0,0 +1,58 @@
+package client
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/erbieio/erb-client/v2/tools"
+	types2 "github.com/erbieio/erb-client/v2/types"
+)
+
+// checkOptionalAddress checks an address that may be left empty.
+func checkOptionalAddress(name, value string) error {
+	if value == "" {
+		return nil
+	}
+	return tools.CheckAddress(name, value)
+}
+
+// checkExchanger checks the address of an exchanger with types.ParseAddress and returns it
+// checksummed, the form it is signed and sent in.
+func checkExchanger(name, value string) (string, error) {
+	address, err := types2.ParseAddress(value)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", name, err)
+	}
+	return address.Hex(), nil
+}
+
+// checkOptionalExchanger is checkExchanger for an exchanger that may be left empty.
+func checkOptionalExchanger(name, value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	return checkExchanger(name, value)
+}
+
+// checkAmount checks an amount sent or pledged, a transaction can not carry a negative value.
+func checkAmount(name string, value types2.Amount) error {
+	if value.Sign() < 0 {
+		return fmt.Errorf("%s must not be negative: %s ERB", name, value)
+	}
+	return nil
+}
+
+// checkFeeRate checks the fee rate of an exchanger, in basis points of the price.
+func checkFeeRate(name string, feeRate int) error {
+	if feeRate < 0 || feeRate > types2.RateDenominator {
+		return fmt.Errorf("%s %d is out of range 0..%d basis points", name, feeRate, types2.RateDenominator)
+	}
+	return nil
+}
+
+// hexLess reports whether the hex number a is less than b, both validated hex numbers.
+func hexLess(a, b string) bool {
+	x, _ := new(big.Int).SetString(a[2:], 16)
+	y, _ := new(big.Int).SetString(b[2:], 16)
+	return x.Cmp(y) < 0
+}
@@ -0,0 +1,95 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// SetPollInterval sets how often WaitMined asks the node for the receipt and the head of the
+// chain, for worm and the clients sharing its connection, 1s by default.
+func (worm *Wormholes) SetPollInterval(interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+	worm.network.pollInterval.Store(int64(interval))
+}
+
+func (worm *Wormholes) pollInterval() time.Duration {
+	if worm.network == nil {
+		return defaultPollInterval
+	}
+	return time.Duration(worm.network.pollInterval.Load())
+}
+
+// WaitMined waits until the transaction txHash is mined and confirmations blocks are built on
+// the block that includes it, and returns its receipt. With 0 confirmations it returns as soon
+// as the receipt is available. A failed transaction is mined too, check the Status of the
+// receipt. The node is polled, see SetPollInterval; when a reorg moves the transaction to
+// another block the confirmations are counted again from it, and when it drops the transaction
+// WaitMined waits for it to be mined again. It returns ctx.Err() once ctx is done, e.g. for a
+// transaction that is never mined:
+//
+//	hash, err := worm.TransferCtx(ctx, nftAddress, to)
+//	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+//	defer cancel()
+//	receipt, err := worm.WaitMined(ctx, hash, 3)
+func (worm *Wormholes) WaitMined(ctx context.Context, txHash string, confirmations uint64) (*types.Receipt, error) {
+	ticker := time.NewTicker(worm.pollInterval())
+	defer ticker.Stop()
+	for {
+		receipt, err := worm.confirmedReceipt(ctx, txHash, confirmations)
+		if err != nil {
+			if deadline, ok := ctx.Deadline(); ctx.Err() != nil || ok && !time.Now().Before(deadline) {
+				// A request interrupted by ctx fails with the error of the transport, which
+				// may set the deadline of ctx on the connection and time out before it.
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}
+			return nil, err
+		}
+		if receipt != nil {
+			return receipt, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// confirmedReceipt returns the receipt of txHash once it has confirmations blocks on top of it,
+// nil if the transaction is not mined or not confirmed yet.
+func (worm *Wormholes) confirmedReceipt(ctx context.Context, txHash string, confirmations uint64) (*types.Receipt, error) {
+	receipt, err := worm.TransactionReceipt(ctx, txHash)
+	if errors.Is(err, ethereum.NotFound) {
+		return nil, nil
+	}
+	if err != nil || confirmations == 0 {
+		return receipt, err
+	}
+	head, err := worm.BlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+	mined := receipt.BlockNumber.Uint64()
+	if head < mined+confirmations {
+		return nil, nil
+	}
+	// The receipt is that of the canonical chain only if its block still is.
+	block, err := worm.HeaderByNumber(ctx, receipt.BlockNumber)
+	if errors.Is(err, ethereum.NotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if block.Hash != receipt.BlockHash {
+		return nil, nil
+	}
+	return receipt, nil
+}
@@ -2,70 +2,300 @@ package client
 
 import (
 	"context"
+	"crypto/ecdsa"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/ethereum/go-ethereum"
 	"log"
 	"math/big"
+	"sync"
 
-	"github.com/erbieio/erb-client/tools"
-	types2 "github.com/erbieio/erb-client/types"
+	"github.com/erbieio/erb-client/v2/tools"
+	types2 "github.com/erbieio/erb-client/v2/types"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/rpc"
+	"golang.org/x/xerrors"
 )
 
+// Wallet signs orders and authorizations with a private key. It is safe for concurrent use.
 type Wallet struct {
-	priKey string
+	mu     sync.RWMutex
+	signer *walletKey
+	book   *tools.AddressBook
+	names  tools.NameResolver
+	rawV   bool
 }
 
+// Wormholes is a client of a wormholes node. It is safe for concurrent use, so one client can
+// serve all the goroutines of a service: the connection is shared, the key and the address book
+// are guarded, and the transactions of an account are sent one after the other, from reading
+// the pending nonce to sending, so concurrent transactions do not take the same nonce.
 type Wormholes struct {
 	Wallet
-	c *rpc.Client
+	c       *rpc.Client
+	senders *senderLocks
+	gas     *gasLimits
+	network *network
+	// dialErr is the error of connecting to the node, returned by the requests.
+	dialErr error
 }
 
 // NewClient creates a new wormclient for the given URL and priKey.
 // when the rawurl is  nil, Initialize the wallet, can sign buyer, seller, exchange information.
 // when the rawurl is not nil, Initialize the NFT, can carry out nft related transactions.
+// If rawurl is invalid the error is logged and returned by every request of the client,
+// use Dial to handle it when creating the client. priKey may have a 0x prefix, an invalid
+// key is logged the same way and returned by the transactions and signatures.
 func NewClient(priKey, rawurl string) *Wormholes {
 	if rawurl == "" {
 		return &Wormholes{
-			Wallet{priKey: priKey},
+			Wallet{signer: newWalletKey(priKey)},
+			nil,
+			newSenderLocks(),
+			newGasLimits(),
+			newNetwork(),
 			nil,
 		}
 	} else {
 		client, err := rpc.Dial(rawurl)
 		if err != nil {
-			log.Fatalf("failed to connect to Ethereum node: %v", err)
-			return &Wormholes{}
+			log.Printf("failed to connect to Ethereum node: %v", err)
+			return &Wormholes{
+				Wallet{signer: newWalletKey(priKey)},
+				nil,
+				newSenderLocks(),
+				newGasLimits(),
+				newNetwork(),
+				err,
+			}
 		}
 		return &Wormholes{
 			Wallet{
-				priKey: priKey,
+				signer: newWalletKey(priKey),
 			},
 			client,
+			newSenderLocks(),
+			newGasLimits(),
+			newNetwork(),
+			nil,
+		}
+	}
+}
+
+// Dial creates a client signing with priKey connected to the node at rawurl. Unlike NewClient
+// it returns the error of an invalid priKey, an empty priKey creates a client that does not sign.
+func Dial(priKey, rawurl string) (*Wormholes, error) {
+	if priKey != "" {
+		if _, err := tools.ParsePriKey(priKey); err != nil {
+			return nil, err
+		}
+	}
+	c, err := rpc.Dial(rawurl)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to connect to node %s: %w", rawurl, err)
+	}
+	return NewClientWithRPC(priKey, c), nil
+}
+
+// NewClientWithRPC creates a wormclient on an already connected rpc client, e.g. an
+// in-process connection created with rpc.DialInProc.
+func NewClientWithRPC(priKey string, c *rpc.Client) *Wormholes {
+	return &Wormholes{
+		Wallet{signer: newWalletKey(priKey)},
+		c,
+		newSenderLocks(),
+		newGasLimits(),
+		newNetwork(),
+		nil,
+	}
+}
+
+// WithPriKey returns a client that signs with priKey and shares the connection of worm, with the
+// address book, name resolver and signature V offset of worm.
+func (worm *Wormholes) WithPriKey(priKey string) *Wormholes {
+	worm.mu.RLock()
+	book, names, rawV := worm.book, worm.names, worm.rawV
+	worm.mu.RUnlock()
+	return &Wormholes{
+		Wallet{signer: newWalletKey(priKey), book: book, names: names, rawV: rawV},
+		worm.c,
+		worm.senders,
+		worm.gas,
+		worm.network,
+		worm.dialErr,
+	}
+}
+
+// NewWallet creates a wallet that signs with priKey without connecting to a node. The key may
+// have a 0x prefix. An invalid key is logged, and returned by Address and the signing methods.
+func NewWallet(priKey string) *Wallet {
+	return &Wallet{signer: newWalletKey(priKey)}
+}
+
+// walletKey is the key of a wallet, parsed once rather than for every signature.
+type walletKey struct {
+	account common.Address
+	key     *ecdsa.PrivateKey
+	// err is the error of tools.ParsePriKey for an invalid key, returned by the signing methods.
+	err error
+	// external signs instead of key, which is nil, for a key held outside the process.
+	external Signer
+}
+
+// newWalletKey parses priKey. An invalid key is logged, a wallet without a key is not.
+func newWalletKey(priKey string) *walletKey {
+	account, key, err := tools.PriKeyToAddress(priKey)
+	if err != nil && priKey != "" {
+		log.Printf("client: %v", err)
+	}
+	return &walletKey{account: account, key: key, err: err}
+}
+
+// Address returns the account of the key of the wallet, or the error of an invalid key.
+func (w *Wallet) Address() (common.Address, error) {
+	account, _, err := w.signingKey()
+	return account, err
+}
+
+// SetAddressBook makes the wallet accept the aliases of book wherever it takes an address string.
+func (w *Wallet) SetAddressBook(book *tools.AddressBook) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.book = book
+}
+
+// SetNameResolver makes the wallet accept the names registered with r wherever it takes an
+// address string. Aliases of the address book take precedence. Wrap r with
+// tools.CachedResolver to not query a registry for every parameter.
+func (w *Wallet) SetNameResolver(r tools.NameResolver) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.names = r
+}
+
+// SetSignatureVOffset sets the offset the wallet adds to the recovery id in the V byte of its
+// signatures: tools.SignatureVOffset, the default and the V of 27 or 28 the chain expects in
+// orders, or tools.RawSignatureVOffset for verifiers of V as 0 or 1.
+func (w *Wallet) SetSignatureVOffset(offset byte) error {
+	if offset != tools.SignatureVOffset && offset != tools.RawSignatureVOffset {
+		return xerrors.Errorf("invalid signature V offset %d, want %d or %d", offset, tools.SignatureVOffset, tools.RawSignatureVOffset)
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.rawV = offset == tools.RawSignatureVOffset
+	return nil
+}
+
+// SignatureVOffset returns the offset set with SetSignatureVOffset.
+func (w *Wallet) SignatureVOffset() byte {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.rawV {
+		return tools.RawSignatureVOffset
+	}
+	return tools.SignatureVOffset
+}
+
+// resolve returns the address of an alias or a registered name, other values are returned
+// unchanged, and so are names the resolver fails to find, which then fail the address checks.
+// The lookup of a name is bounded by ctx and by resolveTimeout.
+func (w *Wallet) resolve(ctx context.Context, value string) string {
+	w.mu.RLock()
+	book, names := w.book, w.names
+	w.mu.RUnlock()
+	if resolved := book.Resolve(value); resolved != value || names == nil || !isName(value) {
+		return resolved
+	}
+	ctx, cancel := context.WithTimeout(ctx, resolveTimeout)
+	defer cancel()
+	address, err := names.LookupName(ctx, value)
+	if err != nil {
+		if !errors.Is(err, tools.ErrNameNotFound) {
+			log.Printf("client: resolve %s: %v", value, err)
 		}
+		return value
+	}
+	return address.Hex()
+}
+
+// walletKey returns the key the wallet signs with, of a zero Wallet an empty one.
+func (w *Wallet) walletKey() *walletKey {
+	w.mu.RLock()
+	signer := w.signer
+	w.mu.RUnlock()
+	if signer == nil {
+		return newWalletKey("")
 	}
+	return signer
+}
+
+// signingKey returns the account and the parsed private key of the wallet, or the error of
+// an invalid key.
+func (w *Wallet) signingKey() (common.Address, *ecdsa.PrivateKey, error) {
+	signer := w.walletKey()
+	return signer.account, signer.key, signer.err
 }
 
 func (worm *Wormholes) CloseConnect() {
-	worm.c.Close()
+	if worm.c != nil {
+		worm.c.Close()
+	}
 }
 
+// call sends a request to the node, it fails with ErrNotConnected without a connection. An error
+// response of the node is returned as an *RPCError.
+func (worm *Wormholes) call(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	if worm.c == nil {
+		return worm.notConnected()
+	}
+	return wrapRPCError(method, worm.c.CallContext(ctx, result, method, args...))
+}
+
+// batchCall sends a batch of requests to the node, it fails with ErrNotConnected without a connection.
+// The error responses of the requests are set as *RPCError.
+func (worm *Wormholes) batchCall(ctx context.Context, reqs []rpc.BatchElem) error {
+	if worm.c == nil {
+		return worm.notConnected()
+	}
+	if err := worm.c.BatchCallContext(ctx, reqs); err != nil {
+		return err
+	}
+	for i := range reqs {
+		reqs[i].Error = wrapRPCError(reqs[i].Method, reqs[i].Error)
+	}
+	return nil
+}
+
+func (worm *Wormholes) notConnected() error {
+	if worm.dialErr != nil {
+		return fmt.Errorf("%w: %w", ErrNotConnected, worm.dialErr)
+	}
+	return ErrNotConnected
+}
+
+// UpdatePri changes the key the client signs with, a key with a 0x prefix is accepted.
 func (worm *Wormholes) UpdatePri(pri string) {
-	worm.priKey = pri
+	signer := newWalletKey(pri)
+	worm.mu.Lock()
+	defer worm.mu.Unlock()
+	worm.signer = signer
 }
 
 // ChainID retrieves the current chain ID for transaction replay protection.
+// It is cached for a minute, see SetMetadataTTL.
 func (worm *Wormholes) ChainID(ctx context.Context) (*big.Int, error) {
-	var result hexutil.Big
-	err := worm.c.CallContext(ctx, &result, "eth_chainId")
-	if err != nil {
-		return nil, err
-	}
-	return (*big.Int)(&result), err
+	return worm.cachedMetadata(ctx, metaChainID, func(ctx context.Context) (*big.Int, error) {
+		var result hexutil.Big
+		err := worm.call(ctx, &result, "eth_chainId")
+		if err != nil {
+			return nil, err
+		}
+		return (*big.Int)(&result), err
+	})
 }
 
 // BlockByNumber returns a block from the current canonical chain. If number is nil, the
@@ -77,6 +307,14 @@ func (worm *Wormholes) BlockByNumber(ctx context.Context, number *big.Int) (*typ
 	return worm.getBlock(ctx, "eth_getBlockByNumber", toBlockNumArg(number), true)
 }
 
+// BlockByHash returns the given full block.
+//
+// Note that loading full blocks requires two requests. Use HeaderByHash
+// if you don't need all transactions or uncle headers.
+func (worm *Wormholes) BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error) {
+	return worm.getBlock(ctx, "eth_getBlockByHash", hash, true)
+}
+
 type rpcBlock struct {
 	Hash         common.Hash      `json:"hash"`
 	Transactions []rpcTransaction `json:"transactions"`
@@ -85,7 +323,7 @@ type rpcBlock struct {
 
 func (worm *Wormholes) getBlock(ctx context.Context, method string, args ...interface{}) (*types.Block, error) {
 	var raw json.RawMessage
-	err := worm.c.CallContext(ctx, &raw, method, args...)
+	err := worm.call(ctx, &raw, method, args...)
 	if err != nil {
 		return nil, err
 	} else if len(raw) == 0 {
@@ -97,6 +335,10 @@ func (worm *Wormholes) getBlock(ctx context.Context, method string, args ...inte
 	if err := json.Unmarshal(raw, &head); err != nil {
 		return nil, err
 	}
+	// When the block is not found, the API returns JSON null.
+	if head == nil {
+		return nil, ethereum.NotFound
+	}
 	if err := json.Unmarshal(raw, &body); err != nil {
 		return nil, err
 	}
@@ -125,7 +367,7 @@ func (worm *Wormholes) getBlock(ctx context.Context, method string, args ...inte
 				Result: &uncles[i],
 			}
 		}
-		if err := worm.c.BatchCallContext(ctx, reqs); err != nil {
+		if err := worm.batchCall(ctx, reqs); err != nil {
 			return nil, err
 		}
 		for i := range reqs {
@@ -148,21 +390,55 @@ func (worm *Wormholes) getBlock(ctx context.Context, method string, args ...inte
 	return types.NewBlockWithHeader(head).WithBody(txs, uncles), nil
 }
 
+// HeaderByNumber returns the header of a block from the current canonical chain without its transactions.
+// If number is nil, the latest known header is returned.
+func (worm *Wormholes) HeaderByNumber(ctx context.Context, number *big.Int) (*types2.Header, error) {
+	return worm.getHeader(ctx, "eth_getBlockByNumber", toBlockNumArg(number), false)
+}
+
+// HeaderByHash returns the header of the block with the given hash without its transactions.
+func (worm *Wormholes) HeaderByHash(ctx context.Context, hash common.Hash) (*types2.Header, error) {
+	return worm.getHeader(ctx, "eth_getBlockByHash", hash, false)
+}
+
+func (worm *Wormholes) getHeader(ctx context.Context, method string, args ...interface{}) (*types2.Header, error) {
+	var head *types2.Header
+	err := worm.call(ctx, &head, method, args...)
+	if err == nil && head == nil {
+		return nil, ethereum.NotFound
+	}
+	return head, err
+}
+
+// GetRawBlock returns the RLP encoding of the block selected by tag, as stored by the node.
+// The debug API must be enabled on the node.
+func (worm *Wormholes) GetRawBlock(ctx context.Context, tag BlockTag) ([]byte, error) {
+	var result hexutil.Bytes
+	err := worm.call(ctx, &result, "debug_getRawBlock", tag)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, ethereum.NotFound
+	}
+	return result, nil
+}
+
 // BlockNumber returns the most recent block number
 func (worm *Wormholes) BlockNumber(ctx context.Context) (uint64, error) {
 	var result hexutil.Uint64
-	err := worm.c.CallContext(ctx, &result, "eth_blockNumber")
+	err := worm.call(ctx, &result, "eth_blockNumber")
 	return uint64(result), err
 }
 
 func (worm *Wormholes) GetBlockByNumber(ctx context.Context, number *big.Int) (map[string]interface{}, error) {
-	var raw json.RawMessage
-	block := make(map[string]interface{})
-	worm.c.CallContext(ctx, &raw, "eth_getBlockByNumber", toBlockNumArg(number), true)
-	err := json.Unmarshal(raw, &block)
-	if err != nil {
+	var block map[string]interface{}
+	if err := worm.call(ctx, &block, "eth_getBlockByNumber", toBlockNumArg(number), true); err != nil {
 		return nil, err
 	}
+	if block == nil {
+		return nil, xerrors.Errorf("block %s: %w", toBlockNumArg(number), ethereum.NotFound)
+	}
 	return block, nil
 }
 
@@ -187,7 +463,70 @@ func (tx *rpcTransaction) UnmarshalJSON(msg []byte) error {
 // TransactionInBlock returns a single transaction at index in the given block.
 func (worm *Wormholes) TransactionInBlock(ctx context.Context, blockHash common.Hash, index uint) (*types.Transaction, error) {
 	var json *rpcTransaction
-	err := worm.c.CallContext(ctx, &json, "eth_getTransactionByBlockHashAndIndex", blockHash, hexutil.Uint64(index))
+	err := worm.call(ctx, &json, "eth_getTransactionByBlockHashAndIndex", blockHash, hexutil.Uint64(index))
+	if err != nil {
+		return nil, err
+	}
+	if json == nil {
+		return nil, ethereum.NotFound
+	} else if _, r, _ := json.tx.RawSignatureValues(); r == nil {
+		return nil, fmt.Errorf("server returned transaction without signature")
+	}
+	if json.From != nil && json.BlockHash != nil {
+		setSenderFromServer(json.tx, *json.From, *json.BlockHash)
+	}
+	return json.tx, err
+}
+
+// SyncProgress retrieves the current progress of the sync algorithm. If there's
+// no sync currently running, it returns nil.
+//
+// A node that is still syncing serves stale account state, so callers should
+// not submit transactions through it until SyncProgress returns nil.
+func (worm *Wormholes) SyncProgress(ctx context.Context) (*ethereum.SyncProgress, error) {
+	var raw json.RawMessage
+	if err := worm.call(ctx, &raw, "eth_syncing"); err != nil {
+		return nil, err
+	}
+	// Handle the possible response types
+	var syncing bool
+	if err := json.Unmarshal(raw, &syncing); err == nil {
+		return nil, nil // Not syncing (always false)
+	}
+	var p *rpcProgress
+	if err := json.Unmarshal(raw, &p); err != nil {
+		return nil, err
+	}
+	return p.toSyncProgress(), nil
+}
+
+type rpcProgress struct {
+	StartingBlock hexutil.Uint64
+	CurrentBlock  hexutil.Uint64
+	HighestBlock  hexutil.Uint64
+
+	PulledStates hexutil.Uint64
+	KnownStates  hexutil.Uint64
+}
+
+func (p *rpcProgress) toSyncProgress() *ethereum.SyncProgress {
+	if p == nil {
+		return nil
+	}
+	return &ethereum.SyncProgress{
+		StartingBlock: uint64(p.StartingBlock),
+		CurrentBlock:  uint64(p.CurrentBlock),
+		HighestBlock:  uint64(p.HighestBlock),
+		PulledStates:  uint64(p.PulledStates),
+		KnownStates:   uint64(p.KnownStates),
+	}
+}
+
+// TransactionInBlockByNumber returns a single transaction at index in the block with the given number.
+// If number is nil, the latest known block is used.
+func (worm *Wormholes) TransactionInBlockByNumber(ctx context.Context, number *big.Int, index uint) (*types.Transaction, error) {
+	var json *rpcTransaction
+	err := worm.call(ctx, &json, "eth_getTransactionByBlockNumberAndIndex", toBlockNumArg(number), hexutil.Uint64(index))
 	if err != nil {
 		return nil, err
 	}
@@ -202,68 +541,344 @@ func (worm *Wormholes) TransactionInBlock(ctx context.Context, blockHash common.
 	return json.tx, err
 }
 
+// NonceAt returns the account nonce of the given account.
+// The block number can be nil, in which case the nonce is taken from the latest known block.
+// Comparing it with PendingNonceAt shows how many transactions of the account are waiting in the pool.
+func (worm *Wormholes) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	return worm.NonceAtTag(ctx, account, blockTagOf(blockNumber))
+}
+
+// NonceAtTag returns the account nonce of the given account at the given block tag.
+func (worm *Wormholes) NonceAtTag(ctx context.Context, account common.Address, tag BlockTag) (uint64, error) {
+	var result hexutil.Uint64
+	err := worm.call(ctx, &result, "eth_getTransactionCount", account, tag)
+	return uint64(result), err
+}
+
 // PendingNonceAt returns the account nonce of the given account in the pending state.
 // This is the nonce that should be used for the next transaction.
 func (worm *Wormholes) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
-	var result hexutil.Uint64
-	err := worm.c.CallContext(ctx, &result, "eth_getTransactionCount", account, "pending")
-	return uint64(result), err
+	return worm.NonceAtTag(ctx, account, Pending)
 }
 
 // SuggestGasPrice retrieves the currently suggested gas price to allow a timely
-// execution of a transaction.
+// execution of a transaction. It is cached for 3s, see SetMetadataTTL.
 func (worm *Wormholes) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return worm.cachedMetadata(ctx, metaGasPrice, func(ctx context.Context) (*big.Int, error) {
+		var hex hexutil.Big
+		if err := worm.call(ctx, &hex, "eth_gasPrice"); err != nil {
+			return nil, err
+		}
+		return (*big.Int)(&hex), nil
+	})
+}
+
+// SuggestGasTipCap retrieves the currently suggested gas tip cap after 1559 to
+// allow a timely execution of a transaction.
+func (worm *Wormholes) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
 	var hex hexutil.Big
-	if err := worm.c.CallContext(ctx, &hex, "eth_gasPrice"); err != nil {
+	if err := worm.call(ctx, &hex, "eth_maxPriorityFeePerGas"); err != nil {
 		return nil, err
 	}
 	return (*big.Int)(&hex), nil
 }
 
+// EstimateGas tries to estimate the gas needed to execute a specific transaction based on
+// the current pending state of the backend blockchain. There is no guarantee that this is
+// the true gas limit requirement as other transactions may be added or removed by miners,
+// but it should provide a basis for setting a reasonable default.
+func (worm *Wormholes) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
+	var hex hexutil.Uint64
+	if err := worm.call(ctx, &hex, "eth_estimateGas", toCallArg(msg)); err != nil {
+		return 0, err
+	}
+	return uint64(hex), nil
+}
+
+func toCallArg(msg ethereum.CallMsg) interface{} {
+	arg := map[string]interface{}{
+		"from": msg.From,
+		"to":   msg.To,
+	}
+	if len(msg.Data) > 0 {
+		arg["input"] = hexutil.Bytes(msg.Data)
+	}
+	if msg.Value != nil {
+		arg["value"] = (*hexutil.Big)(msg.Value)
+	}
+	if msg.Gas != 0 {
+		arg["gas"] = hexutil.Uint64(msg.Gas)
+	}
+	if msg.GasPrice != nil {
+		arg["gasPrice"] = (*hexutil.Big)(msg.GasPrice)
+	}
+	return arg
+}
+
+type feeHistoryResultMarshaling struct {
+	OldestBlock  *hexutil.Big     `json:"oldestBlock"`
+	Reward       [][]*hexutil.Big `json:"reward,omitempty"`
+	BaseFee      []*hexutil.Big   `json:"baseFeePerGas,omitempty"`
+	GasUsedRatio []float64        `json:"gasUsedRatio"`
+}
+
+// FeeHistory retrieves the fee market history of the blockCount blocks ending at lastBlock.
+// The lastBlock can be nil, in which case the history ends at the latest known block.
+// rewardPercentiles selects which percentiles of the priority fees are reported per block.
+func (worm *Wormholes) FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*ethereum.FeeHistory, error) {
+	var res feeHistoryResultMarshaling
+	if err := worm.call(ctx, &res, "eth_feeHistory", hexutil.Uint(blockCount), toBlockNumArg(lastBlock), rewardPercentiles); err != nil {
+		return nil, err
+	}
+	reward := make([][]*big.Int, len(res.Reward))
+	for i, r := range res.Reward {
+		reward[i] = make([]*big.Int, len(r))
+		for j, r := range r {
+			reward[i][j] = (*big.Int)(r)
+		}
+	}
+	baseFee := make([]*big.Int, len(res.BaseFee))
+	for i, b := range res.BaseFee {
+		baseFee[i] = (*big.Int)(b)
+	}
+	return &ethereum.FeeHistory{
+		OldestBlock:  (*big.Int)(res.OldestBlock),
+		Reward:       reward,
+		BaseFee:      baseFee,
+		GasUsedRatio: res.GasUsedRatio,
+	}, nil
+}
+
 // SendTransaction injects a signed transaction into the pending pool for execution.
 //
 // If the transaction was a contract creation use the TransactionReceipt method to get the
-// contract address after the transaction has been mined.
+// contract address after the transaction has been mined. A transaction the node rejects for a
+// known reason returns the matching error of the package, e.g. ErrInsufficientBalance. A client
+// expecting a chain with ExpectChainID returns ErrWrongNetwork instead of sending to another.
 func (worm *Wormholes) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	if err := worm.checkNetwork(ctx, tx); err != nil {
+		return err
+	}
 	data, err := tx.MarshalBinary()
 	if err != nil {
 		return err
 	}
-	return worm.c.CallContext(ctx, nil, "eth_sendRawTransaction", hexutil.Encode(data))
+	return wrapNodeError(worm.call(ctx, nil, "eth_sendRawTransaction", hexutil.Encode(data)))
+}
+
+// ClientVersion returns the version string of the node software, e.g. "wormholes/v1.0.0/linux-amd64/go1.19".
+func (worm *Wormholes) ClientVersion(ctx context.Context) (string, error) {
+	var result string
+	err := worm.call(ctx, &result, "web3_clientVersion")
+	return result, err
+}
+
+// NodeInfo returns the node's self description. The admin API is usually
+// not exposed on public endpoints, in which case an error is returned.
+func (worm *Wormholes) NodeInfo(ctx context.Context) (*types2.NodeInfo, error) {
+	var result *types2.NodeInfo
+	err := worm.call(ctx, &result, "admin_nodeInfo")
+	if err == nil {
+		if result == nil {
+			return nil, ethereum.NotFound
+		}
+	}
+	return result, err
 }
 
 // NetworkID returns the network ID (also known as the chain ID) for this chain.
+// It is cached for a minute, see SetMetadataTTL.
 func (worm *Wormholes) NetworkID(ctx context.Context) (*big.Int, error) {
-	version := new(big.Int)
-	var ver string
-	if err := worm.c.CallContext(ctx, &ver, "net_version"); err != nil {
+	return worm.cachedMetadata(ctx, metaNetworkID, func(ctx context.Context) (*big.Int, error) {
+		version := new(big.Int)
+		var ver string
+		if err := worm.call(ctx, &ver, "net_version"); err != nil {
+			return nil, err
+		}
+		if _, ok := version.SetString(ver, 10); !ok {
+			return nil, fmt.Errorf("invalid net_version result %q", ver)
+		}
+		return version, nil
+	})
+}
+
+// PeerCount returns the number of p2p peers currently connected to the node.
+// A node with no peers still answers queries but never propagates or mines transactions.
+func (worm *Wormholes) PeerCount(ctx context.Context) (uint64, error) {
+	var result hexutil.Uint64
+	err := worm.call(ctx, &result, "net_peerCount")
+	return uint64(result), err
+}
+
+// TxPoolStatus returns the number of pending and queued transactions in the node's transaction pool.
+func (worm *Wormholes) TxPoolStatus(ctx context.Context) (*types2.TxPoolStatus, error) {
+	var result struct {
+		Pending hexutil.Uint64 `json:"pending"`
+		Queued  hexutil.Uint64 `json:"queued"`
+	}
+	if err := worm.call(ctx, &result, "txpool_status"); err != nil {
+		return nil, err
+	}
+	return &types2.TxPoolStatus{
+		Pending: uint64(result.Pending),
+		Queued:  uint64(result.Queued),
+	}, nil
+}
+
+// TxPoolContentFrom returns the pending and queued transactions of the given account, keyed by nonce.
+// A queued transaction usually means an earlier nonce is missing.
+func (worm *Wormholes) TxPoolContentFrom(ctx context.Context, account common.Address) (*types2.TxPoolContent, error) {
+	var result map[string]map[string]*rpcTransaction
+	if err := worm.call(ctx, &result, "txpool_contentFrom", account); err != nil {
+		return nil, err
+	}
+	pending, err := toNonceMap(result["pending"])
+	if err != nil {
+		return nil, err
+	}
+	queued, err := toNonceMap(result["queued"])
+	if err != nil {
+		return nil, err
+	}
+	return &types2.TxPoolContent{
+		Pending: pending,
+		Queued:  queued,
+	}, nil
+}
+
+// TxPoolContent returns the pending and queued transactions of every account in the node's transaction pool.
+func (worm *Wormholes) TxPoolContent(ctx context.Context) (map[common.Address]*types2.TxPoolContent, error) {
+	var result map[string]map[common.Address]map[string]*rpcTransaction
+	if err := worm.call(ctx, &result, "txpool_content"); err != nil {
 		return nil, err
 	}
-	if _, ok := version.SetString(ver, 10); !ok {
-		return nil, fmt.Errorf("invalid net_version result %q", ver)
+	content := make(map[common.Address]*types2.TxPoolContent)
+	for pool, accounts := range result {
+		for account, txs := range accounts {
+			nonces, err := toNonceMap(txs)
+			if err != nil {
+				return nil, err
+			}
+			c, ok := content[account]
+			if !ok {
+				c = &types2.TxPoolContent{
+					Pending: make(map[uint64]*types.Transaction),
+					Queued:  make(map[uint64]*types.Transaction),
+				}
+				content[account] = c
+			}
+			switch pool {
+			case "pending":
+				c.Pending = nonces
+			case "queued":
+				c.Queued = nonces
+			}
+		}
 	}
-	return version, nil
+	return content, nil
+}
+
+func toNonceMap(txs map[string]*rpcTransaction) (map[uint64]*types.Transaction, error) {
+	result := make(map[uint64]*types.Transaction, len(txs))
+	for key, tx := range txs {
+		nonce, ok := new(big.Int).SetString(key, 10)
+		if !ok || !nonce.IsUint64() {
+			return nil, fmt.Errorf("invalid txpool nonce %q", key)
+		}
+		result[nonce.Uint64()] = tx.tx
+	}
+	return result, nil
 }
 
 // Balance returns the wei balance of the given account in the pending state.
+// Use BalanceAtTag to query any other state.
 func (worm *Wormholes) Balance(ctx context.Context, account string) (*big.Int, error) {
-	var accounts common.Address
-	accounts = common.HexToAddress(account)
-	var result hexutil.Big
-	err := worm.c.CallContext(ctx, &result, "eth_getBalance", accounts, "pending")
-	return (*big.Int)(&result), err
+	return worm.BalanceAtTag(ctx, account, Pending)
 }
 
 // BalanceAt returns the wei balance of the given account.
 // The block number can be nil, in which case the balance is taken from the latest known block.
 func (worm *Wormholes) BalanceAt(ctx context.Context, account string, blockNumber *big.Int) (*big.Int, error) {
-	var accounts common.Address
-	accounts = common.HexToAddress(account)
+	return worm.BalanceAtTag(ctx, account, blockTagOf(blockNumber))
+}
+
+// BalanceAtTag returns the wei balance of the given account at the given block tag.
+func (worm *Wormholes) BalanceAtTag(ctx context.Context, account string, tag BlockTag) (*big.Int, error) {
+	account = worm.resolve(ctx, account)
 	var result hexutil.Big
-	err := worm.c.CallContext(ctx, &result, "eth_getBalance", accounts, toBlockNumArg(blockNumber))
+	err := worm.call(ctx, &result, "eth_getBalance", common.HexToAddress(account), tag)
 	return (*big.Int)(&result), err
 }
 
+// StorageAt returns the value of key in the contract storage of the given account.
+// The block number can be nil, in which case the value is taken from the latest known block.
+func (worm *Wormholes) StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error) {
+	return worm.StorageAtTag(ctx, account, key, blockTagOf(blockNumber))
+}
+
+// StorageAtTag returns the value of key in the contract storage of the given account at the
+// given block tag.
+func (worm *Wormholes) StorageAtTag(ctx context.Context, account common.Address, key common.Hash, tag BlockTag) ([]byte, error) {
+	var result hexutil.Bytes
+	err := worm.call(ctx, &result, "eth_getStorageAt", account, key, tag)
+	return result, err
+}
+
+// GetProof returns the account and storage values of the specified account including the Merkle-proof.
+// The block number can be nil, in which case the value is taken from the latest known block.
+func (worm *Wormholes) GetProof(ctx context.Context, account common.Address, keys []string, blockNumber *big.Int) (*types2.AccountResult, error) {
+	return worm.GetProofAtTag(ctx, account, keys, blockTagOf(blockNumber))
+}
+
+// GetProofAtTag returns the account and storage values of the specified account including the
+// Merkle-proof, at the given block tag.
+func (worm *Wormholes) GetProofAtTag(ctx context.Context, account common.Address, keys []string, tag BlockTag) (*types2.AccountResult, error) {
+	type storageResult struct {
+		Key   string       `json:"key"`
+		Value *hexutil.Big `json:"value"`
+		Proof []string     `json:"proof"`
+	}
+
+	type accountResult struct {
+		Address      common.Address  `json:"address"`
+		AccountProof []string        `json:"accountProof"`
+		Balance      *hexutil.Big    `json:"balance"`
+		CodeHash     common.Hash     `json:"codeHash"`
+		Nonce        hexutil.Uint64  `json:"nonce"`
+		StorageHash  common.Hash     `json:"storageHash"`
+		StorageProof []storageResult `json:"storageProof"`
+	}
+
+	// Avoid keys being 'null'.
+	if keys == nil {
+		keys = []string{}
+	}
+
+	var res accountResult
+	err := worm.call(ctx, &res, "eth_getProof", account, keys, tag)
+	if err != nil {
+		return nil, err
+	}
+	storageResults := make([]types2.StorageResult, 0, len(res.StorageProof))
+	for _, st := range res.StorageProof {
+		storageResults = append(storageResults, types2.StorageResult{
+			Key:   st.Key,
+			Value: st.Value.ToInt(),
+			Proof: st.Proof,
+		})
+	}
+	result := types2.AccountResult{
+		Address:      res.Address,
+		AccountProof: res.AccountProof,
+		Balance:      res.Balance.ToInt(),
+		Nonce:        uint64(res.Nonce),
+		CodeHash:     res.CodeHash,
+		StorageHash:  res.StorageHash,
+		StorageProof: storageResults,
+	}
+	return &result, nil
+}
+
 func toBlockNumArg(number *big.Int) string {
 	if number == nil {
 		return "latest"
@@ -276,26 +891,62 @@ func toBlockNumArg(number *big.Int) string {
 }
 
 // TransactionReceipt returns the receipt of a transaction by transaction hash.
-// Note that the receipt is not available for pending transactions.
+// Note that the receipt is not available for pending transactions, the error then wraps
+// ethereum.NotFound, test for it with errors.Is.
 func (worm *Wormholes) TransactionReceipt(ctx context.Context, txHash string) (*types.Receipt, error) {
 	txHashs := common.HexToHash(txHash)
 	var r *types.Receipt
-	err := worm.c.CallContext(ctx, &r, "eth_getTransactionReceipt", txHashs)
+	err := worm.call(ctx, &r, "eth_getTransactionReceipt", txHashs)
 	if err == nil {
 		if r == nil {
-			return nil, ethereum.NotFound
+			return nil, xerrors.Errorf("receipt of transaction %s: %w", txHashs.Hex(), ethereum.NotFound)
 		}
 	}
 	return r, err
 }
 
+// TraceTransaction replays the given transaction and returns the raw trace produced by the node.
+// The config can be nil, in which case the node's default tracer is used.
+// This is the way to find out why a wormholes transaction has a failed receipt.
+func (worm *Wormholes) TraceTransaction(ctx context.Context, txHash common.Hash, config *types2.TraceConfig) (json.RawMessage, error) {
+	var result json.RawMessage
+	err := worm.call(ctx, &result, "debug_traceTransaction", txHash, config)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// TraceBlockByNumber replays all transactions of the given block and returns their traces in block order.
+// The number can be nil, in which case the latest known block is traced.
+func (worm *Wormholes) TraceBlockByNumber(ctx context.Context, number *big.Int, config *types2.TraceConfig) ([]*types2.TxTraceResult, error) {
+	var result []*types2.TxTraceResult
+	err := worm.call(ctx, &result, "debug_traceBlockByNumber", toBlockNumArg(number), config)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetValidators returns the validator list at the given block height.
+// The negative rpc.BlockNumber constants are accepted as well, e.g.
+// int64(rpc.LatestBlockNumber) queries the latest block without an extra BlockNumber call.
 func (worm *Wormholes) GetValidators(ctx context.Context, blockNumber int64) (*types2.ValidatorList, error) {
-	blockNrOrHash := rpc.BlockNumber(blockNumber)
+	return worm.GetValidatorsAt(ctx, blockTagWithNumber(rpc.BlockNumber(blockNumber)))
+}
+
+// GetValidatorsAt returns the validator list at the given block tag (Latest, Pending, Finalized or AtBlockNumber(n)).
+// Hash tags are not supported by the node for this query.
+func (worm *Wormholes) GetValidatorsAt(ctx context.Context, tag BlockTag) (*types2.ValidatorList, error) {
+	blockNumber, ok := tag.BlockNumber()
+	if !ok {
+		return nil, errHashTagNotSupported
+	}
 	var r *types2.ValidatorList
-	err := worm.c.CallContext(ctx, &r, "eth_getValidator", blockNrOrHash)
+	err := worm.call(ctx, &r, "eth_getValidator", blockNumber)
 	if err == nil {
 		if r == nil {
-			return nil, ethereum.NotFound
+			return nil, xerrors.Errorf("validators at block %s: %w", tag, ethereum.NotFound)
 		}
 	}
 
@@ -303,14 +954,26 @@ func (worm *Wormholes) GetValidators(ctx context.Context, blockNumber int64) (*t
 }
 
 func (worm *Wormholes) GetAccountInfo(ctx context.Context, address string, block int64) (*types2.Account, error) {
+	return worm.GetAccountInfoAt(ctx, address, blockTagWithNumber(rpc.BlockNumber(block)))
+}
+
+// GetAccountInfoByHash returns the account state at the block with the given hash.
+// Unlike GetAccountInfo the result can not silently come from a different block after a reorg,
+// the node answers with an error if the block is no longer canonical.
+func (worm *Wormholes) GetAccountInfoByHash(ctx context.Context, address string, blockHash common.Hash) (*types2.Account, error) {
+	return worm.GetAccountInfoAt(ctx, address, AtBlockHash(blockHash))
+}
+
+// GetAccountInfoAt returns the account state at the given block tag.
+func (worm *Wormholes) GetAccountInfoAt(ctx context.Context, address string, tag BlockTag) (*types2.Account, error) {
+	address = worm.resolve(ctx, address)
 	var addresss common.Address
 	addresss = common.HexToAddress(address)
-	blockNrOrHash := rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(block))
 	var r *types2.Account
-	err := worm.c.CallContext(ctx, &r, "eth_getAccountInfo", addresss, blockNrOrHash)
+	err := worm.call(ctx, &r, "eth_getAccountInfo", addresss, tag)
 	if err == nil {
 		if r == nil {
-			return nil, ethereum.NotFound
+			return nil, xerrors.Errorf("account %s at block %s: %w", addresss.Hex(), tag, ethereum.NotFound)
 		}
 	}
 	return r, err
@@ -335,7 +998,19 @@ func GetLatestAccountInfo2(nftaddr string) (*types2.Account, error) {
 func (worm *Wormholes) GetBlockBeneficiaryAddressByNumber(ctx context.Context, block int64) (*types2.BeneficiaryAddressList, error) {
 	blockNumber := rpc.BlockNumber(block)
 	var r *types2.BeneficiaryAddressList
-	err := worm.c.CallContext(ctx, &r, "eth_getBlockBeneficiaryAddressByNumber", blockNumber, true)
+	err := worm.call(ctx, &r, "eth_getBlockBeneficiaryAddressByNumber", blockNumber, true)
+	if err == nil {
+		if r == nil {
+			return nil, ethereum.NotFound
+		}
+	}
+	return r, err
+}
+
+// GetActiveLivePool returns the miners that are active at the given block height.
+func (worm *Wormholes) GetActiveLivePool(ctx context.Context, number uint64) (*types2.ActiveMinerList, error) {
+	var r *types2.ActiveMinerList
+	err := worm.call(ctx, &r, "eth_getActiveLivePool", rpc.BlockNumber(number))
 	if err == nil {
 		if r == nil {
 			return nil, ethereum.NotFound
@@ -345,13 +1020,13 @@ func (worm *Wormholes) GetBlockBeneficiaryAddressByNumber(ctx context.Context, b
 }
 
 func (worm *Wormholes) QueryMinerProxy(ctx context.Context, number int64, account string) (types2.MinerProxyList, error) {
+	account = worm.resolve(ctx, account)
 	var result types2.MinerProxyList
-	nu := fmt.Sprintf("0x%x", number)
 	var accounts common.Address
 
 	accounts = common.HexToAddress(account)
 
-	err := worm.c.CallContext(ctx, &result, "eth_queryMinerProxy", nu, accounts)
+	err := worm.call(ctx, &result, "eth_queryMinerProxy", rpc.BlockNumber(number), accounts)
 	if err != nil {
 		return nil, err
 	}
@@ -359,7 +1034,7 @@ func (worm *Wormholes) QueryMinerProxy(ctx context.Context, number int64, accoun
 }
 
 func (w *Wallet) Sign(data []byte, priKey string) ([]byte, error) {
-	key, err := crypto.HexToECDSA(priKey)
+	_, key, err := tools.PriKeyToAddress(priKey)
 	if err != nil {
 		return nil, err
 	}
@@ -369,75 +1044,79 @@ func (w *Wallet) Sign(data []byte, priKey string) ([]byte, error) {
 		return nil, err
 	}
 
-	signature[64] += 27
+	signature[64] += w.SignatureVOffset()
 
 	return signature, nil
 }
 
+// signMessage signs the signing hash of an order or an authorization with the key of the wallet
+// and returns the hex signature.
+func (w *Wallet) signMessage(hash common.Hash) (string, error) {
+	signature, err := w.signHash(hash[:])
+	if err != nil {
+		return "", err
+	}
+	signature[64] += w.SignatureVOffset()
+	return hexutil.Encode(signature), nil
+}
+
 // SignBuyer
 // amount: The amount the buyer purchased the NFT, formatted as a hexadecimal string
 // nftAddress: The NFT address of the transaction. The format is a hexadecimal string. When this field is filled in, it means that the transaction has minted nft. When not filled, it means lazy transaction, and the nft has not been minted
-// exchanger: The exchange on which the transaction took place, formatted as a decimal string
+// exchanger: The exchange on which the transaction took place, a hex address, checksummed when in mixed case
 // blockNumber: Block height, which means that this transaction is valid before this height, the format is a hexadecimal string
 // seller: Seller's address, formatted as a hexadecimal string
 func (w *Wallet) SignBuyer(amount, nftAddress, exchanger, blockNumber, seller string) ([]byte, error) {
-	key, err := crypto.HexToECDSA(w.priKey)
+	exchanger, err := checkOptionalExchanger("SignBuyer() exchanger", w.resolve(context.Background(), exchanger))
 	if err != nil {
 		return nil, err
 	}
-
-	msg := amount + nftAddress + exchanger + blockNumber + seller
-	signature, err := crypto.Sign(tools.SignHash([]byte(msg)), key)
-	if err != nil {
-		return nil, err
-	}
-
-	signature[64] += 27
-
-	buyer := types2.Buyer{
+	return w.SignBuyerOrder(&types2.Buyer{
 		Amount:      amount,
 		NFTAddress:  nftAddress,
 		Exchanger:   exchanger,
 		BlockNumber: blockNumber,
-		Seller:      seller,
-		Sig:         hexutil.Encode(signature),
-	}
+		Seller:      w.resolve(context.Background(), seller),
+	})
+}
 
-	result, err := json.Marshal(buyer)
+// SignBuyerOrder signs an order built with types.NewBuyer and returns it as JSON.
+func (w *Wallet) SignBuyerOrder(b *types2.Buyer) ([]byte, error) {
+	if err := b.Validate(); err != nil {
+		return nil, err
+	}
+	buyer := *b
+	sig, err := w.signMessage(buyer.SigningHash())
 	if err != nil {
 		return nil, err
 	}
-	return result, nil
+	buyer.Sig = sig
+	return json.Marshal(buyer)
+}
+
+// SignBuyerAmount is SignBuyer with the amount as a types.Amount.
+func (w *Wallet) SignBuyerAmount(amount types2.Amount, nftAddress, exchanger, blockNumber, seller string) ([]byte, error) {
+	return w.SignBuyer(amount.Hex(), nftAddress, exchanger, blockNumber, seller)
 }
 
 // SignBuyerAuth
-// exchanger: The exchange on which the transaction took place, formatted as a decimal string
+// exchanger: The exchange on which the transaction took place, a hex address, checksummed when in mixed case
 // blockNumber: Block height, which means that this transaction is valid before this height, the format is a hexadecimal string
 func (w *Wallet) SignBuyerAuth(exchanger, blockNumber string) ([]byte, error) {
-	key, err := crypto.HexToECDSA(w.priKey)
+	exchanger, err := checkExchanger("SignBuyerAuth() exchanger", w.resolve(context.Background(), exchanger))
 	if err != nil {
 		return nil, err
 	}
-
-	msg := exchanger + blockNumber
-	signature, err := crypto.Sign(tools.SignHash([]byte(msg)), key)
-	if err != nil {
+	auth := types2.Buyauth{Exchanger: exchanger, BlockNumber: blockNumber}
+	if err := auth.Validate(); err != nil {
 		return nil, err
 	}
-
-	signature[64] += 27
-
-	buyer := types2.Buyauth{
-		Exchanger:   exchanger,
-		BlockNumber: blockNumber,
-		Sig:         hexutil.Encode(signature),
-	}
-
-	result, err := json.Marshal(buyer)
+	sig, err := w.signMessage(auth.SigningHash())
 	if err != nil {
 		return nil, err
 	}
-	return result, nil
+	auth.Sig = sig
+	return json.Marshal(auth)
 }
 
 // SignSeller1
@@ -445,161 +1124,158 @@ func (w *Wallet) SignBuyerAuth(exchanger, blockNumber string) ([]byte, error) {
 //
 //	amount: The amount the buyer purchased the NFT, formatted as a hexadecimal string
 //	nftAddress: The NFT address of the transaction, formatted as a hexadecimal string
-//	exchanger:	The exchange on which the transaction took place, formatted as a decimal string
+//	exchanger:	The exchange on which the transaction took place, a hex address, checksummed when in mixed case
 //	blockNumber: Block height, which means that this transaction is valid before this height, the format is a hexadecimal string
 func (w *Wallet) SignSeller1(amount, nftAddress, exchanger, blockNumber string) ([]byte, error) {
-	key, err := crypto.HexToECDSA(w.priKey)
-	if err != nil {
-		return nil, err
-	}
-
-	msg := amount + nftAddress + exchanger + blockNumber
-	signature, err := crypto.Sign(tools.SignHash([]byte(msg)), key)
+	exchanger, err := checkOptionalExchanger("SignSeller1() exchanger", w.resolve(context.Background(), exchanger))
 	if err != nil {
 		return nil, err
 	}
-
-	signature[64] += 27
-
-	seller1 := types2.Seller1{
+	return w.SignSeller1Order(&types2.Seller1{
 		Amount:      amount,
 		NFTAddress:  nftAddress,
 		Exchanger:   exchanger,
 		BlockNumber: blockNumber,
-		Sig:         hexutil.Encode(signature),
-	}
+	})
+}
 
-	result, err := json.Marshal(seller1)
+// SignSeller1Order signs an order built with types.NewSeller1 and returns it as JSON.
+func (w *Wallet) SignSeller1Order(s *types2.Seller1) ([]byte, error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	seller1 := *s
+	sig, err := w.signMessage(seller1.SigningHash())
 	if err != nil {
 		return nil, err
 	}
-	return result, nil
+	seller1.Sig = sig
+	return json.Marshal(seller1)
+}
+
+// SignSeller1Amount is SignSeller1 with the amount as a types.Amount.
+func (w *Wallet) SignSeller1Amount(amount types2.Amount, nftAddress, exchanger, blockNumber string) ([]byte, error) {
+	return w.SignSeller1(amount.Hex(), nftAddress, exchanger, blockNumber)
 }
 
 // SignSeller2
 // Signed Unminted Seller
 //
 //	amount: The amount of the NFT transaction, formatted as a hexadecimal string
-//	royalty: royalty, hex string
+//	royalty: royalty in basis points as a hex string, at most 0x2710, see types.ParseRoyalty
 //	metaURL: NFT metadata address
 //	exclusiveFlag: "0": Inclusive, "1": Exclusive
-//	exchanger:	The exchange on which the transaction took place, formatted as a decimal string
+//	exchanger:	The exchange on which the transaction took place, a hex address, checksummed when in mixed case
 //	blockNumber: Block height, which means that this transaction is valid before this height, the format is a hexadecimal string
 func (w *Wallet) SignSeller2(amount, royalty, metaURL, exclusiveFlag, exchanger, blockNumber string) ([]byte, error) {
-	key, err := crypto.HexToECDSA(w.priKey)
+	exchanger, err := checkOptionalExchanger("SignSeller2() exchanger", w.resolve(context.Background(), exchanger))
 	if err != nil {
 		return nil, err
 	}
-
-	msg := amount + royalty + metaURL + exclusiveFlag + exchanger + blockNumber
-	signature, err := crypto.Sign(tools.SignHash([]byte(msg)), key)
-	if err != nil {
-		return nil, err
-	}
-
-	signature[64] += 27
-
-	seller2 := types2.Seller2{
+	return w.SignSeller2Order(&types2.Seller2{
 		Amount:        amount,
 		Royalty:       royalty,
 		MetaURL:       metaURL,
 		ExclusiveFlag: exclusiveFlag,
 		Exchanger:     exchanger,
 		BlockNumber:   blockNumber,
-		Sig:           hexutil.Encode(signature),
-	}
+	})
+}
 
-	result, err := json.Marshal(seller2)
+// SignSeller2Order signs an order built with types.NewSeller2 and returns it as JSON.
+func (w *Wallet) SignSeller2Order(s *types2.Seller2) ([]byte, error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	seller2 := *s
+	sig, err := w.signMessage(seller2.SigningHash())
 	if err != nil {
 		return nil, err
 	}
-	return result, nil
+	seller2.Sig = sig
+	return json.Marshal(seller2)
+}
+
+// SignSeller2Amount is SignSeller2 with the amount as a types.Amount.
+func (w *Wallet) SignSeller2Amount(amount types2.Amount, royalty, metaURL, exclusiveFlag, exchanger, blockNumber string) ([]byte, error) {
+	return w.SignSeller2(amount.Hex(), royalty, metaURL, exclusiveFlag, exchanger, blockNumber)
 }
 
 // SignSellerAuth
 //
-//	exchanger:	The exchange on which the transaction took place, formatted as a decimal string
+//	exchanger:	The exchange on which the transaction took place, a hex address, checksummed when in mixed case
 //	blockNumber: Block height, which means that this transaction is valid before this height, the format is a hexadecimal string
 func (w *Wallet) SignSellerAuth(exchanger, blockNumber string) ([]byte, error) {
-	key, err := crypto.HexToECDSA(w.priKey)
+	exchanger, err := checkExchanger("SignSellerAuth() exchanger", w.resolve(context.Background(), exchanger))
 	if err != nil {
 		return nil, err
 	}
-
-	msg := exchanger + blockNumber
-	signature, err := crypto.Sign(tools.SignHash([]byte(msg)), key)
-	if err != nil {
+	auth := types2.Sellerauth{Exchanger: exchanger, BlockNumber: blockNumber}
+	if err := auth.Validate(); err != nil {
 		return nil, err
 	}
-
-	signature[64] += 27
-
-	seller1 := types2.Sellerauth{
-		Exchanger:   exchanger,
-		BlockNumber: blockNumber,
-		Sig:         hexutil.Encode(signature),
-	}
-
-	result, err := json.Marshal(seller1)
+	sig, err := w.signMessage(auth.SigningHash())
 	if err != nil {
 		return nil, err
 	}
-	return result, nil
+	auth.Sig = sig
+	return json.Marshal(auth)
 }
 
 // SignExchanger
 // Signed by an authorized exchange
 //
-//	exchangerOwner: Authorize exchange, formatted as a hexadecimal string
+//	exchangerOwner: Authorize exchange, a hex address, checksummed when in mixed case
 //	to: Authorized exchange, formatted as a hexadecimal string
 //	block_number: Block height, which means that this transaction is valid before this height, the format is a hexadecimal string
 func (w *Wallet) SignExchanger(exchangerOwner, to, blockNumber string) ([]byte, error) {
-	key, err := crypto.HexToECDSA(w.priKey)
+	exchangerOwner, err := checkExchanger("SignExchanger() exchangerOwner", w.resolve(context.Background(), exchangerOwner))
 	if err != nil {
 		return nil, err
 	}
-
-	msg := exchangerOwner + to + blockNumber
-	signature, err := crypto.Sign(tools.SignHash([]byte(msg)), key)
-	if err != nil {
-		return nil, err
-	}
-
-	signature[64] += 27
-
-	exchangeAuth := types2.ExchangerAuth{
+	return w.SignExchangerAuth(&types2.ExchangerAuth{
 		ExchangerOwner: exchangerOwner,
-		To:             to,
+		To:             w.resolve(context.Background(), to),
 		BlockNumber:    blockNumber,
-		Sig:            hexutil.Encode(signature),
-	}
+	})
+}
 
-	result, err := json.Marshal(exchangeAuth)
+// SignExchangerAuth signs an authorization built with types.NewExchangerAuth and returns it as JSON.
+func (w *Wallet) SignExchangerAuth(a *types2.ExchangerAuth) ([]byte, error) {
+	if err := a.Validate(); err != nil {
+		return nil, err
+	}
+	auth := *a
+	sig, err := w.signMessage(auth.SigningHash())
 	if err != nil {
 		return nil, err
 	}
-	return result, nil
+	auth.Sig = sig
+	return json.Marshal(auth)
 }
 
 func (w *Wallet) SignDelegate(address, pledgeAcoount string) ([]byte, error) {
-	key, err := crypto.HexToECDSA(w.priKey)
-	if err != nil {
+	address = w.resolve(context.Background(), address)
+	pledgeAcoount = w.resolve(context.Background(), pledgeAcoount)
+	if err := tools.CheckAddress("SignDelegate() address", address); err != nil {
+		return nil, err
+	}
+	if err := tools.CheckAddress("SignDelegate() pledgeAcoount", pledgeAcoount); err != nil {
 		return nil, err
 	}
-
 	msg := address + pledgeAcoount
-	signature, err := crypto.Sign(tools.SignHash([]byte(msg)), key)
+	signature, err := w.signHash(tools.SignHash([]byte(msg)))
 	if err != nil {
 		return nil, err
 	}
 
-	signature[64] += 27
+	signature[64] += w.SignatureVOffset()
 	return []byte(hexutil.Encode(signature)), nil
 }
 
 func (worm *Wormholes) GetRandom11ValidatorsWithOutProxy(ctx context.Context, number uint64) ([]common.Address, error) {
 	var res []common.Address
-	err := worm.c.CallContext(ctx, &res, "erb_getValidators", rpc.BlockNumber(number))
+	err := worm.call(ctx, &res, "erb_getValidators", rpc.BlockNumber(number))
 	if err != nil {
 		return nil, err
 	}
@@ -608,7 +1284,7 @@ func (worm *Wormholes) GetRandom11ValidatorsWithOutProxy(ctx context.Context, nu
 
 func (worm *Wormholes) GetRandom11ValidatorsWithProxy(ctx context.Context, number uint64) ([]common.Address, error) {
 	var res []common.Address
-	err := worm.c.CallContext(ctx, &res, "erb_getElevenValidatorsWithProxy", rpc.BlockNumber(number))
+	err := worm.call(ctx, &res, "erb_getElevenValidatorsWithProxy", rpc.BlockNumber(number))
 	if err != nil {
 		return nil, err
 	}
@@ -617,7 +1293,7 @@ func (worm *Wormholes) GetRandom11ValidatorsWithProxy(ctx context.Context, numbe
 
 func (worm *Wormholes) GetRealAddr(ctx context.Context, addr common.Address) (common.Address, error) {
 	var res common.Address
-	err := worm.c.CallContext(ctx, &res, "erb_getRealAddr", addr)
+	err := worm.call(ctx, &res, "erb_getRealAddr", addr)
 	if err != nil {
 		return res, err
 	}
@@ -627,7 +1303,7 @@ func (worm *Wormholes) GetRealAddr(ctx context.Context, addr common.Address) (co
 func (worm *Wormholes) GetCoefficientByNumber(ctx context.Context, number uint64) ([]*types2.BlockParticipants, error) {
 	blockNo := rpc.BlockNumber(number)
 	var res []*types2.BlockParticipants
-	err := worm.c.CallContext(ctx, &res, "erb_getCoefficientByNumber", blockNo)
+	err := worm.call(ctx, &res, "erb_getCoefficientByNumber", blockNo)
 	if err != nil {
 		return res, err
 	}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/erbieio/erb-client/v2/client"
+	"github.com/erbieio/erb-client/v2/loadgen"
+	"golang.org/x/xerrors"
+)
+
+func init() {
+	register("loadgen", "-keys file [-mix mint=1,transfer=1,...] [-nfts a,b] [-duration d] [-count n] [-rate tps] generate load on a devnet", runLoadgen)
+}
+
+func runLoadgen(e *env, args []string) (interface{}, error) {
+	fs := flag.NewFlagSet("loadgen", flag.ContinueOnError)
+	keysFile := fs.String("keys", "", "file of funded hex private keys, one per line")
+	mix := fs.String("mix", "", "weights of mint, transfer, trade and pledge, all equal by default")
+	nfts := fs.String("nfts", "", "comma separated NFTs owned by the keys, for transfers and trades")
+	exchanger := fs.String("exchanger", "", "open exchange of the trades")
+	duration := fs.Duration("duration", 0, "how long to send, 1m by default")
+	count := fs.Int("count", 0, "stop after this many transactions")
+	rate := fs.Float64("rate", 0, "transactions per second of all keys, unlimited by default")
+	if _, err := parseArgs(fs, args); err != nil {
+		return nil, err
+	}
+	if *keysFile == "" {
+		return nil, xerrors.New("loadgen needs -keys")
+	}
+	data, err := ioutil.ReadFile(*keysFile)
+	if err != nil {
+		return nil, xerrors.Errorf("read %s fail. %v", *keysFile, err)
+	}
+	cfg := loadgen.Config{
+		Keys:      strings.Fields(string(data)),
+		Exchanger: *exchanger,
+		Duration:  *duration,
+		Count:     *count,
+		Rate:      *rate,
+	}
+	if *nfts != "" {
+		cfg.NFTs = strings.Split(*nfts, ",")
+	}
+	if *mix != "" {
+		cfg.Mix = make(map[loadgen.Kind]int)
+		for _, weight := range strings.Split(*mix, ",") {
+			kind, n, _ := strings.Cut(weight, "=")
+			w, err := strconv.Atoi(n)
+			if err != nil {
+				return nil, xerrors.Errorf("invalid mix weight %q", weight)
+			}
+			cfg.Mix[loadgen.Kind(kind)] = w
+		}
+	}
+	if e.rpcURL == "" {
+		return nil, xerrors.New("no node endpoint, set -rpc or ERB_RPC")
+	}
+	worm, err := client.DialWithTransport("", e.rpcURL, client.TransportConfig{MaxIdleConns: len(cfg.Keys)})
+	if err != nil {
+		return nil, err
+	}
+	defer worm.CloseConnect()
+	g, err := loadgen.New(worm, cfg)
+	if err != nil {
+		return nil, err
+	}
+	report, err := g.Run(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	report.Print(os.Stderr)
+	return report, nil
+}
@@ -0,0 +1,225 @@
+// Command erb exposes the operations of the erb client on the command line.
+//
+// Usage:
+//
+//	erb [global flags] <command> [command flags] [args]
+//
+// Every command prints its result as JSON on stdout.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/erbieio/erb-client/v2/client"
+	"github.com/erbieio/erb-client/v2/tools"
+	"github.com/erbieio/erb-client/v2/vault"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/xerrors"
+)
+
+type command struct {
+	usage string
+	run   func(env *env, args []string) (interface{}, error)
+}
+
+var commands = map[string]command{}
+
+func register(name, usage string, run func(env *env, args []string) (interface{}, error)) {
+	commands[name] = command{usage: usage, run: run}
+}
+
+// env holds the global flags shared by all commands.
+type env struct {
+	rpcURL       string
+	priKey       string
+	keystorePath string
+	passwordFile string
+	vaultPath    string
+	namesPath    string
+	ensRegistry  string
+}
+
+// client dials the node, signing with the configured key if one is needed.
+func (e *env) client(needKey bool) (*client.Wormholes, error) {
+	key := ""
+	if needKey {
+		var err error
+		if key, err = e.key(); err != nil {
+			return nil, err
+		}
+	}
+	if e.rpcURL == "" {
+		return nil, xerrors.New("no node endpoint, set -rpc or ERB_RPC")
+	}
+	worm := client.NewClient(key, e.rpcURL)
+	if err := e.setNames(worm); err != nil {
+		return nil, err
+	}
+	return worm, nil
+}
+
+// wallet returns a client that can only sign, no node is needed.
+func (e *env) wallet() (*client.Wormholes, error) {
+	key, err := e.key()
+	if err != nil {
+		return nil, err
+	}
+	worm := client.NewClient(key, "")
+	if err := e.setNames(worm); err != nil {
+		return nil, err
+	}
+	return worm, nil
+}
+
+// names returns a client resolving names, which needs a node only for the -ens registry.
+func (e *env) names() (*client.Wormholes, error) {
+	if e.ensRegistry != "" {
+		return e.client(false)
+	}
+	worm := client.NewClient("", "")
+	if err := e.setNames(worm); err != nil {
+		return nil, err
+	}
+	return worm, nil
+}
+
+// setNames makes worm accept the aliases of -names and the names of the -ens registry.
+func (e *env) setNames(worm *client.Wormholes) error {
+	if e.namesPath != "" {
+		book, err := tools.LoadAddressBook(e.namesPath)
+		if err != nil {
+			return err
+		}
+		worm.SetAddressBook(book)
+	}
+	if e.ensRegistry != "" {
+		if err := tools.CheckAddress("-ens", e.ensRegistry); err != nil {
+			return err
+		}
+		worm.SetNameResolver(client.NewENSResolver(worm, e.ensRegistry))
+	}
+	return nil
+}
+
+func (e *env) key() (string, error) {
+	if e.vaultPath != "" {
+		provider, err := vault.New(vault.Config{})
+		if err != nil {
+			return "", err
+		}
+		return provider.PriKey(context.Background(), e.vaultPath)
+	}
+	if e.keystorePath != "" {
+		keyJSON, err := ioutil.ReadFile(e.keystorePath)
+		if err != nil {
+			return "", xerrors.Errorf("read %s fail. %v", e.keystorePath, err)
+		}
+		var password []byte
+		if e.passwordFile != "" {
+			if password, err = ioutil.ReadFile(e.passwordFile); err != nil {
+				return "", xerrors.Errorf("read %s fail. %v", e.passwordFile, err)
+			}
+		}
+		key, err := keystore.DecryptKey(keyJSON, strings.TrimRight(string(password), "\r\n"))
+		if err != nil {
+			return "", xerrors.Errorf("decrypt %s fail. %v", e.keystorePath, err)
+		}
+		return common.Bytes2Hex(crypto.FromECDSA(key.PrivateKey)), nil
+	}
+	if e.priKey == "" {
+		return "", xerrors.New("no private key, set -key, ERB_KEY, -keystore or -vault-key")
+	}
+	return tools.ParsePriKey(e.priKey)
+}
+
+func main() {
+	e := &env{}
+	global := flag.NewFlagSet("erb", flag.ExitOnError)
+	global.StringVar(&e.rpcURL, "rpc", os.Getenv("ERB_RPC"), "node endpoint")
+	global.StringVar(&e.priKey, "key", os.Getenv("ERB_KEY"), "hex private key")
+	global.StringVar(&e.keystorePath, "keystore", "", "keystore file of the signing key")
+	global.StringVar(&e.passwordFile, "password-file", "", "file holding the keystore password")
+	global.StringVar(&e.vaultPath, "vault-key", os.Getenv("ERB_VAULT_KEY"), "path of the Vault KV secret holding the signing key, see VAULT_ADDR and VAULT_TOKEN")
+	global.StringVar(&e.namesPath, "names", os.Getenv("ERB_NAMES"), "JSON address book of aliases accepted as addresses")
+	global.StringVar(&e.ensRegistry, "ens", os.Getenv("ERB_ENS"), "address of an ENS-style name registry, its names are accepted as addresses")
+	global.Usage = func() { usage(global) }
+	global.Parse(os.Args[1:])
+
+	if global.NArg() == 0 {
+		usage(global)
+		os.Exit(2)
+	}
+	name := global.Arg(0)
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n", name)
+		usage(global)
+		os.Exit(2)
+	}
+	result, err := cmd.run(e, global.Args()[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+	out := json.NewEncoder(os.Stdout)
+	out.SetIndent("", "  ")
+	if err := out.Encode(result); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+func usage(global *flag.FlagSet) {
+	fmt.Fprintln(os.Stderr, "usage: erb [global flags] <command> [command flags] [args]")
+	fmt.Fprintln(os.Stderr, "\nglobal flags:")
+	global.PrintDefaults()
+	fmt.Fprintln(os.Stderr, "\ncommands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-22s %s\n", name, commands[name].usage)
+	}
+}
+
+// parseArgs parses the command flags and checks the number of positional arguments.
+func parseArgs(fs *flag.FlagSet, args []string, positional ...string) ([]string, error) {
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() != len(positional) {
+		return nil, xerrors.Errorf("%s expects arguments: %s", fs.Name(), strings.Join(positional, " "))
+	}
+	return fs.Args(), nil
+}
+
+// txResult is the output of the commands that send a transaction.
+type txResult struct {
+	Hash string `json:"hash"`
+}
+
+func sent(hash string, err error) (interface{}, error) {
+	if err != nil {
+		return nil, err
+	}
+	return txResult{Hash: hash}, nil
+}
+
+// readOrder reads a signed order from a file, or from the argument itself if it is inline JSON.
+func readOrder(arg string) ([]byte, error) {
+	if strings.HasPrefix(strings.TrimSpace(arg), "{") {
+		return []byte(arg), nil
+	}
+	return ioutil.ReadFile(arg)
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"flag"
+
+	"github.com/erbieio/erb-client/v2/metadata"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func init() {
+	register("check-metadata", "[-gateway url] <nft address | meta url> check NFT metadata against the marketplace conventions", checkMetadata)
+}
+
+func checkMetadata(e *env, args []string) (interface{}, error) {
+	fs := flag.NewFlagSet("check-metadata", flag.ContinueOnError)
+	gateway := fs.String("gateway", "", "IPFS gateway, https://ipfs.io by default")
+	pos, err := parseArgs(fs, args, "<nft address | meta url>")
+	if err != nil {
+		return nil, err
+	}
+	checker := metadata.NewChecker(metadata.Config{Gateway: *gateway})
+	if !common.IsHexAddress(pos[0]) {
+		return checker.Check(context.Background(), pos[0])
+	}
+	worm, err := e.client(false)
+	if err != nil {
+		return nil, err
+	}
+	return checker.CheckNFT(context.Background(), worm, pos[0])
+}
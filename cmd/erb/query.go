@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"math/big"
+
+	"github.com/erbieio/erb-client/v2/client"
+	"github.com/erbieio/erb-client/v2/tools"
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+func init() {
+	register("block-number", "print the latest block number", blockNumber)
+	register("balance", "<address> print the balance in wei", balance)
+	register("account-info", "[-block n] <address> print the wormholes account state", accountInfo)
+	register("block", "[-number n | -hash h] print a block header", block)
+	register("receipt", "<tx hash> print a transaction receipt", receipt)
+	register("validators", "[-block n] print the validator list", validators)
+	register("resolve", "<name> print the address of a name or alias", resolve)
+	register("lookup", "<address> print the name of an address", lookup)
+	register("decode-data", "<hex data> decode the payload of a wormholes transaction", decodeData)
+}
+
+func resolve(e *env, args []string) (interface{}, error) {
+	pos, err := parseArgs(flag.NewFlagSet("resolve", flag.ContinueOnError), args, "<name>")
+	if err != nil {
+		return nil, err
+	}
+	worm, err := e.names()
+	if err != nil {
+		return nil, err
+	}
+	address, err := worm.Resolve(context.Background(), pos[0])
+	if err != nil {
+		return nil, err
+	}
+	return address.Hex(), nil
+}
+
+func lookup(e *env, args []string) (interface{}, error) {
+	pos, err := parseArgs(flag.NewFlagSet("lookup", flag.ContinueOnError), args, "<address>")
+	if err != nil {
+		return nil, err
+	}
+	if err := tools.CheckAddress("lookup", pos[0]); err != nil {
+		return nil, err
+	}
+	worm, err := e.names()
+	if err != nil {
+		return nil, err
+	}
+	return worm.ReverseLookup(context.Background(), common.HexToAddress(pos[0]))
+}
+
+func blockNumber(e *env, args []string) (interface{}, error) {
+	if _, err := parseArgs(flag.NewFlagSet("block-number", flag.ContinueOnError), args); err != nil {
+		return nil, err
+	}
+	worm, err := e.client(false)
+	if err != nil {
+		return nil, err
+	}
+	return worm.BlockNumber(context.Background())
+}
+
+func balance(e *env, args []string) (interface{}, error) {
+	fs := flag.NewFlagSet("balance", flag.ContinueOnError)
+	number := fs.Int64("block", -1, "block number, pending state by default")
+	pos, err := parseArgs(fs, args, "<address>")
+	if err != nil {
+		return nil, err
+	}
+	worm, err := e.client(false)
+	if err != nil {
+		return nil, err
+	}
+	if *number < 0 {
+		return worm.Balance(context.Background(), pos[0])
+	}
+	return worm.BalanceAt(context.Background(), pos[0], big.NewInt(*number))
+}
+
+func accountInfo(e *env, args []string) (interface{}, error) {
+	fs := flag.NewFlagSet("account-info", flag.ContinueOnError)
+	number := fs.Int64("block", int64(rpc.LatestBlockNumber), "block number, latest by default")
+	pos, err := parseArgs(fs, args, "<address>")
+	if err != nil {
+		return nil, err
+	}
+	worm, err := e.client(false)
+	if err != nil {
+		return nil, err
+	}
+	return worm.GetAccountInfo(context.Background(), pos[0], *number)
+}
+
+func block(e *env, args []string) (interface{}, error) {
+	fs := flag.NewFlagSet("block", flag.ContinueOnError)
+	number := fs.Int64("number", -1, "block number, latest by default")
+	hash := fs.String("hash", "", "block hash")
+	if _, err := parseArgs(fs, args); err != nil {
+		return nil, err
+	}
+	worm, err := e.client(false)
+	if err != nil {
+		return nil, err
+	}
+	if *hash != "" {
+		return worm.HeaderByHash(context.Background(), common.HexToHash(*hash))
+	}
+	if *number < 0 {
+		return worm.HeaderByNumber(context.Background(), nil)
+	}
+	return worm.HeaderByNumber(context.Background(), big.NewInt(*number))
+}
+
+func receipt(e *env, args []string) (interface{}, error) {
+	pos, err := parseArgs(flag.NewFlagSet("receipt", flag.ContinueOnError), args, "<tx hash>")
+	if err != nil {
+		return nil, err
+	}
+	worm, err := e.client(false)
+	if err != nil {
+		return nil, err
+	}
+	return worm.TransactionReceipt(context.Background(), pos[0])
+}
+
+func validators(e *env, args []string) (interface{}, error) {
+	fs := flag.NewFlagSet("validators", flag.ContinueOnError)
+	number := fs.Int64("block", int64(rpc.LatestBlockNumber), "block number, latest by default")
+	if _, err := parseArgs(fs, args); err != nil {
+		return nil, err
+	}
+	worm, err := e.client(false)
+	if err != nil {
+		return nil, err
+	}
+	return worm.GetValidators(context.Background(), *number)
+}
+
+// decodedData is the output of decode-data.
+type decodedData struct {
+	Type    uint8          `json:"type"`
+	Name    string         `json:"name"`
+	Payload types2.Payload `json:"payload"`
+}
+
+func decodeData(_ *env, args []string) (interface{}, error) {
+	pos, err := parseArgs(flag.NewFlagSet("decode-data", flag.ContinueOnError), args, "<hex data>")
+	if err != nil {
+		return nil, err
+	}
+	data, err := hexutil.Decode(pos[0])
+	if err != nil {
+		return nil, err
+	}
+	payload, err := client.DecodeWormholesData(data)
+	if err != nil {
+		return nil, err
+	}
+	return &decodedData{Type: payload.Type(), Name: types2.TypeName(payload.Type()), Payload: payload}, nil
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+
+	"github.com/erbieio/erb-client/v2/tools"
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/erbieio/erb-client/v2/vectors"
+)
+
+func init() {
+	register("sign-buyer", "-amount erb -nft addr -exchanger addr -block hex -seller addr sign a buy order", signBuyer)
+	register("sign-seller1", "-amount erb -nft addr -exchanger addr -block hex sign a sell order of a minted NFT", signSeller1)
+	register("sign-seller2", "-amount erb -royalty bps -meta url -exclusive 0|1 -exchanger addr -block hex sign a sell order of an unminted NFT", signSeller2)
+	register("sign-exchanger", "-owner addr -to addr -block hex sign an exchanger authorization", signExchanger)
+	register("test-vectors", "print the canonical signatures of every kind of order", testVectors)
+}
+
+// order prints the signed order as a JSON object rather than an encoded string.
+func order(data []byte, err error) (interface{}, error) {
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(data), nil
+}
+
+func signBuyer(e *env, args []string) (interface{}, error) {
+	fs := flag.NewFlagSet("sign-buyer", flag.ContinueOnError)
+	var amount types2.Amount
+	fs.TextVar(&amount, "amount", types2.Amount{}, "price in ERB, or in wei as 0x hex")
+	nft := fs.String("nft", "", "NFT address, empty for unminted NFTs")
+	exchanger := fs.String("exchanger", "", "exchanger address")
+	block := fs.String("block", "", "block height the order is valid before, hex")
+	seller := fs.String("seller", "", "seller address")
+	if _, err := parseArgs(fs, args); err != nil {
+		return nil, err
+	}
+	worm, err := e.wallet()
+	if err != nil {
+		return nil, err
+	}
+	return order(worm.SignBuyerAmount(amount, *nft, *exchanger, *block, *seller))
+}
+
+func signSeller1(e *env, args []string) (interface{}, error) {
+	fs := flag.NewFlagSet("sign-seller1", flag.ContinueOnError)
+	var amount types2.Amount
+	fs.TextVar(&amount, "amount", types2.Amount{}, "price in ERB, or in wei as 0x hex")
+	nft := fs.String("nft", "", "NFT address")
+	exchanger := fs.String("exchanger", "", "exchanger address")
+	block := fs.String("block", "", "block height the order is valid before, hex")
+	if _, err := parseArgs(fs, args); err != nil {
+		return nil, err
+	}
+	worm, err := e.wallet()
+	if err != nil {
+		return nil, err
+	}
+	return order(worm.SignSeller1Amount(amount, *nft, *exchanger, *block))
+}
+
+func signSeller2(e *env, args []string) (interface{}, error) {
+	fs := flag.NewFlagSet("sign-seller2", flag.ContinueOnError)
+	var amount types2.Amount
+	fs.TextVar(&amount, "amount", types2.Amount{}, "price in ERB, or in wei as 0x hex")
+	royalty := fs.String("royalty", "0", "royalty in basis points, at most 10000")
+	meta := fs.String("meta", "", "metadata URL")
+	exclusive := fs.String("exclusive", "0", "0: inclusive, 1: exclusive")
+	exchanger := fs.String("exchanger", "", "exchanger address")
+	block := fs.String("block", "", "block height the order is valid before, hex")
+	if _, err := parseArgs(fs, args); err != nil {
+		return nil, err
+	}
+	bps, err := types2.ParseRoyalty(*royalty)
+	if err != nil {
+		return nil, err
+	}
+	worm, err := e.wallet()
+	if err != nil {
+		return nil, err
+	}
+	return order(worm.SignSeller2Amount(amount, tools.EncodeRoyalty(bps), *meta, *exclusive, *exchanger, *block))
+}
+
+func signExchanger(e *env, args []string) (interface{}, error) {
+	fs := flag.NewFlagSet("sign-exchanger", flag.ContinueOnError)
+	owner := fs.String("owner", "", "authorizing exchanger address")
+	to := fs.String("to", "", "authorized exchanger address")
+	block := fs.String("block", "", "block height the authorization is valid before, hex")
+	if _, err := parseArgs(fs, args); err != nil {
+		return nil, err
+	}
+	worm, err := e.wallet()
+	if err != nil {
+		return nil, err
+	}
+	return order(worm.SignExchanger(*owner, *to, *block))
+}
+
+func testVectors(_ *env, args []string) (interface{}, error) {
+	if _, err := parseArgs(flag.NewFlagSet("test-vectors", flag.ContinueOnError), args); err != nil {
+		return nil, err
+	}
+	return vectors.Generate()
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func init() {
+	register("send", "-value erb <to> send ERB", send)
+	register("mint", "-royalty bps -meta url [-exchanger addr] mint an NFT", mint)
+	register("transfer", "<nft> <to> transfer an NFT", transfer)
+	register("author", "<nft> <to> authorize an exchanger for an NFT", author)
+	register("author-revoke", "<nft> <to> revoke an NFT authorization", authorRevoke)
+	register("pledge", "-value erb [-proxy addr -name s -url s -feerate n] <to> pledge ERB", pledge)
+	register("revoke-pledge", "-value erb <to> revoke pledged ERB", revokePledge)
+	register("trade", "<buyer order> <buyer address> settle a minted NFT sale (TransactionNFT)", trade)
+	register("buy", "<seller1 order> buy a minted NFT as the buyer", buy)
+	register("exchange-match", "<buyer> <seller1> <exchanger auth> <buyer address> settle through an authorized exchanger", exchangeMatch)
+	register("foundry-exchange", "<buyer> <seller2> <buyer address> settle a sale of an unminted NFT", foundryExchange)
+}
+
+func send(e *env, args []string) (interface{}, error) {
+	fs := flag.NewFlagSet("send", flag.ContinueOnError)
+	var value types2.Amount
+	fs.TextVar(&value, "value", types2.Amount{}, "amount in ERB, or in wei as 0x hex")
+	data := fs.String("data", "", "transaction data")
+	pos, err := parseArgs(fs, args, "<to>")
+	if err != nil {
+		return nil, err
+	}
+	worm, err := e.client(true)
+	if err != nil {
+		return nil, err
+	}
+	return sent(worm.NormalTransactionCtx(context.Background(), pos[0], value, *data))
+}
+
+func mint(e *env, args []string) (interface{}, error) {
+	fs := flag.NewFlagSet("mint", flag.ContinueOnError)
+	royalty := fs.Uint("royalty", 0, "royalty in basis points, at most 10000")
+	meta := fs.String("meta", "", "metadata URL")
+	exchanger := fs.String("exchanger", "", "exclusive exchanger")
+	if _, err := parseArgs(fs, args); err != nil {
+		return nil, err
+	}
+	worm, err := e.client(true)
+	if err != nil {
+		return nil, err
+	}
+	if *royalty > types2.MaxRoyalty {
+		return nil, fmt.Errorf("royalty %d is out of range 0..%d basis points", *royalty, types2.MaxRoyalty)
+	}
+	return sent(worm.Mint(uint32(*royalty), *meta, *exchanger))
+}
+
+func transfer(e *env, args []string) (interface{}, error) {
+	pos, err := parseArgs(flag.NewFlagSet("transfer", flag.ContinueOnError), args, "<nft>", "<to>")
+	if err != nil {
+		return nil, err
+	}
+	worm, err := e.client(true)
+	if err != nil {
+		return nil, err
+	}
+	return sent(worm.Transfer(pos[0], pos[1]))
+}
+
+func author(e *env, args []string) (interface{}, error) {
+	pos, err := parseArgs(flag.NewFlagSet("author", flag.ContinueOnError), args, "<nft>", "<to>")
+	if err != nil {
+		return nil, err
+	}
+	worm, err := e.client(true)
+	if err != nil {
+		return nil, err
+	}
+	return sent(worm.Author(pos[0], pos[1]))
+}
+
+func authorRevoke(e *env, args []string) (interface{}, error) {
+	pos, err := parseArgs(flag.NewFlagSet("author-revoke", flag.ContinueOnError), args, "<nft>", "<to>")
+	if err != nil {
+		return nil, err
+	}
+	worm, err := e.client(true)
+	if err != nil {
+		return nil, err
+	}
+	return sent(worm.AuthorRevoke(pos[0], pos[1]))
+}
+
+func pledge(e *env, args []string) (interface{}, error) {
+	fs := flag.NewFlagSet("pledge", flag.ContinueOnError)
+	var value types2.Amount
+	fs.TextVar(&value, "value", types2.Amount{}, "amount in ERB, or in wei as 0x hex")
+	proxy := fs.String("proxy", "", "proxy address")
+	name := fs.String("name", "", "exchanger name")
+	url := fs.String("url", "", "exchanger URL")
+	feeRate := fs.Int("feerate", 0, "exchanger fee rate")
+	pos, err := parseArgs(fs, args, "<to>")
+	if err != nil {
+		return nil, err
+	}
+	worm, err := e.client(true)
+	if err != nil {
+		return nil, err
+	}
+	return sent(worm.TokenPledgeCtx(context.Background(), common.HexToAddress(pos[0]), *proxy, *name, *url, value, *feeRate))
+}
+
+func revokePledge(e *env, args []string) (interface{}, error) {
+	fs := flag.NewFlagSet("revoke-pledge", flag.ContinueOnError)
+	var value types2.Amount
+	fs.TextVar(&value, "value", types2.Amount{}, "amount in ERB, or in wei as 0x hex")
+	pos, err := parseArgs(fs, args, "<to>")
+	if err != nil {
+		return nil, err
+	}
+	worm, err := e.client(true)
+	if err != nil {
+		return nil, err
+	}
+	return sent(worm.TokenRevokesPledgeCtx(context.Background(), common.HexToAddress(pos[0]), value))
+}
+
+func trade(e *env, args []string) (interface{}, error) {
+	pos, err := parseArgs(flag.NewFlagSet("trade", flag.ContinueOnError), args, "<buyer order>", "<buyer address>")
+	if err != nil {
+		return nil, err
+	}
+	buyer, err := readOrder(pos[0])
+	if err != nil {
+		return nil, err
+	}
+	worm, err := e.client(true)
+	if err != nil {
+		return nil, err
+	}
+	return sent(worm.TransactionNFT(buyer, pos[1]))
+}
+
+func buy(e *env, args []string) (interface{}, error) {
+	pos, err := parseArgs(flag.NewFlagSet("buy", flag.ContinueOnError), args, "<seller1 order>")
+	if err != nil {
+		return nil, err
+	}
+	seller1, err := readOrder(pos[0])
+	if err != nil {
+		return nil, err
+	}
+	worm, err := e.client(true)
+	if err != nil {
+		return nil, err
+	}
+	return sent(worm.BuyerInitiatingTransaction(seller1))
+}
+
+func exchangeMatch(e *env, args []string) (interface{}, error) {
+	pos, err := parseArgs(flag.NewFlagSet("exchange-match", flag.ContinueOnError), args,
+		"<buyer>", "<seller1>", "<exchanger auth>", "<buyer address>")
+	if err != nil {
+		return nil, err
+	}
+	orders := make([][]byte, 3)
+	for i := range orders {
+		if orders[i], err = readOrder(pos[i]); err != nil {
+			return nil, err
+		}
+	}
+	worm, err := e.client(true)
+	if err != nil {
+		return nil, err
+	}
+	return sent(worm.NftExchangeMatch(orders[0], orders[1], orders[2], pos[3]))
+}
+
+func foundryExchange(e *env, args []string) (interface{}, error) {
+	pos, err := parseArgs(flag.NewFlagSet("foundry-exchange", flag.ContinueOnError), args,
+		"<buyer>", "<seller2>", "<buyer address>")
+	if err != nil {
+		return nil, err
+	}
+	buyer, err := readOrder(pos[0])
+	if err != nil {
+		return nil, err
+	}
+	seller2, err := readOrder(pos[1])
+	if err != nil {
+		return nil, err
+	}
+	worm, err := e.client(true)
+	if err != nil {
+		return nil, err
+	}
+	return sent(worm.FoundryExchange(buyer, seller2, pos[2]))
+}
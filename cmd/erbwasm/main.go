@@ -0,0 +1,83 @@
+//go:build js && wasm
+
+// Command erbwasm exposes the signing of the client to JavaScript, so web frontends produce the
+// same buyer, seller and exchanger signatures as the backend. Build it with
+//
+//	GOOS=js GOARCH=wasm go build -o erb.wasm ./cmd/erbwasm
+//
+// and load erb.wasm with the wasm_exec.js of the Go release. It sets the global object erb,
+// whose functions take the hex private key first and return {result} or {error}:
+//
+//	erb.address(priKey)
+//	erb.signBuyer(priKey, amount, nftAddress, exchanger, blockNumber, seller)
+//	erb.signSeller1(priKey, amount, nftAddress, exchanger, blockNumber)
+//	erb.signSeller2(priKey, amount, royalty, metaURL, exclusiveFlag, exchanger, blockNumber)
+//	erb.signExchanger(priKey, exchangerOwner, to, blockNumber)
+//	erb.recoverAddress(message, signature)
+//
+// The signed orders are JSON strings, as returned by the Sign methods of client.Wallet.
+package main
+
+import (
+	"syscall/js"
+
+	"github.com/erbieio/erb-client/v2/client"
+	"github.com/erbieio/erb-client/v2/tools"
+	"golang.org/x/xerrors"
+)
+
+func main() {
+	js.Global().Set("erb", js.ValueOf(map[string]interface{}{
+		"address": export(1, func(args []string) (string, error) {
+			address, err := client.NewWallet(args[0]).Address()
+			return address.Hex(), err
+		}),
+		"signBuyer": export(6, func(args []string) (string, error) {
+			return signed(client.NewWallet(args[0]).SignBuyer(args[1], args[2], args[3], args[4], args[5]))
+		}),
+		"signSeller1": export(5, func(args []string) (string, error) {
+			return signed(client.NewWallet(args[0]).SignSeller1(args[1], args[2], args[3], args[4]))
+		}),
+		"signSeller2": export(7, func(args []string) (string, error) {
+			return signed(client.NewWallet(args[0]).SignSeller2(args[1], args[2], args[3], args[4], args[5], args[6]))
+		}),
+		"signExchanger": export(4, func(args []string) (string, error) {
+			return signed(client.NewWallet(args[0]).SignExchanger(args[1], args[2], args[3]))
+		}),
+		"recoverAddress": export(2, func(args []string) (string, error) {
+			address, err := tools.RecoverAddress(args[0], args[1])
+			return address.Hex(), err
+		}),
+	}))
+	// The functions must stay callable, the program never exits.
+	select {}
+}
+
+// export wraps f as a JavaScript function of n string arguments returning {result} or {error}.
+func export(n int, f func(args []string) (string, error)) js.Func {
+	return js.FuncOf(func(this js.Value, values []js.Value) interface{} {
+		if len(values) != n {
+			return failure(xerrors.Errorf("want %d arguments, got %d", n, len(values)))
+		}
+		args := make([]string, n)
+		for i, v := range values {
+			if v.Type() != js.TypeString {
+				return failure(xerrors.Errorf("argument %d is a %s, not a string", i+1, v.Type()))
+			}
+			args[i] = v.String()
+		}
+		result, err := f(args)
+		if err != nil {
+			return failure(err)
+		}
+		return map[string]interface{}{"result": result}
+	})
+}
+
+func failure(err error) interface{} {
+	return map[string]interface{}{"error": err.Error()}
+}
+
+func signed(data []byte, err error) (string, error) {
+	return string(data), err
+}
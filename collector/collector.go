@@ -0,0 +1,205 @@
+// Package collector sweeps newly distributed SNFT fragments from a set of reward addresses
+// to a treasury address.
+//
+// SNFT fragments are distributed in address order starting at 0x8000000000000000000000000000000000000000.
+// The collector walks that range, and for every fragment owned by one of its reward addresses
+// sends a Transfer signed with the key of that address.
+package collector
+
+import (
+	"context"
+	"log"
+	"math/big"
+	"time"
+
+	"github.com/erbieio/erb-client/v2/client"
+	"github.com/erbieio/erb-client/v2/tools"
+	"github.com/ethereum/go-ethereum/common"
+	"golang.org/x/xerrors"
+)
+
+const (
+	defaultBatchSize    = 100
+	defaultRetries      = 3
+	defaultRetryDelay   = 2 * time.Second
+	defaultPollInterval = 5 * time.Second
+)
+
+// FirstSNFT is the address of the first SNFT fragment.
+var FirstSNFT, _ = new(big.Int).SetString("8000000000000000000000000000000000000000", 16)
+
+// Config configures a Collector. Zero values select the defaults.
+type Config struct {
+	// PriKeys are the private keys of the reward addresses.
+	PriKeys []string
+	// Treasury receives the collected fragments.
+	Treasury common.Address
+	// Start is the address of the first fragment checked, FirstSNFT by default.
+	Start *big.Int
+	// BatchSize is the number of fragments checked per round, 100 by default.
+	BatchSize int
+	// Retries is the number of times a failed transfer is retried, 3 by default.
+	Retries int
+	// RetryDelay is the wait before retrying a failed transfer, 2s by default.
+	RetryDelay time.Duration
+	// PollInterval is the wait for new fragments once the collector caught up, 5s by default.
+	PollInterval time.Duration
+	// DryRun reports the fragments that would be collected without sending transactions.
+	DryRun bool
+}
+
+// Collected is a fragment moved, or in dry-run mode to be moved, to the treasury.
+type Collected struct {
+	SNFT  string
+	Owner common.Address
+	// TxHash is empty in dry-run mode.
+	TxHash string
+}
+
+// CollectedHandler is called for every collected fragment.
+type CollectedHandler func(collected *Collected)
+
+// Collector walks the SNFT range and transfers the fragments of its reward addresses.
+// It must not be used concurrently.
+type Collector struct {
+	worm     *client.Wormholes
+	cfg      Config
+	owners   map[common.Address]*client.Wormholes
+	next     *big.Int
+	handlers []CollectedHandler
+}
+
+// New creates a collector reading from and sending through worm.
+func New(worm *client.Wormholes, cfg Config) (*Collector, error) {
+	if cfg.Treasury == (common.Address{}) {
+		return nil, xerrors.New("collector: no treasury address")
+	}
+	if cfg.Start == nil {
+		cfg.Start = FirstSNFT
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultBatchSize
+	}
+	if cfg.Retries <= 0 {
+		cfg.Retries = defaultRetries
+	}
+	if cfg.RetryDelay <= 0 {
+		cfg.RetryDelay = defaultRetryDelay
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = defaultPollInterval
+	}
+	c := &Collector{
+		worm:   worm,
+		cfg:    cfg,
+		owners: make(map[common.Address]*client.Wormholes),
+		next:   new(big.Int).Set(cfg.Start),
+	}
+	for _, priKey := range cfg.PriKeys {
+		address, _, err := tools.PriKeyToAddress(priKey)
+		if err != nil {
+			return nil, err
+		}
+		c.owners[address] = worm.WithPriKey(priKey)
+	}
+	return c, nil
+}
+
+// OnCollected registers a handler called for every collected fragment.
+func (c *Collector) OnCollected(h CollectedHandler) {
+	c.handlers = append(c.handlers, h)
+}
+
+// Next returns the address of the next fragment to check.
+func (c *Collector) Next() common.Address {
+	return common.BigToAddress(c.next)
+}
+
+// Run collects until ctx is cancelled. Failures are logged and retried after the poll interval.
+// On cancellation the fragment in progress is finished or left for the next call and Run
+// returns ctx.Err(), Next reports where to resume.
+func (c *Collector) Run(ctx context.Context) error {
+	for {
+		caughtUp, err := c.CollectBatch(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			log.Println("collector batch err ", err)
+			caughtUp = true
+		}
+		if !caughtUp {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(c.cfg.PollInterval):
+		}
+	}
+}
+
+// CollectBatch checks up to BatchSize fragments, fetched in one batch of requests, and
+// collects the ones owned by a reward address. caughtUp reports that it stopped at a fragment
+// that is not distributed yet. On error the failing fragment is checked again by the next call.
+func (c *Collector) CollectBatch(ctx context.Context) (caughtUp bool, err error) {
+	latest, err := c.worm.BlockNumber(ctx)
+	if err != nil {
+		return false, err
+	}
+	snfts := make([]string, c.cfg.BatchSize)
+	next := new(big.Int).Set(c.next)
+	for i := range snfts {
+		snfts[i] = common.BigToAddress(next).Hex()
+		next.Add(next, big.NewInt(1))
+	}
+	results, err := c.worm.GetAccountInfoBatch(ctx, snfts, int64(latest))
+	if err != nil {
+		return false, err
+	}
+	for i, result := range results {
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+		if result.Err != nil {
+			return false, result.Err
+		}
+		snft := snfts[i]
+		owner := result.Account.Nft.Owner
+		if owner == (common.Address{}) {
+			return true, nil
+		}
+		if sender, ok := c.owners[owner]; ok {
+			if err := c.collect(ctx, sender, snft, owner); err != nil {
+				return false, err
+			}
+		}
+		c.next.Add(c.next, big.NewInt(1))
+	}
+	return false, nil
+}
+
+func (c *Collector) collect(ctx context.Context, sender *client.Wormholes, snft string, owner common.Address) error {
+	collected := &Collected{SNFT: snft, Owner: owner}
+	if !c.cfg.DryRun {
+		var err error
+		for attempt := 0; ; attempt++ {
+			collected.TxHash, err = sender.TransferCtx(ctx, snft, c.cfg.Treasury.Hex())
+			if err == nil {
+				break
+			}
+			if attempt == c.cfg.Retries {
+				return xerrors.Errorf("transfer %s: %w", snft, err)
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(c.cfg.RetryDelay):
+			}
+		}
+	}
+	for _, h := range c.handlers {
+		h(collected)
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+// Package export writes the on-chain history of accounts in formats for reconciliation tools.
+package export
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"math/big"
+	"strconv"
+
+	"github.com/erbieio/erb-client/v2/client"
+	"github.com/erbieio/erb-client/v2/scanner"
+	"github.com/erbieio/erb-client/v2/tools"
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum/common"
+	"golang.org/x/xerrors"
+)
+
+// Format is an output format of the exporter.
+type Format int
+
+const (
+	CSV Format = iota
+	JSONLines
+)
+
+// Directions of an activity relative to the exported account.
+const (
+	In   = "in"
+	Out  = "out"
+	Self = "self"
+)
+
+// Activity is a transaction touching the exported account.
+type Activity struct {
+	Block        uint64         `json:"block"`
+	Time         uint64         `json:"time"`
+	TxHash       common.Hash    `json:"tx_hash"`
+	Direction    string         `json:"direction"`
+	Counterparty common.Address `json:"counterparty"`
+	// Value and Fee are in wei. The fee is only set for transactions sent by the account.
+	Value      *big.Int `json:"value"`
+	TxType     string   `json:"tx_type"`
+	NFTAddress string   `json:"nft_address,omitempty"`
+	Fee        *big.Int `json:"fee"`
+}
+
+var csvHeader = []string{"block", "time", "tx_hash", "direction", "counterparty", "value", "tx_type", "nft_address", "fee"}
+
+// Activities returns the transactions of the blocks from..to (inclusive) sent by or to account.
+func Activities(ctx context.Context, worm *client.Wormholes, account common.Address, from, to uint64) ([]*Activity, error) {
+	var activities, paid []*Activity
+	var hashes []common.Hash
+	var gasPrices []*big.Int
+	pool := client.NewFetchPool(client.FetchConfig{})
+	s := scanner.New(worm, scanner.Config{Start: from, Pool: pool})
+	s.OnTransaction(func(ctx context.Context, tx *scanner.Transaction) error {
+		activity := activityOf(account, tx)
+		if activity == nil {
+			return nil
+		}
+		activities = append(activities, activity)
+		if activity.Direction != In {
+			paid = append(paid, activity)
+			hashes = append(hashes, tx.Tx.Hash())
+			gasPrices = append(gasPrices, tx.Tx.GasPrice())
+		}
+		return nil
+	})
+	if err := s.ScanRange(ctx, from, to); err != nil {
+		return nil, err
+	}
+	// The fees of the transactions sent by the account are in their receipts, fetched in parallel.
+	receipts, err := worm.GetReceipts(ctx, pool, hashes)
+	if err != nil {
+		return nil, err
+	}
+	for i, receipt := range receipts {
+		paid[i].Fee.Mul(new(big.Int).SetUint64(receipt.GasUsed), gasPrices[i])
+	}
+	return activities, nil
+}
+
+// activityOf returns the activity of a transaction for account, nil if the account is not
+// involved. The fee is left for the caller to set.
+func activityOf(account common.Address, tx *scanner.Transaction) *Activity {
+	var recipient common.Address
+	if tx.Tx.To() != nil {
+		recipient = *tx.Tx.To()
+	}
+	activity := &Activity{
+		Block:  tx.Block.NumberU64(),
+		Time:   tx.Block.Time(),
+		TxHash: tx.Tx.Hash(),
+		Value:  tx.Tx.Value(),
+		TxType: "Normal",
+		Fee:    new(big.Int),
+	}
+	switch {
+	case tx.From == account && recipient == account:
+		activity.Direction, activity.Counterparty = Self, account
+	case tx.From == account:
+		activity.Direction, activity.Counterparty = Out, recipient
+	case recipient == account:
+		activity.Direction, activity.Counterparty = In, tx.From
+	default:
+		return nil
+	}
+	if tx.Payload != nil {
+		activity.TxType = types2.TypeName(tx.Payload.Type)
+		switch {
+		case tx.Payload.NFTAddress != "":
+			activity.NFTAddress = tx.Payload.NFTAddress
+		case tx.Payload.Buyer != nil:
+			activity.NFTAddress = tx.Payload.Buyer.NFTAddress
+		case tx.Payload.Seller1 != nil:
+			activity.NFTAddress = tx.Payload.Seller1.NFTAddress
+		}
+		activity.NFTAddress = tools.ChecksumAddress(activity.NFTAddress)
+	}
+	return activity
+}
+
+// Write writes activities to w in the given format.
+func Write(w io.Writer, format Format, activities []*Activity) error {
+	switch format {
+	case CSV:
+		out := csv.NewWriter(w)
+		if err := out.Write(csvHeader); err != nil {
+			return err
+		}
+		for _, a := range activities {
+			record := []string{
+				strconv.FormatUint(a.Block, 10),
+				strconv.FormatUint(a.Time, 10),
+				a.TxHash.Hex(),
+				a.Direction,
+				a.Counterparty.Hex(),
+				a.Value.String(),
+				a.TxType,
+				a.NFTAddress,
+				a.Fee.String(),
+			}
+			if err := out.Write(record); err != nil {
+				return err
+			}
+		}
+		out.Flush()
+		return out.Error()
+	case JSONLines:
+		enc := json.NewEncoder(w)
+		for _, a := range activities {
+			if err := enc.Encode(a); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return xerrors.Errorf("unknown export format %d", format)
+}
+
+// ExportActivity writes the activity of account in the blocks from..to (inclusive) to w.
+func ExportActivity(ctx context.Context, worm *client.Wormholes, account common.Address, from, to uint64, format Format, w io.Writer) error {
+	activities, err := Activities(ctx, worm, account, from, to)
+	if err != nil {
+		return err
+	}
+	return Write(w, format, activities)
+}
@@ -0,0 +1,152 @@
+package export
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"io"
+	"math/big"
+	"strconv"
+
+	"github.com/erbieio/erb-client/v2/client"
+	"github.com/erbieio/erb-client/v2/scanner"
+	"github.com/erbieio/erb-client/v2/store"
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// RevenueTrade is a trade settled through an exchanger with the fee it earned.
+type RevenueTrade struct {
+	Block      uint64
+	Time       uint64
+	TxHash     common.Hash
+	NFTAddress string
+	Buyer      common.Address
+	Seller     common.Address
+	// Price and Fee are in wei.
+	Price *big.Int
+	Fee   *big.Int
+}
+
+// RevenueReport is the fee income of an exchanger over a period.
+type RevenueReport struct {
+	Exchanger common.Address
+	Trades    []*RevenueTrade
+	// Volume and Fees are the totals in wei of the prices and fees of the trades.
+	Volume *big.Int
+	Fees   *big.Int
+}
+
+var revenueHeader = []string{"block", "time", "tx_hash", "nft_address", "buyer", "seller", "price_erb", "fee_erb"}
+
+// ExchangerRevenue decodes the trades settled through exchanger in the blocks from..to (inclusive).
+// The fee of a trade is the fee rate of the exchanger at the block before the trade applied to its price.
+// The trades are decoded and added to the report block by block while scanning.
+func ExchangerRevenue(ctx context.Context, worm *client.Wormholes, exchanger common.Address, from, to uint64) (*RevenueReport, error) {
+	report := newRevenueReport(exchanger)
+	var txs []*types.Transaction
+	var hashes []common.Hash
+	pool := client.NewFetchPool(client.FetchConfig{})
+	s := scanner.New(worm, scanner.Config{Start: from, Pool: pool})
+	s.OnTrade(func(ctx context.Context, trade *scanner.Trade) error {
+		if trade.Exchanger != exchanger.Hex() {
+			return nil
+		}
+		txs = append(txs, trade.Transaction.Tx)
+		hashes = append(hashes, trade.Transaction.Tx.Hash())
+		return nil
+	})
+	// The receipts of the trades of a block are fetched together once the block is scanned.
+	s.OnBlockDone(func(ctx context.Context, block *types.Block) error {
+		if len(txs) == 0 {
+			return nil
+		}
+		defer func() { txs, hashes = nil, nil }()
+		receipts, err := worm.GetReceipts(ctx, pool, hashes)
+		if err != nil {
+			return err
+		}
+		for i, tx := range txs {
+			decoded, err := worm.DecodeTrade(ctx, tx, receipts[i])
+			if errors.Is(err, client.ErrTradeFailed) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			report.add(&store.TimedTrade{Trade: decoded, Time: block.Time()})
+		}
+		return nil
+	})
+	if err := s.ScanRange(ctx, from, to); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// NewRevenueReport aggregates the trades settled through exchanger, e.g. the stored trades of
+// store.SQLStore.TradeHistory. Trades through other exchangers are ignored.
+func NewRevenueReport(exchanger common.Address, trades []*store.TimedTrade) *RevenueReport {
+	report := newRevenueReport(exchanger)
+	for _, trade := range trades {
+		if trade.Exchanger == exchanger {
+			report.add(trade)
+		}
+	}
+	return report
+}
+
+func newRevenueReport(exchanger common.Address) *RevenueReport {
+	return &RevenueReport{Exchanger: exchanger, Volume: new(big.Int), Fees: new(big.Int)}
+}
+
+// add appends a trade to the report and to its totals.
+func (report *RevenueReport) add(trade *store.TimedTrade) {
+	fee := trade.FeePaid
+	if fee == nil {
+		fee = new(big.Int)
+	}
+	report.Trades = append(report.Trades, &RevenueTrade{
+		Block:      trade.BlockNumber,
+		Time:       trade.Time,
+		TxHash:     trade.TxHash,
+		NFTAddress: trade.NFTAddress,
+		Buyer:      trade.Buyer,
+		Seller:     trade.Seller,
+		Price:      trade.Price,
+		Fee:        fee,
+	})
+	report.Volume.Add(report.Volume, trade.Price)
+	report.Fees.Add(report.Fees, fee)
+}
+
+// WriteRevenueReport writes the trades of report to w as CSV followed by a row of totals.
+// Amounts are in ERB.
+func WriteRevenueReport(w io.Writer, report *RevenueReport) error {
+	out := csv.NewWriter(w)
+	if err := out.Write(revenueHeader); err != nil {
+		return err
+	}
+	for _, trade := range report.Trades {
+		record := []string{
+			strconv.FormatUint(trade.Block, 10),
+			strconv.FormatUint(trade.Time, 10),
+			trade.TxHash.Hex(),
+			trade.NFTAddress,
+			trade.Buyer.Hex(),
+			trade.Seller.Hex(),
+			types2.Wei(trade.Price).String(),
+			types2.Wei(trade.Fee).String(),
+		}
+		if err := out.Write(record); err != nil {
+			return err
+		}
+	}
+	total := []string{"total", "", "", "", "", "", types2.Wei(report.Volume).String(), types2.Wei(report.Fees).String()}
+	if err := out.Write(total); err != nil {
+		return err
+	}
+	out.Flush()
+	return out.Error()
+}
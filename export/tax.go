@@ -0,0 +1,383 @@
+package export
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"io"
+	"math/big"
+	"strconv"
+
+	"github.com/erbieio/erb-client/v2/client"
+	"github.com/erbieio/erb-client/v2/scanner"
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"golang.org/x/xerrors"
+)
+
+// Categories of a tax event.
+const (
+	Acquisition       = "acquisition"
+	Disposal          = "disposal"
+	RewardIncome      = "reward_income"
+	RoyaltyIncome     = "royalty_income"
+	ExchangeFeeIncome = "exchange_fee_income"
+	Fee               = "fee"
+)
+
+// ERB is the asset of ERB income and fees.
+const ERB = "ERB"
+
+// CostBasisMethod selects the acquisitions a disposal is matched with.
+type CostBasisMethod int
+
+const (
+	// FIFO matches a disposal with the oldest held acquisitions of the asset.
+	FIFO CostBasisMethod = iota
+	// AverageCost values a disposal at the average cost of the held quantity of the asset.
+	AverageCost
+)
+
+// TaxEvent is a taxable event of an account. Amounts are in wei.
+type TaxEvent struct {
+	Block    uint64
+	Time     uint64
+	TxHash   common.Hash
+	Category string
+	// Asset is the NFT address, or ERB for income and fees paid in ERB.
+	Asset string
+	// Quantity is the number of units of Asset, 1 for NFTs and the amount in wei for ERB.
+	Quantity *big.Int
+	// Value is the cost of an acquisition, the proceeds of a disposal, or the amount of an income or fee.
+	Value *big.Int
+	// CostBasis and Gain are set on disposals by ApplyCostBasis.
+	CostBasis *big.Int
+	Gain      *big.Int
+}
+
+var taxHeader = []string{"block", "time", "tx_hash", "category", "asset", "quantity", "value", "cost_basis", "gain"}
+
+// TaxConfig configures a tax report. Zero values select the defaults.
+type TaxConfig struct {
+	// Method is the cost-basis method, FIFO by default.
+	Method CostBasisMethod
+	// SNFTValue returns the value in wei of a rewarded SNFT fragment, used as its income and
+	// cost basis. Rewarded fragments are valued at zero when nil.
+	SNFTValue func(ctx context.Context, snft string, block uint64) (*big.Int, error)
+}
+
+// TaxEvents classifies the history of account in the blocks from..to (inclusive) into
+// acquisitions and disposals of NFTs, reward, royalty and exchange fee income, and fees.
+//
+// NFTs bought or received by Transfer are acquisitions, NFTs sold, sent by Transfer or
+// converted by SNFTToERB are disposals. The ERB rewards and the ERB received for converted
+// SNFTs are not carried by a transaction: they are measured as the balance change of the block
+// that is not explained by the transactions of the account, their values and fees and the
+// amounts settled by trades. Plain ERB transfers are not taxable events since amounts are
+// reported in ERB.
+func TaxEvents(ctx context.Context, worm *client.Wormholes, account common.Address, from, to uint64, cfg TaxConfig) ([]*TaxEvent, error) {
+	c := &taxCollector{worm: worm, account: account, cfg: cfg}
+	s := scanner.New(worm, scanner.Config{Start: from})
+	s.OnBlock(func(ctx context.Context, block *types.Block) error {
+		if err := c.flush(ctx); err != nil {
+			return err
+		}
+		c.current = &taxBlock{block: block, flow: new(big.Int)}
+		return nil
+	})
+	s.OnTransaction(c.transaction)
+	s.OnTrade(c.trade)
+	if err := s.ScanRange(ctx, from, to); err != nil {
+		return nil, err
+	}
+	if err := c.flush(ctx); err != nil {
+		return nil, err
+	}
+	return c.events, nil
+}
+
+type taxCollector struct {
+	worm    *client.Wormholes
+	account common.Address
+	cfg     TaxConfig
+	current *taxBlock
+	events  []*TaxEvent
+}
+
+// taxBlock collects the events of the block being scanned.
+type taxBlock struct {
+	block  *types.Block
+	events []*TaxEvent
+	// flow is the balance change explained by the transactions of the account.
+	flow *big.Int
+	// conversion is the first SNFTToERB disposal of the block, its proceeds are measured.
+	conversion *TaxEvent
+}
+
+func (b *taxBlock) add(txHash common.Hash, category, asset string, quantity, value *big.Int) *TaxEvent {
+	ev := &TaxEvent{
+		Block:    b.block.NumberU64(),
+		Time:     b.block.Time(),
+		TxHash:   txHash,
+		Category: category,
+		Asset:    asset,
+		Quantity: quantity,
+		Value:    value,
+	}
+	b.events = append(b.events, ev)
+	return ev
+}
+
+func (c *taxCollector) transaction(ctx context.Context, tx *scanner.Transaction) error {
+	var recipient common.Address
+	if tx.Tx.To() != nil {
+		recipient = *tx.Tx.To()
+	}
+	if tx.From != c.account && recipient != c.account {
+		return nil
+	}
+	receipt, err := c.worm.TransactionReceipt(ctx, tx.Tx.Hash().Hex())
+	if err != nil {
+		return xerrors.Errorf("receipt of %s: %v", tx.Tx.Hash().Hex(), err)
+	}
+	// A failed transaction only pays its fee.
+	ok := receipt.Status == types.ReceiptStatusSuccessful
+	// The value of a trade is its price, which the buyer pays whether it sends the transaction
+	// or the seller does. The flow of a trade is added by trade from the decoded trade.
+	moves := ok && (tx.Payload == nil || !types2.IsTrade(tx.Payload.Type))
+	b := c.current
+	if tx.From == c.account {
+		fee := new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), tx.Tx.GasPrice())
+		b.add(tx.Tx.Hash(), Fee, ERB, new(big.Int).Set(fee), fee)
+		b.flow.Sub(b.flow, fee)
+		if moves {
+			b.flow.Sub(b.flow, tx.Tx.Value())
+		}
+	}
+	if recipient == c.account && moves {
+		b.flow.Add(b.flow, tx.Tx.Value())
+	}
+	if !ok || tx.Payload == nil || tx.From == recipient {
+		return nil
+	}
+	switch {
+	case tx.Payload.Type == types2.Transfer && tx.From == c.account:
+		b.add(tx.Tx.Hash(), Disposal, tx.Payload.NFTAddress, big.NewInt(1), new(big.Int))
+	case tx.Payload.Type == types2.Transfer:
+		b.add(tx.Tx.Hash(), Acquisition, tx.Payload.NFTAddress, big.NewInt(1), new(big.Int))
+	case tx.Payload.Type == types2.SNFTToERB && tx.From == c.account:
+		ev := b.add(tx.Tx.Hash(), Disposal, tx.Payload.NFTAddress, big.NewInt(1), new(big.Int))
+		if b.conversion == nil {
+			b.conversion = ev
+		}
+	}
+	return nil
+}
+
+func (c *taxCollector) trade(ctx context.Context, trade *scanner.Trade) error {
+	tx := trade.Transaction
+	receipt, err := c.worm.TransactionReceipt(ctx, tx.Tx.Hash().Hex())
+	if err != nil {
+		return xerrors.Errorf("receipt of %s: %v", tx.Tx.Hash().Hex(), err)
+	}
+	decoded, err := c.worm.DecodeTrade(ctx, tx.Tx, receipt)
+	if errors.Is(err, client.ErrTradeFailed) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	b := c.current
+	asset := decoded.NFTAddress
+	if asset == "" {
+		asset = decoded.MetaURL
+	}
+	if decoded.Buyer == c.account {
+		b.add(decoded.TxHash, Acquisition, asset, big.NewInt(1), new(big.Int).Set(decoded.Price))
+		b.flow.Sub(b.flow, decoded.Price)
+	}
+	if decoded.Seller == c.account {
+		proceeds := new(big.Int).Sub(decoded.Price, decoded.FeePaid)
+		if decoded.Creator != c.account {
+			proceeds.Sub(proceeds, decoded.RoyaltyPaid)
+		}
+		b.add(decoded.TxHash, Disposal, asset, big.NewInt(1), proceeds)
+		b.flow.Add(b.flow, proceeds)
+	}
+	// The royalty of a creator selling its own NFT is part of the proceeds.
+	if decoded.Creator == c.account && decoded.Seller != c.account && decoded.RoyaltyPaid.Sign() > 0 {
+		b.add(decoded.TxHash, RoyaltyIncome, ERB, new(big.Int).Set(decoded.RoyaltyPaid), new(big.Int).Set(decoded.RoyaltyPaid))
+		b.flow.Add(b.flow, decoded.RoyaltyPaid)
+	}
+	if decoded.Exchanger == c.account && decoded.FeePaid.Sign() > 0 {
+		b.add(decoded.TxHash, ExchangeFeeIncome, ERB, new(big.Int).Set(decoded.FeePaid), new(big.Int).Set(decoded.FeePaid))
+		b.flow.Add(b.flow, decoded.FeePaid)
+	}
+	return nil
+}
+
+// flush adds the rewards of the current block and moves its events to the result.
+func (c *taxCollector) flush(ctx context.Context) error {
+	b := c.current
+	if b == nil {
+		return nil
+	}
+	c.current = nil
+	number := b.block.NumberU64()
+	beneficiaries, err := c.worm.GetBlockBeneficiaryAddressByNumber(ctx, int64(number))
+	if err != nil {
+		return xerrors.Errorf("beneficiaries of block %d: %v", number, err)
+	}
+	var rewarded bool
+	for _, beneficiary := range *beneficiaries {
+		if beneficiary.Address != c.account {
+			continue
+		}
+		if beneficiary.NftAddress == (common.Address{}) {
+			rewarded = true
+			continue
+		}
+		snft := beneficiary.NftAddress.Hex()
+		value := new(big.Int)
+		if c.cfg.SNFTValue != nil {
+			if value, err = c.cfg.SNFTValue(ctx, snft, number); err != nil {
+				return err
+			}
+		}
+		b.add(common.Hash{}, RewardIncome, snft, big.NewInt(1), value)
+	}
+
+	if (rewarded || b.conversion != nil) && number > 0 {
+		received, err := c.unexplained(ctx, b)
+		if err != nil {
+			return err
+		}
+		if b.conversion != nil {
+			b.conversion.Value = received
+		} else if received.Sign() > 0 {
+			b.add(common.Hash{}, RewardIncome, ERB, new(big.Int).Set(received), received)
+		}
+	}
+	c.events = append(c.events, b.events...)
+	return nil
+}
+
+// unexplained returns the balance change of the block not explained by the transactions of the account.
+func (c *taxCollector) unexplained(ctx context.Context, b *taxBlock) (*big.Int, error) {
+	number := b.block.Number()
+	after, err := c.worm.BalanceAt(ctx, c.account.Hex(), number)
+	if err != nil {
+		return nil, err
+	}
+	before, err := c.worm.BalanceAt(ctx, c.account.Hex(), new(big.Int).Sub(number, big.NewInt(1)))
+	if err != nil {
+		return nil, err
+	}
+	received := new(big.Int).Sub(after, before)
+	return received.Sub(received, b.flow), nil
+}
+
+// taxLot is a held quantity of an asset and its cost.
+type taxLot struct {
+	quantity *big.Int
+	cost     *big.Int
+}
+
+// ApplyCostBasis sets the cost basis and gain of the disposals of events, which must be ordered
+// by block. Acquisitions and rewarded NFTs add to the held quantity of their asset at their value.
+// A disposal of more than the held quantity, e.g. of an NFT minted or acquired before the
+// reported range, has a zero cost basis for the quantity that is not held.
+func ApplyCostBasis(events []*TaxEvent, method CostBasisMethod) error {
+	if method != FIFO && method != AverageCost {
+		return xerrors.Errorf("unknown cost basis method %d", method)
+	}
+	lots := make(map[string][]*taxLot)
+	for _, ev := range events {
+		switch {
+		case ev.Category == Acquisition, ev.Category == RewardIncome && ev.Asset != ERB:
+			lot := &taxLot{quantity: new(big.Int).Set(ev.Quantity), cost: new(big.Int).Set(ev.Value)}
+			if method == AverageCost && len(lots[ev.Asset]) > 0 {
+				held := lots[ev.Asset][0]
+				held.quantity.Add(held.quantity, lot.quantity)
+				held.cost.Add(held.cost, lot.cost)
+				continue
+			}
+			lots[ev.Asset] = append(lots[ev.Asset], lot)
+		case ev.Category == Disposal:
+			var basis *big.Int
+			lots[ev.Asset], basis = dispose(lots[ev.Asset], ev.Quantity)
+			ev.CostBasis = basis
+			ev.Gain = new(big.Int).Sub(ev.Value, basis)
+		}
+	}
+	return nil
+}
+
+// dispose removes quantity from the lots, oldest first, and returns the remaining lots and
+// the cost of the removed quantity. A partially consumed lot keeps the cost of its rest.
+func dispose(lots []*taxLot, quantity *big.Int) ([]*taxLot, *big.Int) {
+	basis := new(big.Int)
+	left := new(big.Int).Set(quantity)
+	for len(lots) > 0 && left.Sign() > 0 {
+		lot := lots[0]
+		if lot.quantity.Cmp(left) <= 0 {
+			basis.Add(basis, lot.cost)
+			left.Sub(left, lot.quantity)
+			lots = lots[1:]
+			continue
+		}
+		cost := new(big.Int).Mul(lot.cost, left)
+		cost.Quo(cost, lot.quantity)
+		basis.Add(basis, cost)
+		lot.cost.Sub(lot.cost, cost)
+		lot.quantity.Sub(lot.quantity, left)
+		left.SetInt64(0)
+	}
+	return lots, basis
+}
+
+// WriteTaxReport writes events to w as CSV.
+func WriteTaxReport(w io.Writer, events []*TaxEvent) error {
+	out := csv.NewWriter(w)
+	if err := out.Write(taxHeader); err != nil {
+		return err
+	}
+	for _, ev := range events {
+		record := []string{
+			strconv.FormatUint(ev.Block, 10),
+			strconv.FormatUint(ev.Time, 10),
+			ev.TxHash.Hex(),
+			ev.Category,
+			ev.Asset,
+			ev.Quantity.String(),
+			ev.Value.String(),
+			optionalBig(ev.CostBasis),
+			optionalBig(ev.Gain),
+		}
+		if err := out.Write(record); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// ExportTaxReport writes the tax events of account in the blocks from..to (inclusive) to w as CSV.
+func ExportTaxReport(ctx context.Context, worm *client.Wormholes, account common.Address, from, to uint64, cfg TaxConfig, w io.Writer) error {
+	events, err := TaxEvents(ctx, worm, account, from, to, cfg)
+	if err != nil {
+		return err
+	}
+	if err := ApplyCostBasis(events, cfg.Method); err != nil {
+		return err
+	}
+	return WriteTaxReport(w, events)
+}
+
+func optionalBig(n *big.Int) string {
+	if n == nil {
+		return ""
+	}
+	return n.String()
+}
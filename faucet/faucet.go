@@ -0,0 +1,131 @@
+// Package faucet funds test accounts on a devnet from a funded root key.
+package faucet
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/erbieio/erb-client/v2/client"
+	"github.com/erbieio/erb-client/v2/tools"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	transferGas         = 21000
+	defaultPollInterval = time.Second
+)
+
+// Account is a generated test account.
+type Account struct {
+	Address common.Address
+	// PriKey is the hex private key without 0x prefix, as taken by client.NewClient.
+	PriKey string
+}
+
+// Faucet sends ERB from a root account. The transactions of one call are sent with
+// consecutive nonces and confirmed together, a Faucet must not be used concurrently.
+type Faucet struct {
+	worm *client.Wormholes
+	key  *ecdsa.PrivateKey
+	root common.Address
+
+	// PollInterval is the interval between receipt polls, one second by default.
+	PollInterval time.Duration
+}
+
+// New creates a faucet that sends from the account of rootKey through worm.
+func New(worm *client.Wormholes, rootKey string) (*Faucet, error) {
+	root, key, err := tools.PriKeyToAddress(rootKey)
+	if err != nil {
+		return nil, err
+	}
+	return &Faucet{worm: worm, key: key, root: root, PollInterval: defaultPollInterval}, nil
+}
+
+// NewAccounts generates n accounts and funds each with amount wei.
+func (f *Faucet) NewAccounts(ctx context.Context, n int, amount *big.Int) ([]*Account, error) {
+	accounts := make([]*Account, n)
+	addrs := make([]common.Address, n)
+	for i := range accounts {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			return nil, err
+		}
+		accounts[i] = &Account{
+			Address: crypto.PubkeyToAddress(key.PublicKey),
+			PriKey:  common.Bytes2Hex(crypto.FromECDSA(key)),
+		}
+		addrs[i] = accounts[i].Address
+	}
+	if err := f.FundAccounts(ctx, addrs, amount); err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+// FundAccounts sends amount wei to every address and waits until all transfers are mined.
+func (f *Faucet) FundAccounts(ctx context.Context, addrs []common.Address, amount *big.Int) error {
+	nonce, err := f.worm.PendingNonceAt(ctx, f.root)
+	if err != nil {
+		return err
+	}
+	gasPrice, err := f.worm.SuggestGasPrice(ctx)
+	if err != nil {
+		return err
+	}
+	chainID, err := f.worm.NetworkID(ctx)
+	if err != nil {
+		return err
+	}
+	signer := types.NewEIP155Signer(chainID)
+
+	hashes := make([]common.Hash, len(addrs))
+	for i, addr := range addrs {
+		tx := types.NewTransaction(nonce+uint64(i), addr, amount, transferGas, gasPrice, nil)
+		signedTx, err := types.SignTx(tx, signer, f.key)
+		if err != nil {
+			return err
+		}
+		if err := f.worm.SendTransaction(ctx, signedTx); err != nil {
+			return fmt.Errorf("fund %s: %w", addr, err)
+		}
+		hashes[i] = signedTx.Hash()
+	}
+	for i, hash := range hashes {
+		receipt, err := f.waitMined(ctx, hash)
+		if err != nil {
+			return fmt.Errorf("fund %s: %w", addrs[i], err)
+		}
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			return fmt.Errorf("fund %s: transaction %s failed", addrs[i], hash)
+		}
+	}
+	return nil
+}
+
+// waitMined polls the receipt of a transaction until it is available or ctx is done.
+func (f *Faucet) waitMined(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	ticker := time.NewTicker(f.PollInterval)
+	defer ticker.Stop()
+	for {
+		receipt, err := f.worm.TransactionReceipt(ctx, hash.Hex())
+		if err == nil {
+			return receipt, nil
+		}
+		if !errors.Is(err, ethereum.NotFound) {
+			return nil, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,259 @@
+// Package fixture records the JSON-RPC traffic of a client to a file and replays it, so
+// tests written against a live node can run hermetically.
+//
+// A test records once against a node:
+//
+//	rec := fixture.NewRecorder(nil)
+//	c, _ := fixture.Dial(endpoint, rec)
+//	worm := client.NewClientWithRPC(priKey, c)
+//	... // exercise worm
+//	rec.Save("testdata/mint.json")
+//
+// and later replays the file without a node:
+//
+//	rep, _ := fixture.Load("testdata/mint.json")
+//	c, _ := fixture.Dial("http://fixture", rep)
+//
+// Calls are matched by method and parameters. When the same call was recorded several times,
+// the responses are served in the recorded order and the last one is repeated afterwards.
+package fixture
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Exchange is one recorded JSON-RPC call.
+type Exchange struct {
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  json.RawMessage `json:"error,omitempty"`
+}
+
+func (e *Exchange) key() string {
+	return e.Method + string(compact(e.Params))
+}
+
+type message struct {
+	Version string          `json:"jsonrpc,omitempty"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   json.RawMessage `json:"error,omitempty"`
+}
+
+// Dial connects an rpc client to rawurl over the given HTTP transport.
+func Dial(rawurl string, transport http.RoundTripper) (*rpc.Client, error) {
+	return rpc.DialOptions(context.Background(), rawurl, rpc.WithHTTPClient(&http.Client{Transport: transport}))
+}
+
+// Recorder is an HTTP transport that forwards JSON-RPC requests and records the exchanges.
+type Recorder struct {
+	base http.RoundTripper
+
+	mu        sync.Mutex
+	exchanges []*Exchange
+}
+
+// NewRecorder creates a recorder forwarding to base, http.DefaultTransport if nil.
+func NewRecorder(base http.RoundTripper) *Recorder {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Recorder{base: base}
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	calls, _, err := decodeMessages(reqBody)
+	if err != nil {
+		return resp, nil
+	}
+	answers, _, err := decodeMessages(respBody)
+	if err != nil {
+		return resp, nil
+	}
+	byID := make(map[string]*message, len(answers))
+	for _, answer := range answers {
+		byID[string(answer.ID)] = answer
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, call := range calls {
+		answer, ok := byID[string(call.ID)]
+		if !ok {
+			continue
+		}
+		r.exchanges = append(r.exchanges, &Exchange{
+			Method: call.Method,
+			Params: compact(call.Params),
+			Result: answer.Result,
+			Error:  answer.Error,
+		})
+	}
+	return resp, nil
+}
+
+// Exchanges returns the calls recorded so far.
+func (r *Recorder) Exchanges() []*Exchange {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*Exchange(nil), r.exchanges...)
+}
+
+// Save writes the recorded calls to a fixture file.
+func (r *Recorder) Save(path string) error {
+	data, err := json.MarshalIndent(r.Exchanges(), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// Replayer is an HTTP transport that answers JSON-RPC requests from recorded exchanges
+// without network access.
+type Replayer struct {
+	mu        sync.Mutex
+	responses map[string][]*Exchange
+}
+
+// Load reads a fixture file written by Recorder.Save.
+func Load(path string) (*Replayer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var exchanges []*Exchange
+	if err := json.Unmarshal(data, &exchanges); err != nil {
+		return nil, fmt.Errorf("fixture %s: %w", path, err)
+	}
+	return NewReplayer(exchanges), nil
+}
+
+// NewReplayer creates a replayer serving the given exchanges.
+func NewReplayer(exchanges []*Exchange) *Replayer {
+	r := &Replayer{responses: make(map[string][]*Exchange)}
+	for _, e := range exchanges {
+		r.responses[e.key()] = append(r.responses[e.key()], e)
+	}
+	return r
+}
+
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+	calls, batch, err := decodeMessages(reqBody)
+	if err != nil {
+		return nil, err
+	}
+	answers := make([]*message, 0, len(calls))
+	for _, call := range calls {
+		e, err := r.next(call)
+		if err != nil {
+			return nil, err
+		}
+		if call.ID == nil {
+			continue
+		}
+		answers = append(answers, &message{Version: "2.0", ID: call.ID, Result: e.Result, Error: e.Error})
+	}
+
+	var body []byte
+	if batch {
+		body, err = json.Marshal(answers)
+	} else if len(answers) > 0 {
+		body, err = json.Marshal(answers[0])
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// next returns the recorded response for call, the last one is repeated once the
+// recorded responses are used up.
+func (r *Replayer) next(call *message) (*Exchange, error) {
+	key := (&Exchange{Method: call.Method, Params: call.Params}).key()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	queue := r.responses[key]
+	if len(queue) == 0 {
+		return nil, fmt.Errorf("fixture: no recorded response for %s %s", call.Method, compact(call.Params))
+	}
+	if len(queue) > 1 {
+		r.responses[key] = queue[1:]
+	}
+	return queue[0], nil
+}
+
+// readBody reads and replaces body, so the request or response can still be consumed.
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil {
+		return nil, nil
+	}
+	data, err := io.ReadAll(*body)
+	(*body).Close()
+	if err != nil {
+		return nil, err
+	}
+	*body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+
+// decodeMessages decodes a single or batch JSON-RPC message.
+func decodeMessages(data []byte) ([]*message, bool, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		var msgs []*message
+		err := json.Unmarshal(data, &msgs)
+		return msgs, true, err
+	}
+	msg := new(message)
+	err := json.Unmarshal(data, msg)
+	return []*message{msg}, false, err
+}
+
+func compact(data json.RawMessage) json.RawMessage {
+	if len(data) == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, data); err != nil {
+		return data
+	}
+	return buf.Bytes()
+}
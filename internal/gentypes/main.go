@@ -0,0 +1,213 @@
+// Command gentypes generates the wormholes transaction types of the types package from the
+// schema types/txtypes.json: the type constants, their names, the Transaction payload and a
+// typed payload per type with its decoder. Run it with go generate in the types directory
+// after adding a type or a field to the schema:
+//
+//	go generate ./types
+//
+// With -check it fails if the generated file is not up to date instead of writing it.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"sort"
+	"text/template"
+)
+
+// schema is the definition of the transaction types.
+type schema struct {
+	// Fields are the payload fields of Transaction, in order.
+	Fields []field  `json:"fields"`
+	Types  []txType `json:"types"`
+}
+
+type field struct {
+	Name string `json:"name"`
+	JSON string `json:"json"`
+	Type string `json:"type"`
+}
+
+type txType struct {
+	Name string `json:"name"`
+	Code uint8  `json:"code"`
+	// Doc completes "... a <Name> transaction, which".
+	Doc    string   `json:"doc"`
+	Fields []string `json:"fields"`
+}
+
+func main() {
+	schemaPath := flag.String("schema", "txtypes.json", "schema of the transaction types")
+	out := flag.String("out", "txtypes_gen.go", "generated Go file")
+	check := flag.Bool("check", false, "fail if the generated file is not up to date")
+	flag.Parse()
+
+	src, err := generate(*schemaPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "gentypes:", err)
+		os.Exit(1)
+	}
+	if *check {
+		current, err := os.ReadFile(*out)
+		if err != nil || !bytes.Equal(current, src) {
+			fmt.Fprintf(os.Stderr, "gentypes: %s is not up to date with %s, run go generate\n", *out, *schemaPath)
+			os.Exit(1)
+		}
+		return
+	}
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, "gentypes:", err)
+		os.Exit(1)
+	}
+}
+
+// generate returns the formatted Go source of the schema at path.
+func generate(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var s schema
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	if err := s.check(); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	sort.Slice(s.Types, func(i, j int) bool { return s.Types[i].Code < s.Types[j].Code })
+	var buf bytes.Buffer
+	if err := source.Execute(&buf, &s); err != nil {
+		return nil, err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format the generated source: %v", err)
+	}
+	return src, nil
+}
+
+// check rejects duplicate types and codes and fields missing from the payload.
+func (s *schema) check() error {
+	fields := make(map[string]bool, len(s.Fields))
+	for _, f := range s.Fields {
+		if f.Name == "" || f.JSON == "" || f.Type == "" {
+			return fmt.Errorf("incomplete field %+v", f)
+		}
+		if fields[f.Name] {
+			return fmt.Errorf("duplicate field %s", f.Name)
+		}
+		fields[f.Name] = true
+	}
+	names := make(map[string]bool, len(s.Types))
+	codes := make(map[uint8]string, len(s.Types))
+	for _, t := range s.Types {
+		if t.Name == "" || t.Doc == "" {
+			return fmt.Errorf("type %d needs a name and a doc", t.Code)
+		}
+		if names[t.Name] {
+			return fmt.Errorf("duplicate type %s", t.Name)
+		}
+		if other, ok := codes[t.Code]; ok {
+			return fmt.Errorf("types %s and %s have the same code %d", other, t.Name, t.Code)
+		}
+		names[t.Name], codes[t.Code] = true, t.Name
+		for _, f := range t.Fields {
+			if !fields[f] {
+				return fmt.Errorf("type %s has the unknown field %s", t.Name, f)
+			}
+		}
+	}
+	return nil
+}
+
+// Field returns the payload field name, for the template.
+func (s *schema) Field(name string) field {
+	for _, f := range s.Fields {
+		if f.Name == name {
+			return f
+		}
+	}
+	return field{}
+}
+
+var source = template.Must(template.New("").Parse(`// Code generated by gentypes from txtypes.json. DO NOT EDIT.
+
+package types
+
+import "fmt"
+
+// The wormholes transaction types, the Type of a Transaction. The codes are defined by the
+// chain, the missing ones are not used.
+const (
+{{- range .Types}}
+	{{.Name}} = {{.Code}}
+{{- end}}
+)
+
+var typeNames = map[uint8]string{
+{{- range .Types}}
+	{{.Name}}: "{{.Name}}",
+{{- end}}
+}
+
+// Transaction is the payload of a wormholes transaction, JSON in the data of the transaction
+// after the wormholes prefix. Each type uses some of the fields, see DecodePayload.
+type Transaction struct {
+	Type uint8 ` + "`json:\"type\"`" + `
+{{- range .Fields}}
+	{{.Name}} {{.Type}} ` + "`json:\"{{.JSON}},omitempty\"`" + `
+{{- end}}
+	Version string ` + "`json:\"version\"`" + `
+}
+
+// Payload is the typed payload of a wormholes transaction, holding the fields of its type.
+type Payload interface {
+	// Type returns the wormholes transaction type.
+	Type() uint8
+	// Transaction returns the payload to send, of the current version.
+	Transaction() *Transaction
+}
+{{range .Types}}
+// {{.Name}}Payload is the payload of a {{.Name}} transaction, which {{.Doc}}.
+type {{.Name}}Payload struct {
+{{- range .Fields}}{{with $.Field .}}
+	{{.Name}} {{.Type}} ` + "`json:\"{{.JSON}},omitempty\"`" + `
+{{- end}}{{end}}
+}
+
+// Type returns {{.Name}}.
+func (p *{{.Name}}Payload) Type() uint8 {
+	return {{.Name}}
+}
+
+// Transaction returns the payload to send.
+func (p *{{.Name}}Payload) Transaction() *Transaction {
+	return &Transaction{
+		Type: {{.Name}},
+{{- range .Fields}}
+		{{.}}: p.{{.}},
+{{- end}}
+		Version: WormHolesVersion,
+	}
+}
+{{end}}
+// DecodePayload returns the typed payload of t with the fields of its type, the others are
+// ignored.
+func DecodePayload(t *Transaction) (Payload, error) {
+	switch t.Type {
+{{- range .Types}}
+	case {{.Name}}:
+		return &{{.Name}}Payload{
+{{- range .Fields}}
+			{{.}}: t.{{.}},
+{{- end}}
+		}, nil
+{{- end}}
+	}
+	return nil, fmt.Errorf("unknown wormholes transaction type %d", t.Type)
+}
+`))
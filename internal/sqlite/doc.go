@@ -0,0 +1,8 @@
+// Package sqlite registers a minimal database/sql driver named "sqlite3" on the SQLite library
+// of the system, for the tests of the store package. It is only built with cgo and the sqlite3
+// build tag, as it needs the SQLite headers:
+//
+//	go test -tags sqlite3 ./test/
+//
+// Without the tag the package is empty and the tests using the driver are skipped.
+package sqlite
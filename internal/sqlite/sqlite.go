@@ -0,0 +1,233 @@
+//go:build cgo && sqlite3
+
+package sqlite
+
+/*
+#cgo LDFLAGS: -lsqlite3
+#include <stdlib.h>
+#include <sqlite3.h>
+
+// SQLITE_TRANSIENT is a macro cgo can not use, it makes SQLite copy the bound value.
+static int bind_text(sqlite3_stmt *stmt, int i, const char *s, int n) {
+	return sqlite3_bind_text(stmt, i, s, n, SQLITE_TRANSIENT);
+}
+
+static int bind_blob(sqlite3_stmt *stmt, int i, const void *p, int n) {
+	return sqlite3_bind_blob(stmt, i, p, n, SQLITE_TRANSIENT);
+}
+*/
+import "C"
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"time"
+	"unsafe"
+)
+
+func init() {
+	sql.Register("sqlite3", sqliteDriver{})
+}
+
+type sqliteDriver struct{}
+
+// Open opens the database file name, creating it if needed.
+func (sqliteDriver) Open(name string) (driver.Conn, error) {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+	var db *C.sqlite3
+	flags := C.SQLITE_OPEN_READWRITE | C.SQLITE_OPEN_CREATE | C.SQLITE_OPEN_FULLMUTEX | C.SQLITE_OPEN_URI
+	if rc := C.sqlite3_open_v2(cname, &db, C.int(flags), nil); rc != C.SQLITE_OK {
+		err := fmt.Errorf("sqlite: open %s: %s", name, C.GoString(C.sqlite3_errstr(rc)))
+		C.sqlite3_close_v2(db)
+		return nil, err
+	}
+	C.sqlite3_busy_timeout(db, 5000)
+	return &conn{db: db}, nil
+}
+
+type conn struct {
+	db *C.sqlite3
+}
+
+func (c *conn) error(rc C.int) error {
+	return fmt.Errorf("sqlite: %s (%d)", C.GoString(C.sqlite3_errmsg(c.db)), int(rc))
+}
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	cquery := C.CString(query)
+	defer C.free(unsafe.Pointer(cquery))
+	var stmt *C.sqlite3_stmt
+	if rc := C.sqlite3_prepare_v2(c.db, cquery, -1, &stmt, nil); rc != C.SQLITE_OK {
+		return nil, c.error(rc)
+	}
+	return &statement{c: c, stmt: stmt}, nil
+}
+
+func (c *conn) Close() error {
+	if rc := C.sqlite3_close_v2(c.db); rc != C.SQLITE_OK {
+		return c.error(rc)
+	}
+	c.db = nil
+	return nil
+}
+
+func (c *conn) Begin() (driver.Tx, error) {
+	if err := c.exec("BEGIN"); err != nil {
+		return nil, err
+	}
+	return tx{c}, nil
+}
+
+func (c *conn) exec(query string) error {
+	stmt, err := c.Prepare(query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	_, err = stmt.Exec(nil)
+	return err
+}
+
+type tx struct {
+	c *conn
+}
+
+func (t tx) Commit() error   { return t.c.exec("COMMIT") }
+func (t tx) Rollback() error { return t.c.exec("ROLLBACK") }
+
+type statement struct {
+	c    *conn
+	stmt *C.sqlite3_stmt
+}
+
+func (s *statement) Close() error {
+	C.sqlite3_finalize(s.stmt)
+	s.stmt = nil
+	return nil
+}
+
+func (s *statement) NumInput() int {
+	return int(C.sqlite3_bind_parameter_count(s.stmt))
+}
+
+// bind resets the statement and binds args to its parameters.
+func (s *statement) bind(args []driver.Value) error {
+	C.sqlite3_reset(s.stmt)
+	C.sqlite3_clear_bindings(s.stmt)
+	for i, arg := range args {
+		n := C.int(i + 1)
+		var rc C.int
+		switch v := arg.(type) {
+		case nil:
+			rc = C.sqlite3_bind_null(s.stmt, n)
+		case int64:
+			rc = C.sqlite3_bind_int64(s.stmt, n, C.sqlite3_int64(v))
+		case float64:
+			rc = C.sqlite3_bind_double(s.stmt, n, C.double(v))
+		case bool:
+			b := 0
+			if v {
+				b = 1
+			}
+			rc = C.sqlite3_bind_int64(s.stmt, n, C.sqlite3_int64(b))
+		case string:
+			cs := C.CString(v)
+			rc = C.bind_text(s.stmt, n, cs, C.int(len(v)))
+			C.free(unsafe.Pointer(cs))
+		case []byte:
+			p := C.CBytes(v)
+			rc = C.bind_blob(s.stmt, n, p, C.int(len(v)))
+			C.free(p)
+		case time.Time:
+			cs := C.CString(v.Format(time.RFC3339Nano))
+			rc = C.bind_text(s.stmt, n, cs, -1)
+			C.free(unsafe.Pointer(cs))
+		default:
+			return fmt.Errorf("sqlite: unsupported argument %T", arg)
+		}
+		if rc != C.SQLITE_OK {
+			return s.c.error(rc)
+		}
+	}
+	return nil
+}
+
+func (s *statement) Exec(args []driver.Value) (driver.Result, error) {
+	if err := s.bind(args); err != nil {
+		return nil, err
+	}
+	for {
+		switch rc := C.sqlite3_step(s.stmt); rc {
+		case C.SQLITE_ROW:
+		case C.SQLITE_DONE:
+			return result{
+				changes: int64(C.sqlite3_changes(s.c.db)),
+				lastID:  int64(C.sqlite3_last_insert_rowid(s.c.db)),
+			}, nil
+		default:
+			return nil, s.c.error(rc)
+		}
+	}
+}
+
+func (s *statement) Query(args []driver.Value) (driver.Rows, error) {
+	if err := s.bind(args); err != nil {
+		return nil, err
+	}
+	return &rows{s: s}, nil
+}
+
+type result struct {
+	changes, lastID int64
+}
+
+func (r result) LastInsertId() (int64, error) { return r.lastID, nil }
+func (r result) RowsAffected() (int64, error) { return r.changes, nil }
+
+type rows struct {
+	s *statement
+}
+
+func (r *rows) Columns() []string {
+	names := make([]string, int(C.sqlite3_column_count(r.s.stmt)))
+	for i := range names {
+		names[i] = C.GoString(C.sqlite3_column_name(r.s.stmt, C.int(i)))
+	}
+	return names
+}
+
+func (r *rows) Next(dest []driver.Value) error {
+	switch rc := C.sqlite3_step(r.s.stmt); rc {
+	case C.SQLITE_ROW:
+	case C.SQLITE_DONE:
+		return io.EOF
+	default:
+		return r.s.c.error(rc)
+	}
+	for i := range dest {
+		col := C.int(i)
+		switch C.sqlite3_column_type(r.s.stmt, col) {
+		case C.SQLITE_INTEGER:
+			dest[i] = int64(C.sqlite3_column_int64(r.s.stmt, col))
+		case C.SQLITE_FLOAT:
+			dest[i] = float64(C.sqlite3_column_double(r.s.stmt, col))
+		case C.SQLITE_TEXT:
+			text := C.sqlite3_column_text(r.s.stmt, col)
+			dest[i] = C.GoStringN((*C.char)(unsafe.Pointer(text)), C.sqlite3_column_bytes(r.s.stmt, col))
+		case C.SQLITE_BLOB:
+			blob := C.sqlite3_column_blob(r.s.stmt, col)
+			dest[i] = C.GoBytes(blob, C.sqlite3_column_bytes(r.s.stmt, col))
+		default:
+			dest[i] = nil
+		}
+	}
+	return nil
+}
+
+func (r *rows) Close() error {
+	C.sqlite3_reset(r.s.stmt)
+	return nil
+}
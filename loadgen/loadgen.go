@@ -0,0 +1,375 @@
+// Package loadgen generates load on a wormholes devnet: a configurable mix of mints, NFT
+// transfers, trades and pledges sent from many funded keys, reporting the throughput, the
+// latency percentiles and the failures by reason.
+//
+// Every key sends one transaction at a time and waits for its receipt, the throughput grows
+// with the number of keys. The keys share the connection of the client, so a run also
+// exercises the concurrency of the client.
+package loadgen
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/erbieio/erb-client/v2/client"
+	"github.com/erbieio/erb-client/v2/tools"
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"golang.org/x/xerrors"
+)
+
+const (
+	defaultDuration     = time.Minute
+	defaultPollInterval = 500 * time.Millisecond
+	defaultTimeout      = time.Minute
+	defaultMetaURL      = "/ipfs/loadgen"
+	// orderValidity is the number of blocks the signed sell orders of trades are valid for.
+	orderValidity = 100
+)
+
+var (
+	defaultPrice        = big.NewInt(1e15)
+	defaultPledgeAmount = big.NewInt(1e18)
+)
+
+// Kind is a kind of transaction generated.
+type Kind string
+
+const (
+	// Mint mints an NFT owned by the key.
+	Mint Kind = "mint"
+	// Transfer transfers an NFT of the key to another key.
+	Transfer Kind = "transfer"
+	// Trade sells an NFT of the key to another key, which sends the signed sell order.
+	Trade Kind = "trade"
+	// Pledge pledges ERB of the key to itself.
+	Pledge Kind = "pledge"
+)
+
+// Config configures a Generator. Zero values select the defaults.
+type Config struct {
+	// Keys are the private keys sending the transactions, funded for the gas, the trades and
+	// the pledges.
+	Keys []string
+	// Mix weights the kinds of transactions, e.g. {Mint: 3, Transfer: 1}. All kinds weigh
+	// the same by default.
+	Mix map[Kind]int
+	// NFTs are NFTs owned by the keys, handed around by transfers and trades. The chain does
+	// not report the address of a minted NFT, so minted NFTs are not used. A key picking a
+	// transfer or trade without holding an NFT skips it.
+	NFTs []string
+	// Exchanger is the exchanger of the trades, an open exchange. Trades have none by default.
+	Exchanger string
+	// Price is the price of the trades in wei, 0.001 ERB by default.
+	Price *big.Int
+	// PledgeAmount is the amount of the pledges in wei, 1 ERB by default.
+	PledgeAmount *big.Int
+	// Duration is how long transactions are sent, 1 minute by default.
+	Duration time.Duration
+	// Count stops after this many transactions when positive.
+	Count int
+	// Rate limits the transactions sent per second by all keys together, 0 for no limit.
+	Rate float64
+	// PollInterval is the interval between receipt polls, 500ms by default.
+	PollInterval time.Duration
+	// Timeout is the wait for the receipt of a transaction before it counts as failed,
+	// 1 minute by default.
+	Timeout time.Duration
+}
+
+// Generator sends the load of its configuration. It must not be used concurrently.
+type Generator struct {
+	worm *client.Wormholes
+	cfg  Config
+	keys []*key
+	// kinds lists every kind as often as its weight.
+	kinds []Kind
+}
+
+// key is a sending account and the NFTs it holds.
+type key struct {
+	account common.Address
+	worm    *client.Wormholes
+
+	mu   sync.Mutex
+	nfts []string
+}
+
+// New creates a generator sending through worm.
+func New(worm *client.Wormholes, cfg Config) (*Generator, error) {
+	if len(cfg.Keys) == 0 {
+		return nil, xerrors.New("loadgen: no keys")
+	}
+	g := &Generator{worm: worm}
+	for i, priKey := range cfg.Keys {
+		account, _, err := tools.PriKeyToAddress(priKey)
+		if err != nil {
+			return nil, xerrors.Errorf("loadgen key %d: %w", i, err)
+		}
+		g.keys = append(g.keys, &key{account: account, worm: worm.WithPriKey(priKey)})
+	}
+	if cfg.Mix == nil {
+		cfg.Mix = map[Kind]int{Mint: 1, Transfer: 1, Trade: 1, Pledge: 1}
+	}
+	for _, kind := range []Kind{Mint, Transfer, Trade, Pledge} {
+		weight := cfg.Mix[kind]
+		if weight < 0 {
+			return nil, xerrors.Errorf("loadgen: negative weight of %s", kind)
+		}
+		for i := 0; i < weight; i++ {
+			g.kinds = append(g.kinds, kind)
+		}
+	}
+	for kind := range cfg.Mix {
+		switch kind {
+		case Mint, Transfer, Trade, Pledge:
+		default:
+			return nil, xerrors.Errorf("loadgen: unknown kind %q", kind)
+		}
+	}
+	if len(g.kinds) == 0 {
+		return nil, xerrors.New("loadgen: empty mix")
+	}
+	if (cfg.Mix[Trade] > 0 || cfg.Mix[Transfer] > 0) && len(g.keys) < 2 {
+		return nil, xerrors.New("loadgen: transfers and trades need two keys")
+	}
+	if cfg.Exchanger != "" {
+		if err := tools.CheckAddress("loadgen exchanger", cfg.Exchanger); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.Price == nil {
+		cfg.Price = defaultPrice
+	}
+	if cfg.PledgeAmount == nil {
+		cfg.PledgeAmount = defaultPledgeAmount
+	}
+	if cfg.Duration <= 0 {
+		cfg.Duration = defaultDuration
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = defaultPollInterval
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	g.cfg = cfg
+	return g, nil
+}
+
+// Run sends transactions until the duration elapsed, Count transactions were sent or ctx is
+// cancelled, waits for their receipts and returns the report. It fails if an NFT of the
+// configuration is not owned by a key.
+func (g *Generator) Run(ctx context.Context) (*Report, error) {
+	if err := g.assignNFTs(ctx); err != nil {
+		return nil, err
+	}
+	sendCtx, cancel := context.WithTimeout(ctx, g.cfg.Duration)
+	defer cancel()
+	tokens := g.limiter(sendCtx)
+
+	report := newReport()
+	var started atomic.Int64
+	begin := time.Now()
+	var wg sync.WaitGroup
+	for i, k := range g.keys {
+		wg.Add(1)
+		go func(i int, k *key) {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(begin.UnixNano() + int64(i)))
+			for {
+				if tokens != nil {
+					select {
+					case <-sendCtx.Done():
+						return
+					case <-tokens:
+					}
+				}
+				if sendCtx.Err() != nil {
+					return
+				}
+				if g.cfg.Count > 0 && started.Add(1) > int64(g.cfg.Count) {
+					return
+				}
+				kind := g.kinds[rng.Intn(len(g.kinds))]
+				out := g.send(ctx, rng, k, kind)
+				report.add(kind, out)
+				if out.skipped {
+					// Skipped transactions do not count, wait for an NFT from another key.
+					started.Add(-1)
+					select {
+					case <-sendCtx.Done():
+						return
+					case <-time.After(g.cfg.PollInterval):
+					}
+				}
+			}
+		}(i, k)
+	}
+	wg.Wait()
+	report.finish(time.Since(begin))
+	return report, nil
+}
+
+// limiter returns a channel delivering Rate tokens per second until ctx is done, nil without
+// a rate.
+func (g *Generator) limiter(ctx context.Context) <-chan struct{} {
+	if g.cfg.Rate <= 0 {
+		return nil
+	}
+	tokens := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / g.cfg.Rate))
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case tokens <- struct{}{}:
+			}
+		}
+	}()
+	return tokens
+}
+
+// assignNFTs hands the configured NFTs to the keys owning them.
+func (g *Generator) assignNFTs(ctx context.Context) error {
+	owners := make(map[common.Address]*key, len(g.keys))
+	for _, k := range g.keys {
+		owners[k.account] = k
+		k.nfts = nil
+	}
+	for _, nft := range g.cfg.NFTs {
+		info, err := g.worm.GetAccountInfoAt(ctx, nft, client.Latest)
+		if err != nil {
+			return xerrors.Errorf("loadgen nft %s: %w", nft, err)
+		}
+		k, ok := owners[info.Nft.Owner]
+		if !ok {
+			return xerrors.Errorf("loadgen nft %s is owned by %s, not by a key", nft, info.Nft.Owner.Hex())
+		}
+		k.nfts = append(k.nfts, nft)
+	}
+	return nil
+}
+
+// outcome is the result of one generated transaction.
+type outcome struct {
+	skipped bool
+	latency time.Duration
+	// reason is the reason of a failure, empty on success.
+	reason string
+}
+
+// send sends one transaction of kind from k and waits for its receipt.
+func (g *Generator) send(ctx context.Context, rng *rand.Rand, k *key, kind Kind) outcome {
+	var nft string
+	var other *key
+	if kind == Transfer || kind == Trade {
+		if nft = k.take(); nft == "" {
+			return outcome{skipped: true}
+		}
+		other = g.keys[rng.Intn(len(g.keys)-1)]
+		if other == k {
+			other = g.keys[len(g.keys)-1]
+		}
+	}
+	start := time.Now()
+	hash, err := g.submit(ctx, k, kind, nft, other)
+	if err == nil {
+		err = g.wait(ctx, hash)
+	}
+	if nft != "" {
+		if err == nil {
+			other.give(nft)
+		} else {
+			// An NFT whose transaction timed out may have moved, it is not used again.
+			if !errors.Is(err, errTimeout) {
+				k.give(nft)
+			}
+		}
+	}
+	if err != nil {
+		return outcome{reason: reasonOf(err)}
+	}
+	return outcome{latency: time.Since(start)}
+}
+
+// submit sends the transaction of kind and returns its hash.
+func (g *Generator) submit(ctx context.Context, k *key, kind Kind, nft string, other *key) (string, error) {
+	switch kind {
+	case Mint:
+		return k.worm.MintCtx(ctx, 0, defaultMetaURL, "")
+	case Transfer:
+		return k.worm.TransferCtx(ctx, nft, other.account.Hex())
+	case Trade:
+		number, err := g.worm.BlockNumber(ctx)
+		if err != nil {
+			return "", err
+		}
+		seller1, err := k.worm.SignSeller1(tools.EncodeAmount(g.cfg.Price), nft, g.cfg.Exchanger, tools.EncodeBlockNumber(number+orderValidity))
+		if err != nil {
+			return "", err
+		}
+		return other.worm.BuyerInitiatingTransactionCtx(ctx, seller1)
+	default:
+		return k.worm.TokenPledgeCtx(ctx, k.account, "", "", "", types2.Wei(g.cfg.PledgeAmount), 0)
+	}
+}
+
+var (
+	errTimeout  = errors.New("no receipt before the timeout")
+	errReverted = errors.New("reverted")
+)
+
+// wait polls the receipt of the transaction hash.
+func (g *Generator) wait(ctx context.Context, hash string) error {
+	ctx, cancel := context.WithTimeout(ctx, g.cfg.Timeout)
+	defer cancel()
+	for {
+		receipt, err := g.worm.TransactionReceipt(ctx, hash)
+		switch {
+		case err == nil && receipt.Status == types.ReceiptStatusSuccessful:
+			return nil
+		case err == nil:
+			return errReverted
+		case !errors.Is(err, ethereum.NotFound) && ctx.Err() == nil:
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return errTimeout
+		case <-time.After(g.cfg.PollInterval):
+		}
+	}
+}
+
+// take removes an NFT from the key, empty if it holds none.
+func (k *key) take() string {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if len(k.nfts) == 0 {
+		return ""
+	}
+	nft := k.nfts[len(k.nfts)-1]
+	k.nfts = k.nfts[:len(k.nfts)-1]
+	return nft
+}
+
+func (k *key) give(nft string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.nfts = append(k.nfts, nft)
+}
@@ -0,0 +1,151 @@
+package loadgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/erbieio/erb-client/v2/client"
+)
+
+// Latency holds percentiles of the time from sending a transaction to reading its receipt.
+type Latency struct {
+	P50, P90, P99, Max time.Duration
+}
+
+// Stats counts the transactions of a kind, or of all kinds.
+type Stats struct {
+	// Sent counts the transactions sent or attempted, Confirmed and Failed split them.
+	Sent      int
+	Confirmed int
+	Failed    int
+	// Skipped counts the transfers and trades not sent for lack of an NFT.
+	Skipped int
+	Latency Latency
+
+	latencies []time.Duration
+}
+
+// Report is the result of a run.
+type Report struct {
+	Total Stats
+	Kinds map[Kind]*Stats
+	// Failures counts the failed transactions by reason, e.g. "reverted" or an error of the node.
+	Failures map[string]int
+	Duration time.Duration
+	// TPS is the number of confirmed transactions per second.
+	TPS float64
+
+	mu sync.Mutex
+}
+
+func newReport() *Report {
+	return &Report{Kinds: make(map[Kind]*Stats), Failures: make(map[string]int)}
+}
+
+func (r *Report) add(kind Kind, out outcome) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stats := r.Kinds[kind]
+	if stats == nil {
+		stats = &Stats{}
+		r.Kinds[kind] = stats
+	}
+	for _, s := range []*Stats{&r.Total, stats} {
+		switch {
+		case out.skipped:
+			s.Skipped++
+		case out.reason != "":
+			s.Sent++
+			s.Failed++
+		default:
+			s.Sent++
+			s.Confirmed++
+			s.latencies = append(s.latencies, out.latency)
+		}
+	}
+	if out.reason != "" {
+		r.Failures[out.reason]++
+	}
+}
+
+func (r *Report) finish(elapsed time.Duration) {
+	r.Duration = elapsed
+	if elapsed > 0 {
+		r.TPS = float64(r.Total.Confirmed) / elapsed.Seconds()
+	}
+	r.Total.finish()
+	for _, stats := range r.Kinds {
+		stats.finish()
+	}
+}
+
+func (s *Stats) finish() {
+	sort.Slice(s.latencies, func(i, j int) bool { return s.latencies[i] < s.latencies[j] })
+	s.Latency = Latency{
+		P50: percentile(s.latencies, 50),
+		P90: percentile(s.latencies, 90),
+		P99: percentile(s.latencies, 99),
+		Max: percentile(s.latencies, 100),
+	}
+}
+
+// percentile returns the p-th percentile of the sorted durations, by the nearest rank.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// Print writes the report as a table.
+func (r *Report) Print(w io.Writer) {
+	fmt.Fprintf(w, "%d confirmed, %d failed in %s, %.1f tps\n",
+		r.Total.Confirmed, r.Total.Failed, r.Duration.Round(time.Millisecond), r.TPS)
+	fmt.Fprintf(w, "%-10s %8s %8s %8s %8s %10s %10s %10s %10s\n",
+		"kind", "sent", "ok", "failed", "skipped", "p50", "p90", "p99", "max")
+	for _, kind := range []Kind{Mint, Transfer, Trade, Pledge} {
+		if s := r.Kinds[kind]; s != nil {
+			s.print(w, string(kind))
+		}
+	}
+	r.Total.print(w, "total")
+	reasons := make([]string, 0, len(r.Failures))
+	for reason := range r.Failures {
+		reasons = append(reasons, reason)
+	}
+	sort.Slice(reasons, func(i, j int) bool { return r.Failures[reasons[i]] > r.Failures[reasons[j]] })
+	for _, reason := range reasons {
+		fmt.Fprintf(w, "%8d  %s\n", r.Failures[reason], reason)
+	}
+}
+
+func (s *Stats) print(w io.Writer, name string) {
+	l := s.Latency
+	fmt.Fprintf(w, "%-10s %8d %8d %8d %8d %10s %10s %10s %10s\n", name, s.Sent, s.Confirmed, s.Failed, s.Skipped,
+		l.P50.Round(time.Millisecond), l.P90.Round(time.Millisecond), l.P99.Round(time.Millisecond), l.Max.Round(time.Millisecond))
+}
+
+// reasonOf groups a failure by the kind of error, without the details of the transaction.
+func reasonOf(err error) string {
+	var rpcErr *client.RPCError
+	switch {
+	case errors.Is(err, errTimeout), errors.Is(err, errReverted):
+		return err.Error()
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return "cancelled"
+	case errors.As(err, &rpcErr):
+		message, _, _ := strings.Cut(rpcErr.Error(), ":")
+		return message
+	}
+	return err.Error()
+}
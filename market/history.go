@@ -0,0 +1,157 @@
+package market
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/erbieio/erb-client/v2/client"
+	"github.com/erbieio/erb-client/v2/scanner"
+	"github.com/erbieio/erb-client/v2/store"
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// PricePoint is the price of a single sale.
+type PricePoint struct {
+	Time        time.Time
+	BlockNumber uint64
+	TxHash      common.Hash
+	NFTAddress  string
+	Creator     common.Address
+	// Price is the sale price in wei.
+	Price *big.Int
+}
+
+// TradeHistoryLoader reads persisted trades with their block time, implemented by store.SQLStore.
+type TradeHistoryLoader interface {
+	TradeHistory(ctx context.Context, from uint64) ([]*store.TimedTrade, error)
+}
+
+// PriceHistory keeps the sale prices of NFTs as time series per NFT and per creator
+// collection. It is safe for concurrent use.
+type PriceHistory struct {
+	store store.Store
+
+	mu     sync.Mutex
+	points []*PricePoint
+	hashes map[common.Hash]bool
+}
+
+// NewPriceHistory creates an empty history. When s is not nil, added trades are saved to it.
+func NewPriceHistory(s store.Store) *PriceHistory {
+	return &PriceHistory{store: s, hashes: make(map[common.Hash]bool)}
+}
+
+// Load adds the persisted trades from block number on, without saving them again.
+func (h *PriceHistory) Load(ctx context.Context, loader TradeHistoryLoader, from uint64) error {
+	trades, err := loader.TradeHistory(ctx, from)
+	if err != nil {
+		return err
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, trade := range trades {
+		h.insert(trade.Trade, time.Unix(int64(trade.Time), 0))
+	}
+	return nil
+}
+
+// Attach registers a handler on s that decodes its trades with worm and adds them.
+// With a store, the blocks of the trades are saved too so their time can be loaded back.
+func (h *PriceHistory) Attach(s *scanner.Scanner, worm *client.Wormholes) {
+	s.OnTrade(func(ctx context.Context, trade *scanner.Trade) error {
+		tx := trade.Transaction
+		receipt, err := worm.TransactionReceipt(ctx, tx.Tx.Hash().Hex())
+		if err != nil {
+			return err
+		}
+		decoded, err := worm.DecodeTrade(ctx, tx.Tx, receipt)
+		if errors.Is(err, client.ErrTradeFailed) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if h.store != nil {
+			if err := h.store.SaveBlock(ctx, store.NewBlock(tx.Block)); err != nil {
+				return err
+			}
+		}
+		return h.Add(ctx, decoded, blockTime(tx.Block))
+	})
+}
+
+// Add records a trade settled at the given time and saves it to the store, if any.
+func (h *PriceHistory) Add(ctx context.Context, trade *types2.Trade, at time.Time) error {
+	if h.store != nil {
+		if err := h.store.SaveTrade(ctx, trade); err != nil {
+			return err
+		}
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.insert(trade, at)
+	return nil
+}
+
+// insert adds a trade keeping the points ordered by time, h.mu must be held.
+// A trade that was added before is ignored.
+func (h *PriceHistory) insert(trade *types2.Trade, at time.Time) {
+	if h.hashes[trade.TxHash] {
+		return
+	}
+	h.hashes[trade.TxHash] = true
+	point := &PricePoint{
+		Time:        at,
+		BlockNumber: trade.BlockNumber,
+		TxHash:      trade.TxHash,
+		NFTAddress:  trade.NFTAddress,
+		Creator:     trade.Creator,
+		Price:       new(big.Int).Set(trade.Price),
+	}
+	i := sort.Search(len(h.points), func(i int) bool { return h.points[i].Time.After(at) })
+	h.points = append(h.points, nil)
+	copy(h.points[i+1:], h.points[i:])
+	h.points[i] = point
+}
+
+// NFT returns the sales of an NFT in [from, to), ordered by time. Zero times are unbounded.
+func (h *PriceHistory) NFT(nftAddress string, from, to time.Time) []*PricePoint {
+	return h.series(from, to, func(p *PricePoint) bool {
+		return p.NFTAddress != "" && strings.EqualFold(p.NFTAddress, nftAddress)
+	})
+}
+
+// Collection returns the sales of the NFTs of a creator in [from, to), ordered by time.
+// Zero times are unbounded.
+func (h *PriceHistory) Collection(creator common.Address, from, to time.Time) []*PricePoint {
+	return h.series(from, to, func(p *PricePoint) bool { return p.Creator == creator })
+}
+
+func (h *PriceHistory) series(from, to time.Time, match func(p *PricePoint) bool) []*PricePoint {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var points []*PricePoint
+	for _, p := range h.points {
+		if !from.IsZero() && p.Time.Before(from) {
+			continue
+		}
+		if !to.IsZero() && !p.Time.Before(to) {
+			break
+		}
+		if match(p) {
+			points = append(points, p)
+		}
+	}
+	return points
+}
+
+func blockTime(block *types.Block) time.Time {
+	return time.Unix(int64(block.Time()), 0)
+}
@@ -0,0 +1,213 @@
+// Package market keeps rolling trade statistics per collection and exchanger, fed by the
+// trades decoded from a scanner.
+package market
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/erbieio/erb-client/v2/client"
+	"github.com/erbieio/erb-client/v2/scanner"
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// DefaultWindows are the statistic windows used when Config.Windows is empty.
+var DefaultWindows = []time.Duration{time.Hour, 24 * time.Hour, 7 * 24 * time.Hour}
+
+// Config configures a Market. Zero values select the defaults.
+type Config struct {
+	// Windows are the lengths of the rolling windows statistics are kept for.
+	Windows []time.Duration
+	// CollectionOf returns the collection of a trade, the hex address of the NFT creator by default.
+	CollectionOf func(trade *types2.Trade) string
+}
+
+// Stats are the statistics of the trades in a window ending at the latest block.
+type Stats struct {
+	Window time.Duration
+	Trades int
+	// Volume is the sum of the trade prices in wei.
+	Volume *big.Int
+	// Floor is the lowest trade price in wei, nil if there was no trade.
+	Floor        *big.Int
+	UniqueBuyers int
+}
+
+type record struct {
+	time       time.Time
+	collection string
+	exchanger  common.Address
+	buyer      common.Address
+	price      *big.Int
+}
+
+// Market aggregates trades. Time is chain time: windows end at the time of the latest block
+// or trade seen, so statistics of a backfill are meaningful. It is safe for concurrent use.
+type Market struct {
+	cfg       Config
+	retention time.Duration
+
+	mu      sync.Mutex
+	now     time.Time
+	records []*record
+}
+
+// New creates an empty market.
+func New(cfg Config) *Market {
+	if len(cfg.Windows) == 0 {
+		cfg.Windows = DefaultWindows
+	}
+	if cfg.CollectionOf == nil {
+		cfg.CollectionOf = func(trade *types2.Trade) string { return trade.Creator.Hex() }
+	}
+	m := &Market{cfg: cfg}
+	for _, window := range cfg.Windows {
+		if window > m.retention {
+			m.retention = window
+		}
+	}
+	return m
+}
+
+// Attach registers handlers on s that decode its trades with worm and add them to the market.
+func (m *Market) Attach(s *scanner.Scanner, worm *client.Wormholes) {
+	s.OnBlock(func(ctx context.Context, block *types.Block) error {
+		m.Advance(blockTime(block))
+		return nil
+	})
+	s.OnTrade(func(ctx context.Context, trade *scanner.Trade) error {
+		tx := trade.Transaction
+		receipt, err := worm.TransactionReceipt(ctx, tx.Tx.Hash().Hex())
+		if err != nil {
+			return err
+		}
+		decoded, err := worm.DecodeTrade(ctx, tx.Tx, receipt)
+		if errors.Is(err, client.ErrTradeFailed) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		m.Add(decoded, blockTime(tx.Block))
+		return nil
+	})
+}
+
+// Add records a trade settled at the given time.
+func (m *Market) Add(trade *types2.Trade, at time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.records = append(m.records, &record{
+		time:       at,
+		collection: m.cfg.CollectionOf(trade),
+		exchanger:  trade.Exchanger,
+		buyer:      trade.Buyer,
+		price:      new(big.Int).Set(trade.Price),
+	})
+	if at.After(m.now) {
+		m.now = at
+	}
+	m.prune()
+}
+
+// Advance moves the market clock to now, dropping trades older than the longest window.
+func (m *Market) Advance(now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if now.After(m.now) {
+		m.now = now
+		m.prune()
+	}
+}
+
+// prune drops the records that left every window, m.mu must be held.
+func (m *Market) prune() {
+	cutoff := m.now.Add(-m.retention)
+	i := 0
+	for _, r := range m.records {
+		if r.time.After(cutoff) {
+			m.records[i] = r
+			i++
+		}
+	}
+	for j := i; j < len(m.records); j++ {
+		m.records[j] = nil
+	}
+	m.records = m.records[:i]
+}
+
+// Collection returns the statistics of a collection, one per configured window.
+func (m *Market) Collection(collection string) []*Stats {
+	return m.stats(func(r *record) bool { return r.collection == collection })
+}
+
+// Exchanger returns the statistics of the trades on an exchanger, one per configured window.
+func (m *Market) Exchanger(exchanger common.Address) []*Stats {
+	return m.stats(func(r *record) bool { return r.exchanger == exchanger })
+}
+
+// Collections returns the collections with trades in the longest window, sorted.
+func (m *Market) Collections() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	seen := make(map[string]bool)
+	var collections []string
+	for _, r := range m.records {
+		if !seen[r.collection] {
+			seen[r.collection] = true
+			collections = append(collections, r.collection)
+		}
+	}
+	sort.Strings(collections)
+	return collections
+}
+
+// Exchangers returns the exchangers with trades in the longest window, trades without an
+// exchanger are reported under the zero address.
+func (m *Market) Exchangers() []common.Address {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	seen := make(map[common.Address]bool)
+	var exchangers []common.Address
+	for _, r := range m.records {
+		if !seen[r.exchanger] {
+			seen[r.exchanger] = true
+			exchangers = append(exchangers, r.exchanger)
+		}
+	}
+	sort.Slice(exchangers, func(i, j int) bool {
+		return exchangers[i].Hex() < exchangers[j].Hex()
+	})
+	return exchangers
+}
+
+func (m *Market) stats(match func(r *record) bool) []*Stats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	result := make([]*Stats, len(m.cfg.Windows))
+	for i, window := range m.cfg.Windows {
+		cutoff := m.now.Add(-window)
+		stats := &Stats{Window: window, Volume: new(big.Int)}
+		buyers := make(map[common.Address]bool)
+		for _, r := range m.records {
+			if !r.time.After(cutoff) || !match(r) {
+				continue
+			}
+			stats.Trades++
+			stats.Volume.Add(stats.Volume, r.price)
+			if stats.Floor == nil || r.price.Cmp(stats.Floor) < 0 {
+				stats.Floor = new(big.Int).Set(r.price)
+			}
+			buyers[r.buyer] = true
+		}
+		stats.UniqueBuyers = len(buyers)
+		result[i] = stats
+	}
+	return result
+}
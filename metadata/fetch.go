@@ -0,0 +1,101 @@
+package metadata
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/erbieio/erb-client/v2/client"
+	"golang.org/x/xerrors"
+)
+
+const (
+	defaultGateway = "https://ipfs.io"
+	defaultTimeout = 30 * time.Second
+	// maxDocumentSize bounds the metadata read, marketplaces reject larger documents anyway.
+	maxDocumentSize = 1 << 20
+)
+
+// Config configures a Checker. Zero values select the defaults.
+type Config struct {
+	// Gateway is the IPFS gateway fetching ipfs:// and /ipfs/ URLs, https://ipfs.io by default.
+	Gateway string
+	// Client fetches the documents, an http.Client with a 30s timeout by default.
+	Client *http.Client
+}
+
+// Checker fetches and validates the metadata of NFTs.
+type Checker struct {
+	gateway string
+	client  *http.Client
+}
+
+// NewChecker creates a checker.
+func NewChecker(cfg Config) *Checker {
+	if cfg.Gateway == "" {
+		cfg.Gateway = defaultGateway
+	}
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: defaultTimeout}
+	}
+	return &Checker{gateway: strings.TrimRight(cfg.Gateway, "/"), client: cfg.Client}
+}
+
+// Check fetches the metadata at metaURL and validates it. The error is of the fetch, the
+// issues of the document are in the report.
+func (c *Checker) Check(ctx context.Context, metaURL string) (*Report, error) {
+	data, err := c.fetch(ctx, metaURL)
+	if err != nil {
+		return nil, err
+	}
+	r := Validate(data)
+	if strings.HasPrefix(metaURL, "/ipfs/") {
+		r.add(Warning, "", "meta URL %s is a gateway path, external marketplaces expect ipfs://%s", metaURL, strings.TrimPrefix(metaURL, "/ipfs/"))
+	}
+	return r, nil
+}
+
+// CheckNFT validates the metadata of the native NFT nftAddress, read at the latest block.
+func (c *Checker) CheckNFT(ctx context.Context, worm *client.Wormholes, nftAddress string) (*Report, error) {
+	info, err := worm.GetAccountInfoAt(ctx, nftAddress, client.Latest)
+	if err != nil {
+		return nil, err
+	}
+	if info.Nft.MetaURL == "" {
+		return nil, xerrors.Errorf("nft %s has no meta URL", nftAddress)
+	}
+	return c.Check(ctx, info.Nft.MetaURL)
+}
+
+// fetch reads the document at metaURL, through the gateway for IPFS.
+func (c *Checker) fetch(ctx context.Context, metaURL string) ([]byte, error) {
+	u := metaURL
+	switch {
+	case strings.HasPrefix(u, "ipfs://"):
+		u = c.gateway + "/ipfs/" + strings.TrimPrefix(strings.TrimPrefix(u, "ipfs://"), "ipfs/")
+	case strings.HasPrefix(u, "/ipfs/"):
+		u = c.gateway + u
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, xerrors.Errorf("invalid meta URL %s: %w", metaURL, err)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, xerrors.Errorf("fetch metadata %s: %w", metaURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, xerrors.Errorf("fetch metadata %s: %s", metaURL, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxDocumentSize+1))
+	if err != nil {
+		return nil, xerrors.Errorf("fetch metadata %s: %w", metaURL, err)
+	}
+	if len(data) > maxDocumentSize {
+		return nil, xerrors.Errorf("metadata %s is larger than %d bytes", metaURL, maxDocumentSize)
+	}
+	return data, nil
+}
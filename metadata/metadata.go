@@ -0,0 +1,209 @@
+// Package metadata checks NFT metadata against the conventions of ERC-721 and of OpenSea, which
+// most external marketplaces follow, so wormholes NFTs mirrored to them render correctly:
+// a JSON object with a name, a description, an image reachable by URL, and attributes with a
+// trait_type, a value and an optional display_type.
+package metadata
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Severity tells how an issue affects the rendering of the NFT.
+type Severity string
+
+const (
+	// Error marks metadata the marketplaces reject or render without the field.
+	Error Severity = "error"
+	// Warning marks a recommended field missing or a field some marketplaces ignore.
+	Warning Severity = "warning"
+)
+
+// Issue is an incompatibility of metadata.
+type Issue struct {
+	Severity Severity `json:"severity"`
+	// Field is the path of the field, e.g. "attributes[2].value", empty for the document.
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (i Issue) String() string {
+	if i.Field == "" {
+		return fmt.Sprintf("%s: %s", i.Severity, i.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", i.Severity, i.Field, i.Message)
+}
+
+// Report lists the issues of metadata.
+type Report struct {
+	Issues []Issue `json:"issues"`
+}
+
+// Compatible tells if the metadata has no errors, warnings allowed.
+func (r *Report) Compatible() bool {
+	for _, issue := range r.Issues {
+		if issue.Severity == Error {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *Report) add(severity Severity, field, format string, args ...interface{}) {
+	r.Issues = append(r.Issues, Issue{Severity: severity, Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// displayTypes are the display types of attributes, all but string need numeric values.
+var displayTypes = map[string]bool{"string": false, "number": true, "boost_number": true, "boost_percentage": true, "date": true}
+
+// Validate checks the metadata document data.
+func Validate(data []byte) *Report {
+	r := &Report{}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		r.add(Error, "", "not a JSON object: %v", err)
+		return r
+	}
+	for _, field := range []string{"name", "description"} {
+		if _, ok := doc[field]; !ok {
+			r.add(Warning, field, "missing")
+		} else if s, ok := doc[field].(string); !ok {
+			r.add(Error, field, "is a %s, want a string", kind(doc[field]))
+		} else if strings.TrimSpace(s) == "" {
+			r.add(Warning, field, "empty")
+		}
+	}
+	_, hasImage := doc["image"]
+	_, hasImageData := doc["image_data"]
+	if !hasImage && !hasImageData {
+		r.add(Error, "image", "missing, marketplaces show a placeholder")
+	}
+	for _, field := range []string{"image", "external_url", "animation_url", "youtube_url"} {
+		if v, ok := doc[field]; ok {
+			checkURL(r, field, v)
+		}
+	}
+	if v, ok := doc["image_data"]; ok {
+		if _, ok := v.(string); !ok {
+			r.add(Error, "image_data", "is a %s, want SVG as a string", kind(v))
+		}
+	}
+	if v, ok := doc["background_color"]; ok {
+		if s, ok := v.(string); !ok || !isHexColor(s) {
+			r.add(Error, "background_color", "%v is not six hex digits without #", v)
+		}
+	}
+	if v, ok := doc["attributes"]; ok {
+		checkAttributes(r, v)
+	}
+	return r
+}
+
+// checkURL checks a URL the marketplaces fetch.
+func checkURL(r *Report, field string, v interface{}) {
+	s, ok := v.(string)
+	if !ok {
+		r.add(Error, field, "is a %s, want a URL", kind(v))
+		return
+	}
+	if strings.HasPrefix(s, "/ipfs/") {
+		r.add(Error, field, "%s is a gateway path, use ipfs://%s", s, strings.TrimPrefix(s, "/ipfs/"))
+		return
+	}
+	u, err := url.Parse(s)
+	if err != nil || u.Scheme == "" {
+		r.add(Error, field, "%q is not an absolute URL", s)
+		return
+	}
+	switch u.Scheme {
+	case "https", "ipfs", "ar", "data":
+	case "http":
+		r.add(Warning, field, "%s is not https, some marketplaces do not load it", s)
+	default:
+		r.add(Error, field, "unsupported scheme %s", u.Scheme)
+	}
+}
+
+func checkAttributes(r *Report, v interface{}) {
+	attributes, ok := v.([]interface{})
+	if !ok {
+		r.add(Error, "attributes", "is a %s, want an array", kind(v))
+		return
+	}
+	for i, a := range attributes {
+		field := fmt.Sprintf("attributes[%d]", i)
+		attribute, ok := a.(map[string]interface{})
+		if !ok {
+			r.add(Error, field, "is a %s, want an object", kind(a))
+			continue
+		}
+		if t, ok := attribute["trait_type"]; !ok {
+			r.add(Warning, field+".trait_type", "missing, the value is shown without a trait")
+		} else if _, ok := t.(string); !ok {
+			r.add(Error, field+".trait_type", "is a %s, want a string", kind(t))
+		}
+		value, ok := attribute["value"]
+		if !ok {
+			r.add(Error, field+".value", "missing")
+			continue
+		}
+		_, numeric := value.(float64)
+		switch value.(type) {
+		case string, float64, bool:
+		default:
+			r.add(Error, field+".value", "is a %s, want a string, number or boolean", kind(value))
+			continue
+		}
+		if d, ok := attribute["display_type"]; ok {
+			displayType, _ := d.(string)
+			needsNumber, known := displayTypes[displayType]
+			if !known {
+				r.add(Error, field+".display_type", "unknown display type %v", d)
+			} else if needsNumber && !numeric {
+				r.add(Error, field+".value", "is a %s, display type %s needs a number", kind(value), displayType)
+			}
+		}
+		if m, ok := attribute["max_value"]; ok {
+			max, ok := m.(float64)
+			if !ok {
+				r.add(Error, field+".max_value", "is a %s, want a number", kind(m))
+			} else if !numeric {
+				r.add(Error, field+".max_value", "set for a %s value", kind(value))
+			} else if value.(float64) > max {
+				r.add(Error, field+".value", "%v is above max_value %v", value, max)
+			}
+		}
+	}
+}
+
+func isHexColor(s string) bool {
+	if len(s) != 6 {
+		return false
+	}
+	for _, c := range s {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return false
+		}
+	}
+	return true
+}
+
+// kind names the JSON type of a decoded value.
+func kind(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
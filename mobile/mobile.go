@@ -0,0 +1,217 @@
+// Package mobile is the part of the client for iOS and Android wallet apps, bound with
+//
+//	gomobile bind -target=android github.com/erbieio/erb-client/v2/mobile
+//	gomobile bind -target=ios github.com/erbieio/erb-client/v2/mobile
+//
+// It covers keys, the signing of orders and authorizations and basic queries and transfers,
+// with the types gomobile supports: strings, byte slices, integers, errors and pointers to the
+// types of the package. Amounts are strings, decimal ERB or hex wei with a 0x prefix as in
+// types.ParseAmount, and are returned as decimal ERB. Structured results are JSON strings.
+package mobile
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/erbieio/erb-client/v2/client"
+	"github.com/erbieio/erb-client/v2/tools"
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/xerrors"
+)
+
+const defaultTimeout = 30 * time.Second
+
+// NewPrivateKey generates a private key and returns it as hex without a 0x prefix.
+func NewPrivateKey() (string, error) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		return "", err
+	}
+	return common.Bytes2Hex(crypto.FromECDSA(key)), nil
+}
+
+// AddressOf returns the checksummed address of the hex private key priKey.
+func AddressOf(priKey string) (string, error) {
+	account, _, err := tools.PriKeyToAddress(priKey)
+	if err != nil {
+		return "", err
+	}
+	return account.Hex(), nil
+}
+
+// RecoverAddress returns the address that signed msg, see tools.RecoverAddress.
+func RecoverAddress(msg, sig string) (string, error) {
+	account, err := tools.RecoverAddress(msg, sig)
+	if err != nil {
+		return "", err
+	}
+	return account.Hex(), nil
+}
+
+// Wallet signs orders and authorizations with a private key, the signed orders are JSON as
+// returned by client.Wallet.
+type Wallet struct {
+	w *client.Wallet
+}
+
+// NewWallet creates a wallet signing with the hex private key priKey.
+func NewWallet(priKey string) (*Wallet, error) {
+	if _, err := tools.ParsePriKey(priKey); err != nil {
+		return nil, err
+	}
+	return &Wallet{w: client.NewWallet(priKey)}, nil
+}
+
+// Address returns the checksummed address of the wallet.
+func (w *Wallet) Address() string {
+	account, _ := w.w.Address()
+	return account.Hex()
+}
+
+// Sign signs data as a personal message, see client.Wallet.Sign.
+func (w *Wallet) Sign(data []byte, priKey string) ([]byte, error) {
+	return w.w.Sign(data, priKey)
+}
+
+// SignBuyer signs a buy order, amount is decimal ERB or hex wei.
+func (w *Wallet) SignBuyer(amount, nftAddress, exchanger, blockNumber, seller string) ([]byte, error) {
+	value, err := types2.ParseAmount(amount)
+	if err != nil {
+		return nil, err
+	}
+	return w.w.SignBuyerAmount(value, nftAddress, exchanger, blockNumber, seller)
+}
+
+// SignSeller1 signs a sell order of a minted NFT, amount is decimal ERB or hex wei.
+func (w *Wallet) SignSeller1(amount, nftAddress, exchanger, blockNumber string) ([]byte, error) {
+	value, err := types2.ParseAmount(amount)
+	if err != nil {
+		return nil, err
+	}
+	return w.w.SignSeller1Amount(value, nftAddress, exchanger, blockNumber)
+}
+
+// SignSeller2 signs a sell order of an unminted NFT, amount is decimal ERB or hex wei.
+func (w *Wallet) SignSeller2(amount, royalty, metaURL, exclusiveFlag, exchanger, blockNumber string) ([]byte, error) {
+	value, err := types2.ParseAmount(amount)
+	if err != nil {
+		return nil, err
+	}
+	return w.w.SignSeller2Amount(value, royalty, metaURL, exclusiveFlag, exchanger, blockNumber)
+}
+
+// SignExchanger signs the authorization of an exchanger.
+func (w *Wallet) SignExchanger(exchangerOwner, to, blockNumber string) ([]byte, error) {
+	return w.w.SignExchanger(exchangerOwner, to, blockNumber)
+}
+
+// Client is a light client of a node. Every call waits at most the timeout, 30s by default.
+type Client struct {
+	worm    *client.Wormholes
+	timeout time.Duration
+}
+
+// Dial connects to the node at rawurl, sending transactions signed with the hex private key
+// priKey, which may be empty for queries only.
+func Dial(priKey, rawurl string) (*Client, error) {
+	worm, err := client.Dial(priKey, rawurl)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{worm: worm, timeout: defaultTimeout}, nil
+}
+
+// SetTimeout sets the timeout of the calls in milliseconds.
+func (c *Client) SetTimeout(millis int64) {
+	c.timeout = time.Duration(millis) * time.Millisecond
+}
+
+// Close closes the connection to the node.
+func (c *Client) Close() {
+	c.worm.CloseConnect()
+}
+
+func (c *Client) context() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), c.timeout)
+}
+
+// BlockNumber returns the number of the latest block.
+func (c *Client) BlockNumber() (int64, error) {
+	ctx, cancel := c.context()
+	defer cancel()
+	number, err := c.worm.BlockNumber(ctx)
+	return int64(number), err
+}
+
+// Balance returns the balance of account in decimal ERB.
+func (c *Client) Balance(account string) (string, error) {
+	ctx, cancel := c.context()
+	defer cancel()
+	balance, err := c.worm.Balance(ctx, account)
+	if err != nil {
+		return "", err
+	}
+	return types2.Wei(balance).String(), nil
+}
+
+// AccountInfo returns the state of account at the latest block as JSON.
+func (c *Client) AccountInfo(account string) (string, error) {
+	ctx, cancel := c.context()
+	defer cancel()
+	info, err := c.worm.GetAccountInfoAt(ctx, account, client.Latest)
+	if err != nil {
+		return "", err
+	}
+	return marshal(info)
+}
+
+// Receipt returns the receipt of the transaction txHash as JSON, the error wraps
+// ethereum.NotFound while it is pending.
+func (c *Client) Receipt(txHash string) (string, error) {
+	ctx, cancel := c.context()
+	defer cancel()
+	receipt, err := c.worm.TransactionReceipt(ctx, txHash)
+	if err != nil {
+		return "", err
+	}
+	return marshal(receipt)
+}
+
+// SendERB sends amount, decimal ERB or hex wei, to the address to with the transaction data
+// data, which may be empty, and returns the hash of the transaction.
+func (c *Client) SendERB(to, amount, data string) (string, error) {
+	value, err := types2.ParseAmount(amount)
+	if err != nil {
+		return "", err
+	}
+	ctx, cancel := c.context()
+	defer cancel()
+	return c.worm.NormalTransactionCtx(ctx, to, value, data)
+}
+
+// TransferNFT transfers the NFT at nftAddress to the address to and returns the hash of the
+// transaction.
+func (c *Client) TransferNFT(nftAddress, to string) (string, error) {
+	ctx, cancel := c.context()
+	defer cancel()
+	return c.worm.TransferCtx(ctx, nftAddress, to)
+}
+
+// BuyNFT sends the buy transaction of the signed sell order of a minted NFT, as returned by
+// Wallet.SignSeller1, and returns the hash of the transaction.
+func (c *Client) BuyNFT(seller1 []byte) (string, error) {
+	ctx, cancel := c.context()
+	defer cancel()
+	return c.worm.BuyerInitiatingTransactionCtx(ctx, seller1)
+}
+
+func marshal(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", xerrors.Errorf("failed to encode the result: %w", err)
+	}
+	return string(data), nil
+}
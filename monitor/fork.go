@@ -0,0 +1,191 @@
+// Package monitor watches a node for chain consistency problems.
+package monitor
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/erbieio/erb-client/v2/client"
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	defaultWindow       = 1000
+	defaultPollInterval = time.Second
+)
+
+// ForkKind is the kind of inconsistency reported by a ForkMonitor.
+type ForkKind int
+
+const (
+	// ConflictingHash is a head at a height that was seen before with a different hash.
+	ConflictingHash ForkKind = iota
+	// ParentMismatch is a head whose parent hash differs from the hash seen at the height below.
+	ParentMismatch
+)
+
+func (k ForkKind) String() string {
+	switch k {
+	case ConflictingHash:
+		return "conflicting hash"
+	case ParentMismatch:
+		return "parent mismatch"
+	}
+	return "unknown"
+}
+
+// ForkEvent describes an inconsistency between the heads observed by a ForkMonitor.
+type ForkEvent struct {
+	Kind ForkKind
+	// Head is the newly observed head.
+	Head *types2.Header
+	// Previous is the header remembered at the conflicting height: the same height
+	// for ConflictingHash, the height below for ParentMismatch.
+	Previous *types2.Header
+}
+
+// ForkHandler is called for every detected inconsistency.
+type ForkHandler func(ev *ForkEvent)
+
+// ForkStats are the counters of a ForkMonitor.
+type ForkStats struct {
+	Heads            uint64
+	Conflicts        uint64
+	ParentMismatches uint64
+}
+
+// ForkConfig configures a ForkMonitor. Zero values select the defaults.
+type ForkConfig struct {
+	// Window is the number of heights remembered below the latest head, 1000 by default.
+	Window uint64
+	// PollInterval is the interval between head polls in Run, 1s by default.
+	PollInterval time.Duration
+}
+
+// ForkMonitor follows the heads of a node and reports same-height blocks with different
+// hashes and heads that do not link to the block seen below them. It only remembers the
+// first block seen at each height, so a fork is reported on every poll until the window
+// moves past it.
+type ForkMonitor struct {
+	worm *client.Wormholes
+	cfg  ForkConfig
+
+	mu       sync.Mutex
+	headers  map[uint64]*types2.Header
+	latest   uint64
+	stats    ForkStats
+	handlers []ForkHandler
+}
+
+// NewForkMonitor creates a monitor polling worm.
+func NewForkMonitor(worm *client.Wormholes, cfg ForkConfig) *ForkMonitor {
+	if cfg.Window == 0 {
+		cfg.Window = defaultWindow
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = defaultPollInterval
+	}
+	return &ForkMonitor{
+		worm:    worm,
+		cfg:     cfg,
+		headers: make(map[uint64]*types2.Header),
+	}
+}
+
+// OnFork registers a handler called for every detected inconsistency.
+func (m *ForkMonitor) OnFork(h ForkHandler) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.handlers = append(m.handlers, h)
+}
+
+// Stats returns the counters of the monitor.
+func (m *ForkMonitor) Stats() ForkStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.stats
+}
+
+// Run polls the head until ctx is cancelled. Failures to reach the node are logged.
+func (m *ForkMonitor) Run(ctx context.Context) error {
+	ticker := time.NewTicker(m.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := m.Check(ctx); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			log.Println("fork monitor check err ", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check fetches the latest head, compares it with the remembered heads and returns the
+// detected inconsistencies after calling the handlers.
+func (m *ForkMonitor) Check(ctx context.Context) ([]*ForkEvent, error) {
+	head, err := m.worm.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	events, handlers := m.observe(head)
+	for _, ev := range events {
+		for _, h := range handlers {
+			h(ev)
+		}
+	}
+	return events, nil
+}
+
+func (m *ForkMonitor) observe(head *types2.Header) ([]*ForkEvent, []ForkHandler) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stats.Heads++
+
+	var events []*ForkEvent
+	if prev, ok := m.headers[head.Number]; ok {
+		if prev.Hash != head.Hash {
+			m.stats.Conflicts++
+			events = append(events, &ForkEvent{Kind: ConflictingHash, Head: head, Previous: prev})
+		}
+	} else {
+		m.headers[head.Number] = head
+	}
+	if head.Number > 0 {
+		if parent, ok := m.headers[head.Number-1]; ok && parent.Hash != head.ParentHash {
+			m.stats.ParentMismatches++
+			events = append(events, &ForkEvent{Kind: ParentMismatch, Head: head, Previous: parent})
+		}
+	}
+
+	if head.Number > m.latest {
+		m.latest = head.Number
+	}
+	if m.latest >= m.cfg.Window {
+		cutoff := m.latest - m.cfg.Window
+		for number := range m.headers {
+			if number < cutoff {
+				delete(m.headers, number)
+			}
+		}
+	}
+	return events, m.handlers
+}
+
+// Hash returns the hash remembered at a height.
+func (m *ForkMonitor) Hash(number uint64) (common.Hash, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	header, ok := m.headers[number]
+	if !ok {
+		return common.Hash{}, false
+	}
+	return header.Hash, true
+}
@@ -0,0 +1,245 @@
+package monitor
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/erbieio/erb-client/v2/client"
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+const (
+	defaultHealthInterval = 10 * time.Second
+	defaultStallAfter     = 30 * time.Second
+)
+
+// Names of the gauges set by a HealthMonitor.
+const (
+	GaugeHeadNumber             = "erb/head/number"
+	GaugeHeadAge                = "erb/head/age_seconds"
+	GaugeBlockInterval          = "erb/block/interval_seconds"
+	GaugeGasUsedRatio           = "erb/block/gas_used_ratio"
+	GaugeValidators             = "erb/validators/total"
+	GaugeActiveValidators       = "erb/validators/active"
+	GaugeValidatorParticipation = "erb/validators/participation_ratio"
+	GaugeTxPoolPending          = "erb/txpool/pending"
+	GaugeTxPoolQueued           = "erb/txpool/queued"
+	GaugeStalled                = "erb/chain/stalled"
+)
+
+// Gauges receives the sampled values of a HealthMonitor.
+type Gauges interface {
+	SetGauge(name string, value float64)
+}
+
+// RegistryGauges sets the gauges in a go-ethereum metrics registry, the default registry when r
+// is nil, from which they are served by its Prometheus and InfluxDB exporters. Like all
+// go-ethereum metrics they only record values when metrics.Enabled is set.
+func RegistryGauges(r metrics.Registry) Gauges {
+	return registryGauges{r}
+}
+
+type registryGauges struct {
+	r metrics.Registry
+}
+
+func (g registryGauges) SetGauge(name string, value float64) {
+	metrics.GetOrRegisterGaugeFloat64(name, g.r).Update(value)
+}
+
+// HealthSample is one sample of the chain health.
+type HealthSample struct {
+	Time time.Time
+	Head *types2.Header
+	// HeadAge is the time since the head was produced.
+	HeadAge time.Duration
+	// BlockInterval is the time between the head and its parent.
+	BlockInterval time.Duration
+	GasUsedRatio  float64
+	// Validators, ActiveValidators and Participation are zero when validators are not sampled.
+	Validators       int
+	ActiveValidators int
+	Participation    float64
+	TxPoolPending    uint64
+	TxPoolQueued     uint64
+	// Stalled reports that the head is older than the stall threshold.
+	Stalled bool
+}
+
+// HealthHandler is called with every sample.
+type HealthHandler func(s *HealthSample)
+
+// HealthConfig configures a HealthMonitor. Zero values select the defaults.
+type HealthConfig struct {
+	// Gauges receives the sampled values, none by default.
+	Gauges Gauges
+	// PollInterval is the interval between samples in Run, 10s by default.
+	PollInterval time.Duration
+	// StallAfter is the head age from which the chain is considered stalled, 30s by default.
+	StallAfter time.Duration
+	// SkipValidators disables sampling the validator participation, for nodes without the
+	// wormholes validator APIs.
+	SkipValidators bool
+}
+
+// HealthMonitor samples the block interval, gas usage, validator participation and transaction
+// pool depth of a node, and reports chain stalls that would hold back pending settlements.
+type HealthMonitor struct {
+	worm *client.Wormholes
+	cfg  HealthConfig
+
+	mu       sync.Mutex
+	last     *HealthSample
+	handlers []HealthHandler
+	stalls   []HealthHandler
+}
+
+// NewHealthMonitor creates a monitor sampling worm.
+func NewHealthMonitor(worm *client.Wormholes, cfg HealthConfig) *HealthMonitor {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = defaultHealthInterval
+	}
+	if cfg.StallAfter <= 0 {
+		cfg.StallAfter = defaultStallAfter
+	}
+	return &HealthMonitor{worm: worm, cfg: cfg}
+}
+
+// OnSample registers a handler called with every sample.
+func (m *HealthMonitor) OnSample(h HealthHandler) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.handlers = append(m.handlers, h)
+}
+
+// OnStall registers a handler called with the first stalled sample of every stall.
+func (m *HealthMonitor) OnStall(h HealthHandler) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stalls = append(m.stalls, h)
+}
+
+// Last returns the last sample, nil before the first one.
+func (m *HealthMonitor) Last() *HealthSample {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.last
+}
+
+// Run samples the node until ctx is cancelled. Failures to reach the node are logged.
+func (m *HealthMonitor) Run(ctx context.Context) error {
+	ticker := time.NewTicker(m.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := m.Sample(ctx); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			log.Println("health monitor sample err ", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sample takes one sample, sets the gauges and calls the handlers.
+func (m *HealthMonitor) Sample(ctx context.Context) (*HealthSample, error) {
+	head, err := m.worm.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	s := &HealthSample{Time: time.Now(), Head: head}
+	if produced := time.Unix(int64(head.Time), 0); s.Time.After(produced) {
+		s.HeadAge = s.Time.Sub(produced)
+	}
+	s.Stalled = s.HeadAge >= m.cfg.StallAfter
+	if head.Number > 0 {
+		parent, err := m.worm.HeaderByHash(ctx, head.ParentHash)
+		if err != nil {
+			return nil, err
+		}
+		if head.Time > parent.Time {
+			s.BlockInterval = time.Duration(head.Time-parent.Time) * time.Second
+		}
+	}
+	if head.GasLimit > 0 {
+		s.GasUsedRatio = float64(head.GasUsed) / float64(head.GasLimit)
+	}
+	if !m.cfg.SkipValidators {
+		validators, err := m.worm.IterateValidators(ctx, int64(head.Number))
+		if err != nil {
+			return nil, err
+		}
+		if s.Validators, err = count(validators); err != nil {
+			return nil, err
+		}
+		active, err := m.worm.IterateActiveLivePool(ctx, head.Number)
+		if err != nil {
+			return nil, err
+		}
+		if s.ActiveValidators, err = count(active); err != nil {
+			return nil, err
+		}
+		if s.Validators > 0 {
+			s.Participation = float64(s.ActiveValidators) / float64(s.Validators)
+		}
+	}
+	status, err := m.worm.TxPoolStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.TxPoolPending, s.TxPoolQueued = status.Pending, status.Queued
+
+	m.record(s)
+	return s, nil
+}
+
+func (m *HealthMonitor) record(s *HealthSample) {
+	m.mu.Lock()
+	stalled := s.Stalled && (m.last == nil || !m.last.Stalled)
+	m.last = s
+	handlers, stalls := m.handlers, m.stalls
+	m.mu.Unlock()
+
+	if g := m.cfg.Gauges; g != nil {
+		g.SetGauge(GaugeHeadNumber, float64(s.Head.Number))
+		g.SetGauge(GaugeHeadAge, s.HeadAge.Seconds())
+		g.SetGauge(GaugeBlockInterval, s.BlockInterval.Seconds())
+		g.SetGauge(GaugeGasUsedRatio, s.GasUsedRatio)
+		if !m.cfg.SkipValidators {
+			g.SetGauge(GaugeValidators, float64(s.Validators))
+			g.SetGauge(GaugeActiveValidators, float64(s.ActiveValidators))
+			g.SetGauge(GaugeValidatorParticipation, s.Participation)
+		}
+		g.SetGauge(GaugeTxPoolPending, float64(s.TxPoolPending))
+		g.SetGauge(GaugeTxPoolQueued, float64(s.TxPoolQueued))
+		var flag float64
+		if s.Stalled {
+			flag = 1
+		}
+		g.SetGauge(GaugeStalled, flag)
+	}
+	for _, h := range handlers {
+		h(s)
+	}
+	if stalled {
+		for _, h := range stalls {
+			h(s)
+		}
+	}
+}
+
+// count returns the number of entries of it, decoding them one at a time.
+func count[T any](it *client.ListIterator[T]) (int, error) {
+	n := 0
+	for it.Next() {
+		n++
+	}
+	return n, it.Err()
+}
@@ -0,0 +1,205 @@
+package monitor
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/erbieio/erb-client/v2/client"
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// PendingActivity is an unmined transaction touching watched NFTs or accounts.
+type PendingActivity struct {
+	Tx   *types.Transaction
+	From common.Address
+	// Payload is nil for plain ERB transfers.
+	Payload *types2.Transaction
+	// NFTs and Accounts are the watched NFTs and accounts the transaction touches.
+	NFTs     []string
+	Accounts []common.Address
+}
+
+// PendingHandler is called once for every flagged pending transaction.
+type PendingHandler func(activity *PendingActivity)
+
+// MempoolConfig configures a MempoolWatcher. Zero values select the defaults.
+type MempoolConfig struct {
+	// NFTs are the watched NFT addresses.
+	NFTs []string
+	// Accounts are the watched accounts, matched against the sender, the recipient and
+	// the exchangers named in the payload.
+	Accounts []common.Address
+	// PollInterval is the interval between pool polls in Run, 1s by default.
+	PollInterval time.Duration
+}
+
+// MempoolWatcher polls the transaction pool of a node and flags the wormholes transactions
+// touching watched NFTs or accounts, e.g. a competing settlement of a listing about to be matched.
+type MempoolWatcher struct {
+	worm *client.Wormholes
+	cfg  MempoolConfig
+
+	mu       sync.Mutex
+	nfts     map[string]bool
+	accounts map[common.Address]bool
+	seen     map[common.Hash]bool
+	handlers []PendingHandler
+}
+
+// NewMempoolWatcher creates a watcher polling the pool of worm.
+func NewMempoolWatcher(worm *client.Wormholes, cfg MempoolConfig) *MempoolWatcher {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = defaultPollInterval
+	}
+	w := &MempoolWatcher{
+		worm:     worm,
+		cfg:      cfg,
+		nfts:     make(map[string]bool),
+		accounts: make(map[common.Address]bool),
+		seen:     make(map[common.Hash]bool),
+	}
+	for _, nft := range cfg.NFTs {
+		w.WatchNFT(nft)
+	}
+	for _, account := range cfg.Accounts {
+		w.WatchAccount(account)
+	}
+	return w
+}
+
+// WatchNFT adds an NFT to the watched set.
+func (w *MempoolWatcher) WatchNFT(nft string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.nfts[strings.ToLower(nft)] = true
+}
+
+// WatchAccount adds an account to the watched set.
+func (w *MempoolWatcher) WatchAccount(account common.Address) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.accounts[account] = true
+}
+
+// OnPending registers a handler called for every flagged transaction.
+func (w *MempoolWatcher) OnPending(h PendingHandler) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.handlers = append(w.handlers, h)
+}
+
+// Run polls the pool until ctx is cancelled. Failures to reach the node are logged.
+func (w *MempoolWatcher) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := w.Check(ctx); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			log.Println("mempool watcher check err ", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check fetches the pool once and returns the newly flagged transactions after calling the
+// handlers. A transaction is flagged once while it stays in the pool.
+func (w *MempoolWatcher) Check(ctx context.Context) ([]*PendingActivity, error) {
+	content, err := w.worm.TxPoolContent(ctx)
+	if err != nil {
+		return nil, err
+	}
+	w.mu.Lock()
+	seen := make(map[common.Hash]bool, len(w.seen))
+	var flagged []*PendingActivity
+	for from, account := range content {
+		for _, txs := range []map[uint64]*types.Transaction{account.Pending, account.Queued} {
+			for _, tx := range txs {
+				seen[tx.Hash()] = true
+				if w.seen[tx.Hash()] {
+					continue
+				}
+				if activity := w.match(from, tx); activity != nil {
+					flagged = append(flagged, activity)
+				}
+			}
+		}
+	}
+	w.seen = seen
+	handlers := w.handlers
+	w.mu.Unlock()
+
+	for _, activity := range flagged {
+		for _, h := range handlers {
+			h(activity)
+		}
+	}
+	return flagged, nil
+}
+
+// match returns the activity of tx if it touches a watched NFT or account, w.mu must be held.
+func (w *MempoolWatcher) match(from common.Address, tx *types.Transaction) *PendingActivity {
+	activity := &PendingActivity{Tx: tx, From: from}
+	addAccount := func(account common.Address) {
+		if !w.accounts[account] {
+			return
+		}
+		for _, a := range activity.Accounts {
+			if a == account {
+				return
+			}
+		}
+		activity.Accounts = append(activity.Accounts, account)
+	}
+	addNFT := func(nft string) {
+		if nft == "" || !w.nfts[strings.ToLower(nft)] {
+			return
+		}
+		for _, n := range activity.NFTs {
+			if strings.EqualFold(n, nft) {
+				return
+			}
+		}
+		activity.NFTs = append(activity.NFTs, nft)
+	}
+	addExchanger := func(exchanger string) {
+		if common.IsHexAddress(exchanger) {
+			addAccount(common.HexToAddress(exchanger))
+		}
+	}
+
+	addAccount(from)
+	if tx.To() != nil {
+		addAccount(*tx.To())
+	}
+	if payload, ok := client.ParseWormholesData(tx.Data()); ok {
+		activity.Payload = payload
+		addNFT(payload.NFTAddress)
+		addExchanger(payload.Exchanger)
+		if payload.Buyer != nil {
+			addNFT(payload.Buyer.NFTAddress)
+			addExchanger(payload.Buyer.Exchanger)
+		}
+		if payload.Seller1 != nil {
+			addNFT(payload.Seller1.NFTAddress)
+			addExchanger(payload.Seller1.Exchanger)
+		}
+		if payload.Seller2 != nil {
+			addExchanger(payload.Seller2.Exchanger)
+		}
+	}
+	if len(activity.NFTs) == 0 && len(activity.Accounts) == 0 {
+		return nil
+	}
+	return activity
+}
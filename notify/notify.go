@@ -0,0 +1,235 @@
+// Package notify posts webhooks for the chain events observed by a scanner.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/erbieio/erb-client/v2/scanner"
+	"github.com/erbieio/erb-client/v2/tools"
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum/common"
+	"golang.org/x/xerrors"
+)
+
+// Event types.
+const (
+	EventTransaction     = "transaction"
+	EventPaymentReceived = "payment_received"
+	EventNFTTransfer     = "nft_transfer"
+	EventNFTSold         = "nft_sold"
+	EventPledge          = "pledge"
+	EventPledgeRevoked   = "pledge_revoked"
+)
+
+// SignatureHeader carries the hex HMAC-SHA256 of the request body keyed with the webhook secret.
+const SignatureHeader = "X-Erb-Signature"
+
+// Event is the JSON body of a webhook.
+type Event struct {
+	Type       string         `json:"type"`
+	Block      uint64         `json:"block"`
+	TxHash     common.Hash    `json:"tx_hash"`
+	From       common.Address `json:"from"`
+	To         common.Address `json:"to"`
+	NFTAddress string         `json:"nft_address,omitempty"`
+	// Value is the transferred amount in wei as a decimal string.
+	Value string `json:"value"`
+}
+
+// Filter selects the events delivered to a webhook. Empty fields match everything.
+type Filter struct {
+	Types []string
+	// Addresses match the sender or recipient of the event.
+	Addresses []common.Address
+	NFTs      []string
+}
+
+func (f *Filter) match(ev *Event) bool {
+	if len(f.Types) > 0 && !contains(f.Types, ev.Type) {
+		return false
+	}
+	if len(f.Addresses) > 0 {
+		found := false
+		for _, addr := range f.Addresses {
+			if addr == ev.From || addr == ev.To {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(f.NFTs) > 0 {
+		found := false
+		for _, nft := range f.NFTs {
+			if ev.NFTAddress != "" && tools.SameAddress(nft, ev.NFTAddress) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Webhook is an endpoint receiving the events matching its filter.
+type Webhook struct {
+	URL    string
+	Secret string
+	Filter Filter
+}
+
+// Notifier delivers events to webhooks.
+type Notifier struct {
+	hooks   []Webhook
+	client  *http.Client
+	retries int
+}
+
+// New creates a notifier for the given webhooks.
+func New(hooks ...Webhook) *Notifier {
+	return &Notifier{
+		hooks:   hooks,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		retries: 3,
+	}
+}
+
+// Attach registers the notifier on a scanner. A webhook that still fails after the retries
+// stops the scanner, so the block is delivered again once it restarts.
+func (n *Notifier) Attach(s *scanner.Scanner) {
+	s.OnTransaction(func(ctx context.Context, tx *scanner.Transaction) error {
+		for _, ev := range Events(tx) {
+			if err := n.Notify(ctx, ev); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Events derives the events of a scanned transaction.
+func Events(tx *scanner.Transaction) []*Event {
+	base := Event{
+		Type:   EventTransaction,
+		Block:  tx.Block.NumberU64(),
+		TxHash: tx.Tx.Hash(),
+		From:   tx.From,
+		Value:  "0",
+	}
+	if tx.Tx.To() != nil {
+		base.To = *tx.Tx.To()
+	}
+	if v := tx.Tx.Value(); v != nil {
+		base.Value = v.String()
+	}
+	events := []*Event{&base}
+	with := func(typ, nft string) {
+		ev := base
+		ev.Type, ev.NFTAddress = typ, tools.ChecksumAddress(nft)
+		events = append(events, &ev)
+	}
+	if tx.Payload == nil {
+		if tx.Tx.Value() != nil && tx.Tx.Value().Cmp(big.NewInt(0)) > 0 {
+			with(EventPaymentReceived, "")
+		}
+		return events
+	}
+	switch tx.Payload.Type {
+	case types2.Transfer:
+		with(EventNFTTransfer, tx.Payload.NFTAddress)
+	case types2.TokenPledge:
+		with(EventPledge, "")
+	case types2.TokenRevokesPledge:
+		with(EventPledgeRevoked, "")
+	case types2.TransactionNFT, types2.BuyerInitiatingTransaction, types2.FoundryTradeBuyer,
+		types2.FoundryExchange, types2.NftExchangeMatch, types2.FoundryExchangeInitiated,
+		types2.FtDoesNotAuthorizeExchanges, types2.BatchSellTransfer, types2.ForceBuyingTransfer:
+		nft := ""
+		if tx.Payload.Buyer != nil {
+			nft = tx.Payload.Buyer.NFTAddress
+		} else if tx.Payload.Seller1 != nil {
+			nft = tx.Payload.Seller1.NFTAddress
+		}
+		with(EventNFTSold, nft)
+	}
+	return events
+}
+
+// Notify posts ev to every webhook whose filter matches it.
+func (n *Notifier) Notify(ctx context.Context, ev *Event) error {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	for i := range n.hooks {
+		hook := &n.hooks[i]
+		if !hook.Filter.match(ev) {
+			continue
+		}
+		if err := n.post(ctx, hook, body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Sign returns the signature header value of body for secret.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (n *Notifier) post(ctx context.Context, hook *Webhook, body []byte) error {
+	var err error
+	for attempt := 0; attempt <= n.retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Duration(attempt) * time.Second):
+			}
+		}
+		var req *http.Request
+		req, err = http.NewRequestWithContext(ctx, "POST", hook.URL, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if hook.Secret != "" {
+			req.Header.Set(SignatureHeader, Sign(hook.Secret, body))
+		}
+		var resp *http.Response
+		resp, err = n.client.Do(req)
+		if err != nil {
+			continue
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return nil
+		}
+		err = xerrors.Errorf("webhook %s answered %s", hook.URL, resp.Status)
+	}
+	return err
+}
@@ -0,0 +1,134 @@
+// Package pkcs11 signs with secp256k1 keys held by PKCS#11 tokens such as SoftHSM, YubiHSM or
+// network HSMs, so exchanger and validator keys never leave the token. Its Signer is a
+// client.Signer for transactions, orders and authorizations:
+//
+//	signer, err := pkcs11.NewSigner(session)
+//	worm.SetSigner(signer)
+//
+// The package does not link a PKCS#11 library. The Session is implemented over the library of
+// the token, e.g. github.com/miekg/pkcs11, with SignInit using CKM_ECDSA and the private key
+// object followed by Sign, and GetAttributeValue of CKA_EC_POINT of the public key object.
+//
+// Tokens sign with ECDSA as is: the signer turns high S values into low ones, which the chain
+// requires, and finds the recovery id the token does not return.
+package pkcs11
+
+import (
+	"crypto/ecdsa"
+	"encoding/asn1"
+	"math/big"
+	"sync"
+
+	"github.com/erbieio/erb-client/v2/client"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/xerrors"
+)
+
+// Session is a logged in session of a PKCS#11 token holding the key.
+type Session interface {
+	// Sign signs the hash with mechanism CKM_ECDSA and returns the signature, R || S of 64
+	// bytes as PKCS#11 specifies, or DER encoded as some tokens do.
+	Sign(hash []byte) ([]byte, error)
+	// ECPoint returns the CKA_EC_POINT attribute of the public key, the uncompressed point
+	// DER encoded as an OCTET STRING, or the raw point as some tokens return it.
+	ECPoint() ([]byte, error)
+}
+
+var _ client.Signer = (*Signer)(nil)
+
+var (
+	secp256k1N     = crypto.S256().Params().N
+	secp256k1HalfN = new(big.Int).Rsh(secp256k1N, 1)
+)
+
+// Signer signs with the key of a Session. PKCS#11 sessions can not be used concurrently, it
+// signs one hash at a time.
+type Signer struct {
+	mu      sync.Mutex
+	session Session
+	pub     *ecdsa.PublicKey
+	account common.Address
+}
+
+// NewSigner creates a signer of the key of session, reading its public key.
+func NewSigner(session Session) (*Signer, error) {
+	point, err := session.ECPoint()
+	if err != nil {
+		return nil, xerrors.Errorf("failed to read the public key: %w", err)
+	}
+	pub, err := parseECPoint(point)
+	if err != nil {
+		return nil, err
+	}
+	return &Signer{session: session, pub: pub, account: crypto.PubkeyToAddress(*pub)}, nil
+}
+
+// parseECPoint decodes CKA_EC_POINT into a secp256k1 public key.
+func parseECPoint(point []byte) (*ecdsa.PublicKey, error) {
+	if len(point) != 65 {
+		var raw []byte
+		if rest, err := asn1.Unmarshal(point, &raw); err != nil || len(rest) != 0 {
+			return nil, xerrors.Errorf("invalid CKA_EC_POINT %x", point)
+		}
+		point = raw
+	}
+	pub, err := crypto.UnmarshalPubkey(point)
+	if err != nil {
+		return nil, xerrors.Errorf("CKA_EC_POINT is not a secp256k1 key: %w", err)
+	}
+	return pub, nil
+}
+
+// Address returns the account of the key.
+func (s *Signer) Address() common.Address {
+	return s.account
+}
+
+// SignHash signs a 32 byte hash and returns [R || S || V] with a low S and the recovery id V.
+func (s *Signer) SignHash(hash []byte) ([]byte, error) {
+	if len(hash) != common.HashLength {
+		return nil, xerrors.Errorf("hash is %d bytes, want %d", len(hash), common.HashLength)
+	}
+	s.mu.Lock()
+	der, err := s.session.Sign(hash)
+	s.mu.Unlock()
+	if err != nil {
+		return nil, xerrors.Errorf("pkcs11 sign: %w", err)
+	}
+	r, sv, err := parseSignature(der)
+	if err != nil {
+		return nil, err
+	}
+	if sv.Cmp(secp256k1HalfN) > 0 {
+		sv.Sub(secp256k1N, sv)
+	}
+	signature := make([]byte, crypto.SignatureLength)
+	r.FillBytes(signature[:32])
+	sv.FillBytes(signature[32:64])
+	want := crypto.FromECDSAPub(s.pub)
+	for v := byte(0); v < 2; v++ {
+		signature[64] = v
+		if pub, err := crypto.Ecrecover(hash, signature); err == nil && string(pub) == string(want) {
+			return signature, nil
+		}
+	}
+	return nil, xerrors.Errorf("pkcs11 signature %x does not recover the key of %s", signature[:64], s.account.Hex())
+}
+
+// parseSignature decodes R and S of a raw or DER encoded ECDSA signature.
+func parseSignature(sig []byte) (r, s *big.Int, err error) {
+	if len(sig) == 64 {
+		return new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:]), nil
+	}
+	var der struct{ R, S *big.Int }
+	if rest, err := asn1.Unmarshal(sig, &der); err != nil || len(rest) != 0 {
+		return nil, nil, xerrors.Errorf("invalid pkcs11 signature %x", sig)
+	}
+	for _, n := range []*big.Int{der.R, der.S} {
+		if n.Sign() <= 0 || n.Cmp(secp256k1N) >= 0 {
+			return nil, nil, xerrors.Errorf("invalid pkcs11 signature %x", sig)
+		}
+	}
+	return der.R, der.S, nil
+}
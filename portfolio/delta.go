@@ -0,0 +1,142 @@
+package portfolio
+
+import (
+	"context"
+	"strings"
+
+	"github.com/erbieio/erb-client/v2/client"
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	defaultDeltaConcurrency = 4
+	// maxDeltaBlocks is the largest number of blocks scanned for touched accounts, a longer
+	// gap refreshes every account.
+	maxDeltaBlocks = 100
+)
+
+// touchSet holds the addresses whose state the scanned blocks may have changed.
+type touchSet struct {
+	addresses map[common.Address]bool
+	// prefixes are the lowercase hex prefixes of the SNFT groups handled as a whole, e.g.
+	// by merges and exchanges, without 0x.
+	prefixes []string
+}
+
+// add adds a hex address of a payload, shorter hex strings are SNFT prefixes.
+func (s *touchSet) add(hex string) {
+	if common.IsHexAddress(hex) {
+		s.addresses[common.HexToAddress(hex)] = true
+		return
+	}
+	hex = strings.ToLower(strings.TrimPrefix(strings.TrimPrefix(hex, "0x"), "0X"))
+	if len(hex) > 0 && len(hex) < 2*common.AddressLength {
+		s.prefixes = append(s.prefixes, hex)
+	}
+}
+
+// has reports whether the NFT or account at hex may have changed.
+func (s *touchSet) has(hex string) bool {
+	if common.IsHexAddress(hex) && s.addresses[common.HexToAddress(hex)] {
+		return true
+	}
+	hex = strings.ToLower(strings.TrimPrefix(strings.TrimPrefix(hex, "0x"), "0X"))
+	for _, prefix := range s.prefixes {
+		if strings.HasPrefix(hex, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// touched returns the addresses touched by the blocks from..to (inclusive).
+func (t *Tracker) touched(ctx context.Context, from, to uint64) (*touchSet, error) {
+	results, err := t.worm.GetBlocks(ctx, t.cfg.Pool, from, to)
+	if err != nil {
+		return nil, err
+	}
+	set := &touchSet{addresses: make(map[common.Address]bool)}
+	for _, result := range results {
+		block := result.Block
+		set.addresses[block.Coinbase()] = true
+		for _, tx := range block.Transactions() {
+			if sender, err := client.TransactionSender(block, tx); err == nil {
+				set.addresses[sender] = true
+			}
+			if tx.To() != nil {
+				set.addresses[*tx.To()] = true
+			}
+			if payload, ok := client.ParseWormholesData(tx.Data()); ok {
+				set.addPayload(payload)
+			}
+		}
+	}
+	beneficiaries := make([]*types2.BeneficiaryAddressList, len(results))
+	errs := t.cfg.Pool.Run(ctx, len(results), func(ctx context.Context, i int) (err error) {
+		beneficiaries[i], err = t.worm.GetBlockBeneficiaryAddressByNumber(ctx, int64(results[i].Number))
+		return err
+	})
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, list := range beneficiaries {
+		for _, beneficiary := range *list {
+			set.addresses[beneficiary.Address] = true
+			set.addresses[beneficiary.NftAddress] = true
+		}
+	}
+	return set, nil
+}
+
+// addPayload adds the accounts and NFTs named in a wormholes transaction.
+func (s *touchSet) addPayload(payload *types2.Transaction) {
+	hexes := []string{payload.NFTAddress, payload.Exchanger, payload.Creator, payload.ProxyAddress}
+	if buyer := payload.Buyer; buyer != nil {
+		hexes = append(hexes, buyer.NFTAddress, buyer.Exchanger, buyer.Seller)
+	}
+	if seller := payload.Seller1; seller != nil {
+		hexes = append(hexes, seller.NFTAddress, seller.Exchanger)
+	}
+	if seller := payload.Seller2; seller != nil {
+		hexes = append(hexes, seller.Exchanger)
+	}
+	if auth := payload.ExchangerAuth; auth != nil {
+		hexes = append(hexes, auth.ExchangerOwner, auth.To)
+	}
+	for _, hex := range hexes {
+		if hex != "" {
+			s.add(hex)
+		}
+	}
+}
+
+// changed reports whether account, of the last snapshot, was touched directly or through one
+// of its NFTs, owned or listed as candidates.
+func (t *Tracker) changed(ctx context.Context, touched *touchSet, account *Account) (bool, error) {
+	if touched.addresses[account.Address] {
+		return true, nil
+	}
+	for _, nfts := range [][]*NFT{account.NFTs, account.SNFTs} {
+		for _, nft := range nfts {
+			if touched.has(nft.Address) {
+				return true, nil
+			}
+		}
+	}
+	if t.cfg.NFTs == nil {
+		return false, nil
+	}
+	candidates, err := t.cfg.NFTs(ctx, account.Address)
+	if err != nil {
+		return false, err
+	}
+	for _, candidate := range candidates {
+		if touched.has(candidate) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
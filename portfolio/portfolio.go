@@ -0,0 +1,293 @@
+// Package portfolio aggregates the holdings of a set of addresses into one snapshot:
+// ERB balances, pledges, owned NFTs and SNFTs, authorizations and pending transactions.
+package portfolio
+
+import (
+	"context"
+	"log"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/erbieio/erb-client/v2/client"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const defaultPollInterval = 5 * time.Second
+
+// NFTLister returns the addresses of the NFTs and SNFTs that may be owned by owner, e.g. from
+// the store of an indexer. The chain has no such query, every listed NFT is checked against
+// its current owner, so stale entries are harmless.
+type NFTLister func(ctx context.Context, owner common.Address) ([]string, error)
+
+// UpdateHandler is called with every new snapshot taken by Run.
+type UpdateHandler func(snapshot *Snapshot)
+
+// Config configures a Tracker. Zero values select the defaults.
+type Config struct {
+	// Addresses are the tracked accounts.
+	Addresses []common.Address
+	// NFTs lists candidate NFTs per account, without it NFTs and SNFTs are not reported.
+	NFTs NFTLister
+	// PollInterval is the wait between polls for a new block in Run, 5s by default.
+	PollInterval time.Duration
+	// Incremental refreshes only the accounts touched by the blocks since the last snapshot:
+	// the senders and recipients of their transactions, the addresses and NFTs in wormholes
+	// payloads, the miners and the reward beneficiaries. An account is touched as well when
+	// one of its NFTs is. The other accounts are the ones of the last snapshot, including
+	// their pending transactions. Balance changes by contract calls are not seen. After more
+	// than 100 blocks, or without a last snapshot, every account is refreshed.
+	Incremental bool
+	// Pool runs the block requests of Incremental refreshes, 4 parallel requests by default.
+	Pool *client.FetchPool
+}
+
+// Snapshot is the state of the tracked accounts at a block.
+type Snapshot struct {
+	Block    uint64
+	Time     time.Time
+	Accounts []*Account
+	// TotalBalance and TotalPledged sum up the accounts, in wei.
+	TotalBalance *big.Int
+	TotalPledged *big.Int
+}
+
+// Account is the state of a single tracked account.
+type Account struct {
+	Address common.Address
+	Balance *big.Int
+	// Pledged is the ERB pledged by the account, in wei.
+	Pledged   *big.Int
+	Exchanger bool
+	// NFTCount is the number of NFTs the chain reports for the account.
+	NFTCount uint64
+	NFTs     []*NFT
+	SNFTs    []*NFT
+	// Approved are the addresses authorized to handle all NFTs of the account.
+	Approved []common.Address
+	// Pending are the transactions of the account in the node's pool, by nonce.
+	Pending []*types.Transaction
+}
+
+// NFT is an NFT or SNFT owned by a tracked account.
+type NFT struct {
+	Address    string
+	MetaURL    string
+	MergeLevel uint8
+	// Approved is the address authorized to handle this NFT, zero if there is none.
+	Approved common.Address
+}
+
+// Tracker takes snapshots of the holdings of the configured addresses.
+type Tracker struct {
+	worm *client.Wormholes
+	cfg  Config
+
+	mu       sync.Mutex
+	last     *Snapshot
+	handlers []UpdateHandler
+}
+
+// New creates a tracker reading from worm.
+func New(worm *client.Wormholes, cfg Config) *Tracker {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = defaultPollInterval
+	}
+	if cfg.Pool == nil {
+		cfg.Pool = client.NewFetchPool(client.FetchConfig{MinConcurrency: defaultDeltaConcurrency, MaxConcurrency: defaultDeltaConcurrency})
+	}
+	return &Tracker{worm: worm, cfg: cfg}
+}
+
+// OnUpdate registers a handler called with every snapshot taken by Run.
+func (t *Tracker) OnUpdate(h UpdateHandler) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.handlers = append(t.handlers, h)
+}
+
+// Snapshot returns the last snapshot taken, nil before the first refresh.
+func (t *Tracker) Snapshot() *Snapshot {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.last
+}
+
+// Refresh takes a snapshot at the latest block, see Config.Incremental.
+func (t *Tracker) Refresh(ctx context.Context) (*Snapshot, error) {
+	number, err := t.worm.BlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+	snapshot, err := t.snapshot(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	t.mu.Lock()
+	t.last = snapshot
+	t.mu.Unlock()
+	return snapshot, nil
+}
+
+// Run refreshes the snapshot on every new block until ctx is cancelled. Failures are
+// logged and retried on the next poll.
+func (t *Tracker) Run(ctx context.Context) error {
+	ticker := time.NewTicker(t.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		if err := t.poll(ctx); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			log.Println("portfolio refresh err ", err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (t *Tracker) poll(ctx context.Context) error {
+	number, err := t.worm.BlockNumber(ctx)
+	if err != nil {
+		return err
+	}
+	if last := t.Snapshot(); last != nil && last.Block == number {
+		return nil
+	}
+	snapshot, err := t.snapshot(ctx, number)
+	if err != nil {
+		return err
+	}
+	t.mu.Lock()
+	t.last = snapshot
+	handlers := t.handlers
+	t.mu.Unlock()
+	for _, h := range handlers {
+		h(snapshot)
+	}
+	return nil
+}
+
+// snapshot takes a snapshot at block number, only refreshing the touched accounts of the last
+// snapshot in Incremental mode.
+func (t *Tracker) snapshot(ctx context.Context, number uint64) (*Snapshot, error) {
+	last := t.Snapshot()
+	if !t.cfg.Incremental || last == nil || number <= last.Block || number-last.Block > maxDeltaBlocks ||
+		len(last.Accounts) != len(t.cfg.Addresses) {
+		return t.take(ctx, number, nil, nil)
+	}
+	touched, err := t.touched(ctx, last.Block+1, number)
+	if err != nil {
+		return nil, err
+	}
+	return t.take(ctx, number, last, touched)
+}
+
+// take takes a snapshot at block number. With a last snapshot, the accounts not touched since
+// are kept from it.
+func (t *Tracker) take(ctx context.Context, number uint64, last *Snapshot, touched *touchSet) (*Snapshot, error) {
+	snapshot := &Snapshot{
+		Block:        number,
+		Time:         time.Now(),
+		TotalBalance: new(big.Int),
+		TotalPledged: new(big.Int),
+	}
+	for i, address := range t.cfg.Addresses {
+		var account *Account
+		if last != nil {
+			changed, err := t.changed(ctx, touched, last.Accounts[i])
+			if err != nil {
+				return nil, err
+			}
+			if !changed {
+				account = last.Accounts[i]
+			}
+		}
+		if account == nil {
+			var err error
+			if account, err = t.account(ctx, address, number); err != nil {
+				return nil, err
+			}
+		}
+		snapshot.Accounts = append(snapshot.Accounts, account)
+		snapshot.TotalBalance.Add(snapshot.TotalBalance, account.Balance)
+		snapshot.TotalPledged.Add(snapshot.TotalPledged, account.Pledged)
+	}
+	return snapshot, nil
+}
+
+func (t *Tracker) account(ctx context.Context, address common.Address, number uint64) (*Account, error) {
+	info, err := t.worm.GetAccountInfo(ctx, address.Hex(), int64(number))
+	if err != nil {
+		return nil, err
+	}
+	account := &Account{
+		Address: address,
+		Balance: new(big.Int),
+		Pledged: new(big.Int),
+	}
+	if info.Balance != nil {
+		account.Balance.Set(info.Balance)
+	}
+	if worm := info.Worm; worm != nil {
+		if worm.PledgedBalance != nil {
+			account.Pledged.Set(worm.PledgedBalance)
+		}
+		account.Exchanger = worm.ExchangerFlag
+		account.NFTCount = worm.NFTBalance
+		account.Approved = worm.ApproveAddressList
+	}
+
+	if t.cfg.NFTs != nil {
+		candidates, err := t.cfg.NFTs(ctx, address)
+		if err != nil {
+			return nil, err
+		}
+		for _, candidate := range candidates {
+			nft, err := t.worm.GetAccountInfo(ctx, candidate, int64(number))
+			if err != nil {
+				return nil, err
+			}
+			if nft.Nft.Owner != address {
+				continue
+			}
+			owned := &NFT{
+				Address:    candidate,
+				MetaURL:    nft.Nft.MetaURL,
+				MergeLevel: nft.Nft.MergeLevel,
+				Approved:   nft.Nft.NFTApproveAddressList,
+			}
+			if isSNFT(candidate) {
+				account.SNFTs = append(account.SNFTs, owned)
+			} else {
+				account.NFTs = append(account.NFTs, owned)
+			}
+		}
+	}
+
+	content, err := t.worm.TxPoolContentFrom(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	nonces := make([]uint64, 0, len(content.Pending))
+	for nonce := range content.Pending {
+		nonces = append(nonces, nonce)
+	}
+	sort.Slice(nonces, func(i, j int) bool { return nonces[i] < nonces[j] })
+	for _, nonce := range nonces {
+		account.Pending = append(account.Pending, content.Pending[nonce])
+	}
+	return account, nil
+}
+
+// isSNFT reports whether address is in the SNFT address range, which starts at 0x8000...
+func isSNFT(address string) bool {
+	hex := strings.TrimPrefix(strings.TrimPrefix(address, "0x"), "0X")
+	return len(hex) > 0 && hex[0] == '8'
+}
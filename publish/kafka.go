@@ -0,0 +1,119 @@
+package publish
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// KafkaConfig configures a Kafka broker.
+type KafkaConfig struct {
+	// URL is the base URL of a Kafka REST Proxy, e.g. http://localhost:8082. The proxy must
+	// produce with acks=all for the messages to survive the loss of a Kafka broker.
+	URL string
+	// Header is added to the requests, e.g. for the authorization of the proxy.
+	Header http.Header
+	// Client sends the requests, an http.Client with a 30s timeout by default.
+	Client *http.Client
+}
+
+// Kafka publishes to Kafka topics through the v2 API of a Kafka REST Proxy, the messages as
+// JSON records keyed with their Key. Applications producing with a Kafka client library
+// implement Broker with a synchronous producer instead.
+type Kafka struct {
+	url    string
+	header http.Header
+	client *http.Client
+}
+
+// NewKafka creates a Kafka broker.
+func NewKafka(cfg KafkaConfig) *Kafka {
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &Kafka{url: strings.TrimRight(cfg.URL, "/"), header: cfg.Header, client: cfg.Client}
+}
+
+type kafkaRecord struct {
+	Key   string          `json:"key"`
+	Value json.RawMessage `json:"value"`
+}
+
+type kafkaOffsets struct {
+	Offsets []struct {
+		Partition int     `json:"partition"`
+		Offset    int64   `json:"offset"`
+		ErrorCode *int    `json:"error_code"`
+		Error     *string `json:"error"`
+	} `json:"offsets"`
+}
+
+// Publish produces the messages of every topic in one request.
+func (k *Kafka) Publish(ctx context.Context, msgs []Message) error {
+	var topics []string
+	records := make(map[string][]kafkaRecord)
+	for _, m := range msgs {
+		if _, ok := records[m.Topic]; !ok {
+			topics = append(topics, m.Topic)
+		}
+		records[m.Topic] = append(records[m.Topic], kafkaRecord{Key: m.Key, Value: m.Value})
+	}
+	for _, topic := range topics {
+		if err := k.produce(ctx, topic, records[topic]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (k *Kafka) produce(ctx context.Context, topic string, records []kafkaRecord) error {
+	body, err := json.Marshal(map[string]interface{}{"records": records})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, k.url+"/topics/"+url.PathEscape(topic), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for name, values := range k.header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Content-Type", "application/vnd.kafka.json.v2+json")
+	req.Header.Set("Accept", "application/vnd.kafka.v2+json")
+	resp, err := k.client.Do(req)
+	if err != nil {
+		return xerrors.Errorf("produce to kafka topic %s: %w", topic, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return xerrors.Errorf("produce to kafka topic %s: %w", topic, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return xerrors.Errorf("produce to kafka topic %s: %s: %s", topic, resp.Status, strings.TrimSpace(string(data)))
+	}
+	var offsets kafkaOffsets
+	if err := json.Unmarshal(data, &offsets); err != nil {
+		return xerrors.Errorf("produce to kafka topic %s: invalid response: %w", topic, err)
+	}
+	if len(offsets.Offsets) != len(records) {
+		return xerrors.Errorf("produce to kafka topic %s: %d offsets for %d records", topic, len(offsets.Offsets), len(records))
+	}
+	for i, o := range offsets.Offsets {
+		if o.ErrorCode != nil || o.Error != nil {
+			msg := ""
+			if o.Error != nil {
+				msg = *o.Error
+			}
+			return xerrors.Errorf("produce to kafka topic %s: record %d failed: %s", topic, i, msg)
+		}
+	}
+	return nil
+}
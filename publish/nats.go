@@ -0,0 +1,320 @@
+package publish
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// NATSConfig configures a NATS broker.
+type NATSConfig struct {
+	// URL is the server, nats://host:4222 or tls://host:4222, with the user and password of the
+	// connection if any.
+	URL string
+	// Token authenticates the connection instead of a user and password.
+	Token string
+	// Timeout bounds the connection and the acknowledgements of a publication without a
+	// deadline, 10s by default.
+	Timeout time.Duration
+}
+
+// NATS publishes to the subjects of NATS JetStream streams, the topics being the subjects.
+// Every subject must be bound to a stream, JetStream acknowledges the messages once the
+// stream stored them and drops the duplicates by ID within the duplicate window of the stream.
+// Core NATS subjects without a stream do not acknowledge and fail the publication.
+type NATS struct {
+	cfg NATSConfig
+
+	mu    sync.Mutex
+	conn  net.Conn
+	r     *bufio.Reader
+	inbox string
+	seq   uint64
+}
+
+// NewNATS creates a NATS broker, it connects on the first publication.
+func NewNATS(cfg NATSConfig) *NATS {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	return &NATS{cfg: cfg}
+}
+
+// Close closes the connection.
+func (n *NATS) Close() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.disconnect()
+}
+
+func (n *NATS) disconnect() error {
+	if n.conn == nil {
+		return nil
+	}
+	err := n.conn.Close()
+	n.conn, n.r = nil, nil
+	return err
+}
+
+// Publish publishes the messages and waits for the acknowledgements of JetStream. A failed
+// publication closes the connection, the next one reconnects.
+func (n *NATS) Publish(ctx context.Context, msgs []Message) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if err := n.publish(ctx, msgs); err != nil {
+		n.disconnect()
+		return err
+	}
+	return nil
+}
+
+func (n *NATS) publish(ctx context.Context, msgs []Message) error {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(n.cfg.Timeout)
+	}
+	if n.conn == nil {
+		if err := n.connect(ctx, deadline); err != nil {
+			return err
+		}
+	}
+	if err := n.conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+	// Cancelling ctx interrupts the blocked reads and writes.
+	stop := make(chan struct{})
+	defer close(stop)
+	go func(conn net.Conn) {
+		select {
+		case <-ctx.Done():
+			conn.SetDeadline(time.Unix(1, 0))
+		case <-stop:
+		}
+	}(n.conn)
+
+	var buf strings.Builder
+	pending := make(map[string]string, len(msgs))
+	for _, m := range msgs {
+		if !isSubject(m.Topic) {
+			return xerrors.Errorf("invalid NATS subject %q", m.Topic)
+		}
+		n.seq++
+		reply := n.inbox + "." + strconv.FormatUint(n.seq, 10)
+		pending[reply] = m.Topic
+		header := "NATS/1.0\r\n"
+		if m.ID != "" {
+			header += "Nats-Msg-Id: " + m.ID + "\r\n"
+		}
+		header += "\r\n"
+		fmt.Fprintf(&buf, "HPUB %s %s %d %d\r\n%s%s\r\n", m.Topic, reply, len(header), len(header)+len(m.Value), header, m.Value)
+	}
+	if _, err := io.WriteString(n.conn, buf.String()); err != nil {
+		return n.wrap(ctx, err)
+	}
+	for len(pending) > 0 {
+		subject, header, payload, err := n.next()
+		if err != nil {
+			return n.wrap(ctx, err)
+		}
+		topic, ok := pending[subject]
+		if !ok {
+			// The acknowledgement of an earlier failed publication.
+			continue
+		}
+		delete(pending, subject)
+		if strings.HasPrefix(header, "NATS/1.0 503") {
+			return xerrors.Errorf("publish to NATS subject %s: no stream bound to the subject", topic)
+		}
+		var ack struct {
+			Stream string `json:"stream"`
+			Error  *struct {
+				Code        int    `json:"code"`
+				Description string `json:"description"`
+			} `json:"error"`
+		}
+		if err := json.Unmarshal(payload, &ack); err != nil {
+			return xerrors.Errorf("publish to NATS subject %s: invalid acknowledgement %q", topic, payload)
+		}
+		if ack.Error != nil {
+			return xerrors.Errorf("publish to NATS subject %s: %d %s", topic, ack.Error.Code, ack.Error.Description)
+		}
+		if ack.Stream == "" {
+			return xerrors.Errorf("publish to NATS subject %s: acknowledgement without a stream", topic)
+		}
+	}
+	return nil
+}
+
+// wrap returns the error of ctx rather than the timeout it caused.
+func (n *NATS) wrap(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return xerrors.Errorf("nats: %w", err)
+}
+
+// connect connects, authenticates and subscribes to the inbox of the acknowledgements.
+func (n *NATS) connect(ctx context.Context, deadline time.Time) error {
+	u, err := url.Parse(n.cfg.URL)
+	if err != nil || u.Host == "" {
+		return xerrors.Errorf("invalid NATS URL %q", n.cfg.URL)
+	}
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), "4222")
+	}
+	dialer := net.Dialer{Deadline: deadline}
+	conn, err := dialer.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return xerrors.Errorf("connect to NATS %s: %w", host, err)
+	}
+	switch u.Scheme {
+	case "nats":
+	case "tls":
+		conn = tls.Client(conn, &tls.Config{ServerName: u.Hostname()})
+	default:
+		conn.Close()
+		return xerrors.Errorf("unsupported NATS URL scheme %s", u.Scheme)
+	}
+	n.conn, n.r = conn, bufio.NewReader(conn)
+	if err := conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+	line, err := n.readLine()
+	if err != nil {
+		return xerrors.Errorf("connect to NATS %s: %w", host, err)
+	}
+	if !strings.HasPrefix(line, "INFO ") {
+		return xerrors.Errorf("connect to NATS %s: unexpected %q", host, line)
+	}
+	options := map[string]interface{}{
+		"verbose":  false,
+		"pedantic": false,
+		"headers":  true,
+		"lang":     "go",
+		"name":     "erb-client",
+		"protocol": 1,
+	}
+	if u.User != nil {
+		options["user"] = u.User.Username()
+		options["pass"], _ = u.User.Password()
+	}
+	if n.cfg.Token != "" {
+		options["auth_token"] = n.cfg.Token
+	}
+	connect, err := json.Marshal(options)
+	if err != nil {
+		return err
+	}
+	var id [8]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return err
+	}
+	n.inbox = "_INBOX." + hex.EncodeToString(id[:])
+	if _, err := fmt.Fprintf(conn, "CONNECT %s\r\nPING\r\nSUB %s.* 1\r\n", connect, n.inbox); err != nil {
+		return xerrors.Errorf("connect to NATS %s: %w", host, err)
+	}
+	for {
+		line, err := n.readLine()
+		if err != nil {
+			return xerrors.Errorf("connect to NATS %s: %w", host, err)
+		}
+		switch {
+		case line == "PONG":
+			return nil
+		case strings.HasPrefix(line, "-ERR"):
+			return xerrors.Errorf("connect to NATS %s: %s", host, strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		}
+	}
+}
+
+// next returns the next message of the subscription, answering the pings of the server.
+func (n *NATS) next() (subject, header string, payload []byte, err error) {
+	for {
+		line, err := n.readLine()
+		if err != nil {
+			return "", "", nil, err
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "PING":
+			if _, err := io.WriteString(n.conn, "PONG\r\n"); err != nil {
+				return "", "", nil, err
+			}
+		case "-ERR":
+			return "", "", nil, xerrors.Errorf("server error %s", strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		case "MSG":
+			// MSG <subject> <sid> [reply] <size>
+			if len(fields) < 4 {
+				return "", "", nil, xerrors.Errorf("invalid message %q", line)
+			}
+			size, err := strconv.Atoi(fields[len(fields)-1])
+			if err != nil {
+				return "", "", nil, xerrors.Errorf("invalid message %q", line)
+			}
+			data, err := n.read(size)
+			return fields[1], "", data, err
+		case "HMSG":
+			// HMSG <subject> <sid> [reply] <header size> <total size>
+			if len(fields) < 5 {
+				return "", "", nil, xerrors.Errorf("invalid message %q", line)
+			}
+			headerSize, err1 := strconv.Atoi(fields[len(fields)-2])
+			size, err2 := strconv.Atoi(fields[len(fields)-1])
+			if err1 != nil || err2 != nil || headerSize > size {
+				return "", "", nil, xerrors.Errorf("invalid message %q", line)
+			}
+			data, err := n.read(size)
+			if err != nil {
+				return "", "", nil, err
+			}
+			return fields[1], string(data[:headerSize]), data[headerSize:], nil
+		}
+	}
+}
+
+// read reads a payload of size bytes and its line end.
+func (n *NATS) read(size int) ([]byte, error) {
+	data := make([]byte, size+2)
+	if _, err := io.ReadFull(n.r, data); err != nil {
+		return nil, err
+	}
+	return data[:size], nil
+}
+
+func (n *NATS) readLine() (string, error) {
+	line, err := n.r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// isSubject tells if s is a subject to publish to, tokens separated by dots without wildcards.
+func isSubject(s string) bool {
+	if s == "" || strings.ContainsAny(s, " \t\r\n*>") {
+		return false
+	}
+	for _, token := range strings.Split(s, ".") {
+		if token == "" {
+			return false
+		}
+	}
+	return true
+}
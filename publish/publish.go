@@ -0,0 +1,237 @@
+// Package publish pushes the events decoded by a scanner, blocks, trades, NFT transfers and
+// pledges, to the topics of a message broker such as Kafka or NATS, so consumers follow the
+// chain without a node.
+//
+// Delivery is at least once: the events of a block are published when the scanner finished
+// the block and before it checkpoints it, and a block whose events are not acknowledged by the
+// broker stops the scanner, so it is published again after a restart. Consumers drop the
+// duplicates by the ID of the events, which is the same for every delivery; NATS JetStream
+// drops them itself within its duplicate window.
+package publish
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/erbieio/erb-client/v2/scanner"
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Event types.
+const (
+	EventBlock         = "block"
+	EventTrade         = "trade"
+	EventNFTTransfer   = "nft_transfer"
+	EventPledge        = "pledge"
+	EventPledgeRevoked = "pledge_revoked"
+)
+
+// Event is the JSON value of a message.
+type Event struct {
+	// ID identifies the event across deliveries, the block hash for blocks and the transaction
+	// hash and the type for the others.
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	Block     uint64      `json:"block"`
+	BlockHash common.Hash `json:"block_hash"`
+	Time      uint64      `json:"time"`
+	// Transactions is the number of transactions of a block.
+	Transactions int `json:"transactions,omitempty"`
+
+	TxHash *common.Hash `json:"tx_hash,omitempty"`
+	// TxType is the name of the wormholes transaction type.
+	TxType     string          `json:"tx_type,omitempty"`
+	From       *common.Address `json:"from,omitempty"`
+	To         *common.Address `json:"to,omitempty"`
+	Buyer      *common.Address `json:"buyer,omitempty"`
+	NFTAddress string          `json:"nft_address,omitempty"`
+	Exchanger  string          `json:"exchanger,omitempty"`
+	// Value is the price of a trade or the pledged amount, in wei as a decimal string.
+	Value string `json:"value,omitempty"`
+}
+
+// Message is an event to publish.
+type Message struct {
+	Topic string
+	// Key partitions the messages, the NFT of trades and transfers, the sender of pledges and the
+	// block number of blocks, so the events of an NFT or an account stay in order.
+	Key string
+	// ID is the ID of the event, for brokers that drop duplicates.
+	ID    string
+	Value []byte
+}
+
+// Broker delivers messages to topics.
+type Broker interface {
+	// Publish returns once the broker acknowledged every message, in order of msgs within a
+	// topic. An error may leave some of them published.
+	Publish(ctx context.Context, msgs []Message) error
+}
+
+const (
+	defaultPrefix        = "erb."
+	defaultRetries       = 3
+	defaultRetryInterval = time.Second
+)
+
+// Config configures a Publisher. Zero values select the defaults.
+type Config struct {
+	// Prefix is prepended to the event type to name the topic, "erb." by default, e.g. the
+	// trades are published to erb.trade.
+	Prefix string
+	// Types are the event types published, all by default.
+	Types []string
+	// Retries is the number of retries of a failed publication before the scanner stops, 3 by
+	// default.
+	Retries int
+	// RetryInterval is the wait before the first retry, growing with every retry, 1s by default.
+	RetryInterval time.Duration
+}
+
+// Publisher publishes the events of a scanner to a broker.
+type Publisher struct {
+	broker  Broker
+	cfg     Config
+	types   map[string]bool
+	pending []Message
+	err     error
+}
+
+// New creates a publisher delivering to broker.
+func New(broker Broker, cfg Config) *Publisher {
+	if cfg.Prefix == "" {
+		cfg.Prefix = defaultPrefix
+	}
+	if cfg.Retries <= 0 {
+		cfg.Retries = defaultRetries
+	}
+	if cfg.RetryInterval <= 0 {
+		cfg.RetryInterval = defaultRetryInterval
+	}
+	p := &Publisher{broker: broker, cfg: cfg}
+	if len(cfg.Types) > 0 {
+		p.types = make(map[string]bool, len(cfg.Types))
+		for _, t := range cfg.Types {
+			p.types[t] = true
+		}
+	}
+	return p
+}
+
+// Topic returns the topic of an event type.
+func (p *Publisher) Topic(eventType string) string {
+	return p.cfg.Prefix + eventType
+}
+
+// Attach registers the publisher on a scanner, it publishes the events of every block before
+// the block is checkpointed. A publisher is attached to a single scanner.
+func (p *Publisher) Attach(s *scanner.Scanner) {
+	s.OnBlock(func(ctx context.Context, block *types.Block) error {
+		p.pending, p.err = p.pending[:0], nil
+		p.add(&Event{
+			ID:           block.Hash().Hex(),
+			Type:         EventBlock,
+			Transactions: len(block.Transactions()),
+		}, block, strconv.FormatUint(block.NumberU64(), 10))
+		return nil
+	})
+	s.OnTransaction(func(ctx context.Context, tx *scanner.Transaction) error {
+		if tx.Payload == nil {
+			return nil
+		}
+		eventType := ""
+		switch tx.Payload.Type {
+		case types2.TokenPledge, types2.AdditionalPledgeAmount:
+			eventType = EventPledge
+		case types2.TokenRevokesPledge, types2.RevokesPledgeAmount:
+			eventType = EventPledgeRevoked
+		default:
+			return nil
+		}
+		ev := txEvent(tx, eventType)
+		ev.Value = tx.Tx.Value().String()
+		p.add(ev, tx.Block, tx.From.Hex())
+		return nil
+	})
+	s.OnNFTTransfer(func(ctx context.Context, transfer *scanner.NFTTransfer) error {
+		ev := txEvent(transfer.Transaction, EventNFTTransfer)
+		ev.NFTAddress = transfer.NFTAddress
+		p.add(ev, transfer.Transaction.Block, transfer.NFTAddress)
+		return nil
+	})
+	s.OnTrade(func(ctx context.Context, trade *scanner.Trade) error {
+		ev := txEvent(trade.Transaction, EventTrade)
+		buyer := trade.Buyer
+		ev.Buyer, ev.NFTAddress, ev.Exchanger = &buyer, trade.NFTAddress, trade.Exchanger
+		if trade.Price != nil {
+			ev.Value = trade.Price.String()
+		}
+		key := trade.NFTAddress
+		if key == "" {
+			key = trade.Transaction.Tx.Hash().Hex()
+		}
+		p.add(ev, trade.Transaction.Block, key)
+		return nil
+	})
+	s.OnBlockDone(func(ctx context.Context, block *types.Block) error {
+		if p.err != nil {
+			return p.err
+		}
+		return p.Publish(ctx, p.pending)
+	})
+}
+
+// txEvent returns the event of a transaction.
+func txEvent(tx *scanner.Transaction, eventType string) *Event {
+	hash, from := tx.Tx.Hash(), tx.From
+	ev := &Event{
+		ID:     hash.Hex() + ":" + eventType,
+		Type:   eventType,
+		TxHash: &hash,
+		TxType: types2.TypeName(tx.Payload.Type),
+		From:   &from,
+	}
+	if to := tx.Tx.To(); to != nil {
+		ev.To = to
+	}
+	return ev
+}
+
+// add queues the message of ev, an event of block.
+func (p *Publisher) add(ev *Event, block *types.Block, key string) {
+	if p.types != nil && !p.types[ev.Type] {
+		return
+	}
+	ev.Block, ev.BlockHash, ev.Time = block.NumberU64(), block.Hash(), block.Time()
+	value, err := json.Marshal(ev)
+	if err != nil {
+		p.err = err
+		return
+	}
+	p.pending = append(p.pending, Message{Topic: p.Topic(ev.Type), Key: key, ID: ev.ID, Value: value})
+}
+
+// Publish publishes msgs, retrying a failed publication with the same messages.
+func (p *Publisher) Publish(ctx context.Context, msgs []Message) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+	var err error
+	for attempt := 0; attempt <= p.cfg.Retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Duration(attempt) * p.cfg.RetryInterval):
+			}
+		}
+		if err = p.broker.Publish(ctx, msgs); err == nil {
+			return nil
+		}
+	}
+	return err
+}
@@ -0,0 +1,310 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/erbieio/erb-client/v2/client"
+	"github.com/erbieio/erb-client/v2/tools"
+	"golang.org/x/xerrors"
+)
+
+const (
+	defaultUnitSize   = 1000
+	defaultRetries    = 3
+	defaultRetryDelay = 2 * time.Second
+)
+
+// Unit is a work unit of a backfill, the blocks From..To (inclusive).
+type Unit struct {
+	From uint64 `json:"from"`
+	To   uint64 `json:"to"`
+}
+
+// UnitHandler processes the blocks of a unit reading from worm. Units are processed concurrently
+// and out of order, and a unit interrupted by a restart is processed again, so the handler must
+// be idempotent.
+type UnitHandler func(ctx context.Context, worm *client.Wormholes, unit Unit) error
+
+// ScanUnits returns a UnitHandler that scans every unit with a new Scanner, on which configure
+// registers the handlers. The blocks of a unit are delivered in order.
+func ScanUnits(configure func(s *Scanner)) UnitHandler {
+	return func(ctx context.Context, worm *client.Wormholes, unit Unit) error {
+		s := New(worm, Config{Start: unit.From})
+		configure(s)
+		return s.ScanRange(ctx, unit.From, unit.To)
+	}
+}
+
+// BackfillState persists the completed units of a backfill.
+type BackfillState interface {
+	// Load returns the completed units of the backfill of from..to with the given unit size.
+	Load(ctx context.Context, from, to, unitSize uint64) ([]Unit, error)
+	// Done records a completed unit.
+	Done(ctx context.Context, unit Unit) error
+}
+
+// MemoryBackfillState keeps the completed units in memory only.
+type MemoryBackfillState struct {
+	mu    sync.Mutex
+	units []Unit
+}
+
+func (s *MemoryBackfillState) Load(ctx context.Context, from, to, unitSize uint64) ([]Unit, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Unit(nil), s.units...), nil
+}
+
+func (s *MemoryBackfillState) Done(ctx context.Context, unit Unit) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.units = append(s.units, unit)
+	return nil
+}
+
+// FileBackfillState keeps the completed units as JSON in a file, together with the range and
+// unit size they belong to. The file is replaced atomically after every unit.
+type FileBackfillState struct {
+	Path string
+
+	mu    sync.Mutex
+	state fileBackfill
+}
+
+type fileBackfill struct {
+	From     uint64 `json:"from"`
+	To       uint64 `json:"to"`
+	UnitSize uint64 `json:"unit_size"`
+	Done     []Unit `json:"done"`
+}
+
+func (s *FileBackfillState) Load(ctx context.Context, from, to, unitSize uint64) ([]Unit, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state = fileBackfill{From: from, To: to, UnitSize: unitSize}
+	data, err := ioutil.ReadFile(s.Path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("read backfill state %s fail. %v", s.Path, err)
+	}
+	var saved fileBackfill
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, xerrors.Errorf("invalid backfill state %s. %v", s.Path, err)
+	}
+	if saved.From != from || saved.To != to || saved.UnitSize != unitSize {
+		return nil, xerrors.Errorf("backfill state %s is for blocks %d..%d in units of %d", s.Path, saved.From, saved.To, saved.UnitSize)
+	}
+	s.state.Done = saved.Done
+	return append([]Unit(nil), saved.Done...), nil
+}
+
+func (s *FileBackfillState) Done(ctx context.Context, unit Unit) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state.Done = append(s.state.Done, unit)
+	data, err := json.Marshal(s.state)
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(s.Path), filepath.Base(s.Path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), s.Path)
+}
+
+// BackfillConfig configures a Backfill. Zero values select the defaults.
+type BackfillConfig struct {
+	// UnitSize is the number of blocks of a unit, 1000 by default.
+	UnitSize uint64
+	// Concurrency is the number of units processed in parallel, the number of clients by default.
+	Concurrency int
+	// Retries is the number of times a failed unit is retried, on the next client, 3 by default.
+	Retries int
+	// RetryDelay is the wait before retrying a failed unit, 2s by default.
+	RetryDelay time.Duration
+	// State stores the completed units, in memory by default.
+	State BackfillState
+}
+
+// BackfillError reports the units that failed after all retries.
+type BackfillError struct {
+	Failed []Unit
+	// Err is the last error of the first failed unit.
+	Err error
+}
+
+func (e *BackfillError) Error() string {
+	return fmt.Sprintf("%d backfill unit(s) failed, first failed unit %d..%d: %v", len(e.Failed), e.Failed[0].From, e.Failed[0].To, e.Err)
+}
+
+func (e *BackfillError) Unwrap() error { return e.Err }
+
+// Backfill splits a block range into units and processes them with bounded concurrency,
+// spreading the units over one or more endpoints. It records every completed unit, so a
+// restarted backfill only processes the units that were not completed.
+type Backfill struct {
+	clients []*client.Wormholes
+	handler UnitHandler
+	cfg     BackfillConfig
+
+	mu    sync.Mutex
+	total int
+	done  int
+}
+
+// NewBackfill creates a backfill processing units with handler, reading from clients.
+func NewBackfill(clients []*client.Wormholes, handler UnitHandler, cfg BackfillConfig) (*Backfill, error) {
+	if len(clients) == 0 {
+		return nil, xerrors.New("backfill: no clients")
+	}
+	if cfg.UnitSize == 0 {
+		cfg.UnitSize = defaultUnitSize
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = len(clients)
+	}
+	if cfg.Retries <= 0 {
+		cfg.Retries = defaultRetries
+	}
+	if cfg.RetryDelay <= 0 {
+		cfg.RetryDelay = defaultRetryDelay
+	}
+	if cfg.State == nil {
+		cfg.State = &MemoryBackfillState{}
+	}
+	return &Backfill{clients: clients, handler: handler, cfg: cfg}, nil
+}
+
+// Progress returns the number of completed units and the number of units of the running or last run.
+func (b *Backfill) Progress() (done, total int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.done, b.total
+}
+
+// Run processes the units of from..to (inclusive) that are not completed yet. Units failing after
+// all retries do not stop the others, they are reported by a *BackfillError once all units ran.
+func (b *Backfill) Run(ctx context.Context, from, to uint64) error {
+	if from > to {
+		return fmt.Errorf("invalid block range %d..%d", from, to)
+	}
+	completed, err := b.cfg.State.Load(ctx, from, to, b.cfg.UnitSize)
+	if err != nil {
+		return err
+	}
+	skip := make(map[Unit]bool, len(completed))
+	for _, unit := range completed {
+		skip[unit] = true
+	}
+	var units []Unit
+	total := 0
+	for start := from; ; start += b.cfg.UnitSize {
+		end := start + b.cfg.UnitSize - 1
+		if end > to || end < start {
+			end = to
+		}
+		unit := Unit{From: start, To: end}
+		total++
+		if !skip[unit] {
+			units = append(units, unit)
+		}
+		if end == to {
+			break
+		}
+	}
+	b.mu.Lock()
+	b.total, b.done = total, total-len(units)
+	b.mu.Unlock()
+
+	queue := make(chan Unit)
+	var (
+		wg       sync.WaitGroup
+		failedMu sync.Mutex
+		failed   []Unit
+		firstErr = make(map[Unit]error)
+	)
+	for i := 0; i < b.cfg.Concurrency; i++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for unit := range queue {
+				if err := b.process(ctx, worker, unit); err != nil {
+					failedMu.Lock()
+					failed = append(failed, unit)
+					firstErr[unit] = err
+					failedMu.Unlock()
+				}
+			}
+		}(i)
+	}
+	for _, unit := range units {
+		if ctx.Err() != nil {
+			break
+		}
+		queue <- unit
+	}
+	close(queue)
+	wg.Wait()
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if len(failed) > 0 {
+		sort.Slice(failed, func(i, j int) bool { return failed[i].From < failed[j].From })
+		return &BackfillError{Failed: failed, Err: firstErr[failed[0]]}
+	}
+	return nil
+}
+
+// process runs a unit, retrying on the next client, and records it when completed.
+func (b *Backfill) process(ctx context.Context, worker int, unit Unit) error {
+	var err error
+	for attempt := 0; attempt <= b.cfg.Retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(b.cfg.RetryDelay):
+			}
+		}
+		worm := b.clients[(worker+attempt)%len(b.clients)]
+		if err = b.handler(ctx, worm, unit); err == nil {
+			break
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+	if err != nil {
+		return err
+	}
+	// Record the completed unit also when ctx was cancelled meanwhile, it is not run again.
+	flushCtx, cancel := tools.FlushContext(ctx)
+	defer cancel()
+	if err := b.cfg.State.Done(flushCtx, unit); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	b.done++
+	b.mu.Unlock()
+	return nil
+}
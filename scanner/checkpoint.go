@@ -0,0 +1,78 @@
+package scanner
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/xerrors"
+)
+
+// Checkpoint persists the number of the next block to scan, so a scanner can resume after a restart.
+type Checkpoint interface {
+	// Load returns the saved block number, ok is false when nothing has been saved yet.
+	Load(ctx context.Context) (next uint64, ok bool, err error)
+	Save(ctx context.Context, next uint64) error
+}
+
+// MemoryCheckpoint keeps the checkpoint in memory only.
+type MemoryCheckpoint struct {
+	mu    sync.Mutex
+	next  uint64
+	saved bool
+}
+
+func (c *MemoryCheckpoint) Load(ctx context.Context) (uint64, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.next, c.saved, nil
+}
+
+func (c *MemoryCheckpoint) Save(ctx context.Context, next uint64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.next, c.saved = next, true
+	return nil
+}
+
+// FileCheckpoint keeps the checkpoint as a decimal number in a file.
+// The file is replaced atomically, so a crash never leaves a partial checkpoint behind.
+type FileCheckpoint struct {
+	Path string
+}
+
+func (c *FileCheckpoint) Load(ctx context.Context) (uint64, bool, error) {
+	data, err := ioutil.ReadFile(c.Path)
+	if os.IsNotExist(err) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, xerrors.Errorf("read checkpoint %s fail. %v", c.Path, err)
+	}
+	next, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, false, xerrors.Errorf("invalid checkpoint %s. %v", c.Path, err)
+	}
+	return next, true, nil
+}
+
+func (c *FileCheckpoint) Save(ctx context.Context, next uint64) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(c.Path), filepath.Base(c.Path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.WriteString(strconv.FormatUint(next, 10) + "\n"); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), c.Path)
+}
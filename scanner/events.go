@@ -0,0 +1,102 @@
+package scanner
+
+import (
+	"math/big"
+
+	"github.com/erbieio/erb-client/v2/client"
+	"github.com/erbieio/erb-client/v2/tools"
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Transaction is a transaction of a scanned block together with its decoded wormholes payload.
+type Transaction struct {
+	Block *types.Block
+	Index int
+	Tx    *types.Transaction
+	From  common.Address
+	// Payload is nil for plain ERB transfers and contract calls.
+	Payload *types2.Transaction
+}
+
+// NFTTransfer is a wormholes Transfer transaction.
+type NFTTransfer struct {
+	Transaction *Transaction
+	NFTAddress  string
+	From        common.Address
+	To          common.Address
+}
+
+// Trade is a wormholes transaction that settles an NFT sale.
+type Trade struct {
+	Transaction *Transaction
+	Type        uint8
+	NFTAddress  string
+	Price       *big.Int
+	Exchanger   string
+	Buyer       common.Address
+}
+
+func newTransaction(block *types.Block, index int, tx *types.Transaction) *Transaction {
+	from, _ := client.TransactionSender(block, tx)
+	payload, _ := client.ParseWormholesData(tx.Data())
+	return &Transaction{
+		Block:   block,
+		Index:   index,
+		Tx:      tx,
+		From:    from,
+		Payload: payload,
+	}
+}
+
+func (t *Transaction) to() common.Address {
+	if t.Tx.To() == nil {
+		return common.Address{}
+	}
+	return *t.Tx.To()
+}
+
+func (t *Transaction) nftTransfer() (*NFTTransfer, bool) {
+	if t.Payload == nil || t.Payload.Type != types2.Transfer {
+		return nil, false
+	}
+	return &NFTTransfer{
+		Transaction: t,
+		NFTAddress:  tools.ChecksumAddress(t.Payload.NFTAddress),
+		From:        t.From,
+		To:          t.to(),
+	}, true
+}
+
+func (t *Transaction) trade() (*Trade, bool) {
+	if t.Payload == nil {
+		return nil, false
+	}
+	if !types2.IsTrade(t.Payload.Type) {
+		return nil, false
+	}
+	trade := &Trade{
+		Transaction: t,
+		Type:        t.Payload.Type,
+		Price:       t.Tx.Value(),
+		Buyer:       t.to(),
+	}
+	if types2.SenderIsBuyer(t.Payload.Type) {
+		trade.Buyer = t.From
+	}
+	switch {
+	case t.Payload.Buyer != nil:
+		trade.NFTAddress = t.Payload.Buyer.NFTAddress
+		trade.Exchanger = t.Payload.Buyer.Exchanger
+	case t.Payload.Seller1 != nil:
+		trade.NFTAddress = t.Payload.Seller1.NFTAddress
+		trade.Exchanger = t.Payload.Seller1.Exchanger
+	case t.Payload.Seller2 != nil:
+		trade.Exchanger = t.Payload.Seller2.Exchanger
+	}
+	// The payload holds the addresses as signed, in the case chosen by the signer.
+	trade.NFTAddress = tools.ChecksumAddress(trade.NFTAddress)
+	trade.Exchanger = tools.ChecksumAddress(trade.Exchanger)
+	return trade, true
+}
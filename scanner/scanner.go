@@ -0,0 +1,274 @@
+// Package scanner walks the chain block by block, decodes wormholes transactions and
+// hands them to registered handlers, checkpointing its progress so it can resume.
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/erbieio/erb-client/v2/client"
+	"github.com/erbieio/erb-client/v2/tools"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	defaultBatchSize    = 100
+	defaultConcurrency  = 4
+	defaultPollInterval = 5 * time.Second
+)
+
+// Config configures a Scanner. Zero values select the defaults.
+type Config struct {
+	// Start is the first block scanned when the checkpoint is empty.
+	Start uint64
+	// Confirmations is the number of blocks behind the head that are not scanned yet.
+	Confirmations uint64
+	// BatchSize is the number of blocks fetched per round trip, 100 by default.
+	BatchSize uint64
+	// Concurrency is the largest number of parallel block requests of the default Pool, 4 by default.
+	Concurrency int
+	// Pool runs the block requests, share one to limit the requests of several scanners to a node.
+	// By default the scanner has its own pool adapting between 1 and Concurrency requests.
+	Pool *client.FetchPool
+	// PollInterval is the wait before polling for new blocks once the scanner caught up, 5s by default.
+	PollInterval time.Duration
+	// Checkpoint stores the scan progress, in memory by default.
+	Checkpoint Checkpoint
+}
+
+type (
+	BlockHandler       func(ctx context.Context, block *types.Block) error
+	TransactionHandler func(ctx context.Context, tx *Transaction) error
+	NFTTransferHandler func(ctx context.Context, transfer *NFTTransfer) error
+	TradeHandler       func(ctx context.Context, trade *Trade) error
+)
+
+// Scanner delivers the blocks of the chain in order to the registered handlers.
+// Handlers are called from a single goroutine. When a handler returns an error
+// scanning stops and the block is delivered again after a restart.
+type Scanner struct {
+	worm *client.Wormholes
+	cfg  Config
+
+	blockHandlers       []BlockHandler
+	blockDoneHandlers   []BlockHandler
+	transactionHandlers []TransactionHandler
+	transferHandlers    []NFTTransferHandler
+	tradeHandlers       []TradeHandler
+}
+
+// New creates a scanner reading from worm.
+func New(worm *client.Wormholes, cfg Config) *Scanner {
+	if cfg.BatchSize == 0 {
+		cfg.BatchSize = defaultBatchSize
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = defaultConcurrency
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = defaultPollInterval
+	}
+	if cfg.Checkpoint == nil {
+		cfg.Checkpoint = &MemoryCheckpoint{}
+	}
+	if cfg.Pool == nil {
+		cfg.Pool = client.NewFetchPool(client.FetchConfig{MaxConcurrency: cfg.Concurrency})
+	}
+	return &Scanner{worm: worm, cfg: cfg}
+}
+
+// OnBlock registers a handler called for every block.
+func (s *Scanner) OnBlock(h BlockHandler) { s.blockHandlers = append(s.blockHandlers, h) }
+
+// OnBlockDone registers a handler called for every block after the handlers of its
+// transactions and before the block is checkpointed, e.g. to flush what they buffered.
+func (s *Scanner) OnBlockDone(h BlockHandler) {
+	s.blockDoneHandlers = append(s.blockDoneHandlers, h)
+}
+
+// OnTransaction registers a handler called for every transaction.
+func (s *Scanner) OnTransaction(h TransactionHandler) {
+	s.transactionHandlers = append(s.transactionHandlers, h)
+}
+
+// OnNFTTransfer registers a handler called for every wormholes Transfer transaction.
+func (s *Scanner) OnNFTTransfer(h NFTTransferHandler) {
+	s.transferHandlers = append(s.transferHandlers, h)
+}
+
+// OnTrade registers a handler called for every NFT settlement transaction.
+func (s *Scanner) OnTrade(h TradeHandler) { s.tradeHandlers = append(s.tradeHandlers, h) }
+
+// Next returns the number of the next block to be scanned.
+func (s *Scanner) Next(ctx context.Context) (uint64, error) {
+	next, ok, err := s.cfg.Checkpoint.Load(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return s.cfg.Start, nil
+	}
+	return next, nil
+}
+
+// Run scans until ctx is cancelled or a handler fails. Failures to reach the node are
+// logged and retried after the poll interval. On cancellation the block in progress is
+// finished or left for the next run, the checkpoint of the delivered blocks is saved and
+// Run returns ctx.Err().
+func (s *Scanner) Run(ctx context.Context) error {
+	for {
+		caughtUp, err := s.step(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if _, ok := err.(*handlerError); ok {
+				return err
+			}
+			log.Println("scanner step err ", err)
+			caughtUp = true
+		}
+		if !caughtUp {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(s.cfg.PollInterval):
+		}
+	}
+}
+
+// step scans one batch of confirmed blocks and reports whether the scanner has caught up.
+func (s *Scanner) step(ctx context.Context) (bool, error) {
+	next, err := s.Next(ctx)
+	if err != nil {
+		return false, err
+	}
+	head, err := s.worm.BlockNumber(ctx)
+	if err != nil {
+		return false, err
+	}
+	if head < s.cfg.Confirmations || head-s.cfg.Confirmations < next {
+		return true, nil
+	}
+	safe := head - s.cfg.Confirmations
+	to := next + s.cfg.BatchSize - 1
+	if to > safe {
+		to = safe
+	}
+	if err := s.ScanRange(ctx, next, to); err != nil {
+		return false, err
+	}
+	return to == safe, nil
+}
+
+// ScanRange delivers the blocks from..to (inclusive) to the handlers and checkpoints
+// after every block. The blocks are fetched Config.BatchSize at a time and every batch is
+// delivered before the next one is fetched. When a block can not be fetched the blocks
+// before it are delivered and the error is returned. Once ctx is done it delivers no
+// further block.
+func (s *Scanner) ScanRange(ctx context.Context, from, to uint64) error {
+	if from > to {
+		return fmt.Errorf("invalid block range %d..%d", from, to)
+	}
+	for start := from; ; start += s.cfg.BatchSize {
+		end := start + s.cfg.BatchSize - 1
+		if end > to || end < start {
+			end = to
+		}
+		if err := s.scanBatch(ctx, start, end); err != nil {
+			return err
+		}
+		if end == to {
+			return nil
+		}
+	}
+}
+
+func (s *Scanner) scanBatch(ctx context.Context, from, to uint64) error {
+	results, err := s.worm.GetBlocks(ctx, s.cfg.Pool, from, to)
+	for _, result := range results {
+		if result.Err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := s.process(ctx, result.Block); err != nil {
+			return &handlerError{number: result.Number, err: err}
+		}
+		if err := s.save(ctx, result.Number+1); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// save checkpoints a delivered block, also when ctx was cancelled while delivering it.
+func (s *Scanner) save(ctx context.Context, next uint64) error {
+	ctx, cancel := tools.FlushContext(ctx)
+	defer cancel()
+	return s.cfg.Checkpoint.Save(ctx, next)
+}
+
+func (s *Scanner) process(ctx context.Context, block *types.Block) error {
+	for _, h := range s.blockHandlers {
+		if err := h(ctx, block); err != nil {
+			return err
+		}
+	}
+	if err := s.processTransactions(ctx, block); err != nil {
+		return err
+	}
+	for _, h := range s.blockDoneHandlers {
+		if err := h(ctx, block); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Scanner) processTransactions(ctx context.Context, block *types.Block) error {
+	if len(s.transactionHandlers) == 0 && len(s.transferHandlers) == 0 && len(s.tradeHandlers) == 0 {
+		return nil
+	}
+	for i, tx := range block.Transactions() {
+		transaction := newTransaction(block, i, tx)
+		for _, h := range s.transactionHandlers {
+			if err := h(ctx, transaction); err != nil {
+				return err
+			}
+		}
+		if transfer, ok := transaction.nftTransfer(); ok {
+			for _, h := range s.transferHandlers {
+				if err := h(ctx, transfer); err != nil {
+					return err
+				}
+			}
+		}
+		if trade, ok := transaction.trade(); ok {
+			for _, h := range s.tradeHandlers {
+				if err := h(ctx, trade); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// handlerError is returned when a handler fails, it stops Run instead of being retried.
+type handlerError struct {
+	number uint64
+	err    error
+}
+
+func (e *handlerError) Error() string {
+	return "scanner handler failed at block " + strconv.FormatUint(e.number, 10) + ": " + e.err.Error()
+}
+
+func (e *handlerError) Unwrap() error { return e.err }
@@ -0,0 +1,191 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/erbieio/erb-client/v2/client"
+	"github.com/erbieio/erb-client/v2/tools"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// RollbackHandler is called for a previously delivered block that is no longer canonical.
+type RollbackHandler func(ctx context.Context, block *types.Block) error
+
+// Watcher delivers blocks as soon as they are mined and follows the canonical chain.
+// The handlers registered with OnBlock, OnTransaction and the other methods of Scanner receive
+// tentative events. When a reorg replaces
+// delivered blocks, the rollback handlers are called for them, newest first, before the blocks
+// of the new chain are delivered. Once a block is Config.Confirmations deep, the finalized
+// handlers are called and the checkpoint moves past it.
+//
+// After a restart the blocks that were delivered but not finalized are delivered again.
+type Watcher struct {
+	scanner *Scanner
+
+	next              uint64
+	started           bool
+	pending           []*types.Block
+	rollbackHandlers  []RollbackHandler
+	finalizedHandlers []BlockHandler
+}
+
+// NewWatcher creates a watcher reading from worm. Config.Confirmations is the finality depth.
+func NewWatcher(worm *client.Wormholes, cfg Config) *Watcher {
+	return &Watcher{scanner: New(worm, cfg)}
+}
+
+// OnBlock registers a handler called for every delivered block.
+func (w *Watcher) OnBlock(h BlockHandler) { w.scanner.OnBlock(h) }
+
+// OnBlockDone registers a handler called for every delivered block after the handlers of its
+// transactions.
+func (w *Watcher) OnBlockDone(h BlockHandler) { w.scanner.OnBlockDone(h) }
+
+// OnTransaction registers a handler called for every delivered transaction.
+func (w *Watcher) OnTransaction(h TransactionHandler) { w.scanner.OnTransaction(h) }
+
+// OnNFTTransfer registers a handler called for every delivered wormholes Transfer transaction.
+func (w *Watcher) OnNFTTransfer(h NFTTransferHandler) { w.scanner.OnNFTTransfer(h) }
+
+// OnTrade registers a handler called for every delivered NFT settlement transaction.
+func (w *Watcher) OnTrade(h TradeHandler) { w.scanner.OnTrade(h) }
+
+// Next returns the number of the first block that is not finalized.
+func (w *Watcher) Next(ctx context.Context) (uint64, error) { return w.scanner.Next(ctx) }
+
+// OnRollback registers a handler called for every delivered block invalidated by a reorg.
+func (w *Watcher) OnRollback(h RollbackHandler) {
+	w.rollbackHandlers = append(w.rollbackHandlers, h)
+}
+
+// OnFinalized registers a handler called once a delivered block has enough confirmations.
+func (w *Watcher) OnFinalized(h BlockHandler) {
+	w.finalizedHandlers = append(w.finalizedHandlers, h)
+}
+
+// Run watches the chain until ctx is cancelled or a handler fails. Failures to reach the
+// node are logged and retried after the poll interval. On cancellation no further block is
+// delivered, the checkpoint of the finalized blocks is saved and Run returns ctx.Err().
+func (w *Watcher) Run(ctx context.Context) error {
+	for {
+		caughtUp, err := w.step(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if _, ok := err.(*handlerError); ok {
+				return err
+			}
+			log.Println("watcher step err ", err)
+			caughtUp = true
+		}
+		if !caughtUp {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(w.scanner.cfg.PollInterval):
+		}
+	}
+}
+
+func (w *Watcher) step(ctx context.Context) (bool, error) {
+	if !w.started {
+		next, err := w.Next(ctx)
+		if err != nil {
+			return false, err
+		}
+		w.next, w.started = next, true
+	}
+	if err := w.rollbackStale(ctx); err != nil {
+		return false, err
+	}
+	head, err := w.scanner.worm.BlockNumber(ctx)
+	if err != nil {
+		return false, err
+	}
+	if head < w.next {
+		return true, nil
+	}
+	to := w.next + w.scanner.cfg.BatchSize - 1
+	if to > head {
+		to = head
+	}
+	results, err := w.scanner.worm.GetBlocks(ctx, w.scanner.cfg.Pool, w.next, to)
+	if err != nil {
+		return false, err
+	}
+	for _, result := range results {
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+		block := result.Block
+		if n := len(w.pending); n > 0 && block.ParentHash() != w.pending[n-1].Hash() {
+			// The chain reorganized while fetching, verify the delivered blocks on the next poll.
+			return true, nil
+		}
+		if err := w.scanner.process(ctx, block); err != nil {
+			return false, &handlerError{number: result.Number, err: err}
+		}
+		w.pending = append(w.pending, block)
+		w.next = result.Number + 1
+		if err := w.finalize(ctx, result.Number); err != nil {
+			return false, err
+		}
+	}
+	return to == head, nil
+}
+
+// rollbackStale drops the delivered blocks that are no longer part of the canonical chain.
+func (w *Watcher) rollbackStale(ctx context.Context) error {
+	for len(w.pending) > 0 {
+		last := w.pending[len(w.pending)-1]
+		header, err := w.scanner.worm.HeaderByNumber(ctx, last.Number())
+		if err != nil && !errors.Is(err, ethereum.NotFound) {
+			return err
+		}
+		if err == nil && header.Hash == last.Hash() {
+			return nil
+		}
+		for _, h := range w.rollbackHandlers {
+			if err := h(ctx, last); err != nil {
+				return &handlerError{number: last.NumberU64(), err: err}
+			}
+		}
+		w.pending = w.pending[:len(w.pending)-1]
+		w.next = last.NumberU64()
+	}
+	return nil
+}
+
+// finalize hands the delivered blocks that are deep enough below head to the finalized handlers.
+func (w *Watcher) finalize(ctx context.Context, head uint64) error {
+	for len(w.pending) > 0 {
+		first := w.pending[0]
+		if head-first.NumberU64() < w.scanner.cfg.Confirmations {
+			return nil
+		}
+		for _, h := range w.finalizedHandlers {
+			if err := h(ctx, first); err != nil {
+				return &handlerError{number: first.NumberU64(), err: err}
+			}
+		}
+		if err := w.save(ctx, first.NumberU64()+1); err != nil {
+			return err
+		}
+		w.pending = w.pending[1:]
+	}
+	return nil
+}
+
+// save checkpoints a finalized block, also when ctx was cancelled while finalizing it.
+func (w *Watcher) save(ctx context.Context, next uint64) error {
+	ctx, cancel := tools.FlushContext(ctx)
+	defer cancel()
+	return w.scanner.cfg.Checkpoint.Save(ctx, next)
+}
@@ -0,0 +1,296 @@
+// Package server exposes the read APIs and the signing and submission flows of the
+// client over HTTP/JSON, for services that are not written in Go.
+//
+// All requests must carry one of the configured API keys, either as "X-API-Key: <key>"
+// or as "Authorization: Bearer <key>". Responses are JSON, errors are {"error": "..."}.
+//
+//	GET  /v1/block-number                    latest block number
+//	GET  /v1/blocks/{number|hash}            block header, "latest" for the head
+//	GET  /v1/balances/{address}?block=n      balance in wei, pending state by default
+//	GET  /v1/accounts/{address}?block=n      wormholes account state, latest by default
+//	GET  /v1/validators?block=n              validator list, latest by default
+//	GET  /v1/receipts/{hash}                 transaction receipt
+//	POST /v1/transactions                    {"raw": "0x..."} submit a signed transaction
+//	POST /v1/orders/buyer                    {"amount","nft_address","exchanger","block_number","seller"}
+//	POST /v1/orders/seller1                  {"amount","nft_address","exchanger","block_number"}
+//	POST /v1/orders/seller2                  {"amount","royalty","meta_url","exclusive_flag","exchanger","block_number"}, royalty in basis points
+//	POST /v1/orders/exchanger                {"exchanger_owner","to","block_number"}
+//
+// The order endpoints sign with the key of the configured wallet and are disabled without one.
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/erbieio/erb-client/v2/client"
+	"github.com/erbieio/erb-client/v2/tools"
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// maxBodySize bounds the size of request bodies.
+const maxBodySize = 1 << 20
+
+// Config configures a Server.
+type Config struct {
+	// APIKeys are the accepted API keys, at least one is required.
+	APIKeys []string
+	// Wallet signs orders, the order endpoints are disabled when it is nil.
+	Wallet *client.Wallet
+}
+
+// Server is an http.Handler serving the gateway API.
+type Server struct {
+	worm *client.Wormholes
+	cfg  Config
+	mux  *http.ServeMux
+}
+
+// New creates a gateway for the node behind worm.
+func New(worm *client.Wormholes, cfg Config) (*Server, error) {
+	if len(cfg.APIKeys) == 0 {
+		return nil, errors.New("server: no API keys configured")
+	}
+	if cfg.Wallet != nil {
+		if _, err := cfg.Wallet.Address(); err != nil {
+			return nil, fmt.Errorf("server: wallet: %w", err)
+		}
+	}
+	s := &Server{worm: worm, cfg: cfg, mux: http.NewServeMux()}
+	s.handle("GET", "/v1/block-number", s.blockNumber)
+	s.handle("GET", "/v1/blocks/", s.block)
+	s.handle("GET", "/v1/balances/", s.balance)
+	s.handle("GET", "/v1/accounts/", s.account)
+	s.handle("GET", "/v1/validators", s.validators)
+	s.handle("GET", "/v1/receipts/", s.receipt)
+	s.handle("POST", "/v1/transactions", s.submit)
+	s.handle("POST", "/v1/orders/", s.signOrder)
+	return s, nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(r) {
+		writeError(w, http.StatusUnauthorized, errors.New("invalid API key"))
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	key := r.Header.Get("X-API-Key")
+	if key == "" {
+		key = strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	}
+	ok := false
+	for _, k := range s.cfg.APIKeys {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(k)) == 1 {
+			ok = true
+		}
+	}
+	return ok && key != ""
+}
+
+type handlerFunc func(r *http.Request, arg string) (interface{}, error)
+
+// handle registers h for method on the path, the remainder of a path ending in "/" is passed as arg.
+func (s *Server) handle(method, path string, h handlerFunc) {
+	s.mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != method {
+			writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+			return
+		}
+		arg := strings.TrimPrefix(r.URL.Path, path)
+		if strings.HasSuffix(path, "/") && (arg == "" || strings.Contains(arg, "/")) {
+			writeError(w, http.StatusNotFound, errors.New("not found"))
+			return
+		}
+		result, err := h(r, arg)
+		if err != nil {
+			writeError(w, statusOf(err), err)
+			return
+		}
+		writeJSON(w, http.StatusOK, result)
+	})
+}
+
+// badRequest marks errors caused by the request.
+type badRequest struct{ error }
+
+func statusOf(err error) int {
+	var bad badRequest
+	switch {
+	case errors.As(err, &bad):
+		return http.StatusBadRequest
+	case errors.Is(err, ethereum.NotFound):
+		return http.StatusNotFound
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	}
+	return http.StatusBadGateway
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// blockParam parses the optional block query parameter.
+func blockParam(r *http.Request, def int64) (int64, error) {
+	v := r.URL.Query().Get("block")
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.ParseInt(v, 0, 64)
+	if err != nil || n < 0 {
+		return 0, badRequest{errors.New("invalid block parameter")}
+	}
+	return n, nil
+}
+
+func address(arg string) (string, error) {
+	if !common.IsHexAddress(arg) {
+		return "", badRequest{errors.New("invalid address " + arg)}
+	}
+	return arg, nil
+}
+
+func (s *Server) blockNumber(r *http.Request, _ string) (interface{}, error) {
+	return s.worm.BlockNumber(r.Context())
+}
+
+func (s *Server) block(r *http.Request, arg string) (interface{}, error) {
+	if arg == "latest" {
+		return s.worm.HeaderByNumber(r.Context(), nil)
+	}
+	if len(arg) == 66 && strings.HasPrefix(arg, "0x") {
+		return s.worm.HeaderByHash(r.Context(), common.HexToHash(arg))
+	}
+	n, ok := new(big.Int).SetString(arg, 0)
+	if !ok || n.Sign() < 0 {
+		return nil, badRequest{errors.New("invalid block " + arg)}
+	}
+	return s.worm.HeaderByNumber(r.Context(), n)
+}
+
+func (s *Server) balance(r *http.Request, arg string) (interface{}, error) {
+	addr, err := address(arg)
+	if err != nil {
+		return nil, err
+	}
+	block, err := blockParam(r, -1)
+	if err != nil {
+		return nil, err
+	}
+	var balance *big.Int
+	if block < 0 {
+		balance, err = s.worm.Balance(r.Context(), addr)
+	} else {
+		balance, err = s.worm.BalanceAt(r.Context(), addr, big.NewInt(block))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return (*hexutil.Big)(balance), nil
+}
+
+func (s *Server) account(r *http.Request, arg string) (interface{}, error) {
+	addr, err := address(arg)
+	if err != nil {
+		return nil, err
+	}
+	block, err := blockParam(r, int64(rpc.LatestBlockNumber))
+	if err != nil {
+		return nil, err
+	}
+	return s.worm.GetAccountInfo(r.Context(), addr, block)
+}
+
+func (s *Server) validators(r *http.Request, _ string) (interface{}, error) {
+	block, err := blockParam(r, int64(rpc.LatestBlockNumber))
+	if err != nil {
+		return nil, err
+	}
+	return s.worm.GetValidators(r.Context(), block)
+}
+
+func (s *Server) receipt(r *http.Request, arg string) (interface{}, error) {
+	return s.worm.TransactionReceipt(r.Context(), arg)
+}
+
+func (s *Server) submit(r *http.Request, _ string) (interface{}, error) {
+	var req struct {
+		Raw hexutil.Bytes `json:"raw"`
+	}
+	if err := decodeBody(r, &req); err != nil {
+		return nil, err
+	}
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(req.Raw); err != nil {
+		return nil, badRequest{err}
+	}
+	if err := s.worm.SendTransaction(r.Context(), tx); err != nil {
+		return nil, err
+	}
+	return map[string]string{"hash": tx.Hash().Hex()}, nil
+}
+
+func (s *Server) signOrder(r *http.Request, kind string) (interface{}, error) {
+	if s.cfg.Wallet == nil {
+		return nil, badRequest{errors.New("order signing is disabled")}
+	}
+	var req map[string]string
+	if err := decodeBody(r, &req); err != nil {
+		return nil, err
+	}
+	var (
+		order []byte
+		err   error
+	)
+	w := s.cfg.Wallet
+	switch kind {
+	case "buyer":
+		order, err = w.SignBuyer(req["amount"], req["nft_address"], req["exchanger"], req["block_number"], req["seller"])
+	case "seller1":
+		order, err = w.SignSeller1(req["amount"], req["nft_address"], req["exchanger"], req["block_number"])
+	case "seller2":
+		royalty, perr := types2.ParseRoyalty(req["royalty"])
+		if perr != nil {
+			return nil, badRequest{perr}
+		}
+		order, err = w.SignSeller2(req["amount"], tools.EncodeRoyalty(royalty), req["meta_url"], req["exclusive_flag"], req["exchanger"], req["block_number"])
+	case "exchanger":
+		order, err = w.SignExchanger(req["exchanger_owner"], req["to"], req["block_number"])
+	default:
+		return nil, badRequest{errors.New("unknown order type " + kind)}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(order), nil
+}
+
+func decodeBody(r *http.Request, v interface{}) error {
+	dec := json.NewDecoder(http.MaxBytesReader(nil, r.Body, maxBodySize))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return badRequest{err}
+	}
+	return nil
+}
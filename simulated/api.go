@@ -0,0 +1,292 @@
+package simulated
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// ethAPI serves the eth namespace methods used by the client.
+type ethAPI struct {
+	b *Backend
+}
+
+func (api *ethAPI) ChainId() *hexutil.Big {
+	return (*hexutil.Big)(api.b.chainID)
+}
+
+func (api *ethAPI) BlockNumber() hexutil.Uint64 {
+	api.b.mu.Lock()
+	defer api.b.mu.Unlock()
+	return hexutil.Uint64(len(api.b.blocks) - 1)
+}
+
+func (api *ethAPI) GasPrice() *hexutil.Big {
+	return (*hexutil.Big)(GasPrice)
+}
+
+// callArgs are the fields of a call used by EstimateGas.
+type callArgs struct {
+	Input hexutil.Bytes `json:"input"`
+}
+
+// EstimateGas returns the intrinsic gas of the data, the only gas the backend charges.
+func (api *ethAPI) EstimateGas(args callArgs) hexutil.Uint64 {
+	return hexutil.Uint64(intrinsicGas(args.Input))
+}
+
+func (api *ethAPI) GetTransactionCount(addr common.Address, blockNrOrHash rpc.BlockNumberOrHash) (hexutil.Uint64, error) {
+	api.b.mu.Lock()
+	defer api.b.mu.Unlock()
+	state, err := api.b.stateAt(blockNrOrHash)
+	if err != nil {
+		return 0, err
+	}
+	nonce := state.copyAccount(addr).Nonce
+	if number, ok := blockNrOrHash.Number(); ok && number == rpc.PendingBlockNumber {
+		for _, tx := range api.b.pending {
+			if api.b.senders[tx.Hash()] == addr {
+				nonce++
+			}
+		}
+	}
+	return hexutil.Uint64(nonce), nil
+}
+
+func (api *ethAPI) GetBalance(addr common.Address, blockNrOrHash rpc.BlockNumberOrHash) (*hexutil.Big, error) {
+	api.b.mu.Lock()
+	defer api.b.mu.Unlock()
+	state, err := api.b.stateAt(blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	return (*hexutil.Big)(state.copyAccount(addr).Balance), nil
+}
+
+func (api *ethAPI) GetAccountInfo(addr common.Address, blockNrOrHash rpc.BlockNumberOrHash) (*types2.Account, error) {
+	api.b.mu.Lock()
+	defer api.b.mu.Unlock()
+	state, err := api.b.stateAt(blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	return state.copyAccount(addr), nil
+}
+
+func (api *ethAPI) SendRawTransaction(input hexutil.Bytes) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(input); err != nil {
+		return common.Hash{}, err
+	}
+	from, err := types.Sender(api.b.signer, tx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	api.b.mu.Lock()
+	defer api.b.mu.Unlock()
+	expected := api.b.state.copyAccount(from).Nonce
+	for _, pending := range api.b.pending {
+		if api.b.senders[pending.Hash()] == from {
+			expected++
+		}
+	}
+	if tx.Nonce() != expected {
+		return common.Hash{}, errors.New("invalid nonce")
+	}
+	// Like the transaction pool of the node, refuse transactions the sender can not pay for.
+	cost := new(big.Int).Mul(tx.GasPrice(), new(big.Int).SetUint64(tx.Gas()))
+	if cost.Add(cost, tx.Value()).Cmp(api.b.state.copyAccount(from).Balance) > 0 {
+		return common.Hash{}, errors.New("insufficient funds for gas * price + value")
+	}
+	api.b.senders[tx.Hash()] = from
+	api.b.pending = append(api.b.pending, tx)
+	return tx.Hash(), nil
+}
+
+func (api *ethAPI) GetTransactionReceipt(hash common.Hash) map[string]interface{} {
+	api.b.mu.Lock()
+	defer api.b.mu.Unlock()
+	receipt, ok := api.b.receipts[hash]
+	if !ok {
+		return nil
+	}
+	block := api.b.byHash[receipt.BlockHash]
+	tx := block.Transactions()[receipt.TransactionIndex]
+	fields := map[string]interface{}{
+		"blockHash":         receipt.BlockHash,
+		"blockNumber":       hexutil.Uint64(receipt.BlockNumber.Uint64()),
+		"transactionHash":   hash,
+		"transactionIndex":  hexutil.Uint64(receipt.TransactionIndex),
+		"from":              api.b.senders[hash],
+		"to":                tx.To(),
+		"gasUsed":           hexutil.Uint64(receipt.GasUsed),
+		"cumulativeGasUsed": hexutil.Uint64(receipt.CumulativeGasUsed),
+		"contractAddress":   nil,
+		"logs":              receipt.Logs,
+		"logsBloom":         receipt.Bloom,
+		"type":              hexutil.Uint(tx.Type()),
+		"effectiveGasPrice": (*hexutil.Big)(tx.GasPrice()),
+		"status":            hexutil.Uint(receipt.Status),
+	}
+	return fields
+}
+
+func (api *ethAPI) GetBlockByNumber(number rpc.BlockNumber, fullTx bool) (map[string]interface{}, error) {
+	api.b.mu.Lock()
+	defer api.b.mu.Unlock()
+	block, err := api.b.blockByNumber(number)
+	if err != nil {
+		return nil, nil
+	}
+	return api.b.marshalBlock(block, fullTx), nil
+}
+
+func (api *ethAPI) GetBlockByHash(hash common.Hash, fullTx bool) map[string]interface{} {
+	api.b.mu.Lock()
+	defer api.b.mu.Unlock()
+	block, ok := api.b.byHash[hash]
+	if !ok {
+		return nil
+	}
+	return api.b.marshalBlock(block, fullTx)
+}
+
+// GetBlockBeneficiaryAddressByNumber returns the reward beneficiaries of a block, the simulated
+// chain pays no block rewards so the list is empty.
+func (api *ethAPI) GetBlockBeneficiaryAddressByNumber(number rpc.BlockNumber, fullTx bool) []interface{} {
+	api.b.mu.Lock()
+	defer api.b.mu.Unlock()
+	if _, err := api.b.blockByNumber(number); err != nil {
+		return nil
+	}
+	return []interface{}{}
+}
+
+// marshalBlock encodes a block like the node does, b.mu must be held.
+func (b *Backend) marshalBlock(block *types.Block, fullTx bool) map[string]interface{} {
+	head := block.Header()
+	fields := map[string]interface{}{
+		"number":           (*hexutil.Big)(head.Number),
+		"hash":             block.Hash(),
+		"parentHash":       head.ParentHash,
+		"nonce":            head.Nonce,
+		"mixHash":          head.MixDigest,
+		"sha3Uncles":       head.UncleHash,
+		"logsBloom":        head.Bloom,
+		"stateRoot":        head.Root,
+		"miner":            head.Coinbase,
+		"difficulty":       (*hexutil.Big)(head.Difficulty),
+		"extraData":        hexutil.Bytes(head.Extra),
+		"size":             hexutil.Uint64(block.Size()),
+		"gasLimit":         hexutil.Uint64(head.GasLimit),
+		"gasUsed":          hexutil.Uint64(head.GasUsed),
+		"timestamp":        hexutil.Uint64(head.Time),
+		"transactionsRoot": head.TxHash,
+		"receiptsRoot":     head.ReceiptHash,
+		"uncles":           []common.Hash{},
+	}
+	txs := make([]interface{}, len(block.Transactions()))
+	for i, tx := range block.Transactions() {
+		if !fullTx {
+			txs[i] = tx.Hash()
+			continue
+		}
+		txs[i] = b.marshalTransaction(tx, block, i)
+	}
+	fields["transactions"] = txs
+	return fields
+}
+
+// marshalTransaction encodes a transaction like the node does, block is nil for a pending
+// transaction. b.mu must be held.
+func (b *Backend) marshalTransaction(tx *types.Transaction, block *types.Block, index int) map[string]interface{} {
+	v, r, s := tx.RawSignatureValues()
+	fields := map[string]interface{}{
+		"blockHash":        nil,
+		"blockNumber":      nil,
+		"from":             b.senders[tx.Hash()],
+		"gas":              hexutil.Uint64(tx.Gas()),
+		"gasPrice":         (*hexutil.Big)(tx.GasPrice()),
+		"hash":             tx.Hash(),
+		"input":            hexutil.Bytes(tx.Data()),
+		"nonce":            hexutil.Uint64(tx.Nonce()),
+		"to":               tx.To(),
+		"transactionIndex": nil,
+		"value":            (*hexutil.Big)(tx.Value()),
+		"type":             hexutil.Uint64(tx.Type()),
+		"v":                (*hexutil.Big)(v),
+		"r":                (*hexutil.Big)(r),
+		"s":                (*hexutil.Big)(s),
+	}
+	if block != nil {
+		fields["blockHash"] = block.Hash()
+		fields["blockNumber"] = (*hexutil.Big)(block.Number())
+		fields["transactionIndex"] = hexutil.Uint64(index)
+	}
+	return fields
+}
+
+// netAPI serves the net namespace.
+type netAPI struct {
+	b *Backend
+}
+
+func (api *netAPI) Version() string {
+	return api.b.chainID.String()
+}
+
+func (api *netAPI) PeerCount() hexutil.Uint {
+	return 0
+}
+
+// txpoolAPI serves the txpool namespace, all uncommitted transactions are pending.
+type txpoolAPI struct {
+	b *Backend
+}
+
+func (api *txpoolAPI) Status() map[string]hexutil.Uint {
+	api.b.mu.Lock()
+	defer api.b.mu.Unlock()
+	return map[string]hexutil.Uint{
+		"pending": hexutil.Uint(len(api.b.pending)),
+		"queued":  0,
+	}
+}
+
+func (api *txpoolAPI) ContentFrom(addr common.Address) map[string]map[string]map[string]interface{} {
+	api.b.mu.Lock()
+	defer api.b.mu.Unlock()
+	pending := make(map[string]map[string]interface{})
+	for _, tx := range api.b.pending {
+		if api.b.senders[tx.Hash()] == addr {
+			pending[fmt.Sprint(tx.Nonce())] = api.b.marshalTransaction(tx, nil, 0)
+		}
+	}
+	return map[string]map[string]map[string]interface{}{
+		"pending": pending,
+		"queued":  {},
+	}
+}
+
+func (api *txpoolAPI) Content() map[string]map[common.Address]map[string]map[string]interface{} {
+	api.b.mu.Lock()
+	defer api.b.mu.Unlock()
+	pending := make(map[common.Address]map[string]map[string]interface{})
+	for _, tx := range api.b.pending {
+		from := api.b.senders[tx.Hash()]
+		if pending[from] == nil {
+			pending[from] = make(map[string]map[string]interface{})
+		}
+		pending[from][fmt.Sprint(tx.Nonce())] = api.b.marshalTransaction(tx, nil, 0)
+	}
+	return map[string]map[common.Address]map[string]map[string]interface{}{
+		"pending": pending,
+		"queued":  {},
+	}
+}
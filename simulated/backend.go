@@ -0,0 +1,219 @@
+// Package simulated provides an in-process wormholes node for tests. It executes the
+// wormholes transaction types against in-memory state, so mint, trade and pledge flows
+// can be tested deterministically without a devnet.
+//
+// The backend models the state transitions the client relies on, not the full consensus
+// rules of the chain: there are no SNFT rewards, and unsupported transaction types fail with a
+// reverted receipt. State queries are answered from the state after the requested block, and
+// Fork replaces the latest blocks to test reorgs.
+package simulated
+
+import (
+	"errors"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/erbieio/erb-client/v2/client"
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// DefaultChainID is the chain ID of a backend created with NewBackend.
+var DefaultChainID = big.NewInt(51888)
+
+// GasPrice is the gas price suggested by the backend.
+var GasPrice = big.NewInt(1000000000)
+
+// blockGasLimit is the gas limit of the simulated blocks.
+const blockGasLimit = 30000000
+
+// Backend is a simulated wormholes node. Sent transactions stay pending until Commit.
+type Backend struct {
+	mu       sync.Mutex
+	chainID  *big.Int
+	signer   types.Signer
+	state    *state
+	pending  []*types.Transaction
+	blocks   []*types.Block
+	states   []*state // the states after the canonical blocks, by number
+	byHash   map[common.Hash]*types.Block
+	receipts map[common.Hash]*types.Receipt
+	senders  map[common.Hash]common.Address
+	time     uint64
+
+	server *rpc.Server
+}
+
+// NewBackend creates a backend whose genesis state credits the given balances in wei.
+func NewBackend(alloc map[common.Address]*big.Int) *Backend {
+	b := &Backend{
+		chainID:  DefaultChainID,
+		signer:   types.NewEIP155Signer(DefaultChainID),
+		state:    newState(),
+		byHash:   make(map[common.Hash]*types.Block),
+		receipts: make(map[common.Hash]*types.Receipt),
+		senders:  make(map[common.Hash]common.Address),
+		time:     uint64(time.Now().Unix()),
+	}
+	for addr, balance := range alloc {
+		b.state.account(addr).Balance.Set(balance)
+	}
+	b.appendBlock(nil, nil)
+
+	b.server = rpc.NewServer()
+	if err := b.server.RegisterName("eth", &ethAPI{b}); err != nil {
+		panic(err)
+	}
+	if err := b.server.RegisterName("net", &netAPI{b}); err != nil {
+		panic(err)
+	}
+	if err := b.server.RegisterName("txpool", &txpoolAPI{b}); err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// Client returns a client connected to the backend that signs with priKey.
+func (b *Backend) Client(priKey string) *client.Wormholes {
+	return client.NewClientWithRPC(priKey, rpc.DialInProc(b.server))
+}
+
+// Handler returns the JSON-RPC handler of the backend, to serve it over HTTP to clients
+// created with a URL.
+func (b *Backend) Handler() http.Handler {
+	return b.server
+}
+
+// Close stops the backend, clients connected to it fail afterwards.
+func (b *Backend) Close() {
+	b.server.Stop()
+}
+
+// Commit mines the pending transactions into a new block and returns its hash.
+func (b *Backend) Commit() common.Hash {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	number := uint64(len(b.blocks))
+	txs := b.pending
+	b.pending = nil
+	receipts := make([]*types.Receipt, len(txs))
+	var cumulative uint64
+	for i, tx := range txs {
+		from := b.senders[tx.Hash()]
+		gasUsed, err := b.state.apply(from, tx, number)
+		cumulative += gasUsed
+		receipt := &types.Receipt{
+			Type:              tx.Type(),
+			Status:            types.ReceiptStatusSuccessful,
+			CumulativeGasUsed: cumulative,
+			TxHash:            tx.Hash(),
+			GasUsed:           gasUsed,
+			TransactionIndex:  uint(i),
+			Logs:              []*types.Log{},
+		}
+		if err != nil {
+			receipt.Status = types.ReceiptStatusFailed
+		}
+		receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+		receipts[i] = receipt
+	}
+	block := b.appendBlock(txs, receipts)
+	for _, receipt := range receipts {
+		receipt.BlockHash = block.Hash()
+		receipt.BlockNumber = block.Number()
+		b.receipts[receipt.TxHash] = receipt
+	}
+	return block.Hash()
+}
+
+func (b *Backend) appendBlock(txs []*types.Transaction, receipts []*types.Receipt) *types.Block {
+	header := &types.Header{
+		UncleHash:  types.EmptyUncleHash,
+		Difficulty: big.NewInt(1),
+		Number:     big.NewInt(int64(len(b.blocks))),
+		GasLimit:   blockGasLimit,
+		Time:       b.time + uint64(len(b.blocks)),
+	}
+	if n := len(b.blocks); n > 0 {
+		header.ParentHash = b.blocks[n-1].Hash()
+	}
+	for _, receipt := range receipts {
+		header.GasUsed += receipt.GasUsed
+	}
+	block := types.NewBlock(header, txs, nil, receipts, trie.NewStackTrie(nil))
+	b.blocks = append(b.blocks, block)
+	b.states = append(b.states, b.state.copy())
+	b.byHash[block.Hash()] = block
+	return block
+}
+
+var errUnknownBlock = errors.New("unknown block")
+
+// blockByNumber resolves a block number argument, b.mu must be held.
+func (b *Backend) blockByNumber(number rpc.BlockNumber) (*types.Block, error) {
+	switch {
+	case number < 0:
+		return b.blocks[len(b.blocks)-1], nil
+	case int64(number) < int64(len(b.blocks)):
+		return b.blocks[number], nil
+	}
+	return nil, errUnknownBlock
+}
+
+// stateAt resolves the block argument of a state query to the state after that block, b.mu
+// must be held.
+func (b *Backend) stateAt(blockNrOrHash rpc.BlockNumberOrHash) (*state, error) {
+	if hash, ok := blockNrOrHash.Hash(); ok {
+		block, ok := b.byHash[hash]
+		if !ok || b.blocks[block.NumberU64()].Hash() != hash {
+			return nil, errUnknownBlock
+		}
+		return b.states[block.NumberU64()], nil
+	}
+	number, _ := blockNrOrHash.Number()
+	switch {
+	case number < 0:
+		return b.state, nil
+	case int64(number) < int64(len(b.states)):
+		return b.states[number], nil
+	}
+	return nil, errUnknownBlock
+}
+
+// Fork discards the blocks after parent, the next Commit builds on parent. The transactions
+// of the discarded blocks are dropped with the pending ones. The blocks of the new chain get
+// later times than the discarded ones, so the hashes differ even for the same transactions.
+func (b *Backend) Fork(parent common.Hash) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	block, ok := b.byHash[parent]
+	if !ok || b.blocks[block.NumberU64()].Hash() != parent {
+		return errUnknownBlock
+	}
+	number := block.NumberU64()
+	for _, discarded := range b.blocks[number+1:] {
+		for _, tx := range discarded.Transactions() {
+			delete(b.receipts, tx.Hash())
+		}
+	}
+	b.blocks = b.blocks[:number+1]
+	b.states = b.states[:number+1]
+	b.state = b.states[number].copy()
+	b.pending = nil
+	b.time++
+	return nil
+}
+
+// Account returns a copy of the current state of an account, for assertions in tests.
+func (b *Backend) Account(addr common.Address) *types2.Account {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state.copyAccount(addr)
+}
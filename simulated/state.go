@@ -0,0 +1,324 @@
+package simulated
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/erbieio/erb-client/v2/client"
+	"github.com/erbieio/erb-client/v2/tools"
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var (
+	errInsufficientFunds = errors.New("insufficient funds")
+	errIntrinsicGas      = errors.New("intrinsic gas too low")
+	errNotOwner          = errors.New("sender is not the owner of the nft")
+	errUnknownNFT        = errors.New("unknown nft")
+	errNotExchanger      = errors.New("not an exchanger")
+	errUnsupported       = errors.New("unsupported transaction type")
+)
+
+// state is the in-memory world state of the backend.
+type state struct {
+	accounts map[common.Address]*types2.Account
+	nextNFT  *big.Int
+}
+
+func newState() *state {
+	return &state{
+		accounts: make(map[common.Address]*types2.Account),
+		nextNFT:  big.NewInt(1),
+	}
+}
+
+// copy returns a copy of the state, e.g. to keep the state after a block.
+func (s *state) copy() *state {
+	return &state{accounts: s.snapshot(), nextNFT: new(big.Int).Set(s.nextNFT)}
+}
+
+// account returns the account of addr, creating it if needed.
+func (s *state) account(addr common.Address) *types2.Account {
+	account, ok := s.accounts[addr]
+	if !ok {
+		account = &types2.Account{
+			Balance: new(big.Int),
+			Worm: &types2.WormholesExtension{
+				PledgedBalance:     new(big.Int),
+				PledgedBlockNumber: new(big.Int),
+				BlockNumber:        new(big.Int),
+				ExchangerBalance:   new(big.Int),
+				VoteBlockNumber:    new(big.Int),
+				VoteWeight:         new(big.Int),
+			},
+		}
+		s.accounts[addr] = account
+	}
+	return account
+}
+
+// copyAccount returns a deep enough copy of an account to hand out of the lock.
+func (s *state) copyAccount(addr common.Address) *types2.Account {
+	account := *s.account(addr)
+	worm := *account.Worm
+	account.Balance = new(big.Int).Set(account.Balance)
+	worm.PledgedBalance = new(big.Int).Set(worm.PledgedBalance)
+	worm.ApproveAddressList = append([]common.Address(nil), worm.ApproveAddressList...)
+	account.Worm = &worm
+	if account.Nft.Price != nil {
+		account.Nft.Price = new(big.Int).Set(account.Nft.Price)
+	}
+	return &account
+}
+
+// intrinsicGas is the gas charged for a transaction, the simulated chain does not run code.
+func intrinsicGas(data []byte) uint64 {
+	gas := uint64(21000)
+	for _, b := range data {
+		if b == 0 {
+			gas += 4
+		} else {
+			gas += 16
+		}
+	}
+	return gas
+}
+
+// apply executes tx sent by from in block number. The fee is charged and the nonce
+// incremented even if the transaction fails, in which case the state changes of the
+// transaction itself are discarded.
+func (s *state) apply(from common.Address, tx *types.Transaction, number uint64) (uint64, error) {
+	sender := s.account(from)
+	gas := intrinsicGas(tx.Data())
+	fee := new(big.Int).Mul(tx.GasPrice(), new(big.Int).SetUint64(gas))
+	sender.Nonce++
+	if gas > tx.Gas() {
+		return tx.Gas(), errIntrinsicGas
+	}
+	if sender.Balance.Cmp(fee) < 0 {
+		return gas, errInsufficientFunds
+	}
+	sender.Balance.Sub(sender.Balance, fee)
+
+	snapshot := s.snapshot()
+	if err := s.execute(from, tx, number); err != nil {
+		s.accounts = snapshot
+		// The sender still pays the fee for the failed transaction.
+		s.account(from).Balance.Set(sender.Balance)
+		s.account(from).Nonce = sender.Nonce
+		return gas, err
+	}
+	return gas, nil
+}
+
+// snapshot returns a copy of the accounts, nextNFT is only advanced on success.
+func (s *state) snapshot() map[common.Address]*types2.Account {
+	accounts := make(map[common.Address]*types2.Account, len(s.accounts))
+	for addr := range s.accounts {
+		accounts[addr] = s.copyAccount(addr)
+	}
+	return accounts
+}
+
+func (s *state) execute(from common.Address, tx *types.Transaction, number uint64) error {
+	payload, ok := client.ParseWormholesData(tx.Data())
+	if !ok || tx.To() == nil {
+		return s.transfer(from, tx.To(), tx.Value())
+	}
+	to := *tx.To()
+	switch payload.Type {
+	case types2.Mint:
+		nft := common.BigToAddress(s.nextNFT)
+		account := s.account(nft)
+		account.Nft.Owner = from
+		account.Nft.Creator = from
+		account.Nft.Royalty = payload.Royalty
+		account.Nft.MetaURL = payload.MetaURL
+		if payload.Exchanger != "" {
+			account.Nft.Exchanger = common.HexToAddress(payload.Exchanger)
+		}
+		s.account(from).Worm.NFTBalance++
+		s.nextNFT.Add(s.nextNFT, big.NewInt(1))
+		return nil
+
+	case types2.Transfer:
+		return s.moveNFT(from, payload.NFTAddress, from, to)
+
+	case types2.Author, types2.AuthorRevoke:
+		nft, err := s.ownedNFT(from, payload.NFTAddress)
+		if err != nil {
+			return err
+		}
+		if payload.Type == types2.Author {
+			nft.Nft.NFTApproveAddressList = to
+		} else {
+			nft.Nft.NFTApproveAddressList = common.Address{}
+		}
+		return nil
+
+	case types2.AccountAuthor:
+		worm := s.account(from).Worm
+		for _, approved := range worm.ApproveAddressList {
+			if approved == to {
+				return nil
+			}
+		}
+		worm.ApproveAddressList = append(worm.ApproveAddressList, to)
+		return nil
+
+	case types2.AccountAuthorRevoke:
+		worm := s.account(from).Worm
+		for i, approved := range worm.ApproveAddressList {
+			if approved == to {
+				worm.ApproveAddressList = append(worm.ApproveAddressList[:i], worm.ApproveAddressList[i+1:]...)
+				break
+			}
+		}
+		return nil
+
+	case types2.TokenPledge:
+		sender := s.account(from)
+		if sender.Balance.Cmp(tx.Value()) < 0 {
+			return errInsufficientFunds
+		}
+		sender.Balance.Sub(sender.Balance, tx.Value())
+		worm := s.account(to).Worm
+		worm.PledgedBalance.Add(worm.PledgedBalance, tx.Value())
+		worm.PledgedBlockNumber.SetUint64(number)
+		if payload.Name != "" || payload.Url != "" {
+			worm.ExchangerFlag = true
+			worm.ExchangerName = payload.Name
+			worm.ExchangerURL = payload.Url
+			worm.FeeRate = uint16(payload.FeeRate)
+			worm.BlockNumber.SetUint64(number)
+		}
+		return nil
+
+	case types2.TokenRevokesPledge:
+		worm := s.account(to).Worm
+		if worm.PledgedBalance.Cmp(tx.Value()) < 0 {
+			return errInsufficientFunds
+		}
+		worm.PledgedBalance.Sub(worm.PledgedBalance, tx.Value())
+		if worm.PledgedBalance.Sign() == 0 {
+			worm.ExchangerFlag = false
+		}
+		s.account(from).Balance.Add(s.account(from).Balance, tx.Value())
+		return nil
+
+	case types2.TransactionNFT:
+		// The seller sends the signed buy order, the buyer pays the order amount.
+		b := payload.Buyer
+		if b == nil {
+			return errUnsupported
+		}
+		buyer, err := tools.RecoverAddress(b.Amount+b.NFTAddress+b.Exchanger+b.BlockNumber+b.Seller, b.Sig)
+		if err != nil {
+			return err
+		}
+		return s.trade(b.NFTAddress, b.Amount, b.Exchanger, from, buyer)
+
+	case types2.BuyerInitiatingTransaction:
+		// The buyer sends the signed sell order, the seller is the signer of the order.
+		s1 := payload.Seller1
+		if s1 == nil {
+			return errUnsupported
+		}
+		seller, err := tools.RecoverAddress(s1.Amount+s1.NFTAddress+s1.Exchanger+s1.BlockNumber, s1.Sig)
+		if err != nil {
+			return err
+		}
+		return s.trade(s1.NFTAddress, s1.Amount, s1.Exchanger, seller, from)
+	}
+	return errUnsupported
+}
+
+// transfer moves value from one account to another.
+func (s *state) transfer(from common.Address, to *common.Address, value *big.Int) error {
+	sender := s.account(from)
+	if sender.Balance.Cmp(value) < 0 {
+		return errInsufficientFunds
+	}
+	sender.Balance.Sub(sender.Balance, value)
+	if to != nil {
+		recipient := s.account(*to)
+		recipient.Balance.Add(recipient.Balance, value)
+	}
+	return nil
+}
+
+// ownedNFT returns the nft account if operator may handle it on behalf of its owner.
+func (s *state) ownedNFT(operator common.Address, address string) (*types2.Account, error) {
+	if err := tools.CheckHex("nft address", address); err != nil {
+		return nil, err
+	}
+	nft, ok := s.accounts[common.HexToAddress(address)]
+	if !ok || nft.Nft.Owner == (common.Address{}) {
+		return nil, errUnknownNFT
+	}
+	owner := nft.Nft.Owner
+	if operator == owner || operator == nft.Nft.NFTApproveAddressList {
+		return nft, nil
+	}
+	for _, approved := range s.account(owner).Worm.ApproveAddressList {
+		if approved == operator {
+			return nft, nil
+		}
+	}
+	return nil, errNotOwner
+}
+
+// moveNFT transfers an nft handled by operator from its owner to to.
+func (s *state) moveNFT(operator common.Address, address string, owner, to common.Address) error {
+	nft, err := s.ownedNFT(operator, address)
+	if err != nil {
+		return err
+	}
+	if nft.Nft.Owner != owner {
+		return errNotOwner
+	}
+	nft.Nft.Owner = to
+	nft.Nft.NFTApproveAddressList = common.Address{}
+	s.account(owner).Worm.NFTBalance--
+	s.account(to).Worm.NFTBalance++
+	return nil
+}
+
+// trade settles the sale of an nft: the buyer pays amount, the creator receives the
+// royalty, the exchanger its fee and the seller the rest.
+func (s *state) trade(address, amount, exchanger string, seller, buyer common.Address) error {
+	price, err := hexutil.DecodeBig(amount)
+	if err != nil {
+		return err
+	}
+	nft, err := s.ownedNFT(seller, address)
+	if err != nil {
+		return err
+	}
+	if err := s.transfer(buyer, &seller, price); err != nil {
+		return err
+	}
+	royalty := shareOf(price, uint64(nft.Nft.Royalty))
+	if err := s.transfer(seller, &nft.Nft.Creator, royalty); err != nil {
+		return err
+	}
+	if exchanger != "" {
+		exchangerAddr := common.HexToAddress(exchanger)
+		worm := s.account(exchangerAddr).Worm
+		if !worm.ExchangerFlag {
+			return errNotExchanger
+		}
+		if err := s.transfer(seller, &exchangerAddr, shareOf(price, uint64(worm.FeeRate))); err != nil {
+			return err
+		}
+	}
+	return s.moveNFT(seller, address, nft.Nft.Owner, buyer)
+}
+
+// shareOf returns amount * rate / types2.RateDenominator.
+func shareOf(amount *big.Int, rate uint64) *big.Int {
+	share := new(big.Int).Mul(amount, new(big.Int).SetUint64(rate))
+	return share.Div(share, big.NewInt(types2.RateDenominator))
+}
@@ -0,0 +1,253 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"math/big"
+	"strconv"
+	"strings"
+
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum/common"
+	"golang.org/x/xerrors"
+)
+
+// SQLStore is a Store on top of database/sql. The database driver is not imported by this
+// package, register one in the application, e.g. modernc.org/sqlite or github.com/lib/pq.
+type SQLStore struct {
+	db       *sql.DB
+	postgres bool
+}
+
+// NewSQLite returns a store on a SQLite database and creates its tables.
+func NewSQLite(ctx context.Context, db *sql.DB) (*SQLStore, error) {
+	return newSQLStore(ctx, db, false)
+}
+
+// NewPostgres returns a store on a PostgreSQL database and creates its tables.
+func NewPostgres(ctx context.Context, db *sql.DB) (*SQLStore, error) {
+	return newSQLStore(ctx, db, true)
+}
+
+var schema = []string{
+	`CREATE TABLE IF NOT EXISTS blocks (
+		number BIGINT PRIMARY KEY,
+		hash TEXT NOT NULL,
+		parent_hash TEXT NOT NULL,
+		miner TEXT NOT NULL,
+		time BIGINT NOT NULL,
+		tx_count INTEGER NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS nft_states (
+		address TEXT PRIMARY KEY,
+		owner TEXT NOT NULL,
+		creator TEXT NOT NULL,
+		royalty INTEGER NOT NULL,
+		meta_url TEXT NOT NULL,
+		exchanger TEXT NOT NULL,
+		block_number BIGINT NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS trades (
+		tx_hash TEXT PRIMARY KEY,
+		block_number BIGINT NOT NULL,
+		type INTEGER NOT NULL,
+		buyer TEXT NOT NULL,
+		seller TEXT NOT NULL,
+		exchanger TEXT NOT NULL,
+		nft_address TEXT NOT NULL,
+		meta_url TEXT NOT NULL,
+		price TEXT NOT NULL,
+		creator TEXT NOT NULL,
+		royalty_paid TEXT NOT NULL,
+		fee_paid TEXT NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS trades_block_number ON trades (block_number)`,
+	`CREATE TABLE IF NOT EXISTS checkpoint (
+		id INTEGER PRIMARY KEY,
+		next BIGINT NOT NULL
+	)`,
+}
+
+func newSQLStore(ctx context.Context, db *sql.DB, postgres bool) (*SQLStore, error) {
+	for _, stmt := range schema {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			return nil, xerrors.Errorf("create schema fail. %v", err)
+		}
+	}
+	return &SQLStore{db: db, postgres: postgres}, nil
+}
+
+// rebind replaces the ? placeholders by $n for PostgreSQL.
+func (s *SQLStore) rebind(query string) string {
+	if !s.postgres {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func (s *SQLStore) exec(ctx context.Context, query string, args ...interface{}) error {
+	_, err := s.db.ExecContext(ctx, s.rebind(query), args...)
+	return err
+}
+
+func (s *SQLStore) SaveBlock(ctx context.Context, block *Block) error {
+	return s.exec(ctx, `INSERT INTO blocks (number, hash, parent_hash, miner, time, tx_count) VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (number) DO UPDATE SET hash = excluded.hash, parent_hash = excluded.parent_hash,
+		miner = excluded.miner, time = excluded.time, tx_count = excluded.tx_count`,
+		int64(block.Number), block.Hash.Hex(), block.ParentHash.Hex(), block.Miner.Hex(), int64(block.Time), block.TxCount)
+}
+
+func (s *SQLStore) SaveNFTState(ctx context.Context, state *NFTState) error {
+	return s.exec(ctx, `INSERT INTO nft_states (address, owner, creator, royalty, meta_url, exchanger, block_number) VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (address) DO UPDATE SET owner = excluded.owner, creator = excluded.creator, royalty = excluded.royalty,
+		meta_url = excluded.meta_url, exchanger = excluded.exchanger, block_number = excluded.block_number`,
+		strings.ToLower(state.Address), state.Owner.Hex(), state.Creator.Hex(), int64(state.Royalty), state.MetaURL,
+		state.Exchanger.Hex(), int64(state.BlockNumber))
+}
+
+func (s *SQLStore) SaveTrade(ctx context.Context, trade *types2.Trade) error {
+	return s.exec(ctx, `INSERT INTO trades (tx_hash, block_number, type, buyer, seller, exchanger, nft_address, meta_url,
+		price, creator, royalty_paid, fee_paid) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (tx_hash) DO NOTHING`,
+		trade.TxHash.Hex(), int64(trade.BlockNumber), int64(trade.Type), trade.Buyer.Hex(), trade.Seller.Hex(),
+		trade.Exchanger.Hex(), strings.ToLower(trade.NFTAddress), trade.MetaURL, bigString(trade.Price),
+		trade.Creator.Hex(), bigString(trade.RoyaltyPaid), bigString(trade.FeePaid))
+}
+
+func (s *SQLStore) GetCheckpoint(ctx context.Context) (uint64, bool, error) {
+	var next int64
+	err := s.db.QueryRowContext(ctx, s.rebind(`SELECT next FROM checkpoint WHERE id = ?`), 1).Scan(&next)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return uint64(next), true, nil
+}
+
+func (s *SQLStore) SetCheckpoint(ctx context.Context, next uint64) error {
+	return s.exec(ctx, `INSERT INTO checkpoint (id, next) VALUES (?, ?) ON CONFLICT (id) DO UPDATE SET next = excluded.next`,
+		1, int64(next))
+}
+
+func (s *SQLStore) DeleteFrom(ctx context.Context, number uint64) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, query := range []string{
+		`DELETE FROM blocks WHERE number >= ?`,
+		`DELETE FROM trades WHERE block_number >= ?`,
+		`DELETE FROM nft_states WHERE block_number >= ?`,
+	} {
+		if _, err := tx.ExecContext(ctx, s.rebind(query), int64(number)); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+const tradeColumns = `t.tx_hash, t.block_number, t.type, t.buyer, t.seller, t.exchanger, t.nft_address,
+		t.meta_url, t.price, t.creator, t.royalty_paid, t.fee_paid`
+
+// Trades returns the stored trades of an NFT ordered by block.
+func (s *SQLStore) Trades(ctx context.Context, nftAddress string) ([]*types2.Trade, error) {
+	rows, err := s.db.QueryContext(ctx, s.rebind(`SELECT `+tradeColumns+` FROM trades t
+		WHERE t.nft_address = ? ORDER BY t.block_number`), strings.ToLower(nftAddress))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var trades []*types2.Trade
+	for rows.Next() {
+		trade, err := scanTrade(rows)
+		if err != nil {
+			return nil, err
+		}
+		trades = append(trades, trade)
+	}
+	return trades, rows.Err()
+}
+
+// TradeHistory returns the stored trades from block number on, ordered by block, together
+// with the time of their block. The time is 0 if the block is not stored.
+func (s *SQLStore) TradeHistory(ctx context.Context, from uint64) ([]*TimedTrade, error) {
+	rows, err := s.db.QueryContext(ctx, s.rebind(`SELECT `+tradeColumns+`, COALESCE(b.time, 0) FROM trades t
+		LEFT JOIN blocks b ON b.number = t.block_number WHERE t.block_number >= ? ORDER BY t.block_number`), int64(from))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var trades []*TimedTrade
+	for rows.Next() {
+		var time int64
+		trade, err := scanTrade(rows, &time)
+		if err != nil {
+			return nil, err
+		}
+		trades = append(trades, &TimedTrade{Trade: trade, Time: uint64(time)})
+	}
+	return trades, rows.Err()
+}
+
+// scanTrade scans the tradeColumns of a row followed by extra columns.
+func scanTrade(rows *sql.Rows, extra ...interface{}) (*types2.Trade, error) {
+	var (
+		hash, buyer, seller, exchanger, creator, price, royalty, fee string
+		number, txType                                               int64
+		trade                                                        types2.Trade
+	)
+	dest := []interface{}{&hash, &number, &txType, &buyer, &seller, &exchanger, &trade.NFTAddress, &trade.MetaURL,
+		&price, &creator, &royalty, &fee}
+	if err := rows.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	trade.TxHash = common.HexToHash(hash)
+	trade.BlockNumber = uint64(number)
+	trade.Type = uint8(txType)
+	trade.Buyer = common.HexToAddress(buyer)
+	trade.Seller = common.HexToAddress(seller)
+	trade.Exchanger = common.HexToAddress(exchanger)
+	trade.Creator = common.HexToAddress(creator)
+	for _, amount := range []struct {
+		dest   **big.Int
+		column string
+		value  string
+	}{
+		{&trade.Price, "price", price},
+		{&trade.RoyaltyPaid, "royalty_paid", royalty},
+		{&trade.FeePaid, "fee_paid", fee},
+	} {
+		n, ok := new(big.Int).SetString(amount.value, 10)
+		if !ok {
+			return nil, xerrors.Errorf("trade %s: invalid %s %q", hash, amount.column, amount.value)
+		}
+		*amount.dest = n
+	}
+	return &trade, nil
+}
+
+func (s *SQLStore) Close() error {
+	return s.db.Close()
+}
+
+func bigString(n *big.Int) string {
+	if n == nil {
+		return "0"
+	}
+	return n.String()
+}
+
+var _ Store = (*SQLStore)(nil)
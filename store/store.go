@@ -0,0 +1,95 @@
+// Package store persists the chain data decoded by the scanner.
+package store
+
+import (
+	"context"
+
+	"github.com/erbieio/erb-client/v2/scanner"
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Store is the storage backend of an indexer.
+type Store interface {
+	SaveBlock(ctx context.Context, block *Block) error
+	SaveNFTState(ctx context.Context, state *NFTState) error
+	SaveTrade(ctx context.Context, trade *types2.Trade) error
+	// GetCheckpoint returns the number of the next block to index, ok is false if none was saved.
+	GetCheckpoint(ctx context.Context) (next uint64, ok bool, err error)
+	SetCheckpoint(ctx context.Context, next uint64) error
+	// DeleteFrom removes the blocks, trades and NFT states at or above number, used to roll back
+	// a reorg. The caller saves again the states of the NFTs changed by the removed blocks, read
+	// from the new chain.
+	DeleteFrom(ctx context.Context, number uint64) error
+	Close() error
+}
+
+// Block is the stored summary of a block.
+type Block struct {
+	Number     uint64
+	Hash       common.Hash
+	ParentHash common.Hash
+	Miner      common.Address
+	Time       uint64
+	TxCount    int
+}
+
+// NFTState is the stored state of an NFT as of BlockNumber.
+type NFTState struct {
+	Address     string
+	Owner       common.Address
+	Creator     common.Address
+	Royalty     uint32
+	MetaURL     string
+	Exchanger   common.Address
+	BlockNumber uint64
+}
+
+// TimedTrade is a stored trade with the time of its block.
+type TimedTrade struct {
+	*types2.Trade
+	Time uint64
+}
+
+// NewBlock summarizes a fetched block.
+func NewBlock(block *types.Block) *Block {
+	return &Block{
+		Number:     block.NumberU64(),
+		Hash:       block.Hash(),
+		ParentHash: block.ParentHash(),
+		Miner:      block.Coinbase(),
+		Time:       block.Time(),
+		TxCount:    len(block.Transactions()),
+	}
+}
+
+// NewNFTState extracts the NFT state from the account info of the NFT address at number.
+func NewNFTState(address string, number uint64, account *types2.Account) *NFTState {
+	return &NFTState{
+		Address:     address,
+		Owner:       account.Nft.Owner,
+		Creator:     account.Nft.Creator,
+		Royalty:     account.Nft.Royalty,
+		MetaURL:     account.Nft.MetaURL,
+		Exchanger:   account.Nft.Exchanger,
+		BlockNumber: number,
+	}
+}
+
+// Checkpoint adapts the checkpoint of a Store for use as scanner.Config.Checkpoint.
+func Checkpoint(s Store) scanner.Checkpoint {
+	return checkpoint{s}
+}
+
+type checkpoint struct {
+	s Store
+}
+
+func (c checkpoint) Load(ctx context.Context) (uint64, bool, error) {
+	return c.s.GetCheckpoint(ctx)
+}
+
+func (c checkpoint) Save(ctx context.Context, next uint64) error {
+	return c.s.SetCheckpoint(ctx, next)
+}
@@ -0,0 +1,94 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/erbieio/erb-client/v2/export"
+	"github.com/erbieio/erb-client/v2/simulated"
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestActivities(t *testing.T) {
+	backend := tradingBackend(t, 200, 100)
+	// Block 5 transfers the second NFT to the buyer, block 6 sells the first one to the buyer
+	// and block 7 holds a plain payment of another account.
+	commit(t, backend, func(ctx context.Context) (string, error) {
+		return backend.Client(sellerPriKey).TransferCtx(ctx, nftAddress(2), buyerAddress)
+	})
+	sellTo(t, backend, sellerPriKey, buyerPriKey, buyerAddress, nftAddress(1), exchangeAddress, types2.ERB(1))
+	commit(t, backend, func(ctx context.Context) (string, error) {
+		return backend.Client(exchangerPriKey).NormalTransactionCtx(ctx, buyerAddress, types2.ERB(1), "")
+	})
+	worm := backend.Client("")
+	defer worm.CloseConnect()
+	ctx := context.Background()
+
+	seller, buyer := common.HexToAddress(sellerAddress), common.HexToAddress(buyerAddress)
+	activities, err := export.Activities(ctx, worm, seller, 2, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		block        uint64
+		direction    string
+		counterparty common.Address
+		value        *big.Int
+		txType       string
+		nft          string
+	}{
+		{2, export.Self, seller, new(big.Int), "Mint", ""},
+		{3, export.Self, seller, new(big.Int), "Mint", ""},
+		{4, export.Self, seller, new(big.Int), "Mint", ""},
+		{5, export.Out, buyer, new(big.Int), "Transfer", nftAddress(2)},
+		{6, export.Out, buyer, types2.ERB(1).Wei(), "TransactionNFT", nftAddress(1)},
+	}
+	if len(activities) != len(want) {
+		t.Fatalf("%d seller activities, want %d", len(activities), len(want))
+	}
+	for i, w := range want {
+		a := activities[i]
+		if a.Block != w.block || a.Direction != w.direction || a.Counterparty != w.counterparty || a.Value.Cmp(w.value) != 0 ||
+			a.TxType != w.txType || a.NFTAddress != w.nft {
+			t.Errorf("seller activity %d: %+v, want %+v", i, a, w)
+		}
+		// The seller sent all of them and paid their fees.
+		receipt, err := worm.TransactionReceipt(ctx, a.TxHash.Hex())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fee := new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), simulated.GasPrice); a.Fee.Cmp(fee) != 0 {
+			t.Errorf("seller activity %d fee %s, want %s", i, a.Fee, fee)
+		}
+	}
+
+	activities, err = export.Activities(ctx, worm, buyer, 0, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(activities) != 3 {
+		t.Fatalf("%d buyer activities, want 3", len(activities))
+	}
+	for i, w := range []struct {
+		counterparty common.Address
+		txType       string
+	}{{seller, "Transfer"}, {seller, "TransactionNFT"}, {common.HexToAddress(exchangeAddress), "Normal"}} {
+		a := activities[i]
+		if a.Direction != export.In || a.Counterparty != w.counterparty || a.TxType != w.txType || a.Fee.Sign() != 0 {
+			t.Errorf("buyer activity %d: %+v", i, a)
+		}
+	}
+
+	var out bytes.Buffer
+	if err := export.Write(&out, export.CSV, activities); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[3], "7,") || !strings.Contains(lines[3], ",in,"+exchangeAddress+",1000000000000000000,Normal,,0") {
+		t.Errorf("buyer csv:\n%s", out.String())
+	}
+}
@@ -0,0 +1,49 @@
+package test
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/erbieio/erb-client/v2/simulated"
+	"github.com/erbieio/erb-client/v2/tools"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestAddressBookResolve(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "book.json")
+	if err := os.WriteFile(path, []byte(`{"seller1": "`+sellerAddress+`"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	book, err := tools.LoadAddressBook(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("ERB_ADDR_BUYER1", buyerAddress)
+	env, err := tools.AddressBookFromEnv("ERB_ADDR_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := env.Resolve("buyer1"); got != buyerAddress {
+		t.Errorf("env alias resolved to %s, want %s", got, buyerAddress)
+	}
+
+	backend := simulated.NewBackend(map[common.Address]*big.Int{
+		common.HexToAddress(sellerAddress): big.NewInt(1e18),
+	})
+	defer backend.Close()
+	worm := backend.Client(sellerPriKey)
+	worm.SetAddressBook(book)
+	balance, err := worm.Balance(context.Background(), "Seller1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance.Cmp(big.NewInt(1e18)) != 0 {
+		t.Errorf("balance of seller1 is %s, want 1e18", balance)
+	}
+	if got := book.Resolve(buyerAddress); got != buyerAddress {
+		t.Errorf("plain address resolved to %s", got)
+	}
+}
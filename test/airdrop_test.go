@@ -0,0 +1,87 @@
+package test
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/erbieio/erb-client/v2/airdrop"
+	"github.com/erbieio/erb-client/v2/simulated"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestAirdrop(t *testing.T) {
+	seller := common.HexToAddress(sellerAddress)
+	buyer := common.HexToAddress(buyerAddress)
+	temp := common.HexToAddress(tempAddress)
+	backend := simulated.NewBackend(map[common.Address]*big.Int{
+		seller: big.NewInt(1e18),
+	})
+	defer backend.Close()
+	if _, err := backend.Client(sellerPriKey).Mint(10, "/ipfs/ddfd90be9408b4", ""); err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-time.After(10 * time.Millisecond):
+				backend.Commit()
+			}
+		}
+	}()
+
+	nft := "0x0000000000000000000000000000000000000001"
+	items := []*airdrop.Item{
+		{To: buyer, Amount: big.NewInt(1e15)},
+		{To: temp, Amount: big.NewInt(2e15)},
+		{To: buyer, NFTAddress: nft},
+	}
+	cfg := airdrop.Config{
+		BatchSize:    2,
+		Interval:     time.Millisecond,
+		PollInterval: 10 * time.Millisecond,
+		Checkpoint:   &airdrop.FileCheckpoint{Path: filepath.Join(t.TempDir(), "airdrop.json")},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	a, err := airdrop.New(backend.Client(sellerPriKey), sellerPriKey, items, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := a.Run(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Delivered) != 3 || len(report.Failed) != 0 || len(report.Pending) != 0 {
+		t.Fatalf("delivered %d failed %d pending %d", len(report.Delivered), len(report.Failed), len(report.Pending))
+	}
+	if report.DeliveredAmount.Cmp(big.NewInt(3e15)) != 0 {
+		t.Errorf("delivered amount %s, want 3e15", report.DeliveredAmount)
+	}
+	if owner := backend.Account(common.HexToAddress(nft)).Nft.Owner; owner != buyer {
+		t.Errorf("nft owner %s, want %s", owner, buyer)
+	}
+
+	// A new run on the same checkpoint delivers nothing twice.
+	a, err = airdrop.New(backend.Client(sellerPriKey), sellerPriKey, items, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if balance := backend.Account(temp).Balance; balance.Cmp(big.NewInt(2e15)) != 0 {
+		t.Errorf("balance of %s is %s, want 2e15", temp, balance)
+	}
+
+	if _, err := airdrop.New(backend.Client(sellerPriKey), sellerPriKey, []*airdrop.Item{{To: buyer}}, cfg); err == nil {
+		t.Error("item without amount or nft accepted")
+	}
+}
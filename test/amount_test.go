@@ -0,0 +1,145 @@
+package test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/erbieio/erb-client/v2/simulated"
+	"github.com/erbieio/erb-client/v2/tools"
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestParseAmount(t *testing.T) {
+	for _, c := range []struct {
+		in, hex, erb string
+	}{
+		{"1", "0xde0b6b3a7640000", "1"},
+		{"1.5", "0x14d1120d7b160000", "1.5"},
+		{".001", "0x38d7ea4c68000", "0.001"},
+		{"0.000000000000000001", "0x1", "0.000000000000000001"},
+		{"-2", "-0x1bc16d674ec80000", "-2"},
+		{"0xde0b6b3a7640000", "0xde0b6b3a7640000", "1"},
+		{"0x38D7EA4C68000", "0x38d7ea4c68000", "0.001"},
+	} {
+		amount, err := types2.ParseAmount(c.in)
+		if err != nil {
+			t.Errorf("ParseAmount(%q): %v", c.in, err)
+			continue
+		}
+		if amount.Hex() != c.hex || amount.String() != c.erb {
+			t.Errorf("ParseAmount(%q) = %s %s, want %s %s", c.in, amount.Hex(), amount, c.hex, c.erb)
+		}
+	}
+	for _, in := range []string{"", ".", "1.2.3", "1e18", "0.0000000000000000001", "--1", "1,5", "0xzz"} {
+		if _, err := types2.ParseAmount(in); err == nil {
+			t.Errorf("ParseAmount(%q) succeeded", in)
+		}
+	}
+	if types2.ERB(3).Cmp(types2.Wei(new(big.Int).Mul(big.NewInt(3), big.NewInt(1e18)))) != 0 {
+		t.Error("ERB(3) is not 3e18 wei")
+	}
+	var zero types2.Amount
+	if zero.Hex() != "0x0" || zero.String() != "0" || zero.Sign() != 0 {
+		t.Errorf("zero amount %s %s", zero.Hex(), zero)
+	}
+}
+
+func TestNormalTransactionAmount(t *testing.T) {
+	seller := common.HexToAddress(sellerAddress)
+	backend := simulated.NewBackend(map[common.Address]*big.Int{
+		seller: types2.ERB(10).Wei(),
+	})
+	defer backend.Close()
+	worm := backend.Client(sellerPriKey)
+
+	amount, err := types2.ParseERB("1.25")
+	if err != nil {
+		t.Fatal(err)
+	}
+	to := common.HexToAddress(tempAddress)
+	if _, err := worm.NormalTransactionCtx(context.Background(), tempAddress, amount, ""); err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+	if balance := types2.Wei(backend.Account(to).Balance); balance.Cmp(amount) != 0 {
+		t.Errorf("received %s ERB, want %s", balance, amount)
+	}
+}
+
+func TestERBUnits(t *testing.T) {
+	wei, err := tools.ParseERB("1.5")
+	if err != nil || wei.String() != "1500000000000000000" {
+		t.Errorf("ParseERB(1.5) = %v %v", wei, err)
+	}
+	if _, err := tools.ParseERB("0x1"); err == nil {
+		t.Error("ParseERB of a hex number succeeded")
+	}
+	for _, c := range []struct {
+		wei      string
+		decimals int
+		want     string
+	}{
+		{"1500000000000000000", 2, "1.50"},
+		{"1500000000000000000", 0, "2"},
+		{"1234567890000000000", 4, "1.2346"},
+		{"-1230000000000000000", 1, "-1.2"},
+		{"-1000000000000000", 2, "0.00"},
+		{"1", 18, "0.000000000000000001"},
+		{"1", 30, "0.000000000000000001"},
+		{"1500000000000000000", -1, "1.5"},
+	} {
+		wei, _ := new(big.Int).SetString(c.wei, 10)
+		if got := tools.FormatERB(wei, c.decimals); got != c.want {
+			t.Errorf("FormatERB(%s, %d) = %s, want %s", c.wei, c.decimals, got, c.want)
+		}
+	}
+	if hex, err := tools.ERBToHex("1"); err != nil || hex != "0xde0b6b3a7640000" {
+		t.Errorf("ERBToHex(1) = %s %v", hex, err)
+	}
+	if erb, err := tools.HexToERB("0x14d1120d7b160000"); err != nil || erb != "1.5" {
+		t.Errorf("HexToERB(0x14d1120d7b160000) = %s %v", erb, err)
+	}
+	if _, err := tools.HexToERB("1"); err == nil {
+		t.Error("HexToERB without 0x succeeded")
+	}
+}
+
+func TestAmountRange(t *testing.T) {
+	seller := common.HexToAddress(sellerAddress)
+	large := new(big.Int).Lsh(big.NewInt(1), 70)
+	backend := simulated.NewBackend(map[common.Address]*big.Int{
+		seller: new(big.Int).Mul(large, big.NewInt(2)),
+	})
+	defer backend.Close()
+	worm := backend.Client(sellerPriKey)
+	ctx := context.Background()
+
+	if _, err := worm.NormalTransaction(tempAddress, -1, ""); err == nil {
+		t.Error("NormalTransaction of a negative value succeeded")
+	}
+	if _, err := worm.AdditionalPledgeAmount(-1); err == nil {
+		t.Error("AdditionalPledgeAmount of a negative value succeeded")
+	}
+	if _, err := worm.RevokesPledgeAmountCtx(ctx, types2.ERB(-1)); err == nil {
+		t.Error("RevokesPledgeAmountCtx of a negative value succeeded")
+	}
+	for _, feerate := range []int{-1, 10001, 1 << 32} {
+		if _, err := worm.TokenPledge(seller, "", "", "", 1, feerate); err == nil {
+			t.Errorf("TokenPledge with fee rate %d succeeded", feerate)
+		}
+	}
+	if _, err := worm.Tx().Pay(tempAddress, big.NewInt(-1)).Send(ctx); err == nil {
+		t.Error("Pay of a negative value succeeded")
+	}
+
+	// An amount beyond an int64 of wei is sent as a big.Int.
+	if _, err := worm.NormalTransactionCtx(ctx, tempAddress, types2.Wei(large), ""); err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+	if balance := backend.Account(common.HexToAddress(tempAddress)).Balance; balance.Cmp(large) != 0 {
+		t.Errorf("received %s wei, want %s", balance, large)
+	}
+}
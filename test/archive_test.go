@@ -0,0 +1,222 @@
+package test
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/erbieio/erb-client/v2/archive"
+	"github.com/erbieio/erb-client/v2/scanner"
+	"github.com/erbieio/erb-client/v2/simulated"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// archiveFiles returns the rows of the files under dir by their path relative to dir.
+func archiveFiles(t *testing.T, dir string) map[string][]string {
+	files := map[string][]string{}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r, err := gzip.NewReader(f)
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, path)
+		var rows []string
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			rows = append(rows, scanner.Text())
+		}
+		files[filepath.ToSlash(rel)] = rows
+		return scanner.Err()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func TestArchiveExport(t *testing.T) {
+	backend := simulated.NewBackend(map[common.Address]*big.Int{
+		common.HexToAddress(sellerAddress): big.NewInt(1e18),
+		common.HexToAddress(buyerAddress):  new(big.Int).Mul(big.NewInt(1e18), big.NewInt(10)),
+	})
+	defer backend.Close()
+	head := publishChain(t, backend)
+	if head != 4 {
+		t.Fatalf("head %d, want 4", head)
+	}
+
+	dir := t.TempDir()
+	checkpoint := &scanner.MemoryCheckpoint{}
+	run := func() *archive.Exporter {
+		exporter := archive.New(archive.Config{Store: archive.Dir(dir), Prefix: "erb/", BlockSpan: 2})
+		s := scanner.New(backend.Client(""), scanner.Config{Checkpoint: exporter.Checkpoint(checkpoint)})
+		exporter.Attach(s)
+		next, err := s.Next(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := s.ScanRange(context.Background(), next, head); err != nil {
+			t.Fatal(err)
+		}
+		return exporter
+	}
+
+	run()
+	// Block 4 is not stored yet, the scanner resumes at it.
+	if next, _, _ := checkpoint.Load(context.Background()); next != 4 {
+		t.Errorf("checkpoint %d before the flush, want 4", next)
+	}
+	// A crash before the flush: the next run scans block 4 again and stores it with Flush.
+	if err := run().Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if next, _, _ := checkpoint.Load(context.Background()); next != 5 {
+		t.Errorf("checkpoint %d after the flush, want 5", next)
+	}
+
+	files := archiveFiles(t, dir)
+	dates := map[string]bool{}
+	for key := range files {
+		dates[strings.Split(key, "/")[2]] = true
+	}
+	if len(dates) > 1 {
+		t.Skip("the blocks span midnight")
+	}
+	var keys []string
+	for key := range files {
+		keys = append(keys, key[strings.LastIndex(key, "/")+1:])
+		if !strings.HasPrefix(key, "erb/blocks/date=") && !strings.HasPrefix(key, "erb/trades/date=") {
+			t.Errorf("key %s", key)
+		}
+	}
+	sort.Strings(keys)
+	want := []string{
+		"000000000000-000000000001.jsonl.gz",
+		"000000000002-000000000003.jsonl.gz",
+		"000000000002-000000000003.jsonl.gz",
+		"000000000004-000000000004.jsonl.gz",
+	}
+	if strings.Join(keys, " ") != strings.Join(want, " ") {
+		t.Fatalf("files %v, want %v", keys, want)
+	}
+	blocks := 0
+	for key, rows := range files {
+		if strings.HasPrefix(key, "erb/blocks/") {
+			blocks += len(rows)
+			continue
+		}
+		if len(rows) != 1 {
+			t.Fatalf("trades %v", rows)
+		}
+		var trade archive.Trade
+		if err := json.Unmarshal([]byte(rows[0]), &trade); err != nil {
+			t.Fatal(err)
+		}
+		if trade.Block != 2 || trade.Type != "BuyerInitiatingTransaction" || trade.Price != "100" ||
+			trade.Buyer != common.HexToAddress(buyerAddress) || trade.NFTAddress != "0x0000000000000000000000000000000000000001" {
+			t.Errorf("trade %+v", trade)
+		}
+	}
+	if blocks != 5 {
+		t.Errorf("%d block rows, want 5", blocks)
+	}
+}
+
+// verifySigV4 checks the Signature Version 4 of an S3 request signed with secret.
+func verifySigV4(r *http.Request, body []byte, secret string) bool {
+	auth := strings.TrimPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 ")
+	parts := map[string]string{}
+	for _, part := range strings.Split(auth, ", ") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) == 2 {
+			parts[kv[0]] = kv[1]
+		}
+	}
+	credential := strings.SplitN(parts["Credential"], "/", 2)
+	if len(credential) != 2 {
+		return false
+	}
+	scope := credential[1]
+	scopeParts := strings.Split(scope, "/")
+	payload := sha256.Sum256(body)
+	if r.Header.Get("X-Amz-Content-Sha256") != hex.EncodeToString(payload[:]) || len(scopeParts) != 4 {
+		return false
+	}
+	var headers strings.Builder
+	for _, name := range strings.Split(parts["SignedHeaders"], ";") {
+		value := r.Header.Get(name)
+		if name == "host" {
+			value = r.Host
+		}
+		headers.WriteString(name + ":" + value + "\n")
+	}
+	canonical := r.Method + "\n" + r.URL.EscapedPath() + "\n" + r.URL.RawQuery + "\n" + headers.String() + "\n" +
+		parts["SignedHeaders"] + "\n" + hex.EncodeToString(payload[:])
+	canonicalHash := sha256.Sum256([]byte(canonical))
+	toSign := "AWS4-HMAC-SHA256\n" + r.Header.Get("X-Amz-Date") + "\n" + scope + "\n" + hex.EncodeToString(canonicalHash[:])
+	mac := func(key []byte, data string) []byte {
+		h := hmac.New(sha256.New, key)
+		h.Write([]byte(data))
+		return h.Sum(nil)
+	}
+	key := []byte("AWS4" + secret)
+	for _, part := range scopeParts {
+		key = mac(key, part)
+	}
+	return hex.EncodeToString(mac(key, toSign)) == parts["Signature"]
+}
+
+func TestArchiveS3(t *testing.T) {
+	var mu sync.Mutex
+	objects := map[string][]byte{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Method != http.MethodPut || !verifySigV4(r, body, "secret") {
+			http.Error(w, "<Error><Code>SignatureDoesNotMatch</Code></Error>", http.StatusForbidden)
+			return
+		}
+		mu.Lock()
+		objects[r.URL.Path] = body
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	s3 := archive.NewS3(archive.S3Config{Endpoint: server.URL + "/", Region: "auto", Bucket: "chain", AccessKey: "key", SecretKey: "secret"})
+	key := "erb/trades/date=2024-05-01/000000000000-000000000999.jsonl.gz"
+	if err := s3.Put(context.Background(), key, []byte("rows")); err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	if !bytes.Equal(objects["/chain/"+key], []byte("rows")) {
+		t.Errorf("objects %v", objects)
+	}
+	mu.Unlock()
+
+	wrong := archive.NewS3(archive.S3Config{Endpoint: server.URL, Region: "auto", Bucket: "chain", AccessKey: "key", SecretKey: "other"})
+	if err := wrong.Put(context.Background(), key, []byte("rows")); err == nil || !strings.Contains(err.Error(), "SignatureDoesNotMatch") {
+		t.Errorf("stored with a wrong secret: %v", err)
+	}
+}
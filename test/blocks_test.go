@@ -0,0 +1,158 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/erbieio/erb-client/v2/client"
+	"github.com/erbieio/erb-client/v2/simulated"
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+func TestGetBlocksByRange(t *testing.T) {
+	backend := simulated.NewBackend(nil)
+	defer backend.Close()
+	for i := 0; i < 10; i++ {
+		backend.Commit()
+	}
+	counter := &blockCounter{node: backend.Handler()}
+	node := httptest.NewServer(counter)
+	defer node.Close()
+	worm := client.NewClient("", node.URL)
+	defer worm.CloseConnect()
+	ctx := context.Background()
+
+	results, err := worm.GetBlocksByRange(ctx, 2, 8, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 7 {
+		t.Fatalf("%d results, want 7", len(results))
+	}
+	for i, result := range results {
+		if number := uint64(i + 2); result.Number != number || result.Err != nil || result.Block.NumberU64() != number {
+			t.Errorf("result %d: block %d err %v", i, result.Number, result.Err)
+		}
+	}
+	if results, err := worm.GetBlocksByRange(ctx, 4, 4, 3); err != nil || len(results) != 1 || results[0].Block.NumberU64() != 4 {
+		t.Errorf("single block: %v %v", results, err)
+	}
+	if results, err := worm.GetBlocksByRange(ctx, 5, 4, 3); err == nil || results != nil {
+		t.Errorf("empty range: %v %v", results, err)
+	}
+
+	// The failed block is reported in its place, after and before the fetched ones.
+	counter.failing = "0x5"
+	results, err = worm.GetBlocksByRange(ctx, 3, 7, 2)
+	var rangeErr *client.RangeError
+	if !errors.As(err, &rangeErr) || len(rangeErr.Failed) != 1 || rangeErr.Failed[0] != 5 {
+		t.Fatalf("err %v, want a range error of block 5", err)
+	}
+	if len(results) != 5 {
+		t.Fatalf("%d results, want 5", len(results))
+	}
+	for i, result := range results {
+		number := uint64(i + 3)
+		if result.Number != number {
+			t.Errorf("result %d of block %d", i, result.Number)
+		}
+		if failed := number == 5; failed != (result.Err != nil) || failed != (result.Block == nil) {
+			t.Errorf("block %d: err %v", number, result.Err)
+		}
+	}
+}
+
+// beneficiaryNode answers eth_getBlockBeneficiaryAddressByNumber with two beneficiaries per
+// block, the addresses of 2n and 2n+1 for block n, and fails the request of block failing.
+func beneficiaryNode(failing hexutil.Uint64) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var req struct {
+			ID     json.RawMessage   `json:"id"`
+			Params []json.RawMessage `json:"params"`
+		}
+		var number hexutil.Uint64
+		if json.Unmarshal(body, &req) != nil || len(req.Params) == 0 || json.Unmarshal(req.Params[0], &number) != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if number == failing {
+			w.Write([]byte(`{"jsonrpc":"2.0","id":` + string(req.ID) + `,"error":{"code":-32000,"message":"unavailable"}}`))
+			return
+		}
+		list := types2.BeneficiaryAddressList{
+			{Address: common.BigToAddress(new(big.Int).SetUint64(2 * uint64(number)))},
+			{Address: common.BigToAddress(new(big.Int).SetUint64(2*uint64(number) + 1))},
+		}
+		result, _ := json.Marshal(list)
+		w.Write([]byte(`{"jsonrpc":"2.0","id":` + string(req.ID) + `,"result":` + string(result) + `}`))
+	}))
+}
+
+func TestStreamBeneficiaries(t *testing.T) {
+	node := beneficiaryNode(4)
+	defer node.Close()
+	worm := client.NewClient("", node.URL)
+	defer worm.CloseConnect()
+	ctx := context.Background()
+
+	// With one request at a time the results arrive in block order.
+	var got []uint64
+	for result := range worm.StreamBeneficiaries(ctx, 1, 6, 1) {
+		if result.Block == 4 {
+			if result.Err == nil || result.Beneficiary != nil {
+				t.Errorf("failed block 4: %+v", result)
+			}
+			continue
+		}
+		if result.Err != nil {
+			t.Fatalf("block %d: %v", result.Block, result.Err)
+		}
+		got = append(got, result.Beneficiary.Address.Big().Uint64())
+	}
+	want := []uint64{2, 3, 4, 5, 6, 7, 10, 11, 12, 13}
+	if len(got) != len(want) {
+		t.Fatalf("beneficiaries %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("beneficiaries %v, want %v", got, want)
+		}
+	}
+
+	// In parallel every beneficiary arrives once, with the block it was rewarded in.
+	seen := make(map[uint64]bool)
+	failed := 0
+	for result := range worm.StreamBeneficiaries(ctx, 1, 20, 4) {
+		if result.Err != nil {
+			failed++
+			continue
+		}
+		address := result.Beneficiary.Address.Big().Uint64()
+		if seen[address] || address/2 != result.Block {
+			t.Errorf("beneficiary %d of block %d", address, result.Block)
+		}
+		seen[address] = true
+	}
+	if len(seen) != 38 || failed != 1 {
+		t.Errorf("%d beneficiaries and %d failures, want 38 and 1", len(seen), failed)
+	}
+
+	for result := range worm.StreamBeneficiaries(ctx, 5, 4, 2) {
+		t.Errorf("result %+v of an empty range", result)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	for range worm.StreamBeneficiaries(cancelled, 0, 1000, 2) {
+	}
+}
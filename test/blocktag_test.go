@@ -0,0 +1,80 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/erbieio/erb-client/v2/client"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+func TestBlockTagMarshal(t *testing.T) {
+	hash := common.HexToHash("0x1f4c00c477651531f5e9f145b317e16c02fe3b1d1f4c00c477651531f5e9f145")
+	tests := []struct {
+		tag  client.BlockTag
+		want string
+	}{
+		{client.Latest, `"latest"`},
+		{client.Pending, `"pending"`},
+		{client.Earliest, `"earliest"`},
+		{client.Finalized, `"finalized"`},
+		{client.AtBlockNumber(26), `"0x1a"`},
+		{client.BlockTag{}, `"latest"`},
+		{client.AtBlockHash(hash), `{"blockHash":"` + hash.Hex() + `","requireCanonical":true}`},
+	}
+	for _, tt := range tests {
+		got, err := json.Marshal(tt.tag)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("marshal %s: got %s, want %s", tt.tag, got, tt.want)
+		}
+	}
+}
+
+// tagEthAPI records the block parameters of the state queries.
+type tagEthAPI struct {
+	blocks *[]string
+}
+
+func (api tagEthAPI) GetBalance(address common.Address, block json.RawMessage) *hexutil.Big {
+	*api.blocks = append(*api.blocks, string(block))
+	return (*hexutil.Big)(big.NewInt(1))
+}
+
+func (api tagEthAPI) GetTransactionCount(address common.Address, block json.RawMessage) hexutil.Uint64 {
+	*api.blocks = append(*api.blocks, string(block))
+	return 1
+}
+
+func TestBlockTagQueries(t *testing.T) {
+	var blocks []string
+	rpcServer := rpc.NewServer()
+	if err := rpcServer.RegisterName("eth", tagEthAPI{&blocks}); err != nil {
+		t.Fatal(err)
+	}
+	worm := client.NewClientWithRPC("", rpc.DialInProc(rpcServer))
+	defer worm.CloseConnect()
+	ctx := context.Background()
+	hash := common.HexToHash("0x01")
+	account := common.HexToAddress(buyerAddress)
+
+	worm.Balance(ctx, buyerAddress)
+	worm.BalanceAt(ctx, buyerAddress, big.NewInt(5))
+	worm.BalanceAt(ctx, buyerAddress, nil)
+	worm.BalanceAtTag(ctx, buyerAddress, client.AtBlockNumber(5))
+	worm.BalanceAtTag(ctx, buyerAddress, client.AtBlockHash(hash))
+	worm.PendingNonceAt(ctx, account)
+	worm.NonceAt(ctx, account, big.NewInt(-1))
+	worm.NonceAtTag(ctx, account, client.BlockTag{})
+	want := []string{`"pending"`, `"0x5"`, `"latest"`, `"0x5"`, `{"blockHash":"` + hash.Hex() + `","requireCanonical":true}`, `"pending"`, `"pending"`, `"latest"`}
+	if strings.Join(blocks, " ") != strings.Join(want, " ") {
+		t.Errorf("block parameters %v, want %v", blocks, want)
+	}
+}
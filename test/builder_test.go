@@ -0,0 +1,125 @@
+package test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/erbieio/erb-client/v2/client"
+	"github.com/erbieio/erb-client/v2/simulated"
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestTxBuilder(t *testing.T) {
+	ctx := context.Background()
+	seller := common.HexToAddress(sellerAddress)
+	buyer := common.HexToAddress(buyerAddress)
+	backend := simulated.NewBackend(map[common.Address]*big.Int{
+		seller: big.NewInt(1e18),
+	})
+	defer backend.Close()
+	worm := backend.Client(sellerPriKey)
+
+	gasPrice := big.NewInt(1e9)
+	if _, err := worm.Tx().Mint().Royalty(1000).MetaURL("/ipfs/ddfd90be9408b4").GasPrice(gasPrice).Send(ctx); err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+	nft := "0x0000000000000000000000000000000000000001"
+	account := backend.Account(common.HexToAddress(nft))
+	if account.Nft.Owner != seller || account.Nft.Royalty != 1000 || account.Nft.MetaURL != "/ipfs/ddfd90be9408b4" {
+		t.Fatalf("minted nft %+v", account.Nft)
+	}
+
+	tx, err := worm.Tx().Transfer(nft).To(buyerAddress).Nonce(1).GasLimit(60000).Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Nonce() != 1 || tx.Gas() != 60000 || *tx.To() != buyer {
+		t.Fatalf("built nonce %d gas %d to %s", tx.Nonce(), tx.Gas(), tx.To())
+	}
+	if err := worm.SendTransaction(ctx, tx); err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+	if owner := backend.Account(common.HexToAddress(nft)).Nft.Owner; owner != buyer {
+		t.Errorf("transferred owner %s, want %s", owner, buyer)
+	}
+
+	if _, err := worm.Tx().Transfer(nft).Send(ctx); err == nil {
+		t.Error("transfer without recipient sent")
+	}
+}
+
+func TestTransactionCtx(t *testing.T) {
+	seller := common.HexToAddress(sellerAddress)
+	backend := simulated.NewBackend(map[common.Address]*big.Int{
+		seller: big.NewInt(1e18),
+	})
+	defer backend.Close()
+	worm := backend.Client(sellerPriKey)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := worm.MintCtx(ctx, 10, "/ipfs/ddfd90be9408b4", ""); err == nil {
+		t.Fatal("mint with a cancelled context sent")
+	}
+	if _, err := worm.MintCtx(context.Background(), 10, "/ipfs/ddfd90be9408b4", ""); err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+	if owner := backend.Account(common.HexToAddress("0x0000000000000000000000000000000000000001")).Nft.Owner; owner != seller {
+		t.Errorf("minted owner %s, want %s", owner, seller)
+	}
+}
+
+func TestGasLimits(t *testing.T) {
+	ctx := context.Background()
+	backend := simulated.NewBackend(map[common.Address]*big.Int{
+		common.HexToAddress(sellerAddress): big.NewInt(1e18),
+	})
+	defer backend.Close()
+	worm := backend.Client(sellerPriKey)
+
+	gas := func(b interface {
+		Build(context.Context) (*types.Transaction, error)
+	}) uint64 {
+		tx, err := b.Build(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tx.Gas()
+	}
+	if limit, ok := client.DefaultGasLimit(types2.Mint); !ok || gas(worm.Tx().Mint()) != limit {
+		t.Errorf("mint gas is not the default %d", limit)
+	}
+	if g := gas(worm.Tx().Pay(buyerAddress, big.NewInt(1))); g != 51000 {
+		t.Errorf("pay gas %d, want 51000", g)
+	}
+	worm.SetGasLimit(types2.Mint, 80000)
+	if g := gas(worm.WithPriKey(buyerPriKey).Tx().Mint()); g != 80000 {
+		t.Errorf("overridden mint gas %d, want 80000", g)
+	}
+	if g := gas(worm.Tx().Mint().GasLimit(90000)); g != 90000 {
+		t.Errorf("mint gas set on the transaction %d, want 90000", g)
+	}
+
+	worm.SetGasLimit(types2.Mint, 0)
+	tx, err := worm.Tx().Mint().MetaURL("/ipfs/ddfd90be9408b4").Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if estimated, err := worm.EstimateGas(ctx, ethereum.CallMsg{Data: tx.Data()}); err != nil || tx.Gas() != estimated+estimated/5 {
+		t.Errorf("estimated mint gas %d, node estimates %d %v", tx.Gas(), estimated, err)
+	}
+	if _, err := worm.MintCtx(ctx, 1000, "/ipfs/ddfd90be9408b4", ""); err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+	if owner := backend.Account(common.HexToAddress("0x0000000000000000000000000000000000000001")).Nft.Owner; owner != common.HexToAddress(sellerAddress) {
+		t.Errorf("nft minted with estimated gas owned by %s", owner)
+	}
+}
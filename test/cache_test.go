@@ -0,0 +1,229 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/erbieio/erb-client/v2/client"
+	"github.com/erbieio/erb-client/v2/simulated"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// methodCounter serves a simulated node and counts the requests of every JSON-RPC method.
+type methodCounter struct {
+	node  http.Handler
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func (c *methodCounter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	var req struct {
+		Method string `json:"method"`
+	}
+	if json.Unmarshal(body, &req) == nil {
+		c.mu.Lock()
+		c.calls[req.Method]++
+		c.mu.Unlock()
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	c.node.ServeHTTP(w, r)
+}
+
+func (c *methodCounter) count(method string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls[method]
+}
+
+// cachedNode returns a node with a mint in block 1 and the given number of empty blocks on top,
+// the counter of its requests and the hash of the mint.
+func cachedNode(t *testing.T, blocks int) (*httptest.Server, *methodCounter, string) {
+	backend := simulated.NewBackend(map[common.Address]*big.Int{
+		common.HexToAddress(sellerAddress): big.NewInt(1e18),
+	})
+	t.Cleanup(backend.Close)
+	hash, err := backend.Client(sellerPriKey).MintCtx(context.Background(), 10, "/ipfs/ddfd90be9408b4", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+	for i := 0; i < blocks; i++ {
+		backend.Commit()
+	}
+	counter := &methodCounter{node: backend.Handler(), calls: make(map[string]int)}
+	node := httptest.NewServer(counter)
+	t.Cleanup(node.Close)
+	return node, counter, hash
+}
+
+func TestCachedClientHits(t *testing.T) {
+	node, counter, txHash := cachedNode(t, 12)
+	worm := client.NewClient("", node.URL)
+	defer worm.CloseConnect()
+	cached := client.NewCachedClient(worm, client.CacheConfig{})
+	ctx := context.Background()
+	header, err := worm.HeaderByNumber(ctx, big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := cached.BlockByHash(ctx, header.Hash); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := cached.HeaderByHash(ctx, header.Hash); err != nil {
+			t.Fatal(err)
+		}
+		receipt, err := cached.TransactionReceipt(ctx, txHash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if receipt.BlockNumber.Uint64() != 1 {
+			t.Fatalf("receipt in block %s, want 1", receipt.BlockNumber)
+		}
+		account, err := cached.GetAccountInfo(ctx, sellerAddress, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if account.Worm.NFTBalance != 1 {
+			t.Fatalf("nft balance %d at block 1, want 1", account.Worm.NFTBalance)
+		}
+	}
+	// Both lookups by hash go to eth_getBlockByHash.
+	if n := counter.count("eth_getBlockByHash"); n != 2 {
+		t.Errorf("%d block requests, want 2", n)
+	}
+	if n := counter.count("eth_getTransactionReceipt"); n != 1 {
+		t.Errorf("%d receipt requests, want 1", n)
+	}
+	if n := counter.count("eth_getAccountInfo"); n != 1 {
+		t.Errorf("%d account requests, want 1", n)
+	}
+
+	cached.Purge()
+	if _, err := cached.GetAccountInfo(ctx, sellerAddress, 1); err != nil {
+		t.Fatal(err)
+	}
+	if n := counter.count("eth_getAccountInfo"); n != 2 {
+		t.Errorf("%d account requests after the purge, want 2", n)
+	}
+}
+
+func TestCachedClientUnconfirmed(t *testing.T) {
+	// The mint is in block 1 of 5, not confirmed by 12 blocks.
+	node, counter, txHash := cachedNode(t, 4)
+	worm := client.NewClient("", node.URL)
+	defer worm.CloseConnect()
+	ctx := context.Background()
+
+	cached := client.NewCachedClient(worm, client.CacheConfig{})
+	for i := 0; i < 2; i++ {
+		if _, err := cached.TransactionReceipt(ctx, txHash); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := cached.GetAccountInfo(ctx, sellerAddress, 1); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := cached.GetAccountInfo(ctx, sellerAddress, -1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := counter.count("eth_getTransactionReceipt"); n != 2 {
+		t.Errorf("%d receipt requests, want 2", n)
+	}
+	if n := counter.count("eth_getAccountInfo"); n != 4 {
+		t.Errorf("%d account requests, want 4", n)
+	}
+
+	// With 4 confirmations block 1 is confirmed at head 5.
+	cached = client.NewCachedClient(worm, client.CacheConfig{Confirmations: 4})
+	for i := 0; i < 2; i++ {
+		if _, err := cached.GetAccountInfo(ctx, sellerAddress, 1); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := cached.GetAccountInfo(ctx, sellerAddress, 2); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := counter.count("eth_getAccountInfo"); n != 7 {
+		t.Errorf("%d account requests, want 7", n)
+	}
+
+	// A negative number caches every block.
+	cached = client.NewCachedClient(worm, client.CacheConfig{Confirmations: -1})
+	before := counter.count("eth_blockNumber")
+	for i := 0; i < 2; i++ {
+		if _, err := cached.GetAccountInfo(ctx, sellerAddress, 5); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := counter.count("eth_getAccountInfo"); n != 8 {
+		t.Errorf("%d account requests, want 8", n)
+	}
+	if n := counter.count("eth_blockNumber"); n != before {
+		t.Errorf("%d head requests without confirmations", n-before)
+	}
+}
+
+func TestCachedClientExpiry(t *testing.T) {
+	node, counter, _ := cachedNode(t, 0)
+	worm := client.NewClient("", node.URL)
+	defer worm.CloseConnect()
+	ctx := context.Background()
+	header, err := worm.HeaderByNumber(ctx, big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cached := client.NewCachedClient(worm, client.CacheConfig{TTL: 50 * time.Millisecond})
+	for i := 0; i < 2; i++ {
+		if _, err := cached.BlockByHash(ctx, header.Hash); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := counter.count("eth_getBlockByHash"); n != 1 {
+		t.Errorf("%d block requests before the expiry, want 1", n)
+	}
+	time.Sleep(60 * time.Millisecond)
+	if _, err := cached.BlockByHash(ctx, header.Hash); err != nil {
+		t.Fatal(err)
+	}
+	if n := counter.count("eth_getBlockByHash"); n != 2 {
+		t.Errorf("%d block requests after the expiry, want 2", n)
+	}
+}
+
+func TestCachedClientEviction(t *testing.T) {
+	node, counter, _ := cachedNode(t, 2)
+	worm := client.NewClient("", node.URL)
+	defer worm.CloseConnect()
+	ctx := context.Background()
+	var hashes []common.Hash
+	for i := int64(1); i <= 3; i++ {
+		header, err := worm.HeaderByNumber(ctx, big.NewInt(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		hashes = append(hashes, header.Hash)
+	}
+
+	cached := client.NewCachedClient(worm, client.CacheConfig{Size: 2})
+	// Reading the first block again keeps it, the second is the least recently used.
+	for _, i := range []int{0, 1, 0, 2, 0, 1} {
+		if _, err := cached.BlockByHash(ctx, hashes[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := counter.count("eth_getBlockByHash"); n != 4 {
+		t.Errorf("%d block requests, want 4", n)
+	}
+}
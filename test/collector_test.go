@@ -0,0 +1,53 @@
+package test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/erbieio/erb-client/v2/collector"
+	"github.com/erbieio/erb-client/v2/simulated"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestCollectorCollectBatch(t *testing.T) {
+	seller := common.HexToAddress(sellerAddress)
+	treasury := common.HexToAddress(tempAddress)
+	backend := simulated.NewBackend(map[common.Address]*big.Int{
+		seller:                          big.NewInt(1e18),
+		common.HexToAddress(priAddress): big.NewInt(1e18),
+	})
+	defer backend.Close()
+	// The simulated chain distributes no SNFTs, collect minted NFTs instead.
+	for _, key := range []string{sellerPriKey, priKey, sellerPriKey} {
+		if _, err := backend.Client(key).Mint(10, "/ipfs/ddfd90be9408b4", ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	backend.Commit()
+
+	for _, dryRun := range []bool{true, false} {
+		c, err := collector.New(backend.Client(""), collector.Config{
+			PriKeys:  []string{sellerPriKey},
+			Treasury: treasury,
+			Start:    big.NewInt(1),
+			DryRun:   dryRun,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var collected []*collector.Collected
+		c.OnCollected(func(ev *collector.Collected) { collected = append(collected, ev) })
+		caughtUp, err := c.CollectBatch(context.Background())
+		if err != nil || !caughtUp {
+			t.Fatalf("dry run %v: caught up %v err %v", dryRun, caughtUp, err)
+		}
+		if len(collected) != 2 {
+			t.Fatalf("dry run %v: collected %d, want 2", dryRun, len(collected))
+		}
+		backend.Commit()
+	}
+	if n := backend.Account(treasury).Worm.NFTBalance; n != 2 {
+		t.Errorf("treasury owns %d nfts, want 2", n)
+	}
+}
@@ -0,0 +1,196 @@
+package test
+
+import (
+	"context"
+	"math/big"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/erbieio/erb-client/v2/client"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const counterABI = `[
+	{"type":"function","name":"value","stateMutability":"view","inputs":[],"outputs":[{"type":"uint256"}]},
+	{"type":"function","name":"set","stateMutability":"nonpayable","inputs":[{"name":"v","type":"uint256"}],"outputs":[]}
+]`
+
+// contractAPI serves a counter contract holding 42 and records the sent transactions.
+type contractAPI struct {
+	sent []*types.Transaction
+}
+
+func (api *contractAPI) GetCode(common.Address, rpc.BlockNumberOrHash) hexutil.Bytes {
+	return hexutil.Bytes{0x60, 0x80}
+}
+
+func (api *contractAPI) Call(args map[string]interface{}, block rpc.BlockNumberOrHash) hexutil.Bytes {
+	return common.LeftPadBytes(big.NewInt(42).Bytes(), 32)
+}
+
+func (api *contractAPI) GetBlockByNumber(rpc.BlockNumber, bool) *types.Header {
+	return &types.Header{Number: big.NewInt(7), Difficulty: big.NewInt(1), GasLimit: 8000000}
+}
+
+func (api *contractAPI) GetTransactionCount(common.Address, rpc.BlockNumberOrHash) hexutil.Uint64 {
+	return 3
+}
+
+func (api *contractAPI) GasPrice() *hexutil.Big {
+	return (*hexutil.Big)(big.NewInt(1e9))
+}
+
+func (api *contractAPI) EstimateGas(map[string]interface{}) hexutil.Uint64 {
+	return 50000
+}
+
+func (api *contractAPI) SendRawTransaction(input hexutil.Bytes) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(input); err != nil {
+		return common.Hash{}, err
+	}
+	api.sent = append(api.sent, tx)
+	return tx.Hash(), nil
+}
+
+func (api *contractAPI) GetLogs(query map[string]interface{}) []types.Log {
+	return []types.Log{{Address: common.HexToAddress(query["address"].([]interface{})[0].(string)), Topics: []common.Hash{}, Data: []byte{}}}
+}
+
+func TestContractBackend(t *testing.T) {
+	api := &contractAPI{}
+	rpcServer := rpc.NewServer()
+	if err := rpcServer.RegisterName("eth", api); err != nil {
+		t.Fatal(err)
+	}
+	node := httptest.NewServer(rpcServer)
+	defer node.Close()
+	c, err := rpc.Dial(node.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	worm := client.NewClientWithRPC("", c)
+	defer worm.CloseConnect()
+	backend := worm.ContractBackend()
+	ctx := context.Background()
+
+	parsed, err := abi.JSON(strings.NewReader(counterABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := common.HexToAddress(exchangeAddress)
+	counter := bind.NewBoundContract(address, parsed, backend, backend, backend)
+	var out []interface{}
+	if err := counter.Call(&bind.CallOpts{Context: ctx}, &out, "value"); err != nil {
+		t.Fatal(err)
+	}
+	if value := out[0].(*big.Int); value.Int64() != 42 {
+		t.Errorf("value %s, want 42", value)
+	}
+
+	key, err := crypto.HexToECDSA(priKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(51888))
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts.Context = ctx
+	tx, err := counter.Transact(opts, "set", big.NewInt(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(api.sent) != 1 || api.sent[0].Hash() != tx.Hash() {
+		t.Fatalf("sent %d transactions", len(api.sent))
+	}
+	if tx.Nonce() != 3 || tx.Gas() < 50000 || tx.GasPrice().Int64() != 1e9 || *tx.To() != address {
+		t.Errorf("transaction nonce %d gas %d price %s to %s", tx.Nonce(), tx.Gas(), tx.GasPrice(), tx.To())
+	}
+
+	logs, err := backend.FilterLogs(ctx, ethereum.FilterQuery{Addresses: []common.Address{address}, FromBlock: big.NewInt(1)})
+	if err != nil || len(logs) != 1 || logs[0].Address != address {
+		t.Fatalf("logs %v %v", logs, err)
+	}
+	hash := common.HexToHash("0x01")
+	if _, err := backend.FilterLogs(ctx, ethereum.FilterQuery{BlockHash: &hash, FromBlock: big.NewInt(1)}); err == nil {
+		t.Error("filtered a block hash and a range")
+	}
+	if _, err := backend.SubscribeFilterLogs(ctx, ethereum.FilterQuery{}, make(chan types.Log)); err == nil {
+		t.Error("subscribed over http")
+	}
+}
+
+func TestContractHelpers(t *testing.T) {
+	api := &contractAPI{}
+	rpcServer := rpc.NewServer()
+	if err := rpcServer.RegisterName("eth", api); err != nil {
+		t.Fatal(err)
+	}
+	if err := rpcServer.RegisterName("net", &countingAPI{}); err != nil {
+		t.Fatal(err)
+	}
+	node := httptest.NewServer(rpcServer)
+	defer node.Close()
+	c, err := rpc.Dial(node.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	worm := client.NewClientWithRPC(priKey, c)
+	defer worm.CloseConnect()
+	ctx := context.Background()
+
+	parsed, err := client.ParseABI(counterABI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := crypto.HexToECDSA(priKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	account := crypto.PubkeyToAddress(key.PublicKey)
+	address, hash, err := worm.DeployContract(ctx, parsed, []byte{0x60, 0x80})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(api.sent) != 1 || api.sent[0].Hash().Hex() != hash {
+		t.Fatalf("sent %d transactions", len(api.sent))
+	}
+	deploy := api.sent[0]
+	if deploy.To() != nil || deploy.Gas() != 50000 || deploy.Nonce() != 3 || string(deploy.Data()) != "\x60\x80" {
+		t.Errorf("deployment to %v gas %d nonce %d data %x", deploy.To(), deploy.Gas(), deploy.Nonce(), deploy.Data())
+	}
+	if want := crypto.CreateAddress(account, 3); address != want {
+		t.Errorf("contract address %s, want %s", address.Hex(), want.Hex())
+	}
+
+	if _, err := worm.TransactContract(ctx, address.Hex(), parsed, "set", big.NewInt(7)); err != nil {
+		t.Fatal(err)
+	}
+	set := api.sent[len(api.sent)-1]
+	if set.To() == nil || *set.To() != address {
+		t.Fatalf("call sent to %v", set.To())
+	}
+	if args, err := parsed.Methods["set"].Inputs.Unpack(set.Data()[4:]); err != nil || args[0].(*big.Int).Int64() != 7 {
+		t.Errorf("call arguments %v %v", args, err)
+	}
+	if _, err := worm.TransactContract(ctx, address.Hex(), parsed, "set", "seven"); err == nil {
+		t.Error("sent a call with invalid arguments")
+	}
+
+	out, err := worm.CallContractMethod(ctx, address.Hex(), parsed, "value")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value := out[0].(*big.Int); value.Int64() != 42 {
+		t.Errorf("value %s, want 42", value)
+	}
+}
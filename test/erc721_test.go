@@ -0,0 +1,89 @@
+package test
+
+import (
+	"context"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/erbieio/erb-client/v2/client"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// erc721API serves an ERC-721 contract whose token 5 is owned by the buyer.
+type erc721API struct {
+	contractAPI
+}
+
+func (api *erc721API) Call(args map[string]interface{}, block rpc.BlockNumberOrHash) (hexutil.Bytes, error) {
+	input, _ := args["input"].(string)
+	if input == "" {
+		input, _ = args["data"].(string)
+	}
+	data, err := hexutil.Decode(input)
+	if err != nil {
+		return nil, err
+	}
+	method, err := client.ERC721.MethodById(data[:4])
+	if err != nil {
+		return nil, err
+	}
+	switch method.Name {
+	case "ownerOf":
+		return method.Outputs.Pack(common.HexToAddress(buyerAddress))
+	case "tokenURI":
+		return method.Outputs.Pack("ipfs://token/5")
+	default:
+		return method.Outputs.Pack(big.NewInt(1))
+	}
+}
+
+func TestERC721(t *testing.T) {
+	api := &erc721API{}
+	rpcServer := rpc.NewServer()
+	if err := rpcServer.RegisterName("eth", api); err != nil {
+		t.Fatal(err)
+	}
+	if err := rpcServer.RegisterName("net", &countingAPI{}); err != nil {
+		t.Fatal(err)
+	}
+	node := httptest.NewServer(rpcServer)
+	defer node.Close()
+	c, err := rpc.Dial(node.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	worm := client.NewClientWithRPC(buyerPriKey, c)
+	defer worm.CloseConnect()
+	ctx := context.Background()
+
+	asset, err := worm.ERC721Asset(ctx, exchangeAddress, big.NewInt(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if asset.Kind != client.ERC721Token || asset.Owner != common.HexToAddress(buyerAddress) || asset.MetaURL != "ipfs://token/5" || asset.TokenID.Int64() != 5 {
+		t.Errorf("asset %+v", asset)
+	}
+	balance, err := worm.ERC721BalanceOf(ctx, exchangeAddress, buyerAddress)
+	if err != nil || balance.Int64() != 1 {
+		t.Errorf("balance %v %v", balance, err)
+	}
+
+	if _, err := worm.TransferERC721(ctx, exchangeAddress, sellerAddress, big.NewInt(5)); err != nil {
+		t.Fatal(err)
+	}
+	tx := api.sent[0]
+	method, err := client.ERC721.MethodById(tx.Data()[:4])
+	if err != nil || method.Name != "safeTransferFrom" {
+		t.Fatalf("sent %v %v", method, err)
+	}
+	args, err := method.Inputs.Unpack(tx.Data()[4:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if args[0] != common.HexToAddress(buyerAddress) || args[1] != common.HexToAddress(sellerAddress) || args[2].(*big.Int).Int64() != 5 {
+		t.Errorf("safeTransferFrom arguments %v", args)
+	}
+}
@@ -0,0 +1,272 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/erbieio/erb-client/v2/client"
+	"github.com/erbieio/erb-client/v2/simulated"
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+func TestSentinelErrors(t *testing.T) {
+	seller := common.HexToAddress(sellerAddress)
+	backend := simulated.NewBackend(map[common.Address]*big.Int{
+		seller:                               big.NewInt(1e18),
+		common.HexToAddress(buyerAddress):    big.NewInt(1e15),
+		common.HexToAddress(exchangeAddress): types2.ERB(10).Wei(),
+	})
+	defer backend.Close()
+	ctx := context.Background()
+	worm := backend.Client(sellerPriKey)
+	buyer := backend.Client(buyerPriKey)
+	exchanger := backend.Client(exchangerPriKey)
+
+	if _, err := buyer.NormalTransactionCtx(ctx, sellerAddress, types2.ERB(1), ""); !errors.Is(err, client.ErrInsufficientBalance) {
+		t.Errorf("sending more than the balance: %v, want ErrInsufficientBalance", err)
+	}
+
+	if _, err := worm.MintCtx(ctx, 10, "/ipfs/ddfd90be9408b4", ""); err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+	nft := "0x0000000000000000000000000000000000000001"
+	if err := worm.CheckNFTOwner(ctx, nft, sellerAddress); err != nil {
+		t.Errorf("owner check of the seller: %v", err)
+	}
+	if err := worm.CheckNFTOwner(ctx, nft, buyerAddress); !errors.Is(err, client.ErrNotNFTOwner) {
+		t.Errorf("owner check of the buyer: %v, want ErrNotNFTOwner", err)
+	}
+
+	order, err := buyer.Wallet.SignBuyerAmount(types2.ERB(1), nft, exchangeAddress, "0x100", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := worm.CheckBuyer(ctx, order); !errors.Is(err, client.ErrExchangerNotOpen) {
+		t.Errorf("order of a closed exchanger: %v, want ErrExchangerNotOpen", err)
+	}
+	if _, err := exchanger.TokenPledgeCtx(ctx, common.HexToAddress(exchangeAddress), "", "exchanger", "https://exchanger", types2.ERB(1), 100); err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+	if err := worm.CheckBuyer(ctx, order); !errors.Is(err, client.ErrInsufficientBalance) {
+		t.Errorf("order of a buyer without funds: %v, want ErrInsufficientBalance", err)
+	}
+	expired, err := buyer.Wallet.SignBuyerAmount(types2.ERB(1), nft, exchangeAddress, "0x2", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := worm.CheckBuyer(ctx, expired); !errors.Is(err, client.ErrOrderExpired) {
+		t.Errorf("expired order: %v, want ErrOrderExpired", err)
+	}
+
+	auth, err := exchanger.Wallet.SignExchanger(exchangeAddress, sellerAddress, "0x100")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := worm.CheckExchangerAuth(ctx, auth, exchangeAddress); err != nil {
+		t.Errorf("exchanger authorization: %v", err)
+	}
+	if err := buyer.CheckExchangerAuth(ctx, auth, exchangeAddress); !errors.Is(err, client.ErrUnauthorizedExchange) {
+		t.Errorf("authorization of another account: %v, want ErrUnauthorizedExchange", err)
+	}
+
+	seller2, err := worm.Wallet.SignSeller2Amount(types2.ERB(1), "0xa", "/ipfs/qqqqqqqqqq", "0", exchangeAddress1, "0x100")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := worm.FoundryExchangeCtx(ctx, order, seller2, buyerAddress); !errors.Is(err, client.ErrUnauthorizedExchange) {
+		t.Errorf("orders of different exchangers: %v, want ErrUnauthorizedExchange", err)
+	}
+}
+
+func TestNotConnected(t *testing.T) {
+	ctx := context.Background()
+	if _, err := client.NewClient(priKey, "").Balance(ctx, sellerAddress); !errors.Is(err, client.ErrNotConnected) {
+		t.Errorf("query of a client without node: %v, want ErrNotConnected", err)
+	}
+	worm := client.NewClient(priKey, "bad://127.0.0.1")
+	if _, err := worm.BlockNumber(ctx); !errors.Is(err, client.ErrNotConnected) {
+		t.Errorf("query of a client whose dial failed: %v, want ErrNotConnected", err)
+	}
+	if _, err := worm.GetBlockByNumber(ctx, nil); !errors.Is(err, client.ErrNotConnected) {
+		t.Errorf("block of a client whose dial failed: %v, want ErrNotConnected", err)
+	}
+	if _, err := worm.NormalTransactionCtx(ctx, tempAddress, types2.ERB(1), ""); !errors.Is(err, client.ErrNotConnected) {
+		t.Errorf("transaction of a client whose dial failed: %v, want ErrNotConnected", err)
+	}
+	worm.CloseConnect()
+	if _, err := client.Dial(priKey, "bad://127.0.0.1"); err == nil {
+		t.Error("dialing an invalid url succeeded")
+	}
+
+	backend := simulated.NewBackend(nil)
+	defer backend.Close()
+	if _, err := backend.Client(priKey).BlockByNumber(ctx, big.NewInt(100)); err == nil {
+		t.Error("query of a missing block succeeded")
+	}
+}
+
+func TestWrongNetwork(t *testing.T) {
+	backend := simulated.NewBackend(map[common.Address]*big.Int{
+		common.HexToAddress(sellerAddress): big.NewInt(1e18),
+	})
+	defer backend.Close()
+	ctx := context.Background()
+	worm := backend.Client(sellerPriKey)
+
+	worm.ExpectChainID(big.NewInt(1))
+	if _, err := worm.NormalTransactionCtx(ctx, tempAddress, types2.Wei(big.NewInt(1)), ""); !errors.Is(err, client.ErrWrongNetwork) {
+		t.Errorf("transaction to a node of another chain: %v, want ErrWrongNetwork", err)
+	}
+	if _, err := worm.WithPriKey(sellerPriKey).Tx().Pay(tempAddress, big.NewInt(1)).Send(ctx); !errors.Is(err, client.ErrWrongNetwork) {
+		t.Errorf("transaction of a client sharing the connection: %v, want ErrWrongNetwork", err)
+	}
+
+	worm.ExpectChainID(simulated.DefaultChainID)
+	if _, err := worm.NormalTransactionCtx(ctx, tempAddress, types2.Wei(big.NewInt(1)), ""); err != nil {
+		t.Errorf("transaction to the expected chain: %v", err)
+	}
+	signer := types.NewEIP155Signer(big.NewInt(1))
+	key, _ := crypto.HexToECDSA(sellerPriKey)
+	tx, err := types.SignTx(types.NewTransaction(1, common.HexToAddress(tempAddress), big.NewInt(1), 21000, big.NewInt(1e9), nil), signer, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := worm.SendTransaction(ctx, tx); !errors.Is(err, client.ErrWrongNetwork) {
+		t.Errorf("transaction signed for another chain: %v, want ErrWrongNetwork", err)
+	}
+
+	worm.ExpectChainID(nil)
+	if _, err := worm.NormalTransactionCtx(ctx, tempAddress, types2.Wei(big.NewInt(1)), ""); err != nil {
+		t.Errorf("transaction without expected chain: %v", err)
+	}
+}
+
+func TestRPCError(t *testing.T) {
+	backend := simulated.NewBackend(map[common.Address]*big.Int{
+		common.HexToAddress(buyerAddress): big.NewInt(1e15),
+	})
+	defer backend.Close()
+	ctx := context.Background()
+	worm := backend.Client(buyerPriKey)
+
+	var rpcErr *client.RPCError
+	_, err := worm.GetRawBlock(ctx, client.Latest)
+	if !errors.As(err, &rpcErr) || rpcErr.Code != -32601 || rpcErr.Method != "debug_getRawBlock" {
+		t.Errorf("request of a missing method: %#v", err)
+	}
+	_, err = worm.NormalTransactionCtx(ctx, sellerAddress, types2.ERB(1), "")
+	if !errors.As(err, &rpcErr) || rpcErr.Code != -32000 || rpcErr.Method != "eth_sendRawTransaction" {
+		t.Errorf("rejected transaction: %#v", err)
+	}
+	if !errors.Is(err, client.ErrInsufficientBalance) || err.Error() != "insufficient balance: insufficient funds for gas * price + value" {
+		t.Errorf("rejected transaction: %v, want ErrInsufficientBalance with the message of the node", err)
+	}
+	if _, err := client.NewClient(priKey, "").BlockNumber(ctx); errors.As(err, &rpcErr) {
+		t.Errorf("error without a node is an RPCError: %v", err)
+	}
+}
+
+// nullEthAPI answers the lookups of the client with null, as the node does for unknown
+// accounts, blocks and transactions.
+type nullEthAPI struct{}
+
+func (nullEthAPI) GetAccountInfo(address common.Address, block json.RawMessage) *types2.Account {
+	return nil
+}
+
+func (nullEthAPI) GetValidator(block json.RawMessage) *types2.ValidatorList { return nil }
+
+func (nullEthAPI) GetTransactionReceipt(hash common.Hash) *types.Receipt { return nil }
+
+func (nullEthAPI) GetBlockByNumber(number json.RawMessage, fullTx bool) map[string]interface{} {
+	return nil
+}
+
+func TestNotFoundErrors(t *testing.T) {
+	rpcServer := rpc.NewServer()
+	if err := rpcServer.RegisterName("eth", nullEthAPI{}); err != nil {
+		t.Fatal(err)
+	}
+	node := httptest.NewServer(rpcServer)
+	defer node.Close()
+	c, err := rpc.Dial(node.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	worm := client.NewClientWithRPC("", c)
+	defer worm.CloseConnect()
+	ctx := context.Background()
+
+	hash := common.HexToHash("0x0102")
+	_, accountErr := worm.GetAccountInfo(ctx, buyerAddress, 16)
+	_, validatorsErr := worm.GetValidators(ctx, 16)
+	_, receiptErr := worm.TransactionReceipt(ctx, hash.Hex())
+	_, blockErr := worm.GetBlockByNumber(ctx, big.NewInt(16))
+	for _, test := range []struct {
+		err  error
+		want []string
+	}{
+		{accountErr, []string{buyerAddress, "0x10"}},
+		{validatorsErr, []string{"0x10"}},
+		{receiptErr, []string{hash.Hex()}},
+		{blockErr, []string{"0x10"}},
+	} {
+		if !errors.Is(test.err, ethereum.NotFound) {
+			t.Errorf("got %v, want ethereum.NotFound", test.err)
+			continue
+		}
+		for _, want := range test.want {
+			if !strings.Contains(test.err.Error(), want) {
+				t.Errorf("%q does not name the query %s", test.err, want)
+			}
+		}
+	}
+}
+
+// nonceFailingEthAPI fails the nonce reads and records the transactions sent.
+type nonceFailingEthAPI struct {
+	sent *int
+}
+
+func (nonceFailingEthAPI) GetTransactionCount(address common.Address, block json.RawMessage) (*hexutil.Uint64, error) {
+	return nil, errors.New("nonce unavailable")
+}
+
+func (api nonceFailingEthAPI) SendRawTransaction(data hexutil.Bytes) common.Hash {
+	*api.sent++
+	return common.Hash{}
+}
+
+func TestNonceError(t *testing.T) {
+	sent := 0
+	rpcServer := rpc.NewServer()
+	if err := rpcServer.RegisterName("eth", nonceFailingEthAPI{&sent}); err != nil {
+		t.Fatal(err)
+	}
+	worm := client.NewClientWithRPC(priKey, rpc.DialInProc(rpcServer))
+	defer worm.CloseConnect()
+	ctx := context.Background()
+
+	if _, err := worm.NormalTransactionCtx(ctx, tempAddress, types2.ERB(1), ""); err == nil || !strings.Contains(err.Error(), "nonce unavailable") {
+		t.Errorf("transfer with a failed nonce read: %v", err)
+	}
+	if _, err := worm.MintCtx(ctx, 100, "/ipfs/meta", ""); err == nil || !strings.Contains(err.Error(), "nonce unavailable") {
+		t.Errorf("mint with a failed nonce read: %v", err)
+	}
+	if sent != 0 {
+		t.Errorf("%d transactions sent without a nonce", sent)
+	}
+}
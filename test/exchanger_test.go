@@ -0,0 +1,108 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/erbieio/erb-client/v2/client"
+	"github.com/erbieio/erb-client/v2/simulated"
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestExchangerIndex(t *testing.T) {
+	exchanger, seller, other := common.HexToAddress(exchangeAddress), common.HexToAddress(sellerAddress), common.HexToAddress(priAddress)
+	backend := simulated.NewBackend(map[common.Address]*big.Int{
+		exchanger: types2.ERB(10).Wei(),
+		seller:    types2.ERB(10).Wei(),
+		other:     types2.ERB(10).Wei(),
+	})
+	defer backend.Close()
+	ctx := context.Background()
+	// Block 1 opens an exchanger, block 2 holds a plain pledge.
+	if _, err := backend.Client(exchangerPriKey).TokenPledgeCtx(ctx, exchanger, "", "exchanger", "https://exchanger", types2.ERB(1), 100); err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+	if _, err := backend.Client(sellerPriKey).TokenPledgeCtx(ctx, seller, "", "", "", types2.ERB(1), 0); err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+	counter := &blockCounter{node: backend.Handler()}
+	node := httptest.NewServer(counter)
+	defer node.Close()
+	worm := client.NewClient("", node.URL)
+	defer worm.CloseConnect()
+
+	idx := client.NewExchangerIndex(worm, client.ExchangerIndexConfig{})
+	if err := idx.Update(ctx, 2); err != nil {
+		t.Fatal(err)
+	}
+	exchangers, err := idx.List(ctx, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(exchangers) != 1 || exchangers[0].Address != exchanger || exchangers[0].Name != "exchanger" || exchangers[0].FeeRate != 100 {
+		t.Fatalf("exchangers %+v, want %s", exchangers, exchanger)
+	}
+	if exchangers, err := idx.List(ctx, 0); err != nil || len(exchangers) != 0 {
+		t.Errorf("exchangers at genesis %v, %v", exchangers, err)
+	}
+	checkpoint := idx.Checkpoint()
+	if checkpoint.Next != 3 || len(checkpoint.Candidates) != 2 {
+		t.Fatalf("checkpoint %+v, want the 2 pledging accounts before block 3", checkpoint)
+	}
+
+	// A restored index only scans the new blocks.
+	if _, err := backend.Client(priKey).TokenPledgeCtx(ctx, other, "", "other", "https://other", types2.ERB(1), 200); err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var restored client.ExchangerCheckpoint
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Fatal(err)
+	}
+	fetched := counter.fetched.Load()
+	idx = client.NewExchangerIndex(worm, client.ExchangerIndexConfig{Checkpoint: &restored})
+	if err := idx.Update(ctx, 3); err != nil {
+		t.Fatal(err)
+	}
+	if n := counter.fetched.Load() - fetched; n != 1 {
+		t.Errorf("%d blocks fetched after the checkpoint, want 1", n)
+	}
+	exchangers, err = idx.List(ctx, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(exchangers) != 2 {
+		t.Fatalf("%d exchangers after the restore, want 2", len(exchangers))
+	}
+
+	// The blocks before Start are not scanned.
+	idx = client.NewExchangerIndex(worm, client.ExchangerIndexConfig{Start: 2})
+	if err := idx.Update(ctx, 3); err != nil {
+		t.Fatal(err)
+	}
+	exchangers, err = idx.List(ctx, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(exchangers) != 1 || exchangers[0].Address != other {
+		t.Errorf("exchangers %+v from block 2, want %s", exchangers, other)
+	}
+
+	exchangers, err = worm.ListExchangers(ctx, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(exchangers) != 2 {
+		t.Errorf("%d listed exchangers, want 2", len(exchangers))
+	}
+}
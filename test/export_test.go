@@ -0,0 +1,85 @@
+package test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/erbieio/erb-client/v2/client"
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// personalSign signs like personal_sign of MetaMask, with a V of 27 or 28 unless raw.
+func personalSign(t *testing.T, priKey, data string, raw bool) string {
+	key, err := crypto.HexToECDSA(priKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := crypto.Sign(accounts.TextHash(hexutil.MustDecode(data)), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !raw {
+		sig[64] += 27
+	}
+	return hexutil.Encode(sig)
+}
+
+func TestOrderExport(t *testing.T) {
+	seller1, err := types2.NewSeller1(types2.ERB(2), "0x0000000000000000000000000000000000000001", exchangeAddress, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := client.NewWallet(sellerPriKey).SignSeller1Order(seller1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The exported order goes to the page as JSON and comes back with the signature.
+	exported, err := client.ExportOrder(seller1, sellerAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(exported)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var u client.UnsignedOrder
+	if err := json.Unmarshal(data, &u); err != nil {
+		t.Fatal(err)
+	}
+	if u.Message != seller1.Message() || u.Hash != seller1.SigningHash() || strings.Contains(string(u.Order), "sig") {
+		t.Errorf("exported %s", data)
+	}
+	for _, raw := range []bool{false, true} {
+		signed, signer, err := client.ImportOrder(&u, personalSign(t, sellerPriKey, u.Data, raw))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(signed) != string(want) || signer != common.HexToAddress(sellerAddress) {
+			t.Errorf("imported %s by %s, want %s", signed, signer.Hex(), want)
+		}
+	}
+	if _, _, err := client.ImportOrder(&u, personalSign(t, buyerPriKey, u.Data, false)); err == nil {
+		t.Error("imported an order signed by another account")
+	}
+	tampered := u
+	tampered.Order = json.RawMessage(strings.Replace(string(u.Order), seller1.Amount, "0x1", 1))
+	if _, _, err := client.ImportOrder(&tampered, personalSign(t, sellerPriKey, u.Data, false)); err == nil {
+		t.Error("imported a signature of another order")
+	}
+
+	if signer, err := client.VerifyOrder(client.OrderSeller1, want); err != nil || signer != common.HexToAddress(sellerAddress) {
+		t.Errorf("verified signer %s %v", signer.Hex(), err)
+	}
+	if _, err := client.VerifyOrder(client.OrderSeller1, u.Order); err == nil {
+		t.Error("verified an unsigned order")
+	}
+	if _, err := client.ExportOrder(*seller1, ""); err == nil {
+		t.Error("exported an order value")
+	}
+}
@@ -0,0 +1,48 @@
+package test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/erbieio/erb-client/v2/faucet"
+	"github.com/erbieio/erb-client/v2/simulated"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestFaucetNewAccounts(t *testing.T) {
+	backend := simulated.NewBackend(map[common.Address]*big.Int{
+		common.HexToAddress(priAddress): big.NewInt(1e18),
+	})
+	defer backend.Close()
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-time.After(10 * time.Millisecond):
+				backend.Commit()
+			}
+		}
+	}()
+
+	f, err := faucet.New(backend.Client(priKey), priKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.PollInterval = 10 * time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	accounts, err := f.NewAccounts(ctx, 3, big.NewInt(1e15))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, account := range accounts {
+		if balance := backend.Account(account.Address).Balance; balance.Cmp(big.NewInt(1e15)) != 0 {
+			t.Errorf("balance of %s is %s, want 1e15", account.Address, balance)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/erbieio/erb-client/v2/client"
+	"github.com/erbieio/erb-client/v2/simulated"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+func TestFetchPool(t *testing.T) {
+	ctx := context.Background()
+	pool := client.NewFetchPool(client.FetchConfig{MaxConcurrency: 4, TargetLatency: time.Second})
+	if got := pool.Concurrency(); got != 1 {
+		t.Fatalf("starts with %d requests, want 1", got)
+	}
+
+	var mu sync.Mutex
+	active, peak := 0, 0
+	fetch := func(fail bool) func(ctx context.Context, i int) error {
+		return func(ctx context.Context, i int) error {
+			mu.Lock()
+			if active++; active > peak {
+				peak = active
+			}
+			mu.Unlock()
+			time.Sleep(time.Millisecond)
+			mu.Lock()
+			active--
+			mu.Unlock()
+			if fail {
+				return errors.New("unavailable")
+			}
+			return nil
+		}
+	}
+	if errs := pool.Run(ctx, 100, fetch(false)); errs != nil {
+		t.Fatalf("got errors %v", errs)
+	}
+	if got := pool.Concurrency(); got != 4 {
+		t.Errorf("%d requests after fast successes, want 4", got)
+	}
+	if peak > 4 {
+		t.Errorf("ran %d requests in parallel, want at most 4", peak)
+	}
+
+	errs := pool.Run(ctx, 8, fetch(true))
+	if len(errs) != 8 || errs[0] == nil || errs[7] == nil {
+		t.Fatalf("got errors %v, want one per call", errs)
+	}
+	if got := pool.Concurrency(); got != 1 {
+		t.Errorf("%d requests after failures, want 1", got)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if errs := pool.Run(cancelled, 3, fetch(false)); len(errs) != 3 || !errors.Is(errs[2], context.Canceled) {
+		t.Errorf("cancelled run: got errors %v", errs)
+	}
+
+	backend := simulated.NewBackend(nil)
+	defer backend.Close()
+	for i := 0; i < 10; i++ {
+		backend.Commit()
+	}
+	worm := backend.Client("")
+	results, err := worm.GetBlocks(ctx, pool, 1, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, result := range results {
+		if result.Number != uint64(i+1) || result.Block.NumberU64() != result.Number {
+			t.Fatalf("result %d is block %d", i, result.Number)
+		}
+	}
+	if _, err := worm.GetReceipts(ctx, pool, []common.Hash{common.HexToHash("0x01")}); err == nil {
+		t.Error("receipt of an unknown transaction was fetched")
+	}
+}
+
+func TestGetAccountInfoBatch(t *testing.T) {
+	alloc := make(map[common.Address]*big.Int)
+	var addrs []string
+	for i := 1; i <= 250; i++ {
+		address := common.BigToAddress(big.NewInt(int64(0x10000 + i)))
+		alloc[address] = big.NewInt(int64(i))
+		addrs = append(addrs, address.Hex())
+	}
+	backend := simulated.NewBackend(alloc)
+	defer backend.Close()
+	backend.Commit()
+	worm := backend.Client("")
+
+	results, err := worm.GetAccountInfoBatch(context.Background(), addrs, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != len(addrs) {
+		t.Fatalf("got %d results, want %d", len(results), len(addrs))
+	}
+	for i, result := range results {
+		if result.Err != nil {
+			t.Fatalf("account %s: %v", addrs[i], result.Err)
+		}
+		if result.Address.Hex() != addrs[i] || result.Account.Balance.Int64() != int64(i+1) {
+			t.Fatalf("result %d is %s with balance %s", i, result.Address.Hex(), result.Account.Balance)
+		}
+	}
+
+	if _, err := client.NewClient("", "").GetAccountInfoBatch(context.Background(), addrs[:1], 1); !errors.Is(err, client.ErrNotConnected) {
+		t.Errorf("got %v without a node, want ErrNotConnected", err)
+	}
+}
+
+// blockReceiptsAPI answers eth_getBlockReceipts with receipts, in reverse order if reversed.
+type blockReceiptsAPI struct {
+	receipts []*types.Receipt
+	reversed bool
+	calls    int
+}
+
+func (api *blockReceiptsAPI) GetBlockReceipts(rpc.BlockNumberOrHash) []*types.Receipt {
+	api.calls++
+	if !api.reversed {
+		return api.receipts
+	}
+	reversed := make([]*types.Receipt, len(api.receipts))
+	for i, receipt := range api.receipts {
+		reversed[len(reversed)-1-i] = receipt
+	}
+	return reversed
+}
+
+func TestFetchReceipts(t *testing.T) {
+	backend := simulated.NewBackend(map[common.Address]*big.Int{
+		common.HexToAddress(sellerAddress): big.NewInt(1e18),
+	})
+	defer backend.Close()
+	worm := backend.Client(sellerPriKey)
+	for i := 0; i < 5; i++ {
+		if _, err := worm.Mint(10, "/ipfs/ddfd90be9408b4", ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	backend.Commit()
+	ctx := context.Background()
+	block, err := worm.BlockByNumber(ctx, big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(block.Transactions()) != 5 {
+		t.Fatalf("block has %d transactions, want 5", len(block.Transactions()))
+	}
+
+	// The simulated node has no eth_getBlockReceipts, the receipts are fetched one by one.
+	receipts, err := worm.FetchReceipts(ctx, block, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, receipt := range receipts {
+		if receipt.TxHash != block.Transactions()[i].Hash() {
+			t.Fatalf("receipt %d is of %s", i, receipt.TxHash.Hex())
+		}
+	}
+
+	api := &blockReceiptsAPI{receipts: receipts}
+	rpcServer := rpc.NewServer()
+	if err := rpcServer.RegisterName("eth", api); err != nil {
+		t.Fatal(err)
+	}
+	node := httptest.NewServer(rpcServer)
+	defer node.Close()
+	c, err := rpc.Dial(node.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stub := client.NewClientWithRPC("", c)
+	defer stub.CloseConnect()
+	got, err := stub.FetchReceipts(ctx, block, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if api.calls != 1 || len(got) != 5 || got[4].TxHash != receipts[4].TxHash {
+		t.Fatalf("got %d receipts in %d requests", len(got), api.calls)
+	}
+	api.reversed = true
+	if _, err := stub.FetchReceipts(ctx, block, 3); err == nil {
+		t.Error("receipts out of order were returned")
+	}
+}
@@ -0,0 +1,65 @@
+package test
+
+import (
+	"context"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/erbieio/erb-client/v2/client"
+	"github.com/erbieio/erb-client/v2/fixture"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+type fixtureEthAPI struct{ head uint64 }
+
+func (api *fixtureEthAPI) BlockNumber() hexutil.Uint64 {
+	api.head++
+	return hexutil.Uint64(api.head)
+}
+
+func TestFixtureRecordReplay(t *testing.T) {
+	ctx := context.Background()
+	rpcServer := rpc.NewServer()
+	if err := rpcServer.RegisterName("eth", &fixtureEthAPI{head: 100}); err != nil {
+		t.Fatal(err)
+	}
+	node := httptest.NewServer(rpcServer)
+
+	recorder := fixture.NewRecorder(nil)
+	c, err := fixture.Dial(node.URL, recorder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	worm := client.NewClientWithRPC("", c)
+	for _, want := range []uint64{101, 102} {
+		if got, err := worm.BlockNumber(ctx); err != nil || got != want {
+			t.Fatalf("record: got %d %v, want %d", got, err, want)
+		}
+	}
+	path := filepath.Join(t.TempDir(), "fixture.json")
+	if err := recorder.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	worm.CloseConnect()
+	node.Close()
+
+	replayer, err := fixture.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err = fixture.Dial("http://fixture", replayer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	worm = client.NewClientWithRPC("", c)
+	for _, want := range []uint64{101, 102, 102} {
+		if got, err := worm.BlockNumber(ctx); err != nil || got != want {
+			t.Fatalf("replay: got %d %v, want %d", got, err, want)
+		}
+	}
+	if _, err := worm.ChainID(ctx); err == nil {
+		t.Error("replay of an unrecorded call succeeded")
+	}
+}
@@ -0,0 +1,101 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/erbieio/erb-client/v2/loadgen"
+	"github.com/erbieio/erb-client/v2/simulated"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestLoadgen(t *testing.T) {
+	alloc := make(map[common.Address]*big.Int)
+	keys := make([]string, 4)
+	accounts := make(map[common.Address]bool)
+	for i := range keys {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys[i] = hex.EncodeToString(crypto.FromECDSA(key))
+		account := crypto.PubkeyToAddress(key.PublicKey)
+		alloc[account] = new(big.Int).Mul(big.NewInt(100), big.NewInt(1e18))
+		accounts[account] = true
+	}
+	backend := simulated.NewBackend(alloc)
+	defer backend.Close()
+	worm := backend.Client(keys[0])
+	nfts := []string{"0x0000000000000000000000000000000000000001", "0x0000000000000000000000000000000000000002"}
+	for range nfts {
+		if _, err := worm.Mint(0, "/ipfs/ddfd90be9408b4", ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	backend.Commit()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-time.After(2 * time.Millisecond):
+				backend.Commit()
+			}
+		}
+	}()
+
+	g, err := loadgen.New(worm, loadgen.Config{
+		Keys:         keys,
+		NFTs:         nfts,
+		Count:        40,
+		Duration:     10 * time.Second,
+		PollInterval: 2 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := g.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Total.Sent != 40 || report.Total.Confirmed != 40 {
+		t.Fatalf("sent %d, confirmed %d, failures %v", report.Total.Sent, report.Total.Confirmed, report.Failures)
+	}
+	for _, kind := range []loadgen.Kind{loadgen.Mint, loadgen.Transfer, loadgen.Trade, loadgen.Pledge} {
+		if s := report.Kinds[kind]; s == nil || s.Confirmed == 0 {
+			t.Errorf("no %s was confirmed", kind)
+		}
+	}
+	if report.TPS <= 0 || report.Total.Latency.P50 <= 0 || report.Total.Latency.Max < report.Total.Latency.P99 {
+		t.Errorf("tps %f, latency %+v", report.TPS, report.Total.Latency)
+	}
+	for _, nft := range nfts {
+		if owner := backend.Account(common.HexToAddress(nft)).Nft.Owner; !accounts[owner] {
+			t.Errorf("nft %s is owned by %s", nft, owner.Hex())
+		}
+	}
+	var out bytes.Buffer
+	report.Print(&out)
+	if !bytes.Contains(out.Bytes(), []byte("total")) {
+		t.Errorf("report %q", out.String())
+	}
+
+	if _, err := loadgen.New(worm, loadgen.Config{Keys: keys[:1], Mix: map[loadgen.Kind]int{loadgen.Trade: 1}}); err == nil {
+		t.Error("trades with a single key were accepted")
+	}
+	g, err = loadgen.New(worm, loadgen.Config{Keys: keys, NFTs: []string{"0x0000000000000000000000000000000000000099"}, Count: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.Run(context.Background()); err == nil {
+		t.Error("ran with an nft not owned by a key")
+	}
+}
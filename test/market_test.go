@@ -0,0 +1,84 @@
+package test
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/erbieio/erb-client/v2/market"
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestMarketStats(t *testing.T) {
+	m := market.New(market.Config{Windows: []time.Duration{time.Hour, 24 * time.Hour}})
+	creator := common.HexToAddress(sellerAddress)
+	exchanger := common.HexToAddress(exchangeAddress)
+	start := time.Unix(1650000000, 0)
+	trades := []struct {
+		buyer string
+		price int64
+		at    time.Duration
+	}{
+		{buyerAddress, 300, 0},
+		{buyerAddress, 100, 23 * time.Hour},
+		{tempAddress, 200, 23*time.Hour + 30*time.Minute},
+	}
+	for _, tt := range trades {
+		m.Add(&types2.Trade{
+			Buyer:     common.HexToAddress(tt.buyer),
+			Creator:   creator,
+			Exchanger: exchanger,
+			Price:     big.NewInt(tt.price),
+		}, start.Add(tt.at))
+	}
+
+	stats := m.Collection(creator.Hex())
+	hour, day := stats[0], stats[1]
+	if hour.Trades != 2 || hour.Volume.Int64() != 300 || hour.Floor.Int64() != 100 || hour.UniqueBuyers != 2 {
+		t.Errorf("hour stats %+v", hour)
+	}
+	if day.Trades != 3 || day.Volume.Int64() != 600 || day.Floor.Int64() != 100 || day.UniqueBuyers != 2 {
+		t.Errorf("day stats %+v", day)
+	}
+
+	m.Advance(start.Add(48 * time.Hour))
+	if stats := m.Exchanger(exchanger); stats[1].Trades != 0 || stats[1].Floor != nil {
+		t.Errorf("stats after the windows passed %+v", stats[1])
+	}
+	if collections := m.Collections(); len(collections) != 0 {
+		t.Errorf("collections %v after the windows passed", collections)
+	}
+}
+
+func TestPriceHistory(t *testing.T) {
+	h := market.NewPriceHistory(nil)
+	creator := common.HexToAddress(sellerAddress)
+	start := time.Unix(1650000000, 0)
+	nft := "0x0000000000000000000000000000000000000001"
+	for i, price := range []int64{300, 100, 200} {
+		address := nft
+		if i == 1 {
+			address = "0x0000000000000000000000000000000000000002"
+		}
+		trade := &types2.Trade{
+			TxHash:     common.BigToHash(big.NewInt(int64(i + 1))),
+			Creator:    creator,
+			NFTAddress: address,
+			Price:      big.NewInt(price),
+		}
+		// Added out of order, the series are ordered by time.
+		if err := h.Add(context.Background(), trade, start.Add(time.Duration(2-i)*time.Hour)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	points := h.NFT("0x"+strings.ToUpper(nft[2:]), time.Time{}, time.Time{})
+	if len(points) != 2 || points[0].Price.Int64() != 200 || points[1].Price.Int64() != 300 {
+		t.Fatalf("nft series %v", points)
+	}
+	if points := h.Collection(creator, start.Add(time.Hour), time.Time{}); len(points) != 2 {
+		t.Errorf("collection series from the second hour has %d points, want 2", len(points))
+	}
+}
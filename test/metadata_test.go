@@ -0,0 +1,91 @@
+package test
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/erbieio/erb-client/v2/metadata"
+	"github.com/erbieio/erb-client/v2/simulated"
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestMetadataValidate(t *testing.T) {
+	for _, c := range []struct {
+		doc        string
+		compatible bool
+		// issues are the fields of the issues reported.
+		issues []string
+	}{
+		{`{"name":"Cat","description":"A cat","image":"ipfs://Qm1","attributes":[{"trait_type":"Eyes","value":"green"},{"trait_type":"Age","value":3,"display_type":"number","max_value":10}]}`, true, nil},
+		{`{"name":"Cat","description":"A cat","image_data":"<svg/>"}`, true, nil},
+		{`{"name":"Cat","image":"http://example.com/cat.png"}`, true, []string{"description", "image"}},
+		{`[1,2]`, false, []string{""}},
+		{`{"name":1,"description":"","image":"/ipfs/Qm1"}`, false, []string{"name", "description", "image"}},
+		{`{"name":"Cat","description":"A cat"}`, false, []string{"image"}},
+		{`{"name":"Cat","description":"A cat","image":"ftp://x/cat.png","background_color":"#fff"}`, false, []string{"image", "background_color"}},
+		{`{"name":"Cat","description":"A cat","image":"ipfs://Qm1","attributes":{"eyes":"green"}}`, false, []string{"attributes"}},
+		{`{"name":"Cat","description":"A cat","image":"ipfs://Qm1","attributes":[{"value":"green"},{"trait_type":"Age","value":"old","display_type":"number"},{"trait_type":"Level","value":12,"max_value":10},{"trait_type":"Kind"},{"trait_type":"Born","value":1,"display_type":"time"},{"trait_type":"Tags","value":["a"]}]}`, false,
+			[]string{"attributes[0].trait_type", "attributes[1].value", "attributes[2].value", "attributes[3].value", "attributes[4].display_type", "attributes[5].value"}},
+	} {
+		r := metadata.Validate([]byte(c.doc))
+		if r.Compatible() != c.compatible {
+			t.Errorf("%s: compatible %v, issues %v", c.doc, r.Compatible(), r.Issues)
+		}
+		var fields []string
+		for _, issue := range r.Issues {
+			fields = append(fields, issue.Field)
+		}
+		if len(fields) != len(c.issues) {
+			t.Errorf("%s: issues %v, want fields %v", c.doc, r.Issues, c.issues)
+			continue
+		}
+		for i := range fields {
+			if fields[i] != c.issues[i] {
+				t.Errorf("%s: issues %v, want fields %v", c.doc, r.Issues, c.issues)
+				break
+			}
+		}
+	}
+}
+
+func TestMetadataCheckNFT(t *testing.T) {
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ipfs/good":
+			w.Write([]byte(`{"name":"Cat","description":"A cat","image":"ipfs://Qm1"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer gateway.Close()
+	checker := metadata.NewChecker(metadata.Config{Gateway: gateway.URL})
+	ctx := context.Background()
+
+	if r, err := checker.Check(ctx, "ipfs://good"); err != nil || !r.Compatible() || len(r.Issues) != 0 {
+		t.Errorf("ipfs://good: %v %v", r, err)
+	}
+	if _, err := checker.Check(ctx, "ipfs://missing"); err == nil {
+		t.Error("checked missing metadata")
+	}
+
+	seller := common.HexToAddress(sellerAddress)
+	backend := simulated.NewBackend(map[common.Address]*big.Int{seller: types2.ERB(10).Wei()})
+	defer backend.Close()
+	worm := backend.Client(sellerPriKey)
+	if _, err := worm.Tx().Mint().MetaURL("/ipfs/good").Send(ctx); err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+	r, err := checker.CheckNFT(ctx, worm, "0x0000000000000000000000000000000000000001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The document is compatible, its gateway path meta URL is not.
+	if !r.Compatible() || len(r.Issues) != 1 || r.Issues[0].Severity != metadata.Warning {
+		t.Errorf("nft metadata issues %v", r.Issues)
+	}
+}
@@ -0,0 +1,96 @@
+package test
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/erbieio/erb-client/v2/client"
+	"github.com/erbieio/erb-client/v2/mobile"
+	"github.com/erbieio/erb-client/v2/simulated"
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+func TestMobile(t *testing.T) {
+	address, err := mobile.AddressOf(sellerPriKey)
+	if err != nil || address != common.HexToAddress(sellerAddress).Hex() {
+		t.Fatalf("address %s %v", address, err)
+	}
+	if _, err := mobile.NewWallet("not a key"); err == nil {
+		t.Error("created a wallet of an invalid key")
+	}
+	key, err := mobile.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mobile.AddressOf(key); err != nil {
+		t.Errorf("generated key %s: %v", key, err)
+	}
+
+	// The orders signed by the mobile wallet are those of the client wallet.
+	wallet, err := mobile.NewWallet(sellerPriKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nft := "0x0000000000000000000000000000000000000001"
+	got, err := wallet.SignSeller1("0.001", nft, exchangeAddress, "0x64")
+	if err != nil {
+		t.Fatal(err)
+	}
+	price, err := types2.ParseERB("0.001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := client.NewWallet(sellerPriKey).SignSeller1(price.Hex(), nft, exchangeAddress, "0x64")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("signed %s, want %s", got, want)
+	}
+	sig, err := wallet.Sign([]byte("hello"), sellerPriKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signer, err := mobile.RecoverAddress("hello", hexutil.Encode(sig)); err != nil || signer != address {
+		t.Errorf("recovered %s %v", signer, err)
+	}
+
+	backend := simulated.NewBackend(map[common.Address]*big.Int{
+		common.HexToAddress(sellerAddress): types2.ERB(10).Wei(),
+	})
+	defer backend.Close()
+	node := httptest.NewServer(backend.Handler())
+	defer node.Close()
+	c, err := mobile.Dial(sellerPriKey, node.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	c.SetTimeout(5000)
+
+	hash, err := c.SendERB(tempAddress, "1.5", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+	if balance, err := c.Balance(tempAddress); err != nil || balance != "1.5" {
+		t.Errorf("balance %s %v", balance, err)
+	}
+	receipt, err := c.Receipt(hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields struct {
+		Status string `json:"status"`
+	}
+	if err := json.Unmarshal([]byte(receipt), &fields); err != nil || fields.Status != "0x1" {
+		t.Errorf("receipt %s %v", receipt, err)
+	}
+	if number, err := c.BlockNumber(); err != nil || number != 1 {
+		t.Errorf("block number %d %v", number, err)
+	}
+}
@@ -0,0 +1,131 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/erbieio/erb-client/v2/client"
+	"github.com/erbieio/erb-client/v2/fixture"
+	"github.com/erbieio/erb-client/v2/monitor"
+	"github.com/erbieio/erb-client/v2/simulated"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestForkMonitor(t *testing.T) {
+	heads := []struct {
+		number       uint64
+		hash, parent string
+	}{
+		{10, "0x0a", "0x09"},
+		{11, "0x0b", "0x0a"},
+		{11, "0x1b", "0x0a"},
+		{12, "0x0c", "0x1b"},
+	}
+	var exchanges []*fixture.Exchange
+	for _, head := range heads {
+		exchanges = append(exchanges, &fixture.Exchange{
+			Method: "eth_getBlockByNumber",
+			Params: json.RawMessage(`["latest",false]`),
+			Result: json.RawMessage(fmt.Sprintf(`{"number":"%#x","hash":"%s","parentHash":"%s"}`,
+				head.number, common.HexToHash(head.hash).Hex(), common.HexToHash(head.parent).Hex())),
+		})
+	}
+	c, err := fixture.Dial("http://fixture", fixture.NewReplayer(exchanges))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := monitor.NewForkMonitor(client.NewClientWithRPC("", c), monitor.ForkConfig{})
+	var kinds []monitor.ForkKind
+	m.OnFork(func(ev *monitor.ForkEvent) { kinds = append(kinds, ev.Kind) })
+	for range heads {
+		if _, err := m.Check(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(kinds) != 2 || kinds[0] != monitor.ConflictingHash || kinds[1] != monitor.ParentMismatch {
+		t.Errorf("got fork events %v, want [conflicting hash parent mismatch]", kinds)
+	}
+	if stats := m.Stats(); stats.Heads != 4 || stats.Conflicts != 1 || stats.ParentMismatches != 1 {
+		t.Errorf("got stats %+v", stats)
+	}
+}
+
+func TestMempoolWatcher(t *testing.T) {
+	seller := common.HexToAddress(sellerAddress)
+	backend := simulated.NewBackend(map[common.Address]*big.Int{
+		seller: big.NewInt(1e18),
+	})
+	defer backend.Close()
+	worm := backend.Client(sellerPriKey)
+	nft := "0x0000000000000000000000000000000000000001"
+	if _, err := worm.Mint(10, "/ipfs/ddfd90be9408b4", ""); err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+
+	w := monitor.NewMempoolWatcher(worm, monitor.MempoolConfig{NFTs: []string{nft}})
+	if _, err := worm.Transfer(nft, buyerAddress); err != nil {
+		t.Fatal(err)
+	}
+	flagged, err := w.Check(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(flagged) != 1 || len(flagged[0].NFTs) != 1 || flagged[0].From != seller {
+		t.Fatalf("got flagged %+v, want the transfer of %s", flagged, nft)
+	}
+	if flagged, _ := w.Check(context.Background()); len(flagged) != 0 {
+		t.Errorf("transfer flagged again")
+	}
+}
+
+type gaugeMap map[string]float64
+
+func (g gaugeMap) SetGauge(name string, value float64) { g[name] = value }
+
+func TestHealthMonitor(t *testing.T) {
+	head, parent := common.HexToHash("0x65"), common.HexToHash("0x64")
+	exchanges := []*fixture.Exchange{{
+		Method: "eth_getBlockByNumber",
+		Params: json.RawMessage(`["latest",false]`),
+		Result: json.RawMessage(fmt.Sprintf(`{"number":"0x65","hash":"%s","parentHash":"%s","timestamp":"0x62599e85","gasUsed":"0x5208","gasLimit":"0xa410"}`,
+			head.Hex(), parent.Hex())),
+	}, {
+		Method: "eth_getBlockByHash",
+		Params: json.RawMessage(fmt.Sprintf(`["%s",false]`, parent.Hex())),
+		Result: json.RawMessage(fmt.Sprintf(`{"number":"0x64","hash":"%s","timestamp":"0x62599e80"}`, parent.Hex())),
+	}, {
+		Method: "txpool_status",
+		Result: json.RawMessage(`{"pending":"0x3","queued":"0x1"}`),
+	}}
+	c, err := fixture.Dial("http://fixture", fixture.NewReplayer(exchanges))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gauges := gaugeMap{}
+	m := monitor.NewHealthMonitor(client.NewClientWithRPC("", c), monitor.HealthConfig{Gauges: gauges, SkipValidators: true})
+	var stalls int
+	m.OnStall(func(s *monitor.HealthSample) { stalls++ })
+	for i := 0; i < 2; i++ {
+		if _, err := m.Sample(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s := m.Last()
+	if s.BlockInterval.Seconds() != 5 || s.GasUsedRatio != 0.5 || s.TxPoolPending != 3 || s.TxPoolQueued != 1 {
+		t.Errorf("got sample %+v", s)
+	}
+	// The head of the fixture is years old, the chain is stalled and reported once.
+	if !s.Stalled || stalls != 1 {
+		t.Errorf("stalled %v reported %d times, want once", s.Stalled, stalls)
+	}
+	if gauges[monitor.GaugeHeadNumber] != 101 || gauges[monitor.GaugeStalled] != 1 || gauges[monitor.GaugeTxPoolPending] != 3 {
+		t.Errorf("got gauges %v", gauges)
+	}
+	if _, ok := gauges[monitor.GaugeValidators]; ok {
+		t.Error("validator gauges set with SkipValidators")
+	}
+}
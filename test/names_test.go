@@ -0,0 +1,184 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/erbieio/erb-client/v2/client"
+	"github.com/erbieio/erb-client/v2/simulated"
+	"github.com/erbieio/erb-client/v2/tools"
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var ensStubABI, _ = abi.JSON(strings.NewReader(`[
+	{"type":"function","name":"resolver","inputs":[{"type":"bytes32"}],"outputs":[{"type":"address"}]},
+	{"type":"function","name":"addr","inputs":[{"type":"bytes32"}],"outputs":[{"type":"address"}]},
+	{"type":"function","name":"name","inputs":[{"type":"bytes32"}],"outputs":[{"type":"string"}]}
+]`))
+
+// ensAPI serves a registry at exchangeAddress whose resolver at exchangeAddress1 knows
+// buyer.erb, and the reverse record of the buyer.
+type ensAPI struct {
+	calls atomic.Int32
+}
+
+func (api *ensAPI) Call(args map[string]interface{}, block rpc.BlockNumberOrHash) (hexutil.Bytes, error) {
+	api.calls.Add(1)
+	data, err := hexutil.Decode(args["input"].(string))
+	if err != nil {
+		return nil, err
+	}
+	method, err := ensStubABI.MethodById(data[:4])
+	if err != nil {
+		return nil, err
+	}
+	in, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return nil, err
+	}
+	node := common.Hash(in[0].([32]byte))
+	buyer := common.HexToAddress(buyerAddress)
+	reverse := client.NameHash(strings.ToLower(buyer.Hex()[2:]) + ".addr.reverse")
+	known := node == client.NameHash("buyer.erb") || node == reverse
+	to := common.HexToAddress(args["to"].(string))
+	switch {
+	case method.Name == "resolver" && to == common.HexToAddress(exchangeAddress) && known:
+		return method.Outputs.Pack(common.HexToAddress(exchangeAddress1))
+	case method.Name == "addr" && node == client.NameHash("buyer.erb"):
+		return method.Outputs.Pack(buyer)
+	case method.Name == "name" && node == reverse:
+		return method.Outputs.Pack("Buyer.erb")
+	case method.Name == "name":
+		return method.Outputs.Pack("")
+	default:
+		return method.Outputs.Pack(common.Address{})
+	}
+}
+
+func (api *ensAPI) GetCode(common.Address, rpc.BlockNumberOrHash) hexutil.Bytes {
+	return hexutil.Bytes{0x60, 0x80}
+}
+
+func TestNameResolution(t *testing.T) {
+	if got := client.NameHash("eth").Hex(); got != "0x93cdeb708b7545dc668eb9280176169d1c33cfd8ed6f04690a0bcc88a93fc4ae" {
+		t.Errorf("namehash of eth %s", got)
+	}
+
+	api := &ensAPI{}
+	rpcServer := rpc.NewServer()
+	if err := rpcServer.RegisterName("eth", api); err != nil {
+		t.Fatal(err)
+	}
+	node := httptest.NewServer(rpcServer)
+	defer node.Close()
+	c, err := rpc.Dial(node.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	registry := client.NewClientWithRPC("", c)
+	defer registry.CloseConnect()
+	ens := client.NewENSResolver(registry, exchangeAddress)
+	ctx := context.Background()
+
+	buyer := common.HexToAddress(buyerAddress)
+	if address, err := ens.LookupName(ctx, "BUYER.erb"); err != nil || address != buyer {
+		t.Errorf("buyer.erb resolved to %s %v", address.Hex(), err)
+	}
+	if _, err := ens.LookupName(ctx, "nobody.erb"); !errors.Is(err, tools.ErrNameNotFound) {
+		t.Errorf("nobody.erb: %v", err)
+	}
+	if name, err := ens.ReverseLookup(ctx, buyer); err != nil || name != "Buyer.erb" {
+		t.Errorf("name of the buyer %q %v", name, err)
+	}
+	if _, err := ens.ReverseLookup(ctx, common.HexToAddress(sellerAddress)); !errors.Is(err, tools.ErrNameNotFound) {
+		t.Errorf("name of the seller: %v", err)
+	}
+
+	// Names and aliases are accepted as addresses by the transactions of a client.
+	seller := common.HexToAddress(sellerAddress)
+	backend := simulated.NewBackend(map[common.Address]*big.Int{seller: types2.ERB(10).Wei()})
+	defer backend.Close()
+	worm := backend.Client(sellerPriKey)
+	book := tools.NewAddressBook()
+	if err := book.Set("temp", tempAddress); err != nil {
+		t.Fatal(err)
+	}
+	worm.SetAddressBook(book)
+	worm.SetNameResolver(tools.CachedResolver(ens, time.Minute))
+	before := api.calls.Load()
+	for i := 0; i < 2; i++ {
+		if _, err := worm.NormalTransactionCtx(ctx, "buyer.erb", types2.ERB(1), ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if calls := api.calls.Load() - before; calls != 2 {
+		t.Errorf("resolved the cached name with %d calls, want 2", calls)
+	}
+	if _, err := worm.NormalTransactionCtx(ctx, "temp", types2.ERB(1), ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := worm.NormalTransactionCtx(ctx, "nobody.erb", types2.ERB(1), ""); err == nil {
+		t.Error("sent to an unknown name")
+	}
+	backend.Commit()
+	if balance := types2.Wei(backend.Account(buyer).Balance); balance.Cmp(types2.ERB(2)) != 0 {
+		t.Errorf("buyer.erb received %s ERB, want 2", balance)
+	}
+	if address, err := worm.Resolve(ctx, "temp"); err != nil || address != common.HexToAddress(tempAddress) {
+		t.Errorf("temp resolved to %s %v", address.Hex(), err)
+	}
+	if name, err := worm.ReverseLookup(ctx, common.HexToAddress(tempAddress)); err != nil || name != "temp" {
+		t.Errorf("name of temp %q %v", name, err)
+	}
+}
+
+type resolverKey struct{}
+
+// waitingResolver answers no lookup before its ctx is done and records the ctx value of
+// resolverKey.
+type waitingResolver struct {
+	value atomic.Value
+}
+
+func (r *waitingResolver) LookupName(ctx context.Context, name string) (common.Address, error) {
+	r.value.Store(ctx.Value(resolverKey{}))
+	<-ctx.Done()
+	return common.Address{}, ctx.Err()
+}
+
+func (r *waitingResolver) ReverseLookup(ctx context.Context, address common.Address) (string, error) {
+	return "", tools.ErrNameNotFound
+}
+
+func TestNameResolutionContext(t *testing.T) {
+	seller := common.HexToAddress(sellerAddress)
+	backend := simulated.NewBackend(map[common.Address]*big.Int{seller: types2.ERB(10).Wei()})
+	defer backend.Close()
+	worm := backend.Client(sellerPriKey)
+	resolver := &waitingResolver{}
+	worm.SetNameResolver(resolver)
+
+	// The lookup of a name runs with the context of the call and ends with it.
+	ctx, cancel := context.WithTimeout(context.WithValue(context.Background(), resolverKey{}, "caller"), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := worm.NormalTransactionCtx(ctx, "buyer.erb", types2.ERB(1), ""); err == nil {
+		t.Error("sent to an unresolved name")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("resolved for %s after the deadline of the call", elapsed)
+	}
+	if value := resolver.value.Load(); value != "caller" {
+		t.Errorf("resolver context value %v, want the one of the call", value)
+	}
+}
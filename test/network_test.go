@@ -0,0 +1,236 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/erbieio/erb-client/v2/client"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// countingAPI answers the chain metadata requests and counts them.
+type countingAPI struct{ calls atomic.Int32 }
+
+func (api *countingAPI) ChainId() hexutil.Uint64 {
+	api.calls.Add(1)
+	return 51888
+}
+
+func (api *countingAPI) GasPrice() hexutil.Uint64 {
+	api.calls.Add(1)
+	return 1e9
+}
+
+func (api *countingAPI) Version() string {
+	api.calls.Add(1)
+	return "51888"
+}
+
+func TestMetadataCache(t *testing.T) {
+	api := &countingAPI{}
+	rpcServer := rpc.NewServer()
+	if err := rpcServer.RegisterName("eth", api); err != nil {
+		t.Fatal(err)
+	}
+	if err := rpcServer.RegisterName("net", api); err != nil {
+		t.Fatal(err)
+	}
+	node := httptest.NewServer(rpcServer)
+	defer node.Close()
+	c, err := rpc.Dial(node.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	worm := client.NewClientWithRPC("", c)
+	defer worm.CloseConnect()
+	ctx := context.Background()
+
+	read := func(worm *client.Wormholes) {
+		t.Helper()
+		chainID, err := worm.ChainID(ctx)
+		if err != nil || chainID.Int64() != 51888 {
+			t.Fatalf("chain ID %v %v", chainID, err)
+		}
+		networkID, err := worm.NetworkID(ctx)
+		if err != nil || networkID.Int64() != 51888 {
+			t.Fatalf("network ID %v %v", networkID, err)
+		}
+		gasPrice, err := worm.SuggestGasPrice(ctx)
+		if err != nil || gasPrice.Int64() != 1e9 {
+			t.Fatalf("gas price %v %v", gasPrice, err)
+		}
+		// The cached values are copies.
+		chainID.SetInt64(0)
+		gasPrice.SetInt64(0)
+	}
+	for i := 0; i < 3; i++ {
+		read(worm)
+		read(worm.WithPriKey(buyerPriKey))
+	}
+	if got := api.calls.Load(); got != 3 {
+		t.Errorf("%d requests, want one per value", got)
+	}
+
+	worm.SetMetadataTTL(time.Minute, 0)
+	read(worm)
+	read(worm)
+	if got := api.calls.Load(); got != 3+4 {
+		t.Errorf("%d requests without caching the gas price, want 7", got)
+	}
+}
+
+// listAPI answers the validator and active miner requests with lists of n entries, in the
+// capitalized form written by the node, and null for other blocks.
+type listAPI struct{ n int }
+
+func (api *listAPI) list(field string, number rpc.BlockNumber, entry func(i int) string) json.RawMessage {
+	if number != 1 {
+		return json.RawMessage("null")
+	}
+	entries := make([]string, api.n)
+	for i := range entries {
+		entries[i] = entry(i)
+	}
+	return json.RawMessage(fmt.Sprintf(`{"Other":[{"Addr":"0x1"}],"%s":[%s]}`, field, strings.Join(entries, ",")))
+}
+
+func (api *listAPI) GetValidator(number rpc.BlockNumber) json.RawMessage {
+	return api.list("Validators", number, func(i int) string {
+		return fmt.Sprintf(`{"Addr":"%s","Balance":%d,"Weight":[1,2]}`, common.BigToAddress(big.NewInt(int64(i+1))).Hex(), i)
+	})
+}
+
+func (api *listAPI) GetActiveLivePool(number rpc.BlockNumber) json.RawMessage {
+	return api.list("ActiveMiners", number, func(i int) string {
+		return fmt.Sprintf(`{"Address":"%s","Height":%d}`, common.BigToAddress(big.NewInt(int64(i+1))).Hex(), i)
+	})
+}
+
+func TestListIterators(t *testing.T) {
+	rpcServer := rpc.NewServer()
+	if err := rpcServer.RegisterName("eth", &listAPI{n: 1000}); err != nil {
+		t.Fatal(err)
+	}
+	node := httptest.NewServer(rpcServer)
+	defer node.Close()
+	c, err := rpc.Dial(node.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	worm := client.NewClientWithRPC("", c)
+	defer worm.CloseConnect()
+	ctx := context.Background()
+
+	validators, err := worm.IterateValidators(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := 0
+	for validators.Next() {
+		v := validators.Value()
+		if v.Addr != common.BigToAddress(big.NewInt(int64(n+1))) || v.Balance.Int64() != int64(n) || len(v.Weight) != 2 {
+			t.Fatalf("validator %d is %v", n, v)
+		}
+		n++
+	}
+	if err := validators.Err(); err != nil || n != 1000 {
+		t.Fatalf("got %d validators, %v", n, err)
+	}
+	if validators.Next() {
+		t.Error("iterator restarted after the end")
+	}
+
+	miners, err := worm.IterateActiveLivePool(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n = 0
+	for miners.Next() {
+		if m := miners.Value(); m.Height != uint64(n) {
+			t.Fatalf("miner %d is %v", n, m)
+		}
+		n++
+	}
+	if err := miners.Err(); err != nil || n != 1000 {
+		t.Fatalf("got %d miners, %v", n, err)
+	}
+
+	report, err := worm.AnalyzeActiveMiners(ctx, 1, 1, 1)
+	if err != nil || len(report.Miners) != 1000 {
+		t.Fatalf("analyzed %v, %v", report, err)
+	}
+	if _, err := worm.IterateValidators(ctx, 2); !errors.Is(err, ethereum.NotFound) {
+		t.Errorf("got %v for an unknown block, want NotFound", err)
+	}
+	if _, err := worm.IterateActiveLivePool(ctx, 2); !errors.Is(err, ethereum.NotFound) {
+		t.Errorf("got %v for an unknown block, want NotFound", err)
+	}
+}
+
+func TestDialWithTransport(t *testing.T) {
+	rpcServer := rpc.NewServer()
+	if err := rpcServer.RegisterName("eth", &countingAPI{}); err != nil {
+		t.Fatal(err)
+	}
+	var conns atomic.Int32
+	var gzip atomic.Bool
+	node := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Encoding") != "" {
+			gzip.Store(true)
+		}
+		// Slow answers make the requests of a burst overlap.
+		time.Sleep(5 * time.Millisecond)
+		rpcServer.ServeHTTP(w, r)
+	}))
+	node.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	node.Start()
+	defer node.Close()
+
+	worm, err := client.DialWithTransport("", node.URL, client.TransportConfig{MaxIdleConns: 8, DisableCompression: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer worm.CloseConnect()
+	worm.SetMetadataTTL(0, 0)
+	for burst := 0; burst < 5; burst++ {
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := worm.SuggestGasPrice(context.Background()); err != nil {
+					t.Error(err)
+				}
+			}()
+		}
+		wg.Wait()
+	}
+	if got := conns.Load(); got > 8 {
+		t.Errorf("opened %d connections for bursts of 8 requests, want at most 8", got)
+	}
+	if gzip.Load() {
+		t.Error("asked for compressed responses with DisableCompression")
+	}
+
+	if _, err := client.DialWithTransport("0x01", node.URL, client.TransportConfig{}); err == nil {
+		t.Error("dialed with an invalid key")
+	}
+}
@@ -0,0 +1,121 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/erbieio/erb-client/v2/notify"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func notifyEvent() *notify.Event {
+	return &notify.Event{
+		Type:       notify.EventNFTTransfer,
+		Block:      12,
+		TxHash:     common.HexToHash("0x01"),
+		From:       common.HexToAddress(sellerAddress),
+		To:         common.HexToAddress(buyerAddress),
+		NFTAddress: "0x000000000000000000000000000000000000000A",
+		Value:      "0",
+	}
+}
+
+func TestNotifyDelivery(t *testing.T) {
+	var received []*notify.Event
+	var bad atomic.Int32
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Method != "POST" || r.Header.Get("Content-Type") != "application/json" ||
+			r.Header.Get(notify.SignatureHeader) != notify.Sign("secret", body) {
+			bad.Add(1)
+		}
+		var ev notify.Event
+		if err := json.Unmarshal(body, &ev); err != nil {
+			bad.Add(1)
+		}
+		received = append(received, &ev)
+	}))
+	defer hook.Close()
+	filtered := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("the event does not match the filter of the webhook")
+	}))
+	defer filtered.Close()
+
+	n := notify.New(
+		notify.Webhook{URL: hook.URL, Secret: "secret", Filter: notify.Filter{
+			Types: []string{notify.EventNFTTransfer},
+			NFTs:  []string{"0x000000000000000000000000000000000000000a"},
+		}},
+		notify.Webhook{URL: filtered.URL, Filter: notify.Filter{Addresses: []common.Address{common.HexToAddress(exchangeAddress)}}},
+	)
+	ev := notifyEvent()
+	if err := n.Notify(context.Background(), ev); err != nil {
+		t.Fatal(err)
+	}
+	if bad.Load() != 0 {
+		t.Errorf("%d malformed or unsigned requests", bad.Load())
+	}
+	if len(received) != 1 || *received[0] != *ev {
+		t.Errorf("received %v, want %v", received, ev)
+	}
+}
+
+func TestNotifyRetry(t *testing.T) {
+	var requests atomic.Int32
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer hook.Close()
+
+	// The webhook is posted again after a failure.
+	if err := notify.New(notify.Webhook{URL: hook.URL}).Notify(context.Background(), notifyEvent()); err != nil {
+		t.Fatal(err)
+	}
+	if requests.Load() != 2 {
+		t.Errorf("%d requests, want 2", requests.Load())
+	}
+}
+
+func TestNotifyErrors(t *testing.T) {
+	var requests atomic.Int32
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer hook.Close()
+
+	// A webhook failing until ctx is done stops retrying with the error of ctx.
+	ctx, cancel := context.WithTimeout(context.Background(), 1500*time.Millisecond)
+	defer cancel()
+	if err := notify.New(notify.Webhook{URL: hook.URL}).Notify(ctx, notifyEvent()); err != context.DeadlineExceeded {
+		t.Errorf("got err %v, want %v", err, context.DeadlineExceeded)
+	}
+	if requests.Load() != 2 {
+		t.Errorf("%d requests before the deadline, want 2", requests.Load())
+	}
+
+	// A webhook still failing after the retries returns the status of the last answer.
+	requests.Store(0)
+	err := notify.New(notify.Webhook{URL: hook.URL}).Notify(context.Background(), notifyEvent())
+	if err == nil || !strings.Contains(err.Error(), "500 Internal Server Error") {
+		t.Errorf("got err %v, want the status of the webhook", err)
+	}
+	if requests.Load() != 4 {
+		t.Errorf("%d requests, want 4", requests.Load())
+	}
+
+	// A webhook that can not be requested fails without retrying.
+	err = notify.New(notify.Webhook{URL: "://webhook"}).Notify(context.Background(), notifyEvent())
+	if err == nil || !strings.Contains(err.Error(), "://webhook") {
+		t.Errorf("got err %v for an invalid URL", err)
+	}
+}
@@ -0,0 +1,121 @@
+package test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/asn1"
+	"math/big"
+	"testing"
+
+	"github.com/erbieio/erb-client/v2/client"
+	"github.com/erbieio/erb-client/v2/pkcs11"
+	"github.com/erbieio/erb-client/v2/simulated"
+	"github.com/erbieio/erb-client/v2/tools"
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// softSession is a PKCS#11 session of a software key signing like tokens do: with high S values
+// half of the time, and DER encoded when der is set.
+type softSession struct {
+	key   *ecdsa.PrivateKey
+	der   bool
+	calls int
+}
+
+func (s *softSession) Sign(hash []byte) ([]byte, error) {
+	sig, err := crypto.Sign(hash, s.key)
+	if err != nil {
+		return nil, err
+	}
+	r, sv := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:64])
+	s.calls++
+	if s.calls%2 == 0 {
+		sv.Sub(crypto.S256().Params().N, sv)
+	}
+	if s.der {
+		return asn1.Marshal(struct{ R, S *big.Int }{r, sv})
+	}
+	raw := make([]byte, 64)
+	r.FillBytes(raw[:32])
+	sv.FillBytes(raw[32:])
+	return raw, nil
+}
+
+func (s *softSession) ECPoint() ([]byte, error) {
+	return asn1.Marshal(crypto.FromECDSAPub(&s.key.PublicKey))
+}
+
+func TestPKCS11Signer(t *testing.T) {
+	key, err := crypto.HexToECDSA(sellerPriKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seller := common.HexToAddress(sellerAddress)
+	for _, der := range []bool{false, true} {
+		signer, err := pkcs11.NewSigner(&softSession{key: key, der: der})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if signer.Address() != seller {
+			t.Fatalf("signer of %s, want %s", signer.Address().Hex(), seller.Hex())
+		}
+		hash := crypto.Keccak256([]byte("order"))
+		for i := 0; i < 4; i++ {
+			sig, err := signer.SignHash(hash)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, _ := crypto.Sign(hash, key)
+			if string(sig) != string(want) {
+				t.Errorf("der %v: signature %x, want %x", der, sig, want)
+			}
+		}
+	}
+
+	// Orders signed through the HSM are those signed with the key.
+	signer, err := pkcs11.NewSigner(&softSession{key: key})
+	if err != nil {
+		t.Fatal(err)
+	}
+	nft := "0x0000000000000000000000000000000000000001"
+	for i := 0; i < 2; i++ {
+		got, err := client.NewWalletWithSigner(signer).SignSeller1("0x38d7ea4c68000", nft, exchangeAddress, "0x64")
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := client.NewWallet(sellerPriKey).SignSeller1("0x38d7ea4c68000", nft, exchangeAddress, "0x64")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != string(want) {
+			t.Errorf("signed %s, want %s", got, want)
+		}
+	}
+
+	// Transactions signed through the HSM are accepted by the chain.
+	backend := simulated.NewBackend(map[common.Address]*big.Int{seller: types2.ERB(10).Wei()})
+	defer backend.Close()
+	worm := backend.Client("")
+	worm.SetSigner(signer)
+	for i := 0; i < 2; i++ {
+		if _, err := worm.NormalTransactionCtx(context.Background(), tempAddress, types2.ERB(1), ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := worm.Tx().Pay(tempAddress, types2.ERB(1).Wei()).Send(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+	if balance := types2.Wei(backend.Account(common.HexToAddress(tempAddress)).Balance); balance.Cmp(types2.ERB(3)) != 0 {
+		t.Errorf("received %s ERB, want 3", balance)
+	}
+	sig, err := worm.SignDelegate(tempAddress, sellerAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signer, err := tools.RecoverAddress(tempAddress+sellerAddress, string(sig)); err != nil || signer != seller {
+		t.Errorf("delegation signed by %s %v", signer.Hex(), err)
+	}
+}
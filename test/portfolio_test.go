@@ -0,0 +1,121 @@
+package test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/erbieio/erb-client/v2/portfolio"
+	"github.com/erbieio/erb-client/v2/simulated"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestPortfolioRefresh(t *testing.T) {
+	seller := common.HexToAddress(sellerAddress)
+	backend := simulated.NewBackend(map[common.Address]*big.Int{
+		seller: big.NewInt(1e18),
+	})
+	defer backend.Close()
+	worm := backend.Client(sellerPriKey)
+	nft := "0x0000000000000000000000000000000000000001"
+	if _, err := worm.Mint(10, "/ipfs/ddfd90be9408b4", ""); err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+	if _, err := worm.AuthorizeAll(exchangeAddress); err != nil {
+		t.Fatal(err)
+	}
+
+	tracker := portfolio.New(worm, portfolio.Config{
+		Addresses: []common.Address{seller, common.HexToAddress(buyerAddress)},
+		NFTs: func(ctx context.Context, owner common.Address) ([]string, error) {
+			return []string{nft}, nil
+		},
+	})
+	snapshot, err := tracker.Refresh(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	account := snapshot.Accounts[0]
+	if len(account.NFTs) != 1 || account.NFTs[0].Address != nft {
+		t.Fatalf("seller nfts %v, want %s", account.NFTs, nft)
+	}
+	if len(account.Pending) != 1 {
+		t.Errorf("seller has %d pending transactions, want 1", len(account.Pending))
+	}
+	if len(snapshot.Accounts[1].NFTs) != 0 {
+		t.Errorf("buyer owns %d nfts, want 0", len(snapshot.Accounts[1].NFTs))
+	}
+	if snapshot.TotalBalance.Cmp(account.Balance) != 0 {
+		t.Errorf("total balance %s, want %s", snapshot.TotalBalance, account.Balance)
+	}
+
+	backend.Commit()
+	snapshot, err = tracker.Refresh(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	approved := snapshot.Accounts[0].Approved
+	if len(approved) != 1 || approved[0] != common.HexToAddress(exchangeAddress) {
+		t.Errorf("seller approved %v, want %s", approved, exchangeAddress)
+	}
+}
+
+func TestPortfolioIncremental(t *testing.T) {
+	seller := common.HexToAddress(sellerAddress)
+	buyer := common.HexToAddress(buyerAddress)
+	idle := common.HexToAddress(exchangeAddress1)
+	backend := simulated.NewBackend(map[common.Address]*big.Int{
+		seller: big.NewInt(1e18),
+		idle:   big.NewInt(1e18),
+	})
+	defer backend.Close()
+	worm := backend.Client(sellerPriKey)
+	nft := "0x0000000000000000000000000000000000000001"
+	if _, err := worm.Mint(10, "/ipfs/ddfd90be9408b4", ""); err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+
+	tracker := portfolio.New(worm, portfolio.Config{
+		Addresses: []common.Address{seller, buyer, idle},
+		NFTs: func(ctx context.Context, owner common.Address) ([]string, error) {
+			if owner == idle {
+				return nil, nil
+			}
+			return []string{nft}, nil
+		},
+		Incremental: true,
+	})
+	ctx := context.Background()
+	first, err := tracker.Refresh(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first.Accounts[0].NFTs) != 1 {
+		t.Fatalf("seller nfts %v, want %s", first.Accounts[0].NFTs, nft)
+	}
+
+	if _, err := worm.Transfer(nft, buyerAddress); err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+	backend.Commit()
+	second, err := tracker.Refresh(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.Block != first.Block+2 {
+		t.Fatalf("snapshot at block %d, want %d", second.Block, first.Block+2)
+	}
+	if len(second.Accounts[0].NFTs) != 0 || len(second.Accounts[1].NFTs) != 1 {
+		t.Errorf("seller owns %d nfts and buyer %d after the transfer, want 0 and 1",
+			len(second.Accounts[0].NFTs), len(second.Accounts[1].NFTs))
+	}
+	if second.Accounts[2] != first.Accounts[2] {
+		t.Error("untouched account was fetched again")
+	}
+	if second.TotalBalance.Cmp(new(big.Int).Add(second.Accounts[0].Balance, second.Accounts[2].Balance)) != 0 {
+		t.Errorf("total balance %s", second.TotalBalance)
+	}
+}
@@ -0,0 +1,323 @@
+package test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/erbieio/erb-client/v2/publish"
+	"github.com/erbieio/erb-client/v2/scanner"
+	"github.com/erbieio/erb-client/v2/simulated"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// jetStream is a NATS server storing the messages of the subjects with the stream prefix, the
+// part of JetStream publishers use.
+type jetStream struct {
+	ln     net.Listener
+	prefix string
+
+	mu         sync.Mutex
+	ids        map[string]bool
+	stored     []publish.Event
+	duplicates int
+	// dropAfter closes the connection after storing that many more messages, without acks.
+	dropAfter int
+}
+
+func newJetStream(t *testing.T, prefix string) *jetStream {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	js := &jetStream{ln: ln, prefix: prefix, ids: map[string]bool{}}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go js.serve(conn)
+		}
+	}()
+	return js
+}
+
+func (js *jetStream) url() string { return "nats://" + js.ln.Addr().String() }
+
+func (js *jetStream) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	fmt.Fprintf(conn, "INFO {\"server_id\":\"test\",\"headers\":true}\r\n")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "PING":
+			fmt.Fprintf(conn, "PONG\r\n")
+		case "HPUB":
+			headerSize, _ := strconv.Atoi(fields[3])
+			size, _ := strconv.Atoi(fields[4])
+			data := make([]byte, size+2)
+			if _, err := io.ReadFull(r, data); err != nil {
+				return
+			}
+			subject, reply := fields[1], fields[2]
+			if !strings.HasPrefix(subject, js.prefix) {
+				fmt.Fprintf(conn, "HMSG %s 1 16 16\r\nNATS/1.0 503\r\n\r\n\r\n", reply)
+				continue
+			}
+			id := ""
+			for _, h := range strings.Split(string(data[:headerSize]), "\r\n") {
+				if strings.HasPrefix(h, "Nats-Msg-Id: ") {
+					id = strings.TrimPrefix(h, "Nats-Msg-Id: ")
+				}
+			}
+			js.mu.Lock()
+			duplicate := js.ids[id]
+			if duplicate {
+				js.duplicates++
+			} else {
+				var ev publish.Event
+				json.Unmarshal(data[headerSize:size], &ev)
+				js.ids[id] = true
+				js.stored = append(js.stored, ev)
+			}
+			drop := false
+			if js.dropAfter > 0 {
+				js.dropAfter--
+				drop = js.dropAfter == 0
+			}
+			seq := len(js.stored)
+			js.mu.Unlock()
+			if drop {
+				return
+			}
+			ack := fmt.Sprintf(`{"stream":"ERB","seq":%d,"duplicate":%v}`, seq, duplicate)
+			fmt.Fprintf(conn, "MSG %s 1 %d\r\n%s\r\n", reply, len(ack), ack)
+		}
+	}
+}
+
+// publishChain mints, trades, pledges and transfers an NFT and returns the head block.
+func publishChain(t *testing.T, backend *simulated.Backend) uint64 {
+	seller := backend.Client(sellerPriKey)
+	buyer := backend.Client(buyerPriKey)
+	nft := "0x0000000000000000000000000000000000000001"
+	if _, err := seller.Mint(10, "/ipfs/ddfd90be9408b4", ""); err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+	order, err := seller.SignSeller1("0x64", nft, "", "0x100")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := buyer.BuyerInitiatingTransaction(order); err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+	if _, err := buyer.TokenPledge(common.HexToAddress(buyerAddress), "", "", "", 1, 0); err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+	if _, err := buyer.Transfer(nft, sellerAddress); err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+	head, err := backend.Client("").BlockNumber(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return head
+}
+
+func TestPublishNATS(t *testing.T) {
+	backend := simulated.NewBackend(map[common.Address]*big.Int{
+		common.HexToAddress(sellerAddress): big.NewInt(1e18),
+		common.HexToAddress(buyerAddress):  new(big.Int).Mul(big.NewInt(1e18), big.NewInt(10)),
+	})
+	defer backend.Close()
+	head := publishChain(t, backend)
+
+	js := newJetStream(t, "erb.")
+	defer js.ln.Close()
+	// The connection is lost before the second message is acknowledged, the block is published
+	// again and the stream drops the duplicate.
+	js.dropAfter = 2
+	nats := publish.NewNATS(publish.NATSConfig{URL: js.url()})
+	defer nats.Close()
+	s := scanner.New(backend.Client(""), scanner.Config{})
+	publish.New(nats, publish.Config{RetryInterval: time.Millisecond}).Attach(s)
+	if err := s.ScanRange(context.Background(), 0, head); err != nil {
+		t.Fatal(err)
+	}
+
+	js.mu.Lock()
+	defer js.mu.Unlock()
+	if js.duplicates == 0 {
+		t.Error("no message was published again")
+	}
+	count := map[string]int{}
+	for _, ev := range js.stored {
+		count[ev.Type]++
+	}
+	want := map[string]int{publish.EventBlock: int(head) + 1, publish.EventTrade: 1, publish.EventPledge: 1, publish.EventNFTTransfer: 1}
+	for typ, n := range want {
+		if count[typ] != n {
+			t.Errorf("%d %s events, want %d (all %v)", count[typ], typ, n, count)
+		}
+	}
+	for _, ev := range js.stored {
+		switch ev.Type {
+		case publish.EventTrade:
+			if ev.Buyer == nil || *ev.Buyer != common.HexToAddress(buyerAddress) || ev.Value != "100" ||
+				ev.NFTAddress != "0x0000000000000000000000000000000000000001" || ev.TxType != "BuyerInitiatingTransaction" {
+				t.Errorf("trade event %+v", ev)
+			}
+		case publish.EventPledge:
+			if ev.From == nil || *ev.From != common.HexToAddress(buyerAddress) || ev.Value != "1000000000000000000" {
+				t.Errorf("pledge event %+v", ev)
+			}
+		case publish.EventNFTTransfer:
+			if ev.To == nil || *ev.To != common.HexToAddress(sellerAddress) || ev.Block != head {
+				t.Errorf("transfer event %+v", ev)
+			}
+		}
+	}
+}
+
+func TestPublishNATSNoStream(t *testing.T) {
+	js := newJetStream(t, "erb.")
+	defer js.ln.Close()
+	nats := publish.NewNATS(publish.NATSConfig{URL: js.url()})
+	defer nats.Close()
+	err := nats.Publish(context.Background(), []publish.Message{{Topic: "other.block", ID: "1", Value: []byte("{}")}})
+	if err == nil || !strings.Contains(err.Error(), "no stream") {
+		t.Errorf("published to a subject without a stream: %v", err)
+	}
+	if err := nats.Publish(context.Background(), []publish.Message{{Topic: "erb.block", ID: "1", Value: []byte("{}")}}); err != nil {
+		t.Errorf("publish after a failure: %v", err)
+	}
+	if err := nats.Publish(context.Background(), []publish.Message{{Topic: "erb.*", Value: []byte("{}")}}); err == nil {
+		t.Error("published to a wildcard")
+	}
+}
+
+// brokerFunc is a Broker of a function.
+type brokerFunc func(ctx context.Context, msgs []publish.Message) error
+
+func (f brokerFunc) Publish(ctx context.Context, msgs []publish.Message) error { return f(ctx, msgs) }
+
+func TestPublishCheckpoint(t *testing.T) {
+	backend := simulated.NewBackend(map[common.Address]*big.Int{
+		common.HexToAddress(sellerAddress): big.NewInt(1e18),
+		common.HexToAddress(buyerAddress):  new(big.Int).Mul(big.NewInt(1e18), big.NewInt(10)),
+	})
+	defer backend.Close()
+	head := publishChain(t, backend)
+
+	down := errors.New("broker down")
+	var topics []string
+	broker := brokerFunc(func(ctx context.Context, msgs []publish.Message) error {
+		for _, m := range msgs {
+			if m.Topic == "chain.trade" {
+				return down
+			}
+		}
+		for _, m := range msgs {
+			topics = append(topics, m.Topic)
+		}
+		return nil
+	})
+	s := scanner.New(backend.Client(""), scanner.Config{})
+	publish.New(broker, publish.Config{
+		Prefix:        "chain.",
+		Types:         []string{publish.EventTrade, publish.EventNFTTransfer},
+		Retries:       1,
+		RetryInterval: time.Millisecond,
+	}).Attach(s)
+	if err := s.ScanRange(context.Background(), 0, head); !errors.Is(err, down) {
+		t.Fatalf("scanned with the broker down: %v", err)
+	}
+	// The block of the trade is not checkpointed, it is published again after a restart.
+	if next, _ := s.Next(context.Background()); next != 2 {
+		t.Errorf("checkpoint %d, want the trade block 2", next)
+	}
+	if len(topics) != 0 {
+		t.Errorf("published %v before the trade", topics)
+	}
+}
+
+func TestPublishKafka(t *testing.T) {
+	var mu sync.Mutex
+	records := map[string][]json.RawMessage{}
+	fail := false
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/vnd.kafka.json.v2+json" || r.Header.Get("Authorization") != "Basic test" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		var body struct {
+			Records []struct {
+				Key   string          `json:"key"`
+				Value json.RawMessage `json:"value"`
+			} `json:"records"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		topic := strings.TrimPrefix(r.URL.Path, "/topics/")
+		var offsets []map[string]interface{}
+		mu.Lock()
+		for _, record := range body.Records {
+			if fail {
+				offsets = append(offsets, map[string]interface{}{"partition": nil, "offset": nil, "error_code": 50003, "error": "timeout"})
+				continue
+			}
+			records[topic] = append(records[topic], record.Value)
+			offsets = append(offsets, map[string]interface{}{"partition": 0, "offset": len(records[topic]) - 1, "error_code": nil, "error": nil})
+		}
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/vnd.kafka.v2+json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"offsets": offsets})
+	}))
+	defer proxy.Close()
+
+	kafka := publish.NewKafka(publish.KafkaConfig{URL: proxy.URL + "/", Header: http.Header{"Authorization": {"Basic test"}}})
+	msgs := []publish.Message{
+		{Topic: "erb.block", Key: "1", Value: []byte(`{"type":"block","block":1}`)},
+		{Topic: "erb.trade", Key: "0x01", Value: []byte(`{"type":"trade","block":1}`)},
+		{Topic: "erb.block", Key: "2", Value: []byte(`{"type":"block","block":2}`)},
+	}
+	if err := kafka.Publish(context.Background(), msgs); err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	if len(records["erb.block"]) != 2 || len(records["erb.trade"]) != 1 || !strings.Contains(string(records["erb.block"][1]), `"block":2`) {
+		t.Errorf("records %v", records)
+	}
+	fail = true
+	mu.Unlock()
+	if err := kafka.Publish(context.Background(), msgs); err == nil || !strings.Contains(err.Error(), "timeout") {
+		t.Errorf("published with failed records: %v", err)
+	}
+}
@@ -0,0 +1,97 @@
+package test
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/erbieio/erb-client/v2/simulated"
+	"github.com/erbieio/erb-client/v2/tools"
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// The tests of this file share one client between goroutines, run them with -race.
+
+func TestConcurrentSends(t *testing.T) {
+	seller := common.HexToAddress(sellerAddress)
+	backend := simulated.NewBackend(map[common.Address]*big.Int{
+		seller: types2.ERB(100).Wei(),
+	})
+	defer backend.Close()
+	worm := backend.Client(sellerPriKey)
+	other := worm.WithPriKey(sellerPriKey)
+
+	const sends = 20
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	errs := make(chan error, sends)
+	for i := 0; i < sends; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var err error
+			switch i % 3 {
+			case 0:
+				_, err = worm.NormalTransactionCtx(ctx, tempAddress, types2.ERB(1), "")
+			case 1:
+				_, err = worm.Tx().Pay(tempAddress, types2.ERB(1).Wei()).Send(ctx)
+			default:
+				_, err = other.NormalTransactionCtx(ctx, tempAddress, types2.ERB(1), "")
+			}
+			errs <- err
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	backend.Commit()
+	if balance := backend.Account(common.HexToAddress(tempAddress)).Balance; balance.Cmp(types2.ERB(sends).Wei()) != 0 {
+		t.Errorf("received %s wei, want %d ERB", balance, sends)
+	}
+	if nonce := backend.Account(seller).Nonce; nonce != sends {
+		t.Errorf("sender nonce %d, want %d", nonce, sends)
+	}
+}
+
+func TestConcurrentWallet(t *testing.T) {
+	backend := simulated.NewBackend(map[common.Address]*big.Int{
+		common.HexToAddress(sellerAddress): big.NewInt(1e18),
+	})
+	defer backend.Close()
+	worm := backend.Client(sellerPriKey)
+	book := tools.NewAddressBook()
+	if err := book.Set("exchanger", exchangeAddress); err != nil {
+		t.Fatal(err)
+	}
+	worm.SetAddressBook(book)
+
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			worm.UpdatePri(sellerPriKey)
+			worm.SetAddressBook(book)
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := worm.Wallet.SignBuyerAmount(types2.ERB(1), "", "exchanger", "0xa", ""); err != nil {
+				t.Error(err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := worm.Balance(ctx, sellerAddress); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+}
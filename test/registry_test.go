@@ -0,0 +1,77 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/erbieio/erb-client/v2/client"
+	"github.com/erbieio/erb-client/v2/simulated"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestRegistry(t *testing.T) {
+	mainnet := simulated.NewBackend(map[common.Address]*big.Int{common.HexToAddress(buyerAddress): big.NewInt(1)})
+	defer mainnet.Close()
+	testnet := simulated.NewBackend(map[common.Address]*big.Int{common.HexToAddress(buyerAddress): big.NewInt(2)})
+	defer testnet.Close()
+	mainnetNode := httptest.NewServer(mainnet.Handler())
+	defer mainnetNode.Close()
+	testnetNode := httptest.NewServer(testnet.Handler())
+	defer testnetNode.Close()
+
+	registry := client.NewRegistry()
+	defer registry.Close()
+	if _, err := registry.Dial("mainnet", client.NetworkConfig{URL: mainnetNode.URL, PriKey: buyerPriKey, ChainID: simulated.DefaultChainID}); err != nil {
+		t.Fatal(err)
+	}
+	testnetClient, err := registry.Dial("testnet", client.NetworkConfig{URL: testnetNode.URL, PriKey: sellerPriKey, Transport: &client.TransportConfig{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := registry.Dial("testnet", client.NetworkConfig{URL: testnetNode.URL}); err == nil {
+		t.Error("registered a network twice")
+	}
+	if _, err := registry.Dial("devnet", client.NetworkConfig{URL: testnetNode.URL, PriKey: "0xbad"}); err == nil {
+		t.Error("dialed with an invalid key")
+	}
+	if names := registry.Names(); strings.Join(names, ",") != "mainnet,testnet" {
+		t.Errorf("names %v", names)
+	}
+	worm, _ := registry.Get("testnet")
+	if address, _ := worm.Address(); worm != testnetClient || address != common.HexToAddress(sellerAddress) {
+		t.Errorf("testnet client signs with %s", address)
+	}
+	if worm, _ := registry.Get("mainnet"); worm.ExpectedChainID().Cmp(simulated.DefaultChainID) != 0 {
+		t.Errorf("mainnet expects chain %v", worm.ExpectedChainID())
+	}
+	if _, err := registry.Get("devnet"); !errors.Is(err, client.ErrUnknownNetwork) {
+		t.Errorf("get devnet: %v", err)
+	}
+
+	balances, errs := client.EachNetwork(context.Background(), registry, func(ctx context.Context, name string, worm *client.Wormholes) (*big.Int, error) {
+		return worm.Balance(ctx, buyerAddress)
+	})
+	if errs != nil || balances["mainnet"].Int64() != 1 || balances["testnet"].Int64() != 2 {
+		t.Errorf("balances %v, errors %v", balances, errs)
+	}
+
+	errs = registry.Each(context.Background(), func(ctx context.Context, name string, worm *client.Wormholes) error {
+		if name == "testnet" {
+			return errors.New("maintenance")
+		}
+		return nil
+	}, "mainnet", "testnet", "devnet")
+	if len(errs) != 2 || !strings.Contains(errs["testnet"].Error(), "network testnet: maintenance") ||
+		!errors.Is(errs["devnet"], client.ErrUnknownNetwork) {
+		t.Errorf("errors %v", errs)
+	}
+
+	registry.Remove("testnet")
+	if names := registry.Names(); len(names) != 1 {
+		t.Errorf("names %v after remove", names)
+	}
+}
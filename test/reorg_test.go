@@ -0,0 +1,85 @@
+package test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/erbieio/erb-client/v2/client"
+	"github.com/erbieio/erb-client/v2/simulated"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestCanonicalChecker(t *testing.T) {
+	backend := simulated.NewBackend(nil)
+	defer backend.Close()
+	worm := backend.Client("")
+	defer worm.CloseConnect()
+	ctx := context.Background()
+	hashOf := func(number int64) common.Hash {
+		header, err := worm.HeaderByNumber(ctx, big.NewInt(number))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return header.Hash
+	}
+	check := func(checker *client.CanonicalChecker) *client.ReorgEvent {
+		event, err := checker.CheckHead(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return event
+	}
+
+	for i := 0; i < 3; i++ {
+		backend.Commit()
+	}
+	checker := client.NewCanonicalChecker(worm, 10)
+	if event := check(checker); event != nil {
+		t.Fatalf("reorg %+v on the first check", event)
+	}
+	backend.Commit()
+	backend.Commit()
+	if event := check(checker); event != nil {
+		t.Fatalf("reorg %+v on a growing chain", event)
+	}
+
+	// Blocks 4 and 5 are replaced by a longer chain on block 3.
+	oldHead := hashOf(5)
+	if err := backend.Fork(hashOf(3)); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		backend.Commit()
+	}
+	event := check(checker)
+	if event == nil {
+		t.Fatal("reorg not reported")
+	}
+	if event.From != 4 || event.To != 5 || event.OldHead != oldHead || event.NewHead != hashOf(6) || event.Deep {
+		t.Errorf("reorg %+v, want blocks 4..5 of old head %s", event, oldHead.Hex())
+	}
+	if event.OldHead == hashOf(5) {
+		t.Errorf("old head %s is canonical", event.OldHead.Hex())
+	}
+	if event := check(checker); event != nil {
+		t.Errorf("reorg %+v reported again", event)
+	}
+
+	// A reorg below the remembered blocks is reported as deep from the oldest of them.
+	shallow := client.NewCanonicalChecker(worm, 2)
+	check(shallow)
+	backend.Commit()
+	check(shallow)
+	oldHead = hashOf(7)
+	if err := backend.Fork(hashOf(2)); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 6; i++ {
+		backend.Commit()
+	}
+	event = check(shallow)
+	if event == nil || event.From != 6 || event.To != 7 || event.OldHead != oldHead || !event.Deep {
+		t.Errorf("deep reorg %+v, want blocks 6..7 of old head %s", event, oldHead.Hex())
+	}
+}
@@ -0,0 +1,145 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/erbieio/erb-client/v2/export"
+	"github.com/erbieio/erb-client/v2/simulated"
+	"github.com/erbieio/erb-client/v2/tools"
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// commit fails the test unless the transaction sent by send is mined successfully.
+func commit(t *testing.T, backend *simulated.Backend, send func(ctx context.Context) (string, error)) {
+	t.Helper()
+	ctx := context.Background()
+	hash, err := send(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+	receipt, err := backend.Client("").TransactionReceipt(ctx, hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if receipt.Status != 1 {
+		t.Fatalf("transaction %s failed", hash)
+	}
+}
+
+// tradingBackend returns a backend where the exchanger at exchangeAddress charges feeRate
+// and the seller minted the NFTs 0x...01 to 0x...03 with the given royalty, in blocks 1 to 4.
+func tradingBackend(t *testing.T, feeRate int, royalty uint32) *simulated.Backend {
+	backend := simulated.NewBackend(map[common.Address]*big.Int{
+		common.HexToAddress(sellerAddress):   types2.ERB(10).Wei(),
+		common.HexToAddress(buyerAddress):    types2.ERB(10).Wei(),
+		common.HexToAddress(exchangeAddress): types2.ERB(10).Wei(),
+	})
+	t.Cleanup(backend.Close)
+	exchanger, seller := backend.Client(exchangerPriKey), backend.Client(sellerPriKey)
+	commit(t, backend, func(ctx context.Context) (string, error) {
+		return exchanger.TokenPledgeCtx(ctx, common.HexToAddress(exchangeAddress), "", "exchanger", "https://exchanger", types2.ERB(1), feeRate)
+	})
+	for i := 0; i < 3; i++ {
+		commit(t, backend, func(ctx context.Context) (string, error) {
+			return seller.MintCtx(ctx, royalty, "/ipfs/ddfd90be9408b4", "")
+		})
+	}
+	return backend
+}
+
+// nftAddress returns the address of the nth NFT minted on a simulated backend.
+func nftAddress(n int64) string {
+	return common.BigToAddress(big.NewInt(n)).Hex()
+}
+
+// sellTo settles the sale of nft by the owner with sellerKey through exchanger, sent by the
+// seller with a TransactionNFT.
+func sellTo(t *testing.T, backend *simulated.Backend, sellerKey, buyerKey, to, nft, exchanger string, price types2.Amount) {
+	order, err := backend.Client(buyerKey).Wallet.SignBuyer(price.Hex(), nft, exchanger, tools.EncodeBlockNumber(100), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	commit(t, backend, func(ctx context.Context) (string, error) {
+		return backend.Client(sellerKey).TransactionNFTCtx(ctx, order, to)
+	})
+}
+
+// buyFrom settles the sale of nft by the owner with sellerKey through exchanger, sent by the
+// buyer with a BuyerInitiatingTransaction.
+func buyFrom(t *testing.T, backend *simulated.Backend, sellerKey, buyerKey, nft, exchanger string, price types2.Amount) {
+	order, err := backend.Client(sellerKey).Wallet.SignSeller1(price.Hex(), nft, exchanger, tools.EncodeBlockNumber(100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	commit(t, backend, func(ctx context.Context) (string, error) {
+		return backend.Client(buyerKey).BuyerInitiatingTransactionCtx(ctx, order)
+	})
+}
+
+func TestExchangerRevenue(t *testing.T) {
+	backend := tradingBackend(t, 200, 100)
+	// Block 5 and 6 trade through the exchanger at 2%, block 7 without an exchanger and
+	// block 9 through the exchanger at the 5% set in block 8.
+	sellTo(t, backend, sellerPriKey, buyerPriKey, buyerAddress, nftAddress(1), exchangeAddress, types2.ERB(1))
+	buyFrom(t, backend, sellerPriKey, buyerPriKey, nftAddress(2), exchangeAddress, types2.ERB(2))
+	sellTo(t, backend, sellerPriKey, buyerPriKey, buyerAddress, nftAddress(3), "", types2.ERB(1))
+	commit(t, backend, func(ctx context.Context) (string, error) {
+		return backend.Client(exchangerPriKey).TokenPledgeCtx(ctx, common.HexToAddress(exchangeAddress), "", "exchanger", "https://exchanger", types2.ERB(1), 500)
+	})
+	buyFrom(t, backend, buyerPriKey, sellerPriKey, nftAddress(1), exchangeAddress, types2.ERB(1))
+	worm := backend.Client("")
+	defer worm.CloseConnect()
+
+	report, err := export.ExchangerRevenue(context.Background(), worm, common.HexToAddress(exchangeAddress), 0, 9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seller, buyer := common.HexToAddress(sellerAddress), common.HexToAddress(buyerAddress)
+	want := []struct {
+		block         uint64
+		nft           string
+		seller, buyer common.Address
+		price, fee    types2.Amount
+	}{
+		{5, nftAddress(1), seller, buyer, types2.ERB(1), types2.Wei(big.NewInt(2e16))},
+		{6, nftAddress(2), seller, buyer, types2.ERB(2), types2.Wei(big.NewInt(4e16))},
+		{9, nftAddress(1), buyer, seller, types2.ERB(1), types2.Wei(big.NewInt(5e16))},
+	}
+	if len(report.Trades) != len(want) {
+		t.Fatalf("%d trades, want %d", len(report.Trades), len(want))
+	}
+	for i, w := range want {
+		trade := report.Trades[i]
+		if trade.Block != w.block || !tools.SameAddress(trade.NFTAddress, w.nft) || trade.Seller != w.seller || trade.Buyer != w.buyer ||
+			trade.Price.Cmp(w.price.Wei()) != 0 || trade.Fee.Cmp(w.fee.Wei()) != 0 {
+			t.Errorf("trade %d: %+v, want %+v", i, trade, w)
+		}
+	}
+	if report.Volume.Cmp(types2.ERB(4).Wei()) != 0 || report.Fees.Cmp(big.NewInt(11e16)) != 0 {
+		t.Errorf("volume %s fees %s, want 4 ERB and 0.11 ERB", report.Volume, report.Fees)
+	}
+
+	var out bytes.Buffer
+	if err := export.WriteRevenueReport(&out, report); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 5 || lines[4] != "total,,,,,,4,0.11" {
+		t.Errorf("report csv:\n%s", out.String())
+	}
+
+	// A range without trades through the exchanger is an empty report.
+	report, err = export.ExchangerRevenue(context.Background(), worm, common.HexToAddress(exchangeAddress), 7, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Trades) != 0 || report.Fees.Sign() != 0 {
+		t.Errorf("trades %v in blocks 7..8", report.Trades)
+	}
+}
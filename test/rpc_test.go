@@ -4,7 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"github.com/erbieio/erb-client/client"
+	"github.com/erbieio/erb-client/v2/client"
 	"math/rand"
 	"testing"
 	"time"
@@ -0,0 +1,255 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/erbieio/erb-client/v2/client"
+	"github.com/erbieio/erb-client/v2/scanner"
+	"github.com/erbieio/erb-client/v2/simulated"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestFileCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	checkpoint := &scanner.FileCheckpoint{Path: filepath.Join(t.TempDir(), "checkpoint")}
+	if _, ok, err := checkpoint.Load(ctx); err != nil || ok {
+		t.Fatalf("empty checkpoint: ok %v err %v", ok, err)
+	}
+	if err := checkpoint.Save(ctx, 1024); err != nil {
+		t.Fatal(err)
+	}
+	next, ok, err := checkpoint.Load(ctx)
+	if err != nil || !ok || next != 1024 {
+		t.Fatalf("got %d %v %v, want 1024", next, ok, err)
+	}
+}
+
+func TestBackfillResume(t *testing.T) {
+	backend := simulated.NewBackend(nil)
+	defer backend.Close()
+	for i := 0; i < 10; i++ {
+		backend.Commit()
+	}
+	clients := []*client.Wormholes{backend.Client(""), backend.Client("")}
+	statePath := filepath.Join(t.TempDir(), "backfill.json")
+	cfg := scanner.BackfillConfig{UnitSize: 3, Retries: 1, RetryDelay: time.Millisecond}
+
+	var mu sync.Mutex
+	seen := make(map[uint64]int)
+	fail := true
+	handler := scanner.ScanUnits(func(s *scanner.Scanner) {
+		s.OnBlock(func(ctx context.Context, block *types.Block) error {
+			mu.Lock()
+			defer mu.Unlock()
+			if block.NumberU64() == 7 && fail {
+				return errors.New("unavailable")
+			}
+			seen[block.NumberU64()]++
+			return nil
+		})
+	})
+
+	cfg.State = &scanner.FileBackfillState{Path: statePath}
+	b, err := scanner.NewBackfill(clients, handler, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var backfillErr *scanner.BackfillError
+	if err := b.Run(context.Background(), 0, 10); !errors.As(err, &backfillErr) {
+		t.Fatalf("got err %v, want a backfill error", err)
+	}
+	if len(backfillErr.Failed) != 1 || backfillErr.Failed[0] != (scanner.Unit{From: 6, To: 8}) {
+		t.Fatalf("failed units %v, want [6..8]", backfillErr.Failed)
+	}
+
+	// A restarted backfill only runs the failed unit.
+	fail = false
+	seen = make(map[uint64]int)
+	cfg.State = &scanner.FileBackfillState{Path: statePath}
+	if b, err = scanner.NewBackfill(clients, handler, cfg); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Run(context.Background(), 0, 10); err != nil {
+		t.Fatal(err)
+	}
+	if len(seen) != 3 || seen[6] != 1 || seen[8] != 1 {
+		t.Errorf("restart scanned %v, want blocks 6..8", seen)
+	}
+	if done, total := b.Progress(); done != 4 || total != 4 {
+		t.Errorf("progress %d/%d, want 4/4", done, total)
+	}
+}
+
+// ctxCheckpoint fails like a database checkpoint when its context is done.
+type ctxCheckpoint struct {
+	scanner.MemoryCheckpoint
+}
+
+func (c *ctxCheckpoint) Save(ctx context.Context, next uint64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return c.MemoryCheckpoint.Save(ctx, next)
+}
+
+func TestScannerCancel(t *testing.T) {
+	backend := simulated.NewBackend(nil)
+	defer backend.Close()
+	for i := 0; i < 10; i++ {
+		backend.Commit()
+	}
+	checkpoint := &ctxCheckpoint{}
+	s := scanner.New(backend.Client(""), scanner.Config{Checkpoint: checkpoint, PollInterval: time.Millisecond})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var last uint64
+	s.OnBlock(func(ctx context.Context, block *types.Block) error {
+		last = block.NumberU64()
+		if last == 4 {
+			// Shut down while the block is handled, the handler fails with the cancellation.
+			cancel()
+			return ctx.Err()
+		}
+		return nil
+	})
+	if err := s.Run(ctx); err != context.Canceled {
+		t.Fatalf("got err %v, want %v", err, context.Canceled)
+	}
+	if last != 4 {
+		t.Errorf("delivered up to block %d after the cancellation, want 4", last)
+	}
+	next, err := s.Next(context.Background())
+	if err != nil || next != 4 {
+		t.Errorf("resumes at %d %v, want 4", next, err)
+	}
+
+	// A handler that completes the block while the shutdown is requested checkpoints it.
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	s = scanner.New(backend.Client(""), scanner.Config{Checkpoint: checkpoint, PollInterval: time.Millisecond})
+	s.OnBlock(func(ctx context.Context, block *types.Block) error {
+		last = block.NumberU64()
+		if last == 6 {
+			cancel()
+		}
+		return nil
+	})
+	if err := s.Run(ctx); err != context.Canceled {
+		t.Fatalf("got err %v, want %v", err, context.Canceled)
+	}
+	if next, err := s.Next(context.Background()); err != nil || next != 7 || last != 6 {
+		t.Errorf("resumes at %d %v after block %d, want 7 after 6", next, err, last)
+	}
+}
+
+func TestWatcherFinalized(t *testing.T) {
+	backend := simulated.NewBackend(nil)
+	defer backend.Close()
+	for i := 0; i < 10; i++ {
+		backend.Commit()
+	}
+	w := scanner.NewWatcher(backend.Client(""), scanner.Config{Confirmations: 3, PollInterval: time.Millisecond})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var delivered, finalized uint64
+	w.OnBlock(func(ctx context.Context, block *types.Block) error {
+		if delivered = block.NumberU64(); delivered == 10 {
+			cancel()
+		}
+		return nil
+	})
+	w.OnFinalized(func(ctx context.Context, block *types.Block) error {
+		finalized = block.NumberU64()
+		return nil
+	})
+	if err := w.Run(ctx); err != context.Canceled {
+		t.Fatalf("got err %v, want %v", err, context.Canceled)
+	}
+	if delivered != 10 || finalized != 7 {
+		t.Errorf("delivered up to block %d and finalized up to %d, want 10 and 7", delivered, finalized)
+	}
+	if next, err := w.Next(context.Background()); err != nil || next != 8 {
+		t.Errorf("resumes at %d %v, want 8", next, err)
+	}
+}
+
+// blockCounter serves a simulated node, counts its block requests and fails those of the
+// block failing.
+type blockCounter struct {
+	node    http.Handler
+	fetched atomic.Int32
+	failing string
+}
+
+func (c *blockCounter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	if strings.Contains(string(body), "eth_getBlockByNumber") {
+		if c.failing != "" && strings.Contains(string(body), `"`+c.failing+`"`) {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		c.fetched.Add(1)
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	c.node.ServeHTTP(w, r)
+}
+
+func TestScanRangeBatches(t *testing.T) {
+	backend := simulated.NewBackend(nil)
+	defer backend.Close()
+	for i := 0; i < 10; i++ {
+		backend.Commit()
+	}
+	counter := &blockCounter{node: backend.Handler()}
+	node := httptest.NewServer(counter)
+	defer node.Close()
+	worm := client.NewClient("", node.URL)
+	defer worm.CloseConnect()
+
+	// The blocks of a batch are delivered before the next batch is fetched.
+	checkpoint := &scanner.MemoryCheckpoint{}
+	s := scanner.New(worm, scanner.Config{BatchSize: 3, Checkpoint: checkpoint})
+	var delivered []uint64
+	s.OnBlock(func(ctx context.Context, block *types.Block) error {
+		number := block.NumberU64()
+		if fetched := uint64(counter.fetched.Load()); fetched > number/3*3+3 {
+			t.Errorf("%d blocks fetched when delivering block %d", fetched, number)
+		}
+		delivered = append(delivered, number)
+		return nil
+	})
+	if err := s.ScanRange(context.Background(), 0, 10); err != nil {
+		t.Fatal(err)
+	}
+	if len(delivered) != 11 || delivered[10] != 10 {
+		t.Errorf("delivered %v", delivered)
+	}
+
+	// A block that can not be fetched keeps the blocks before it.
+	counter.failing = "0x7"
+	checkpoint = &scanner.MemoryCheckpoint{}
+	s = scanner.New(worm, scanner.Config{BatchSize: 3, Checkpoint: checkpoint})
+	delivered = nil
+	s.OnBlock(func(ctx context.Context, block *types.Block) error {
+		delivered = append(delivered, block.NumberU64())
+		return nil
+	})
+	var rangeErr *client.RangeError
+	if err := s.ScanRange(context.Background(), 0, 10); !errors.As(err, &rangeErr) {
+		t.Fatalf("got err %v, want a RangeError", err)
+	}
+	if next, err := s.Next(context.Background()); err != nil || next != 7 || len(delivered) != 7 {
+		t.Errorf("resumes at %d %v after %v, want 7", next, err, delivered)
+	}
+}
@@ -0,0 +1,70 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/erbieio/erb-client/v2/client"
+	"github.com/erbieio/erb-client/v2/server"
+	"github.com/erbieio/erb-client/v2/tools"
+)
+
+func TestServerOrders(t *testing.T) {
+	srv, err := server.New(client.NewClient("", ""), server.Config{
+		APIKeys: []string{"secret"},
+		Wallet:  client.NewWallet(exchangerPriKey),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body := `{"exchanger_owner":"` + exchangeAddress + `","to":"` + exchangeAddress1 + `","block_number":"0x10"}`
+	req := httptest.NewRequest("POST", "/v1/orders/exchanger", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("without API key: got status %d", rec.Code)
+	}
+
+	req = httptest.NewRequest("POST", "/v1/orders/exchanger", strings.NewReader(body))
+	req.Header.Set("X-API-Key", "secret")
+	rec = httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got status %d: %s", rec.Code, rec.Body)
+	}
+	var auth struct {
+		ExchangerOwner string `json:"exchanger_owner"`
+		To             string `json:"to"`
+		BlockNumber    string `json:"block_number"`
+		Sig            string `json:"sig"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &auth); err != nil {
+		t.Fatal(err)
+	}
+	signer, err := tools.RecoverAddress(auth.ExchangerOwner+auth.To+auth.BlockNumber, auth.Sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.EqualFold(signer.Hex(), exchangeAddress) {
+		t.Errorf("order signed by %s, want %s", signer.Hex(), exchangeAddress)
+	}
+
+	for royalty, status := range map[string]int{"250": http.StatusOK, "0xfa": http.StatusOK, "10001": http.StatusBadRequest} {
+		body := `{"amount":"0xde0b6b3a7640000","royalty":"` + royalty + `","meta_url":"/ipfs/qqqqqqqqqq","exclusive_flag":"0","exchanger":"` + exchangeAddress + `","block_number":"0x10"}`
+		req := httptest.NewRequest("POST", "/v1/orders/seller2", strings.NewReader(body))
+		req.Header.Set("X-API-Key", "secret")
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, req)
+		if rec.Code != status {
+			t.Errorf("seller2 with royalty %s: got status %d: %s", royalty, rec.Code, rec.Body)
+			continue
+		}
+		if status == http.StatusOK && !strings.Contains(rec.Body.String(), `"royalty":"0xfa"`) {
+			t.Errorf("seller2 with royalty %s: %s", royalty, rec.Body)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/erbieio/erb-client/v2/client"
+	"github.com/erbieio/erb-client/v2/simulated"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestSimulatedMintTransfer(t *testing.T) {
+	seller := common.HexToAddress(sellerAddress)
+	buyer := common.HexToAddress(buyerAddress)
+	backend := simulated.NewBackend(map[common.Address]*big.Int{
+		seller: big.NewInt(1e18),
+	})
+	defer backend.Close()
+	worm := backend.Client(sellerPriKey)
+
+	if _, err := worm.Mint(10, "/ipfs/ddfd90be9408b4", ""); err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+	nft := "0x0000000000000000000000000000000000000001"
+	if owner := backend.Account(common.HexToAddress(nft)).Nft.Owner; owner != seller {
+		t.Fatalf("minted owner %s, want %s", owner, seller)
+	}
+
+	if _, err := worm.Transfer(nft, buyerAddress); err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+	if owner := backend.Account(common.HexToAddress(nft)).Nft.Owner; owner != buyer {
+		t.Fatalf("transferred owner %s, want %s", owner, buyer)
+	}
+	if n := backend.Account(buyer).Worm.NFTBalance; n != 1 {
+		t.Errorf("buyer nft balance %d, want 1", n)
+	}
+}
+
+func TestSimulatedHistoryFork(t *testing.T) {
+	seller := common.HexToAddress(sellerAddress)
+	backend := simulated.NewBackend(map[common.Address]*big.Int{
+		seller: big.NewInt(1e18),
+	})
+	defer backend.Close()
+	worm := backend.Client(sellerPriKey)
+	ctx := context.Background()
+	genesis, err := worm.HeaderByNumber(ctx, big.NewInt(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hash, err := worm.MintCtx(ctx, 10, "/ipfs/ddfd90be9408b4", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	minted := backend.Commit()
+	nft := "0x0000000000000000000000000000000000000001"
+	// The state queries of a past block are answered from the state after it.
+	if balance, err := worm.BalanceAtTag(ctx, sellerAddress, client.AtBlockHash(genesis.Hash)); err != nil || balance.Int64() != 1e18 {
+		t.Errorf("genesis balance %v %v, want 1e18", balance, err)
+	}
+	if balance, err := worm.BalanceAt(ctx, sellerAddress, big.NewInt(1)); err != nil || balance.Int64() >= 1e18 {
+		t.Errorf("balance %v %v after paying the mint", balance, err)
+	}
+	if account, err := worm.GetAccountInfo(ctx, nft, 0); err != nil || account.Nft.Owner != (common.Address{}) {
+		t.Errorf("nft before the mint %v %v", account, err)
+	}
+	if _, err := worm.BalanceAt(ctx, sellerAddress, big.NewInt(5)); err == nil {
+		t.Error("no error for the state of a future block")
+	}
+
+	// A fork at genesis replaces the block of the mint.
+	if err := backend.Fork(genesis.Hash); err != nil {
+		t.Fatal(err)
+	}
+	if backend.Commit() == minted {
+		t.Fatal("the block of the new chain has the hash of the discarded block")
+	}
+	if _, err := worm.TransactionReceipt(ctx, hash); !errors.Is(err, ethereum.NotFound) {
+		t.Errorf("receipt of the discarded transaction: %v", err)
+	}
+	if owner := backend.Account(common.HexToAddress(nft)).Nft.Owner; owner != (common.Address{}) {
+		t.Errorf("nft owned by %s after the fork", owner)
+	}
+	if balance, err := worm.BalanceAtTag(ctx, sellerAddress, client.Latest); err != nil || balance.Int64() != 1e18 {
+		t.Errorf("balance %v %v after the fork, want 1e18", balance, err)
+	}
+	if err := backend.Fork(minted); err == nil {
+		t.Error("no error for a fork at a discarded block")
+	}
+}
@@ -0,0 +1,138 @@
+package test
+
+import (
+	"context"
+	"database/sql"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	_ "github.com/erbieio/erb-client/v2/internal/sqlite"
+	"github.com/erbieio/erb-client/v2/store"
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// openSQLite creates a SQLite store in a temporary file, the test is skipped without the
+// driver of internal/sqlite.
+func openSQLite(t *testing.T) (*store.SQLStore, *sql.DB) {
+	hasDriver := false
+	for _, name := range sql.Drivers() {
+		hasDriver = hasDriver || name == "sqlite3"
+	}
+	if !hasDriver {
+		t.Skip("no sqlite3 driver, run with -tags sqlite3")
+	}
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "store.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := store.NewSQLite(context.Background(), db)
+	if err != nil {
+		db.Close()
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s, db
+}
+
+func storeTrade(number uint64, nft string, price int64) *types2.Trade {
+	return &types2.Trade{
+		TxHash:      common.BigToHash(new(big.Int).SetUint64(number)),
+		BlockNumber: number,
+		Type:        types2.BuyerInitiatingTransaction,
+		Buyer:       common.HexToAddress(buyerAddress),
+		Seller:      common.HexToAddress(sellerAddress),
+		Exchanger:   common.HexToAddress(exchangeAddress),
+		NFTAddress:  nft,
+		Price:       big.NewInt(price),
+		Creator:     common.HexToAddress(sellerAddress),
+		RoyaltyPaid: big.NewInt(price / 10),
+		FeePaid:     new(big.Int).Mul(big.NewInt(price), big.NewInt(1e18)),
+	}
+}
+
+func TestSQLStore(t *testing.T) {
+	s, db := openSQLite(t)
+	ctx := context.Background()
+	nft := "0x000000000000000000000000000000000000000A"
+	other := "0x000000000000000000000000000000000000000b"
+
+	if _, ok, err := s.GetCheckpoint(ctx); ok || err != nil {
+		t.Fatalf("checkpoint of an empty store: %v %v", ok, err)
+	}
+	for number := uint64(1); number <= 3; number++ {
+		if err := s.SaveBlock(ctx, &store.Block{Number: number, Hash: common.BigToHash(big.NewInt(int64(number))), Time: 1000 + number, TxCount: 1}); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.SaveTrade(ctx, storeTrade(number, nft, int64(number)*100)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A trade saved again when a block is indexed twice is stored once.
+	if err := s.SaveTrade(ctx, storeTrade(2, nft, 200)); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveNFTState(ctx, &store.NFTState{Address: other, Owner: common.HexToAddress(sellerAddress), Royalty: 100, BlockNumber: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveNFTState(ctx, &store.NFTState{Address: nft, Owner: common.HexToAddress(buyerAddress), MetaURL: "/ipfs/a", BlockNumber: 3}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetCheckpoint(ctx, 4); err != nil {
+		t.Fatal(err)
+	}
+	if next, ok, err := s.GetCheckpoint(ctx); next != 4 || !ok || err != nil {
+		t.Errorf("checkpoint %d %v %v, want 4", next, ok, err)
+	}
+
+	trades, err := s.Trades(ctx, strings.ToLower(nft))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trades) != 3 {
+		t.Fatalf("%d trades, want 3", len(trades))
+	}
+	want := storeTrade(2, strings.ToLower(nft), 200)
+	got := trades[1]
+	if got.TxHash != want.TxHash || got.BlockNumber != 2 || got.Type != want.Type || got.Buyer != want.Buyer ||
+		got.Seller != want.Seller || got.Exchanger != want.Exchanger || got.NFTAddress != want.NFTAddress ||
+		got.Price.Cmp(want.Price) != 0 || got.RoyaltyPaid.Cmp(want.RoyaltyPaid) != 0 || got.FeePaid.Cmp(want.FeePaid) != 0 {
+		t.Errorf("trade %+v, want %+v", got, want)
+	}
+	history, err := s.TradeHistory(ctx, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 2 || history[0].BlockNumber != 2 || history[0].Time != 1002 || history[1].Time != 1003 {
+		t.Errorf("history %v", history)
+	}
+
+	// Roll back blocks 3 and above.
+	if err := s.DeleteFrom(ctx, 3); err != nil {
+		t.Fatal(err)
+	}
+	if trades, err := s.Trades(ctx, nft); err != nil || len(trades) != 2 {
+		t.Errorf("%d trades after the rollback, %v", len(trades), err)
+	}
+	var blocks, states int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM blocks`).Scan(&blocks); err != nil {
+		t.Fatal(err)
+	}
+	var state string
+	if err := db.QueryRow(`SELECT COUNT(*), MIN(address) FROM nft_states`).Scan(&states, &state); err != nil {
+		t.Fatal(err)
+	}
+	if blocks != 2 || states != 1 || state != other {
+		t.Errorf("%d blocks and %d NFT states (%s) after the rollback, want 2 and 1 (%s)", blocks, states, state, other)
+	}
+
+	// A corrupt amount is an error rather than a price of 0.
+	if _, err := db.Exec(`UPDATE trades SET fee_paid = 'x' WHERE block_number = 2`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Trades(ctx, nft); err == nil || !strings.Contains(err.Error(), "fee_paid") {
+		t.Errorf("trades with a corrupt amount: %v", err)
+	}
+}
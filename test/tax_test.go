@@ -0,0 +1,172 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/erbieio/erb-client/v2/client"
+	"github.com/erbieio/erb-client/v2/export"
+	"github.com/erbieio/erb-client/v2/simulated"
+	"github.com/erbieio/erb-client/v2/tools"
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestApplyCostBasis(t *testing.T) {
+	type event struct {
+		category string
+		asset    string
+		quantity int64
+		value    int64
+	}
+	tests := []struct {
+		name   string
+		method export.CostBasisMethod
+		events []event
+		// basis and gain of the disposals, in order.
+		basis, gain []int64
+	}{
+		{"fifo buy sell", export.FIFO,
+			[]event{{export.Acquisition, "A", 1, 100}, {export.Disposal, "A", 1, 150}},
+			[]int64{100}, []int64{50}},
+		{"fifo oldest first", export.FIFO,
+			[]event{{export.Acquisition, "A", 1, 100}, {export.Acquisition, "A", 1, 300}, {export.Disposal, "A", 1, 200}, {export.Disposal, "A", 1, 200}},
+			[]int64{100, 300}, []int64{100, -100}},
+		{"fifo partial", export.FIFO,
+			[]event{{export.Acquisition, "A", 10, 100}, {export.Acquisition, "A", 10, 300}, {export.Disposal, "A", 15, 600}, {export.Disposal, "A", 10, 100}},
+			[]int64{250, 150}, []int64{350, -50}},
+		{"average partial", export.AverageCost,
+			[]event{{export.Acquisition, "A", 10, 100}, {export.Acquisition, "A", 10, 300}, {export.Disposal, "A", 15, 600}, {export.Disposal, "A", 10, 100}},
+			[]int64{300, 100}, []int64{300, 0}},
+		{"average after a disposal", export.AverageCost,
+			[]event{{export.Acquisition, "A", 2, 100}, {export.Disposal, "A", 1, 80}, {export.Acquisition, "A", 1, 200}, {export.Disposal, "A", 2, 300}},
+			[]int64{50, 250}, []int64{30, 50}},
+		{"assets apart", export.FIFO,
+			[]event{{export.Acquisition, "A", 1, 100}, {export.Acquisition, "B", 1, 500}, {export.Disposal, "B", 1, 400}},
+			[]int64{500}, []int64{-100}},
+		{"rewarded NFT", export.FIFO,
+			[]event{{export.RewardIncome, "S", 1, 40}, {export.RewardIncome, export.ERB, 7, 7}, {export.Disposal, "S", 1, 100}},
+			[]int64{40}, []int64{60}},
+		{"not held", export.AverageCost,
+			[]event{{export.Disposal, "A", 1, 100}},
+			[]int64{0}, []int64{100}},
+	}
+	for _, tt := range tests {
+		var events, disposals []*export.TaxEvent
+		for _, ev := range tt.events {
+			events = append(events, &export.TaxEvent{Category: ev.category, Asset: ev.asset, Quantity: big.NewInt(ev.quantity), Value: big.NewInt(ev.value)})
+			if ev.category == export.Disposal {
+				disposals = append(disposals, events[len(events)-1])
+			}
+		}
+		if err := export.ApplyCostBasis(events, tt.method); err != nil {
+			t.Fatal(err)
+		}
+		for i, ev := range disposals {
+			if ev.CostBasis.Int64() != tt.basis[i] || ev.Gain.Int64() != tt.gain[i] {
+				t.Errorf("%s: disposal %d basis %s gain %s, want %d %d", tt.name, i, ev.CostBasis, ev.Gain, tt.basis[i], tt.gain[i])
+			}
+		}
+	}
+	if err := export.ApplyCostBasis(nil, export.CostBasisMethod(7)); err == nil {
+		t.Error("no error for an unknown method")
+	}
+}
+
+// rewardedNode serves backend over HTTP and reports accounts as the ERB reward beneficiaries
+// of every block, which the simulated chain does not pay.
+func rewardedNode(backend *simulated.Backend, accounts ...common.Address) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		if json.Unmarshal(body, &req) == nil && req.Method == "eth_getBlockBeneficiaryAddressByNumber" {
+			var list types2.BeneficiaryAddressList
+			for _, account := range accounts {
+				list = append(list, &types2.BeneficiaryAddress{Address: account})
+			}
+			result, _ := json.Marshal(list)
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"jsonrpc":"2.0","id":` + string(req.ID) + `,"result":` + string(result) + `}`))
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		backend.Handler().ServeHTTP(w, r)
+	}))
+}
+
+// sellerSentTrade mints an NFT of the seller and sells it to the buyer with a TransactionNFT
+// sent by the seller, each in its own block. It returns the NFT address.
+func sellerSentTrade(t *testing.T, backend *simulated.Backend, price string) string {
+	ctx := context.Background()
+	seller := backend.Client(sellerPriKey)
+	if _, err := seller.MintCtx(ctx, 10, "/ipfs/ddfd90be9408b4", ""); err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+	nft := "0x0000000000000000000000000000000000000001"
+	order, err := backend.Client(buyerPriKey).Wallet.SignBuyer(price, nft, "", tools.EncodeBlockNumber(100), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := seller.TransactionNFTCtx(ctx, order, buyerAddress); err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+	return nft
+}
+
+func TestTaxEventsSellerSentTrade(t *testing.T) {
+	seller, buyer := common.HexToAddress(sellerAddress), common.HexToAddress(buyerAddress)
+	backend := simulated.NewBackend(map[common.Address]*big.Int{
+		seller: types2.ERB(10).Wei(),
+		buyer:  types2.ERB(10).Wei(),
+	})
+	defer backend.Close()
+	nft := sellerSentTrade(t, backend, "0xde0b6b3a7640000")
+	price := types2.ERB(1).Wei()
+	// Both accounts are reported as rewarded in every block, the balance change of the trade
+	// must be explained rather than taken for a reward.
+	node := rewardedNode(backend, seller, buyer)
+	defer node.Close()
+	worm := client.NewClient("", node.URL)
+	defer worm.CloseConnect()
+	ctx := context.Background()
+
+	events, err := export.TaxEvents(ctx, worm, seller, 1, 2, export.TaxConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var disposal *export.TaxEvent
+	for _, ev := range events {
+		switch ev.Category {
+		case export.Disposal:
+			disposal = ev
+		case export.Fee:
+		default:
+			t.Errorf("seller event %s of %s in block %d", ev.Category, ev.Value, ev.Block)
+		}
+	}
+	if disposal == nil || disposal.Block != 2 || !tools.SameAddress(disposal.Asset, nft) || disposal.Value.Cmp(price) != 0 {
+		t.Errorf("seller disposal %+v, want %s for %s", disposal, price, nft)
+	}
+
+	events, err = export.TaxEvents(ctx, worm, buyer, 1, 2, export.TaxConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].Category != export.Acquisition || events[0].Value.Cmp(price) != 0 {
+		for _, ev := range events {
+			t.Errorf("buyer event %+v", ev)
+		}
+		t.Fatalf("%d buyer events, want the acquisition for %s", len(events), price)
+	}
+}
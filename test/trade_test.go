@@ -0,0 +1,178 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/erbieio/erb-client/v2/client"
+	"github.com/erbieio/erb-client/v2/simulated"
+	"github.com/erbieio/erb-client/v2/tools"
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// tradeTx returns a transaction of payload sent by the account of priKey to to with value.
+func tradeTx(t *testing.T, priKey string, to common.Address, value *big.Int, payload *types2.Transaction) *types.Transaction {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := crypto.HexToECDSA(priKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := types.NewTransaction(0, to, value, 100000, simulated.GasPrice, append([]byte(client.TranPrefix), data...))
+	signed, err := types.SignTx(tx, types.NewEIP155Signer(simulated.DefaultChainID), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+// signedOrder signs an order with the wallet of priKey and decodes it into order.
+func signedOrder(t *testing.T, order interface{}, sign func(w *client.Wallet) ([]byte, error), priKey string) {
+	data, err := sign(client.NewWallet(priKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, order); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDecodeTradeTypes(t *testing.T) {
+	// The exchanger charges 2% and the minted NFT 0x...01 of the seller has a royalty of 1%
+	// at block 4. The fee rate set in block 5 applies from the trades of block 6.
+	backend := tradingBackend(t, 200, 100)
+	commit(t, backend, func(ctx context.Context) (string, error) {
+		return backend.Client(exchangerPriKey).TokenPledgeCtx(ctx, common.HexToAddress(exchangeAddress), "", "exchanger", "https://exchanger", types2.ERB(1), 500)
+	})
+	worm := backend.Client("")
+	defer worm.CloseConnect()
+	ctx := context.Background()
+
+	nft := nftAddress(1)
+	expiry := tools.EncodeBlockNumber(100)
+	// The orders are signed for another amount than the price paid with the transaction.
+	amount := types2.ERB(3).Hex()
+	var buyer types2.Buyer
+	signedOrder(t, &buyer, func(w *client.Wallet) ([]byte, error) {
+		return w.SignBuyer(amount, nft, exchangeAddress, expiry, "")
+	}, buyerPriKey)
+	var unmintedBuyer types2.Buyer
+	signedOrder(t, &unmintedBuyer, func(w *client.Wallet) ([]byte, error) {
+		return w.SignBuyer(amount, "", exchangeAddress, expiry, "")
+	}, buyerPriKey)
+	var seller1 types2.Seller1
+	signedOrder(t, &seller1, func(w *client.Wallet) ([]byte, error) {
+		return w.SignSeller1(amount, nft, exchangeAddress, expiry)
+	}, sellerPriKey)
+	var seller2 types2.Seller2
+	signedOrder(t, &seller2, func(w *client.Wallet) ([]byte, error) {
+		return w.SignSeller2(amount, "0x12c", "/ipfs/unminted", "0", exchangeAddress, expiry)
+	}, priKey)
+
+	seller, buyerAddr := common.HexToAddress(sellerAddress), common.HexToAddress(buyerAddress)
+	creator := common.HexToAddress(priAddress)
+	price := types2.ERB(1).Wei()
+	share := func(rate int64) *big.Int {
+		return new(big.Int).Div(new(big.Int).Mul(price, big.NewInt(rate)), big.NewInt(types2.RateDenominator))
+	}
+	tests := []struct {
+		name    string
+		payload *types2.Transaction
+		sender  string
+		// A minted trade sells the NFT of the seller with a royalty of 1%, an unminted one the
+		// NFT of the creator signing seller2 with a royalty of 3%.
+		minted bool
+	}{
+		{"TransactionNFT", &types2.Transaction{Type: types2.TransactionNFT, Buyer: &buyer}, sellerPriKey, true},
+		{"BuyerInitiatingTransaction", &types2.Transaction{Type: types2.BuyerInitiatingTransaction, Seller1: &seller1}, buyerPriKey, true},
+		{"FoundryTradeBuyer", &types2.Transaction{Type: types2.FoundryTradeBuyer, Seller2: &seller2}, buyerPriKey, false},
+		{"FoundryExchange", &types2.Transaction{Type: types2.FoundryExchange, Buyer: &unmintedBuyer, Seller2: &seller2}, exchangerPriKey, false},
+		{"FoundryExchange without buy order", &types2.Transaction{Type: types2.FoundryExchange, Seller2: &seller2}, exchangerPriKey, false},
+		{"NftExchangeMatch", &types2.Transaction{Type: types2.NftExchangeMatch, Buyer: &buyer, Seller1: &seller1}, exchangerPriKey, true},
+		{"FoundryExchangeInitiated", &types2.Transaction{Type: types2.FoundryExchangeInitiated, Buyer: &unmintedBuyer, Seller2: &seller2}, exchangerPriKey, false},
+		{"FtDoesNotAuthorizeExchanges", &types2.Transaction{Type: types2.FtDoesNotAuthorizeExchanges, Buyer: &buyer, Seller1: &seller1}, exchangerPriKey, true},
+		{"BatchSellTransfer", &types2.Transaction{Type: types2.BatchSellTransfer, Buyer: &buyer, Seller1: &seller1}, sellerPriKey, true},
+		{"ForceBuyingTransfer", &types2.Transaction{Type: types2.ForceBuyingTransfer, Buyer: &buyer}, exchangerPriKey, true},
+	}
+	for _, tt := range tests {
+		// The transaction is sent to the buyer, as by the seller or an exchanger.
+		tx := tradeTx(t, tt.sender, buyerAddr, price, tt.payload)
+		receipt := &types.Receipt{Status: types.ReceiptStatusSuccessful, BlockNumber: big.NewInt(5), TxHash: tx.Hash()}
+		trade, err := worm.DecodeTrade(ctx, tx, receipt)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		wantSeller, wantNFT, wantMeta, wantRoyalty := seller, nft, "/ipfs/ddfd90be9408b4", share(100)
+		if !tt.minted {
+			wantSeller, wantNFT, wantMeta, wantRoyalty = creator, "", "/ipfs/unminted", share(300)
+		}
+		if trade.Type != tt.payload.Type || trade.BlockNumber != 5 || trade.TxHash != tx.Hash() {
+			t.Errorf("%s: type %d block %d tx %s", tt.name, trade.Type, trade.BlockNumber, trade.TxHash.Hex())
+		}
+		if trade.Buyer != buyerAddr || trade.Seller != wantSeller || trade.Creator != wantSeller {
+			t.Errorf("%s: buyer %s seller %s creator %s, want %s %s", tt.name, trade.Buyer, trade.Seller, trade.Creator, buyerAddr, wantSeller)
+		}
+		if trade.NFTAddress != wantNFT || trade.MetaURL != wantMeta || trade.Exchanger != common.HexToAddress(exchangeAddress) {
+			t.Errorf("%s: nft %q meta %q exchanger %s", tt.name, trade.NFTAddress, trade.MetaURL, trade.Exchanger)
+		}
+		if trade.Price.Cmp(price) != 0 || trade.RoyaltyPaid.Cmp(wantRoyalty) != 0 || trade.FeePaid.Cmp(share(200)) != 0 {
+			t.Errorf("%s: price %s royalty %s fee %s, want %s %s %s", tt.name, trade.Price, trade.RoyaltyPaid, trade.FeePaid, price, wantRoyalty, share(200))
+		}
+	}
+
+	// The fee rate is the one of the block before the trade.
+	tx := tradeTx(t, sellerPriKey, buyerAddr, price, &types2.Transaction{Type: types2.TransactionNFT, Buyer: &buyer})
+	trade, err := worm.DecodeTrade(ctx, tx, &types.Receipt{Status: types.ReceiptStatusSuccessful, BlockNumber: big.NewInt(6)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if trade.FeePaid.Cmp(share(500)) != 0 {
+		t.Errorf("fee %s after the change of the fee rate, want %s", trade.FeePaid, share(500))
+	}
+
+	if _, err := worm.DecodeTrade(ctx, tx, &types.Receipt{Status: types.ReceiptStatusFailed, BlockNumber: big.NewInt(6)}); !errors.Is(err, client.ErrTradeFailed) {
+		t.Errorf("failed trade: %v", err)
+	}
+	transfer := tradeTx(t, sellerPriKey, buyerAddr, new(big.Int), &types2.Transaction{Type: types2.Transfer, NFTAddress: nft})
+	if _, err := worm.DecodeTrade(ctx, transfer, &types.Receipt{Status: types.ReceiptStatusSuccessful, BlockNumber: big.NewInt(6)}); !errors.Is(err, client.ErrNotTrade) {
+		t.Errorf("transfer: %v", err)
+	}
+}
+
+func TestDecodeMinedTrade(t *testing.T) {
+	// A trade sent by the seller and mined by the backend decodes to the signer of the buy
+	// order as the buyer and the previous owner as the seller.
+	backend := tradingBackend(t, 200, 100)
+	sellTo(t, backend, sellerPriKey, buyerPriKey, buyerAddress, nftAddress(1), exchangeAddress, types2.ERB(1))
+	worm := backend.Client("")
+	defer worm.CloseConnect()
+	ctx := context.Background()
+	block, err := worm.BlockByNumber(ctx, big.NewInt(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := block.Transactions()[0]
+	receipt, err := worm.TransactionReceipt(ctx, tx.Hash().Hex())
+	if err != nil {
+		t.Fatal(err)
+	}
+	trade, err := worm.DecodeTrade(ctx, tx, receipt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if trade.Buyer != common.HexToAddress(buyerAddress) || trade.Seller != common.HexToAddress(sellerAddress) {
+		t.Errorf("buyer %s seller %s", trade.Buyer, trade.Seller)
+	}
+	if trade.Price.Cmp(types2.ERB(1).Wei()) != 0 || trade.RoyaltyPaid.Cmp(big.NewInt(1e16)) != 0 || trade.FeePaid.Cmp(big.NewInt(2e16)) != 0 {
+		t.Errorf("price %s royalty %s fee %s", trade.Price, trade.RoyaltyPaid, trade.FeePaid)
+	}
+}
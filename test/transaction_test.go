@@ -5,11 +5,11 @@ import (
 	"fmt"
 	"log"
 	"math/big"
-	"strings"
 	"testing"
 	"time"
 
-	"github.com/erbieio/erb-client/client"
+	"github.com/erbieio/erb-client/v2/client"
+	"github.com/erbieio/erb-client/v2/tools"
 	"github.com/ethereum/go-ethereum/common"
 )
 
@@ -159,7 +159,7 @@ func TestTokenRevokesPledge(t *testing.T) {
 func TestTransactionNFT(t *testing.T) {
 	worm := client.NewClient(buyerPriKey, endpoint)
 	number, _ := worm.BlockNumber(context.Background())
-	blockNumber := fmt.Sprintf("0x%x", number+10)
+	blockNumber := tools.EncodeBlockNumber(number + 10)
 	buyer, err := worm.Wallet.SignBuyer("0xde0b6b3a7640000", "0x0000000000000000000000000000000000000002", "0x8b07aff2327a3B7e2876D899caFac99f7AE16B10", blockNumber, "")
 	if err != nil {
 		log.Fatalln("Signing failed")
@@ -541,7 +541,7 @@ func TestGetSNFT(t *testing.T) {
 		for ex, pri := range exchanger {
 			fmt.Println((*res1).Nft.Owner.String())
 			fmt.Println(ex)
-			if strings.ToLower(ex) == strings.ToLower(res1.Nft.Owner.String()) {
+			if tools.SameAddress(ex, res1.Nft.Owner.String()) {
 				worms := client.NewClient(pri, "http://192.168.4.240:8561")
 				worms.Transfer(common.BytesToAddress(Nft.Bytes()).String(), collects)
 				break
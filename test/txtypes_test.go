@@ -0,0 +1,81 @@
+package test
+
+import (
+	"encoding/json"
+	"os/exec"
+	"reflect"
+	"testing"
+
+	"github.com/erbieio/erb-client/v2/client"
+	types2 "github.com/erbieio/erb-client/v2/types"
+)
+
+func TestDecodePayload(t *testing.T) {
+	full := types2.Transaction{
+		Dir:           "wormholes",
+		StartIndex:    "0x1",
+		Number:        2,
+		NFTAddress:    "0x0000000000000000000000000000000000000001",
+		Royalty:       100,
+		MetaURL:       "ipfs://meta",
+		Exchanger:     exchangeAddress,
+		FeeRate:       10,
+		Name:          "exchanger",
+		Url:           "www.example.com",
+		Buyer:         &types2.Buyer{Amount: "0x1", NFTAddress: "0x0000000000000000000000000000000000000001"},
+		Seller1:       &types2.Seller1{Amount: "0x1", NFTAddress: "0x0000000000000000000000000000000000000001"},
+		Seller2:       &types2.Seller2{Amount: "0x1", Royalty: "0x64"},
+		BuyerAuth:     &types2.Buyauth{Exchanger: exchangeAddress},
+		SellerAuth:    &types2.Sellerauth{Exchanger: exchangeAddress},
+		ExchangerAuth: &types2.ExchangerAuth{ExchangerOwner: exchangeAddress},
+		Creator:       sellerAddress,
+		RewardFlag:    1,
+		ProxyAddress:  tempAddress,
+		ProxySign:     "0x01",
+	}
+	for _, typ := range types2.Types() {
+		tx := full
+		tx.Type = typ
+		payload, err := types2.DecodePayload(&tx)
+		if err != nil {
+			t.Fatalf("%s: %v", types2.TypeName(typ), err)
+		}
+		if payload.Type() != typ {
+			t.Errorf("%s: payload type %d", types2.TypeName(typ), payload.Type())
+		}
+		sent := payload.Transaction()
+		if sent.Type != typ || sent.Version != types2.WormHolesVersion {
+			t.Errorf("%s: transaction type %d version %s", types2.TypeName(typ), sent.Type, sent.Version)
+		}
+		data, err := json.Marshal(sent)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded, err := client.DecodeWormholesData(append([]byte(client.TranPrefix), data...))
+		if err != nil {
+			t.Fatalf("%s: %v", types2.TypeName(typ), err)
+		}
+		if !reflect.DeepEqual(decoded, payload) {
+			t.Errorf("%s: decoded %+v, want %+v", types2.TypeName(typ), decoded, payload)
+		}
+	}
+
+	mint, _ := types2.DecodePayload(&types2.Transaction{Type: types2.Mint, Royalty: 100, NFTAddress: "0x01"})
+	if p, ok := mint.(*types2.MintPayload); !ok || p.Royalty != 100 || p.Transaction().NFTAddress != "" {
+		t.Errorf("mint payload %+v keeps the fields of other types", mint)
+	}
+	if _, err := types2.DecodePayload(&types2.Transaction{Type: 11}); err == nil {
+		t.Error("decoded an unknown type")
+	}
+	if _, err := client.DecodeWormholesData([]byte("hello")); err == nil {
+		t.Error("decoded data without the wormholes prefix")
+	}
+}
+
+func TestGeneratedTypesUpToDate(t *testing.T) {
+	cmd := exec.Command("go", "run", "../internal/gentypes", "-check")
+	cmd.Dir = "../types"
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("%v: %s", err, out)
+	}
+}
@@ -1,9 +1,18 @@
 package test
 
 import (
+	"encoding/json"
 	"fmt"
-	"github.com/erbieio/erb-client/tools"
+	"math/big"
+	"strings"
 	"testing"
+
+	"github.com/erbieio/erb-client/v2/client"
+	"github.com/erbieio/erb-client/v2/server"
+	"github.com/erbieio/erb-client/v2/simulated"
+	"github.com/erbieio/erb-client/v2/tools"
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum/common"
 )
 
 func TestToHex16(t *testing.T) {
@@ -33,3 +42,177 @@ func TestPriKeyToAddress(t *testing.T) {
 	fmt.Println(accoount)
 	fmt.Println(fromKey)
 }
+
+func TestTypeNames(t *testing.T) {
+	if types2.Mint != 0 || types2.TransactionNFT != 14 || types2.AccountDelegate != 31 {
+		t.Error("wormholes transaction type codes changed")
+	}
+	all := types2.Types()
+	if len(all) != 28 || all[0] != types2.Mint || all[len(all)-1] != types2.AccountDelegate {
+		t.Errorf("types %v", all)
+	}
+	for _, typ := range all {
+		name := types2.TypeName(typ)
+		if got, ok := types2.TypeByName(strings.ToLower(name)); !ok || got != typ {
+			t.Errorf("TypeByName(%s) = %d %v, want %d", name, got, ok, typ)
+		}
+	}
+	if _, ok := types2.TypeByName("Unknown"); ok || types2.TypeName(11) != "Unknown" {
+		t.Error("unused type code has a name")
+	}
+	if typ, ok := types2.TypeByName("UnforzenAccount"); !ok || typ != types2.UnfreezeAccount {
+		t.Errorf("TypeByName of the former name UnforzenAccount = %d %v", typ, ok)
+	}
+}
+
+func TestTradeTypes(t *testing.T) {
+	if !types2.IsTrade(types2.TransactionNFT) || types2.SenderIsBuyer(types2.TransactionNFT) {
+		t.Error("TransactionNFT is a trade initiated by the seller")
+	}
+	if !types2.IsTrade(types2.BuyerInitiatingTransaction) || !types2.SenderIsBuyer(types2.BuyerInitiatingTransaction) {
+		t.Error("BuyerInitiatingTransaction is a trade initiated by the buyer")
+	}
+	if types2.IsTrade(types2.Transfer) || types2.SenderIsBuyer(types2.Transfer) {
+		t.Error("Transfer is not a trade")
+	}
+}
+
+func TestDeprecatedMethods(t *testing.T) {
+	seller := common.HexToAddress(sellerAddress)
+	backend := simulated.NewBackend(map[common.Address]*big.Int{
+		seller: big.NewInt(1e18),
+	})
+	defer backend.Close()
+	worm := backend.Client(sellerPriKey)
+
+	// The former names send the transactions of the new ones.
+	if _, err := worm.AccountAuthor(exchangeAddress); err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+	if approved := backend.Account(seller).Worm.ApproveAddressList; len(approved) != 1 || approved[0] != common.HexToAddress(exchangeAddress) {
+		t.Fatalf("approved %v after AccountAuthor, want %s", approved, exchangeAddress)
+	}
+	if _, err := worm.AccountAuthorRevoke(exchangeAddress); err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+	if approved := backend.Account(seller).Worm.ApproveAddressList; len(approved) != 0 {
+		t.Errorf("approved %v after AccountAuthorRevoke", approved)
+	}
+}
+
+func TestEncoding(t *testing.T) {
+	wei := big.NewInt(1e18)
+	if got := tools.EncodeAmount(wei); got != "0xde0b6b3a7640000" {
+		t.Errorf("EncodeAmount(1e18) = %s", got)
+	}
+	if got := tools.EncodeAmount(nil); got != "0x0" {
+		t.Errorf("EncodeAmount(nil) = %s", got)
+	}
+	if got := tools.EncodeBlockNumber(0); got != "0x0" {
+		t.Errorf("EncodeBlockNumber(0) = %s", got)
+	}
+	if got := tools.EncodeRoyalty(250); got != "0xfa" {
+		t.Errorf("EncodeRoyalty(250) = %s", got)
+	}
+	if got := tools.EncodeAddress(common.HexToAddress(strings.ToLower(exchangeAddress))); !tools.SameAddress(got, exchangeAddress) || got != tools.ChecksumAddress(got) {
+		t.Errorf("EncodeAddress = %s", got)
+	}
+
+	if number, err := tools.DecodeBlockNumber("0x10"); err != nil || number != 16 {
+		t.Errorf("DecodeBlockNumber(0x10) = %d %v", number, err)
+	}
+	for _, s := range []string{"16", "0x010", "0x", ""} {
+		if _, err := tools.DecodeBlockNumber(s); err == nil {
+			t.Errorf("DecodeBlockNumber(%q) succeeded", s)
+		}
+		if _, err := tools.DecodeAmount(s); err == nil {
+			t.Errorf("DecodeAmount(%q) succeeded", s)
+		}
+	}
+	if amount, err := tools.DecodeAmount("0xde0b6b3a7640000"); err != nil || amount.Cmp(wei) != 0 {
+		t.Errorf("DecodeAmount = %v %v", amount, err)
+	}
+
+	// The helpers encode the fields as the order constructors do.
+	buyer, err := types2.NewBuyer(types2.Wei(wei), "", exchangeAddress, 16, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buyer.Amount != tools.EncodeAmount(wei) || buyer.BlockNumber != tools.EncodeBlockNumber(16) || buyer.Exchanger != tools.EncodeAddress(common.HexToAddress(exchangeAddress)) {
+		t.Errorf("buyer %+v", buyer)
+	}
+}
+
+func TestAccountJSON(t *testing.T) {
+	// The node encodes accounts with the Go field names.
+	node := `{"Nonce":3,"Balance":1500000000000000000,"Worm":{"PledgedBalance":70000000000000000000000,"ExchangerFlag":true,"ExchangerName":"market","ExchangerURL":"https://market","FeeRate":250,"LockSNFTFlag":true,"ApproveAddressList":["` + exchangeAddress + `"]},"Nft":{"Owner":"` + sellerAddress + `","MetaURL":"/ipfs/qqqqqqqqqq","Royalty":250}}`
+	var account types2.Account
+	if err := json.Unmarshal([]byte(node), &account); err != nil {
+		t.Fatal(err)
+	}
+	worm := account.Worm
+	if account.Nonce != 3 || worm == nil || worm.ExchangerURL != "https://market" || !worm.LockSNFTFlag || len(worm.ApproveAddressList) != 1 || account.Nft.MetaURL != "/ipfs/qqqqqqqqqq" {
+		t.Fatalf("decoded %+v %+v", account, worm)
+	}
+
+	encoded, err := json.Marshal(account)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{`"nonce":3`, `"balance":1500000000000000000`, `"exchangerUrl":"https://market"`, `"lockSnftFlag":true`, `"metaUrl":"/ipfs/qqqqqqqqqq"`} {
+		if !strings.Contains(string(encoded), key) {
+			t.Errorf("encoded account has no %s: %s", key, encoded)
+		}
+	}
+	var again types2.Account
+	if err := json.Unmarshal(encoded, &again); err != nil || again.Worm.FeeRate != 250 || again.Balance.Cmp(account.Balance) != 0 {
+		t.Errorf("decoded again %+v %v", again, err)
+	}
+
+	s := account.String()
+	for _, part := range []string{"Balance: 1.5 ERB", "PledgedBalance: 70000 ERB", `ExchangerName: "market"`, "Approved: 1", "Royalty: 250"} {
+		if !strings.Contains(s, part) {
+			t.Errorf("String() = %s, want %s", s, part)
+		}
+	}
+	if s := fmt.Sprint(&types2.Validator{Balance: big.NewInt(1e18)}); !strings.Contains(s, "Balance: 1 ERB") {
+		t.Errorf("validator %s", s)
+	}
+}
+
+func TestParsePriKey(t *testing.T) {
+	for _, key := range []string{priKey, "0x" + priKey, "0X" + strings.ToUpper(priKey)} {
+		parsed, err := tools.ParsePriKey(key)
+		if err != nil || !strings.EqualFold(parsed, priKey) {
+			t.Errorf("ParsePriKey(%s) = %s %v", key, parsed, err)
+		}
+	}
+	for _, key := range []string{"", "0x", priKey[1:], priKey + "00", "zz" + priKey[2:], strings.Repeat("0", 64), strings.Repeat("f", 64)} {
+		_, err := tools.ParsePriKey(key)
+		if err == nil {
+			t.Errorf("ParsePriKey(%q) succeeded", key)
+		} else if len(key) > 8 && strings.Contains(err.Error(), key[2:10]) {
+			t.Errorf("error of ParsePriKey contains the key: %v", err)
+		}
+	}
+
+	want, _, _ := tools.PriKeyToAddress(priKey)
+	if account, err := client.NewWallet("0x" + priKey).Address(); err != nil || account != want {
+		t.Errorf("Address of a 0x key = %s %v, want %s", account.Hex(), err, want.Hex())
+	}
+	wallet := client.NewWallet(priKey[2:])
+	if _, err := wallet.Address(); err == nil || !strings.Contains(err.Error(), "64 hex digits") {
+		t.Errorf("Address of a short key: %v", err)
+	}
+	if _, err := wallet.SignExchanger(exchangeAddress, exchangeAddress1, "0x10"); err == nil {
+		t.Error("signed with a short key")
+	}
+	if _, err := client.Dial(priKey[2:], "http://127.0.0.1:1"); err == nil || !strings.Contains(err.Error(), "private key") {
+		t.Errorf("Dial with a short key: %v", err)
+	}
+	if _, err := server.New(client.NewClient("", ""), server.Config{APIKeys: []string{"secret"}, Wallet: wallet}); err == nil {
+		t.Error("server created with an invalid wallet")
+	}
+}
@@ -0,0 +1,382 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/erbieio/erb-client/v2/client"
+	"github.com/erbieio/erb-client/v2/simulated"
+	"github.com/erbieio/erb-client/v2/tools"
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestCheckInputs(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		err  error
+		ok   bool
+	}{
+		{"address", tools.CheckAddress("a", exchangeAddress), true},
+		{"short address", tools.CheckAddress("a", exchangeAddress[:41]), false},
+		{"non-hex address", tools.CheckAddress("a", "0x"+strings.Repeat("g", 40)), false},
+		{"hex", tools.CheckHex("h", "0x6A0"), true},
+		{"empty hex", tools.CheckHex("h", "0x"), false},
+		{"non-hex number", tools.CheckHex("h", "0x12z"), false},
+		{"snft address", tools.CheckNFTAddress("n", "0x800000000000000000000000000000000000000"), true},
+		{"long nft address", tools.CheckNFTAddress("n", exchangeAddress+"0"), false},
+		{"royalty", tools.CheckRoyalty("r", tools.MaxRoyalty), true},
+		{"royalty over 100%", tools.CheckRoyalty("r", tools.MaxRoyalty+1), false},
+		{"hex royalty", tools.CheckHexRoyalty("r", "0xa"), true},
+		{"hex royalty over 100%", tools.CheckHexRoyalty("r", "0x2711"), false},
+		{"meta url", tools.CheckMetaURL("m", "/ipfs/qqqqqqqqqq"), true},
+		{"long meta url", tools.CheckMetaURL("m", strings.Repeat("a", tools.MaxMetaURLLength+1)), false},
+		{"meta url with control characters", tools.CheckMetaURL("m", "/ipfs/\x00"), false},
+	} {
+		if (c.err == nil) != c.ok {
+			t.Errorf("%s: %v", c.name, c.err)
+		}
+	}
+}
+
+func TestValidateBeforeSigning(t *testing.T) {
+	backend := simulated.NewBackend(map[common.Address]*big.Int{
+		common.HexToAddress(sellerAddress): big.NewInt(1e18),
+	})
+	defer backend.Close()
+	ctx := context.Background()
+	worm := backend.Client(sellerPriKey)
+
+	if _, err := worm.Wallet.SignBuyer("1000", "", exchangeAddress, "0xa", ""); err == nil {
+		t.Error("signed a buy order with a decimal amount")
+	}
+	if _, err := worm.Wallet.SignSeller1("0x38d7ea4c68000", "0x1", exchangeAddress[:20], "0xa"); err == nil {
+		t.Error("signed a sell order with a truncated exchanger")
+	}
+	if _, err := worm.Wallet.SignSeller2("0x38d7ea4c68000", "0x2711", "/ipfs/qqqqqqqqqq", "0", exchangeAddress, "0xa"); err == nil {
+		t.Error("signed a sell order with a royalty over 100%")
+	}
+	if _, err := worm.Wallet.SignSeller2Amount(types2.ERB(1), "0xa", "/ipfs/qqqqqqqqqq", "0", exchangeAddress, "0xa"); err != nil {
+		t.Error(err)
+	}
+	if _, err := worm.MintCtx(ctx, tools.MaxRoyalty+1, "/ipfs/ddfd90be9408b4", ""); err == nil {
+		t.Error("minted with a royalty over 100%")
+	}
+	if _, err := worm.Tx().Mint().MetaURL(strings.Repeat("a", tools.MaxMetaURLLength+1)).Send(ctx); err == nil {
+		t.Error("minted with a meta url over the length limit")
+	}
+	if _, err := worm.NormalTransactionCtx(ctx, "0x1234", types2.ERB(1), ""); err == nil {
+		t.Error("sent ERB to a truncated address")
+	}
+	order := []byte(`{"price":"0xde0b6b3a7640000","nft_address":"0x1","exchanger":"0xnothex","block_number":"0xa","sig":"0x00"}`)
+	if _, err := worm.TransactionNFTCtx(ctx, order, buyerAddress); err == nil || !strings.Contains(err.Error(), "buyer exchanger") {
+		t.Errorf("settled an order with a malformed exchanger: %v", err)
+	}
+}
+
+func TestAddressCase(t *testing.T) {
+	checksummed := common.HexToAddress(exchangeAddress).Hex()
+	for _, in := range []string{strings.ToLower(checksummed), "0X" + strings.ToUpper(checksummed[2:]), checksummed} {
+		if got := tools.ChecksumAddress(in); got != checksummed {
+			t.Errorf("ChecksumAddress(%s) = %s, want %s", in, got, checksummed)
+		}
+		if !tools.SameAddress(in, checksummed) {
+			t.Errorf("SameAddress(%s, %s) is false", in, checksummed)
+		}
+	}
+	if got := tools.ChecksumAddress("0x8000000000000000000000000000000000001"); got != "0x8000000000000000000000000000000000001" {
+		t.Errorf("ChecksumAddress changed an SNFT address to %s", got)
+	}
+	if tools.SameAddress(exchangeAddress, exchangeAddress1) || tools.SameAddress("0x", "0x") || tools.SameAddress("0xzz", "0xZZ") {
+		t.Error("SameAddress of different or invalid addresses is true")
+	}
+
+	backend := simulated.NewBackend(map[common.Address]*big.Int{
+		common.HexToAddress(sellerAddress): big.NewInt(1e18),
+	})
+	defer backend.Close()
+	worm := backend.Client(sellerPriKey)
+	buyer, err := backend.Client(buyerPriKey).Wallet.SignBuyerAmount(types2.ERB(1), "", strings.ToLower(exchangeAddress), "0x100", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	seller2, err := worm.Wallet.SignSeller2Amount(types2.ERB(1), "0xa", "/ipfs/qqqqqqqqqq", "0", strings.ToUpper(exchangeAddress[2:]), "0x100")
+	if err == nil {
+		t.Fatal("signing an address without 0x succeeded")
+	}
+	seller2, err = worm.Wallet.SignSeller2Amount(types2.ERB(1), "0xa", "/ipfs/qqqqqqqqqq", "0", "0x"+strings.ToUpper(exchangeAddress[2:]), "0x100")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := worm.FoundryExchangeCtx(context.Background(), buyer, seller2, buyerAddress); err != nil && strings.Contains(err.Error(), "aren`t same") {
+		t.Errorf("orders of one exchanger in different case: %v", err)
+	}
+}
+
+func TestOrderConstructors(t *testing.T) {
+	worm := client.NewClient(buyerPriKey, "")
+	buyer, err := types2.NewBuyer(types2.ERB(1), "0x000000000000000000000000000000000000000A", strings.ToLower(exchangeAddress), 0x100, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buyer.Amount != "0xde0b6b3a7640000" || buyer.NFTAddress != "0x000000000000000000000000000000000000000a" ||
+		buyer.Exchanger != common.HexToAddress(exchangeAddress).Hex() || buyer.BlockNumber != "0x100" {
+		t.Errorf("normalized buyer %+v", buyer)
+	}
+	signed, err := worm.Wallet.SignBuyerOrder(buyer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buyer.Sig != "" {
+		t.Error("signing set the signature of the unsigned order")
+	}
+	same, err := worm.Wallet.SignBuyer(buyer.Amount, buyer.NFTAddress, buyer.Exchanger, buyer.BlockNumber, "")
+	if err != nil || string(same) != string(signed) {
+		t.Errorf("SignBuyer of the same fields: %s %v, want %s", same, err, signed)
+	}
+	var order types2.Buyer
+	if err := json.Unmarshal(signed, &order); err != nil {
+		t.Fatal(err)
+	}
+	if signer, err := tools.RecoverAddress(order.Message(), order.Sig); err != nil || signer != common.HexToAddress(buyerAddress) {
+		t.Errorf("signer of the order %s %v, want %s", signer.Hex(), err, buyerAddress)
+	}
+
+	seller2, err := types2.NewSeller2(types2.ERB(1), 1000, "/ipfs/qqqqqqqqqq", true, exchangeAddress, 0x100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if seller2.Royalty != "0x3e8" || seller2.ExclusiveFlag != "1" || seller2.Message() != "0xde0b6b3a7640000"+"0x3e8"+"/ipfs/qqqqqqqqqq"+"1"+seller2.Exchanger+"0x100" {
+		t.Errorf("seller2 %+v", seller2)
+	}
+	if _, err := worm.Wallet.SignSeller2Order(seller2); err != nil {
+		t.Error(err)
+	}
+	auth, err := types2.NewExchangerAuth(exchangeAddress, sellerAddress, 0x100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := worm.Wallet.SignExchangerAuth(auth); err != nil {
+		t.Error(err)
+	}
+
+	if _, err := types2.NewSeller2(types2.ERB(1), tools.MaxRoyalty+1, "/ipfs/qqqqqqqqqq", false, exchangeAddress, 0x100); err == nil {
+		t.Error("built a sell order with a royalty over 100%")
+	}
+	if _, err := types2.NewSeller1(types2.ERB(1), "", exchangeAddress, 0x100); err == nil {
+		t.Error("built a sell order without nft")
+	}
+	if _, err := types2.NewBuyer(types2.ERB(-1), "", exchangeAddress, 0x100, ""); err == nil {
+		t.Error("built a buy order with a negative amount")
+	}
+	if _, err := types2.NewExchangerAuth(exchangeAddress[:20], sellerAddress, 0x100); err == nil {
+		t.Error("built an authorization of a truncated exchanger")
+	}
+}
+
+func TestExchangerAddress(t *testing.T) {
+	checksummed := common.HexToAddress(exchangeAddress).Hex()
+	// Flip the case of the first letter, the checksum no longer matches.
+	i := strings.IndexAny(checksummed[2:], "abcdefABCDEF") + 2
+	flipped := checksummed[:i] + string(checksummed[i]^0x20) + checksummed[i+1:]
+	for in, valid := range map[string]bool{
+		checksummed:                             true,
+		strings.ToLower(checksummed):            true,
+		"0X" + strings.ToUpper(checksummed[2:]): true,
+		flipped:                                 false,
+		"1330287425438298237498234":             false,
+		checksummed[2:]:                         false,
+		checksummed[:41]:                        false,
+	} {
+		address, err := types2.ParseAddress(in)
+		if valid && (err != nil || address.Hex() != checksummed) {
+			t.Errorf("ParseAddress(%s) = %s, %v", in, address.Hex(), err)
+		}
+		if !valid && err == nil {
+			t.Errorf("ParseAddress(%s) succeeded", in)
+		}
+	}
+
+	wallet := client.NewWallet(buyerPriKey)
+	signed, err := wallet.SignBuyer("0xde0b6b3a7640000", "", strings.ToLower(checksummed), "0x100", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buyer types2.Buyer
+	if err := json.Unmarshal(signed, &buyer); err != nil {
+		t.Fatal(err)
+	}
+	if buyer.Exchanger != checksummed {
+		t.Errorf("signed exchanger %s, want %s", buyer.Exchanger, checksummed)
+	}
+	if _, err := wallet.SignBuyer("0xde0b6b3a7640000", "", flipped, "0x100", ""); err == nil {
+		t.Error("signing an exchanger with an invalid checksum succeeded")
+	}
+	if _, err := wallet.SignSellerAuth("1330287425438298237498234", "0x100"); err == nil {
+		t.Error("signing a decimal exchanger succeeded")
+	}
+	buyer.Exchanger = flipped
+	if err := buyer.Validate(); err == nil {
+		t.Error("order with an invalid exchanger checksum is valid")
+	}
+
+	worm := client.NewClient(sellerPriKey, "")
+	if _, err := worm.Tx().Mint().Royalty(10).MetaURL("/ipfs/qqqqqqqqqq").Via(flipped).Build(context.Background()); err == nil {
+		t.Error("minting for an exchanger with an invalid checksum succeeded")
+	}
+}
+
+func TestParseRoyalty(t *testing.T) {
+	for in, want := range map[string]uint32{"0": 0, "250": 250, "10000": types2.MaxRoyalty, "0xa": 10, "0X2710": types2.MaxRoyalty} {
+		if got, err := types2.ParseRoyalty(in); err != nil || got != want {
+			t.Errorf("ParseRoyalty(%s) = %d %v, want %d", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "0x", "10001", "0x2711", "-1", "+5", "2.5", "2.5%", "4294967546"} {
+		if _, err := types2.ParseRoyalty(in); err == nil {
+			t.Errorf("ParseRoyalty(%q) succeeded", in)
+		}
+	}
+}
+
+func TestSigningHash(t *testing.T) {
+	buyer, err := types2.NewBuyer(types2.ERB(1), "", exchangeAddress, 16, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	seller2, err := types2.NewSeller2(types2.ERB(1), 250, "/ipfs/qqqqqqqqqq", false, exchangeAddress, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seller1, err := types2.NewSeller1(types2.ERB(1), "0x0000000000000000000000000000000000000001", exchangeAddress, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth, err := types2.NewExchangerAuth(exchangeAddress, exchangeAddress1, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, order := range []interface {
+		Message() string
+		SigningHash() common.Hash
+	}{buyer, seller1, seller2, auth, &types2.Buyauth{Exchanger: exchangeAddress, BlockNumber: "0x10"}} {
+		if got, want := order.SigningHash(), common.BytesToHash(tools.SignHash([]byte(order.Message()))); got != want {
+			t.Errorf("SigningHash of %q = %s, want %s", order.Message(), got, want)
+		}
+	}
+	// The personal_sign hash of "hello", for implementations in other languages.
+	if got := (&types2.Sellerauth{Exchanger: "hel", BlockNumber: "lo"}).SigningHash().Hex(); got != "0x50b2c43fd39106bafbba0da34fc430e1f91e3c96ea2acee2bc34119f92b37750" {
+		t.Errorf("SigningHash of hello = %s", got)
+	}
+
+	// A signature of the hash by an external signer is the one of the wallet.
+	signed, err := client.NewWallet(buyerPriKey).SignBuyerOrder(buyer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var order types2.Buyer
+	if err := json.Unmarshal(signed, &order); err != nil {
+		t.Fatal(err)
+	}
+	key, err := crypto.HexToECDSA(buyerPriKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := crypto.Sign(buyer.SigningHash().Bytes(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig[64] += 27
+	if hexutil.Encode(sig) != order.Sig {
+		t.Errorf("external signature %s, wallet signature %s", hexutil.Encode(sig), order.Sig)
+	}
+}
+
+func TestWalletKey(t *testing.T) {
+	if _, err := new(client.Wallet).SignBuyerAuth(exchangeAddress, "0x10"); err == nil {
+		t.Error("a wallet without a key signed")
+	}
+	worm := client.NewClient(sellerPriKey, "")
+	worm.UpdatePri(buyerPriKey)
+	if account, err := worm.Address(); err != nil || account != common.HexToAddress(buyerAddress) {
+		t.Errorf("address after UpdatePri %s %v, want %s", account.Hex(), err, buyerAddress)
+	}
+}
+
+// BenchmarkSignBuyerOrder measures bulk order signing, with the key parsed once per wallet.
+func BenchmarkSignBuyerOrder(b *testing.B) {
+	wallet := client.NewWallet(buyerPriKey)
+	buyer, err := types2.NewBuyer(types2.ERB(1), "0x0000000000000000000000000000000000000001", exchangeAddress, 16, "")
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := wallet.SignBuyerOrder(buyer); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSigningHash(b *testing.B) {
+	seller2, err := types2.NewSeller2(types2.ERB(1), 250, "/ipfs/qqqqqqqqqq", false, exchangeAddress, 16)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		seller2.SigningHash()
+	}
+}
+
+func TestSignatureVOffset(t *testing.T) {
+	auth, err := types2.NewExchangerAuth(exchangeAddress, exchangeAddress1, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wallet := client.NewWallet(exchangerPriKey)
+	if err := wallet.SetSignatureVOffset(1); err == nil {
+		t.Error("SetSignatureVOffset(1) succeeded")
+	}
+	sign := func() string {
+		signed, err := wallet.SignExchangerAuth(auth)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var order types2.ExchangerAuth
+		if err := json.Unmarshal(signed, &order); err != nil {
+			t.Fatal(err)
+		}
+		return order.Sig
+	}
+	sig := sign()
+	if err := wallet.SetSignatureVOffset(tools.RawSignatureVOffset); err != nil {
+		t.Fatal(err)
+	}
+	raw := sign()
+	if v := raw[len(raw)-2:]; v != "00" && v != "01" || raw[:len(raw)-2] != sig[:len(sig)-2] {
+		t.Fatalf("raw signature %s of signature %s", raw, sig)
+	}
+
+	// Both forms verify, and convert into each other.
+	for _, s := range []string{sig, raw} {
+		signer, err := tools.RecoverAddress(auth.Message(), s)
+		if err != nil || !tools.SameAddress(signer.Hex(), exchangeAddress) {
+			t.Errorf("RecoverAddress(%s) = %s %v", s, signer.Hex(), err)
+		}
+	}
+	if converted, err := tools.ConvertSignatureV(raw, tools.SignatureVOffset); err != nil || converted != sig {
+		t.Errorf("ConvertSignatureV(%s, 27) = %s %v, want %s", raw, converted, err, sig)
+	}
+	if converted, err := tools.ConvertSignatureV(sig, tools.RawSignatureVOffset); err != nil || converted != raw {
+		t.Errorf("ConvertSignatureV(%s, 0) = %s %v, want %s", sig, converted, err, raw)
+	}
+	if _, err := tools.RecoverAddress(auth.Message(), sig[:len(sig)-2]+"1d"); err == nil {
+		t.Error("RecoverAddress with V 29 succeeded")
+	}
+}
@@ -0,0 +1,52 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/erbieio/erb-client/v2/vault"
+)
+
+func TestVaultPriKey(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "test-token" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/erb/seller":
+			w.Write([]byte(`{"data":{"data":{"private_key":"0x` + sellerPriKey + `"},"metadata":{"version":1}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[]}`))
+		}
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	provider, err := vault.New(vault.Config{Address: srv.URL, Token: "test-token"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := provider.PriKey(ctx, "erb/seller")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key != sellerPriKey {
+		t.Errorf("got key %s, want %s", key, sellerPriKey)
+	}
+	if _, err := provider.PriKey(ctx, "erb/missing"); err == nil {
+		t.Error("missing secret read")
+	}
+
+	denied, err := vault.New(vault.Config{Address: srv.URL, Token: "other"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := denied.PriKey(ctx, "erb/seller"); err == nil {
+		t.Error("secret read with an invalid token")
+	}
+}
@@ -0,0 +1,84 @@
+package test
+
+import (
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/erbieio/erb-client/v2/vectors"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestVectors(t *testing.T) {
+	vs, err := vectors.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := vectors.VerifyAll(vs); err != nil {
+		t.Fatal(err)
+	}
+	again, _ := vectors.Generate()
+	if !reflect.DeepEqual(vs, again) {
+		t.Error("vectors are not deterministic")
+	}
+	kinds := map[string]bool{}
+	for _, v := range vs {
+		kinds[v.Kind] = true
+	}
+	for _, kind := range []string{vectors.KindBuyer, vectors.KindSeller1, vectors.KindSeller2, vectors.KindExchangerAuth, vectors.KindBuyerAuth, vectors.KindSellerAuth} {
+		if !kinds[kind] {
+			t.Errorf("no %s vector", kind)
+		}
+	}
+
+	// The vectors are published, a change of the signatures breaks the other implementations.
+	first := vs[0]
+	if first.Name != "buyer/minted" ||
+		first.Signer != common.HexToAddress("0x22b1ea7c8e069e2a506eb5c0ea0835204cc5bcf3") ||
+		first.Signature != "0x868bc5361e0dca6aba38079ae854c56a9d622eb4e19d41e00a651ae682b5734274194789aa9f41e8d6a055b8f05ce9b28bab12557d7be2267eca2b5400cab47f1c" {
+		t.Errorf("vector %s changed: signer %s signature %s", first.Name, first.Signer.Hex(), first.Signature)
+	}
+
+	// Read back from JSON.
+	data, err := json.Marshal(vs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded []vectors.Vector
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if err := vectors.VerifyAll(decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	sig := hexutil.MustDecode(first.Signature)
+	raw := append([]byte(nil), sig...)
+	raw[64] -= 27
+	// The other signature of the hash: s negated and the recovery id flipped.
+	high := append([]byte(nil), sig...)
+	copy(high[32:64], common.LeftPadBytes(new(big.Int).Sub(crypto.S256().Params().N, new(big.Int).SetBytes(sig[32:64])).Bytes(), 32))
+	high[64] ^= 1
+	tampered := map[string]func(v *vectors.Vector){
+		"message":    func(v *vectors.Vector) { v.Message += "0" },
+		"hash":       func(v *vectors.Vector) { v.Hash = common.Hash{1} },
+		"signer":     func(v *vectors.Vector) { v.Signer = common.HexToAddress(buyerAddress) },
+		"signature":  func(v *vectors.Vector) { v.Signature = vs[1].Signature },
+		"raw v":      func(v *vectors.Vector) { v.Signature = hexutil.Encode(raw) },
+		"high s":     func(v *vectors.Vector) { v.Signature = hexutil.Encode(high) },
+		"order":      func(v *vectors.Vector) { v.Order = vs[1].Order },
+		"signed":     func(v *vectors.Vector) { v.Signed = vs[1].Signed },
+		"signed sig": func(v *vectors.Vector) { v.Order = v.Signed },
+		"kind":       func(v *vectors.Vector) { v.Kind = vectors.KindSeller2 },
+	}
+	for name, tamper := range tampered {
+		v := first
+		tamper(&v)
+		if err := vectors.Verify(&v); err == nil {
+			t.Errorf("verified a vector with a tampered %s", name)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/erbieio/erb-client/v2/simulated"
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestWaitMined(t *testing.T) {
+	backend := simulated.NewBackend(map[common.Address]*big.Int{
+		common.HexToAddress(priAddress): big.NewInt(1e18),
+	})
+	defer backend.Close()
+	worm := backend.Client(priKey)
+	worm.SetPollInterval(10 * time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	hash, err := worm.NormalTransactionCtx(ctx, tempAddress, types2.Amount{}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mined := make(chan *types.Receipt, 1)
+	go func() {
+		receipt, err := worm.WaitMined(ctx, hash, 2)
+		if err != nil {
+			t.Error(err)
+		}
+		mined <- receipt
+	}()
+	// The transaction is mined in block 1 and confirmed by blocks 2 and 3.
+	for i := 0; i < 3; i++ {
+		time.Sleep(30 * time.Millisecond)
+		select {
+		case receipt := <-mined:
+			t.Fatalf("receipt %v returned after %d blocks", receipt, i)
+		default:
+		}
+		backend.Commit()
+	}
+	receipt := <-mined
+	if receipt == nil || receipt.TxHash.Hex() != hash || receipt.BlockNumber.Uint64() != 1 || receipt.Status != types.ReceiptStatusSuccessful {
+		t.Fatalf("receipt %+v", receipt)
+	}
+
+	// A mined transaction without confirmations returns at once.
+	if receipt, err := worm.WaitMined(ctx, hash, 0); err != nil || receipt.BlockNumber.Uint64() != 1 {
+		t.Errorf("receipt %v, error %v", receipt, err)
+	}
+
+	short, cancelShort := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancelShort()
+	unknown := common.HexToHash("0x01").Hex()
+	if _, err := worm.WaitMined(short, unknown, 0); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("wait for an unknown transaction: %v", err)
+	}
+}
@@ -0,0 +1,104 @@
+package tools
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"golang.org/x/xerrors"
+)
+
+// AddressBook maps human-readable aliases such as "seller1" to addresses.
+// Aliases are case-insensitive. It is safe for concurrent use.
+type AddressBook struct {
+	mu      sync.RWMutex
+	entries map[string]common.Address
+}
+
+// NewAddressBook creates an empty address book.
+func NewAddressBook() *AddressBook {
+	return &AddressBook{entries: make(map[string]common.Address)}
+}
+
+// LoadAddressBook reads an address book from a JSON file holding an object of alias to address,
+// e.g. {"seller1": "0xD9DC702C0d3518aa27F82fd75f1a544233a7150f"}.
+func LoadAddressBook(path string) (*AddressBook, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var entries map[string]string
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, xerrors.Errorf("read address book %s fail. %v", path, err)
+	}
+	book := NewAddressBook()
+	for alias, address := range entries {
+		if err := book.Set(alias, address); err != nil {
+			return nil, err
+		}
+	}
+	return book, nil
+}
+
+// AddressBookFromEnv creates an address book from the environment variables starting with prefix,
+// e.g. with prefix "ERB_ADDR_" the variable ERB_ADDR_SELLER1=0x... defines the alias "seller1".
+func AddressBookFromEnv(prefix string) (*AddressBook, error) {
+	book := NewAddressBook()
+	for _, env := range os.Environ() {
+		name, address, ok := strings.Cut(env, "=")
+		if !ok || !strings.HasPrefix(name, prefix) || len(name) == len(prefix) {
+			continue
+		}
+		if err := book.Set(strings.TrimPrefix(name, prefix), address); err != nil {
+			return nil, err
+		}
+	}
+	return book, nil
+}
+
+// Set defines alias as a name of address, replacing an earlier definition.
+func (b *AddressBook) Set(alias, address string) error {
+	if alias == "" || strings.HasPrefix(alias, "0x") || strings.HasPrefix(alias, "0X") {
+		return xerrors.Errorf("invalid alias %q", alias)
+	}
+	if !common.IsHexAddress(address) {
+		return xerrors.Errorf("alias %s: invalid address %s", alias, address)
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.entries[strings.ToLower(alias)] = common.HexToAddress(address)
+	return nil
+}
+
+// Lookup returns the address of alias.
+func (b *AddressBook) Lookup(alias string) (common.Address, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	address, ok := b.entries[strings.ToLower(alias)]
+	return address, ok
+}
+
+// Resolve returns the hex address of value if it is a known alias, and value unchanged otherwise.
+// A nil address book resolves nothing.
+func (b *AddressBook) Resolve(value string) string {
+	if b == nil {
+		return value
+	}
+	if address, ok := b.Lookup(value); ok {
+		return address.Hex()
+	}
+	return value
+}
+
+// Aliases returns a copy of the aliases in the book.
+func (b *AddressBook) Aliases() map[string]common.Address {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	aliases := make(map[string]common.Address, len(b.entries))
+	for alias, address := range b.entries {
+		aliases[alias] = address
+	}
+	return aliases
+}
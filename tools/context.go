@@ -0,0 +1,26 @@
+package tools
+
+import (
+	"context"
+	"time"
+)
+
+// FlushTimeout bounds the work FlushContext allows after a shutdown was requested.
+const FlushTimeout = 10 * time.Second
+
+// FlushContext returns a context for saving the progress of work done under ctx, e.g. the
+// checkpoint of a block that was processed, once ctx may be cancelled. It keeps the values of ctx
+// but not its cancellation, and expires after FlushTimeout. Call cancel when the save is done.
+func FlushContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(withoutCancel{ctx}, FlushTimeout)
+}
+
+// withoutCancel is context.WithoutCancel of Go 1.21, the module supports Go 1.20.
+type withoutCancel struct {
+	parent context.Context
+}
+
+func (withoutCancel) Deadline() (time.Time, bool)         { return time.Time{}, false }
+func (withoutCancel) Done() <-chan struct{}               { return nil }
+func (withoutCancel) Err() error                          { return nil }
+func (c withoutCancel) Value(key interface{}) interface{} { return c.parent.Value(key) }
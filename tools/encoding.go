@@ -0,0 +1,55 @@
+package tools
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"golang.org/x/xerrors"
+)
+
+// The encoding helpers format the fields of signed orders and wormholes transactions the way the
+// chain decodes them: numbers as hex with a 0x prefix and without leading zeros, e.g.
+// EncodeBlockNumber(16) returns "0x10", and addresses checksummed. Use them instead of formatting
+// the fields by hand, fmt.Sprintf("%#x", n) of a big.Int is not always accepted.
+
+// EncodeBlockNumber formats a block number, e.g. the height a signed order is valid before.
+func EncodeBlockNumber(number uint64) string {
+	return hexutil.EncodeUint64(number)
+}
+
+// DecodeBlockNumber parses a block number formatted by EncodeBlockNumber.
+func DecodeBlockNumber(s string) (uint64, error) {
+	number, err := hexutil.DecodeUint64(s)
+	if err != nil {
+		return 0, xerrors.Errorf("invalid block number %q. %v", s, err)
+	}
+	return number, nil
+}
+
+// EncodeAmount formats an amount of wei, a nil amount as zero.
+func EncodeAmount(wei *big.Int) string {
+	if wei == nil {
+		return "0x0"
+	}
+	return hexutil.EncodeBig(wei)
+}
+
+// DecodeAmount parses an amount of wei formatted by EncodeAmount.
+func DecodeAmount(s string) (*big.Int, error) {
+	wei, err := hexutil.DecodeBig(s)
+	if err != nil {
+		return nil, xerrors.Errorf("invalid wei amount %q. %v", s, err)
+	}
+	return wei, nil
+}
+
+// EncodeRoyalty formats a royalty in basis points, the encoding of sell orders of unminted NFTs.
+func EncodeRoyalty(royalty uint32) string {
+	return hexutil.EncodeUint64(uint64(royalty))
+}
+
+// EncodeAddress formats an address with the EIP-55 checksum.
+func EncodeAddress(address common.Address) string {
+	return address.Hex()
+}
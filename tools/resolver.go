@@ -0,0 +1,115 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrNameNotFound is returned by a NameResolver for a name or an address it does not know.
+var ErrNameNotFound = errors.New("name not found")
+
+// NameResolver maps registered names to addresses and back, e.g. an AddressBook or the registry
+// of client.NewENSResolver.
+type NameResolver interface {
+	// LookupName returns the address of name, the error wraps ErrNameNotFound for an unknown name.
+	LookupName(ctx context.Context, name string) (common.Address, error)
+	// ReverseLookup returns the name of address, the error wraps ErrNameNotFound if it has none.
+	ReverseLookup(ctx context.Context, address common.Address) (string, error)
+}
+
+var _ NameResolver = (*AddressBook)(nil)
+
+// LookupName returns the address of the alias name.
+func (b *AddressBook) LookupName(ctx context.Context, name string) (common.Address, error) {
+	if address, ok := b.Lookup(name); ok {
+		return address, nil
+	}
+	return common.Address{}, ErrNameNotFound
+}
+
+// ReverseLookup returns an alias of address, the first in alphabetical order if it has several.
+func (b *AddressBook) ReverseLookup(ctx context.Context, address common.Address) (string, error) {
+	var names []string
+	for alias, a := range b.Aliases() {
+		if a == address {
+			names = append(names, alias)
+		}
+	}
+	if len(names) == 0 {
+		return "", ErrNameNotFound
+	}
+	sort.Strings(names)
+	return names[0], nil
+}
+
+// cachedResolver is a NameResolver remembering the answers of another one.
+type cachedResolver struct {
+	r   NameResolver
+	ttl time.Duration
+
+	mu        sync.Mutex
+	addresses map[string]cachedAddress
+	names     map[common.Address]cachedName
+}
+
+type cachedAddress struct {
+	address common.Address
+	expires time.Time
+}
+
+type cachedName struct {
+	name    string
+	expires time.Time
+}
+
+// CachedResolver returns a resolver remembering the names and addresses found by r for ttl, so
+// every address parameter does not query the registry again. Unknown names are not remembered.
+func CachedResolver(r NameResolver, ttl time.Duration) NameResolver {
+	return &cachedResolver{
+		r:         r,
+		ttl:       ttl,
+		addresses: make(map[string]cachedAddress),
+		names:     make(map[common.Address]cachedName),
+	}
+}
+
+func (c *cachedResolver) LookupName(ctx context.Context, name string) (common.Address, error) {
+	key := strings.ToLower(name)
+	c.mu.Lock()
+	entry, ok := c.addresses[key]
+	c.mu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry.address, nil
+	}
+	address, err := c.r.LookupName(ctx, name)
+	if err != nil {
+		return common.Address{}, err
+	}
+	c.mu.Lock()
+	c.addresses[key] = cachedAddress{address: address, expires: time.Now().Add(c.ttl)}
+	c.mu.Unlock()
+	return address, nil
+}
+
+func (c *cachedResolver) ReverseLookup(ctx context.Context, address common.Address) (string, error) {
+	c.mu.Lock()
+	entry, ok := c.names[address]
+	c.mu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry.name, nil
+	}
+	name, err := c.r.ReverseLookup(ctx, address)
+	if err != nil {
+		return "", err
+	}
+	c.mu.Lock()
+	c.names[address] = cachedName{name: name, expires: time.Now().Add(c.ttl)}
+	c.mu.Unlock()
+	return name, nil
+}
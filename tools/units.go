@@ -0,0 +1,70 @@
+package tools
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"golang.org/x/xerrors"
+)
+
+// The unit helpers convert between decimal numbers of ERB, as users write them, and wei, as
+// transactions and signed orders carry them, e.g. ERBToHex("1") returns "0xde0b6b3a7640000".
+
+// ParseERB parses a decimal number of ERB with at most 18 decimals, e.g. "1.5", into wei.
+func ParseERB(erb string) (*big.Int, error) {
+	amount, err := types2.ParseERB(erb)
+	if err != nil {
+		return nil, err
+	}
+	return amount.Wei(), nil
+}
+
+// FormatERB formats an amount of wei as a decimal number of ERB with decimals digits after the
+// point, rounded half away from zero, e.g. FormatERB(wei, 2) returns "1.50" for 1.5 ERB. decimals
+// is capped at 18, a negative decimals formats the exact amount without trailing zeros.
+func FormatERB(wei *big.Int, decimals int) string {
+	if decimals < 0 {
+		return types2.Wei(wei).String()
+	}
+	if decimals > types2.ERBDecimals {
+		decimals = types2.ERBDecimals
+	}
+	abs := new(big.Int)
+	if wei != nil {
+		abs.Abs(wei)
+	}
+	unit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(types2.ERBDecimals-decimals)), nil)
+	abs.Add(abs, new(big.Int).Rsh(unit, 1))
+	abs.Quo(abs, unit)
+	whole, frac := abs.QuoRem(abs, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil), new(big.Int))
+	s := whole.String()
+	if decimals > 0 {
+		s += "." + fmt.Sprintf("%0*s", decimals, frac.String())
+	}
+	if wei != nil && wei.Sign() < 0 && strings.Trim(s, "0.") != "" {
+		s = "-" + s
+	}
+	return s
+}
+
+// ERBToHex converts a decimal number of ERB into a hex number of wei, the format of the amounts
+// of signed orders.
+func ERBToHex(erb string) (string, error) {
+	amount, err := types2.ParseERB(erb)
+	if err != nil {
+		return "", err
+	}
+	return amount.Hex(), nil
+}
+
+// HexToERB converts a hex number of wei with a 0x prefix into an exact decimal number of ERB.
+func HexToERB(hex string) (string, error) {
+	wei, err := hexutil.DecodeBig(hex)
+	if err != nil {
+		return "", xerrors.Errorf("invalid wei amount %q. %v", hex, err)
+	}
+	return types2.Wei(wei).String(), nil
+}
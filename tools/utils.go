@@ -5,6 +5,8 @@ import (
 	"crypto/ecdsa"
 	"encoding/hex"
 	"fmt"
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
@@ -13,13 +15,15 @@ import (
 	"golang.org/x/xerrors"
 	"io"
 	"io/ioutil"
+	"math/big"
 	"os/exec"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 func SignHash(data []byte) []byte {
-	msg := fmt.Sprintf("\x19Ethereum Signed Message:\n%d%s", len(data), data)
-	return crypto.Keccak256([]byte(msg))
+	return accounts.TextHash(data)
 }
 
 func GeneratePriKeyHex(no int) []string {
@@ -47,23 +51,22 @@ func GetPriKey(priPath, pwdPath string) (string, error) {
 	return privateKey, nil
 }
 
-// RecoverAddress recover the address from sig
+// The offsets of the recovery id, 0 or 1, in the V byte of a signature. The wallet of the client
+// and personal_sign add 27, signers built on crypto.Sign, such as many hardware and library
+// signers, add nothing.
+const (
+	SignatureVOffset    = 27
+	RawSignatureVOffset = 0
+)
+
+// RecoverAddress recover the address from sig. V may be 27 or 28 as the wallet signs, or 0 or 1
+// as other signers do.
 func RecoverAddress(msg string, sigStr string) (common.Address, error) {
-	if !strings.HasPrefix(sigStr, "0x") &&
-		!strings.HasPrefix(sigStr, "0X") {
-		return common.Address{}, fmt.Errorf("signature must be started with 0x or 0X")
-	}
-	sigData := hexutil.MustDecode(sigStr)
-	if len(sigData) != 65 {
-		return common.Address{}, fmt.Errorf("signature must be 65 bytes long")
-	}
-	if sigData[64] != 27 && sigData[64] != 28 {
-		return common.Address{}, fmt.Errorf("invalid Ethereum signature (V is not 27 or 28)")
+	sigData, err := decodeSignature(sigStr)
+	if err != nil {
+		return common.Address{}, err
 	}
-	sigData[64] -= 27
 	hash, _ := hashMsg([]byte(msg))
-	fmt.Println("msg ", msg)
-	fmt.Println("sigdebug hash=", hexutil.Encode(hash))
 	rpk, err := crypto.SigToPub(hash, sigData)
 	if err != nil {
 		return common.Address{}, err
@@ -71,6 +74,44 @@ func RecoverAddress(msg string, sigStr string) (common.Address, error) {
 	return crypto.PubkeyToAddress(*rpk), nil
 }
 
+// ConvertSignatureV returns the hex signature sig with the V byte for the offset, SignatureVOffset
+// or RawSignatureVOffset, whatever the offset of sig, e.g. to submit to the chain an order signed
+// by a signer of 0 or 1 V values.
+func ConvertSignatureV(sig string, offset byte) (string, error) {
+	if offset != SignatureVOffset && offset != RawSignatureVOffset {
+		return "", xerrors.Errorf("invalid signature V offset %d, want %d or %d", offset, SignatureVOffset, RawSignatureVOffset)
+	}
+	sigData, err := decodeSignature(sig)
+	if err != nil {
+		return "", err
+	}
+	sigData[64] += offset
+	return hexutil.Encode(sigData), nil
+}
+
+// decodeSignature decodes a hex signature with the recovery id, 0 or 1, as V.
+func decodeSignature(sigStr string) ([]byte, error) {
+	if !strings.HasPrefix(sigStr, "0x") &&
+		!strings.HasPrefix(sigStr, "0X") {
+		return nil, fmt.Errorf("signature must be started with 0x or 0X")
+	}
+	sigData, err := hexutil.Decode(sigStr)
+	if err != nil {
+		return nil, err
+	}
+	if len(sigData) != 65 {
+		return nil, fmt.Errorf("signature must be 65 bytes long")
+	}
+	switch sigData[64] {
+	case SignatureVOffset, SignatureVOffset + 1:
+		sigData[64] -= SignatureVOffset
+	case RawSignatureVOffset, RawSignatureVOffset + 1:
+	default:
+		return nil, fmt.Errorf("invalid Ethereum signature (V is not 27, 28, 0 or 1)")
+	}
+	return sigData, nil
+}
+
 // hashMsg return the hash of plain msg
 func hashMsg(data []byte) ([]byte, string) {
 	msg := fmt.Sprintf("\x19Ethereum Signed Message:\n%d%s", len(data), string(data))
@@ -79,8 +120,35 @@ func hashMsg(data []byte) ([]byte, string) {
 	return hasher.Sum(nil), msg
 }
 
+// ParsePriKey checks a hex private key of 64 digits, with or without a 0x prefix, and returns it
+// without the prefix. The errors do not contain the key.
+func ParsePriKey(priKey string) (string, error) {
+	key := priKey
+	if strings.HasPrefix(key, "0x") || strings.HasPrefix(key, "0X") {
+		key = key[2:]
+	}
+	if key == "" {
+		return "", xerrors.New("no private key")
+	}
+	if len(key) != 64 {
+		return "", xerrors.Errorf("invalid private key: want 64 hex digits, got %d", len(key))
+	}
+	if !isHex(key) {
+		return "", xerrors.New("invalid private key: not a hex number")
+	}
+	if _, err := crypto.HexToECDSA(key); err != nil {
+		return "", xerrors.New("invalid private key: out of the range of secp256k1 keys")
+	}
+	return key, nil
+}
+
+// PriKeyToAddress returns the account and the key of a private key accepted by ParsePriKey.
 func PriKeyToAddress(priKey string) (account common.Address, fromKey *ecdsa.PrivateKey, err error) {
-	fromKey, err = crypto.HexToECDSA(priKey)
+	key, err := ParsePriKey(priKey)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	fromKey, err = crypto.HexToECDSA(key)
 	if err != nil {
 		return common.Address{}, nil, err
 	}
@@ -127,6 +195,12 @@ func ToHex16(src string) string {
 	return "0x" + rs
 }
 
+// MaxRoyalty is the largest royalty of an NFT, royalties are in ten thousandths of the price.
+const MaxRoyalty = types2.MaxRoyalty
+
+// MaxMetaURLLength is the longest NFT metadata URL accepted by the client.
+const MaxMetaURLLength = types2.MaxMetaURLLength
+
 func CheckAddress(name, value string) error {
 	if !strings.HasPrefix(value, "0X") && !strings.HasPrefix(value, "0x") {
 		return xerrors.Errorf("%s is not string of 0x", name)
@@ -135,16 +209,93 @@ func CheckAddress(name, value string) error {
 	if len(value) != 42 {
 		return xerrors.Errorf("the len of %s must be 42", name)
 	}
+	if !isHex(value[2:]) {
+		return xerrors.Errorf("%s is not a hex address: %s", name, value)
+	}
 	return nil
 }
 
+// ChecksumAddress returns the EIP-55 checksummed form of a hex address of 40 digits, in any case.
+// Other strings, such as SNFT addresses of less than 40 digits, are returned unchanged.
+func ChecksumAddress(address string) string {
+	if !common.IsHexAddress(address) || !strings.HasPrefix(address, "0x") && !strings.HasPrefix(address, "0X") {
+		return address
+	}
+	return common.HexToAddress(address).Hex()
+}
+
+// SameAddress reports whether a and b are the same hex address, ignoring the case of the digits
+// and of the 0x prefix. Strings that are not hex addresses are never the same.
+func SameAddress(a, b string) bool {
+	if CheckHex("a", a) != nil || CheckHex("b", b) != nil {
+		return false
+	}
+	return strings.EqualFold(a[2:], b[2:])
+}
+
 func CheckHex(name, value string) error {
 	if !strings.HasPrefix(value, "0X") && !strings.HasPrefix(value, "0x") {
 		return xerrors.Errorf("%s is not string of 0x", name)
 	}
+	if len(value) == 2 || !isHex(value[2:]) {
+		return xerrors.Errorf("%s is not a hex number: %q", name, value)
+	}
+	return nil
+}
+
+// CheckNFTAddress checks an NFT address, SNFT addresses have less than 40 hex digits.
+func CheckNFTAddress(name, value string) error {
+	if err := CheckHex(name, value); err != nil {
+		return err
+	}
+	if len(value) > 42 {
+		return xerrors.Errorf("the len of %s must be at most 42", name)
+	}
+	return nil
+}
+
+// CheckRoyalty checks that royalty is at most MaxRoyalty.
+func CheckRoyalty(name string, royalty uint64) error {
+	if royalty > MaxRoyalty {
+		return xerrors.Errorf("%s %d is out of range 0..%d", name, royalty, MaxRoyalty)
+	}
 	return nil
 }
 
+// CheckHexRoyalty checks a royalty formatted as a hex string.
+func CheckHexRoyalty(name, value string) error {
+	if err := CheckHex(name, value); err != nil {
+		return err
+	}
+	royalty, ok := new(big.Int).SetString(value[2:], 16)
+	if !ok || !royalty.IsUint64() {
+		return xerrors.Errorf("%s %s is out of range 0..%d", name, value, MaxRoyalty)
+	}
+	return CheckRoyalty(name, royalty.Uint64())
+}
+
+// CheckMetaURL checks that an NFT metadata URL is at most MaxMetaURLLength bytes of printable characters.
+func CheckMetaURL(name, value string) error {
+	if len(value) > MaxMetaURLLength {
+		return xerrors.Errorf("the len of %s must be at most %d", name, MaxMetaURLLength)
+	}
+	for _, r := range value {
+		if r == utf8.RuneError || !unicode.IsPrint(r) {
+			return xerrors.Errorf("%s contains an invalid character: %q", name, value)
+		}
+	}
+	return nil
+}
+
+func isHex(s string) bool {
+	for _, c := range []byte(s) {
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+			return false
+		}
+	}
+	return true
+}
+
 func CheckFlag(name, value string) error {
 	if value != "0" && value != "1" {
 		return xerrors.Errorf("%s is not the need flag", name)
@@ -0,0 +1,142 @@
+package types
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// ERBDecimals is the number of decimals of ERB, 1 ERB is 10^18 wei.
+const ERBDecimals = 18
+
+var weiPerERB = new(big.Int).Exp(big.NewInt(10), big.NewInt(ERBDecimals), nil)
+
+// Amount is an amount of ERB, held in wei. The zero value is zero ERB.
+//
+// Orders sign amounts as hex strings of wei, e.g. "0xde0b6b3a7640000" for 1 ERB, which Hex
+// returns; String formats the amount as a decimal number of ERB.
+type Amount struct {
+	wei *big.Int
+}
+
+// Wei returns an amount of wei.
+func Wei(wei *big.Int) Amount {
+	if wei == nil {
+		return Amount{}
+	}
+	return Amount{new(big.Int).Set(wei)}
+}
+
+// ERB returns an amount of whole ERB.
+func ERB(erb int64) Amount {
+	return Amount{new(big.Int).Mul(big.NewInt(erb), weiPerERB)}
+}
+
+// ParseERB parses a decimal number of ERB with at most 18 decimals, e.g. "1.5".
+func ParseERB(s string) (Amount, error) {
+	str := strings.TrimSpace(s)
+	neg := strings.HasPrefix(str, "-")
+	str = strings.TrimPrefix(str, "-")
+	whole, frac := str, ""
+	if i := strings.IndexByte(str, '.'); i >= 0 {
+		whole, frac = str[:i], str[i+1:]
+	}
+	if whole == "" && frac == "" || len(frac) > ERBDecimals || strings.ContainsAny(whole+frac, "+-") {
+		return Amount{}, fmt.Errorf("invalid ERB amount %q", s)
+	}
+	wei, ok := new(big.Int).SetString("0"+whole+frac+strings.Repeat("0", ERBDecimals-len(frac)), 10)
+	if !ok {
+		return Amount{}, fmt.Errorf("invalid ERB amount %q", s)
+	}
+	if neg {
+		wei.Neg(wei)
+	}
+	return Amount{wei}, nil
+}
+
+// ParseAmount parses a hex number of wei with a 0x prefix, the format of signed orders, or
+// otherwise a decimal number of ERB.
+func ParseAmount(s string) (Amount, error) {
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		wei, err := hexutil.DecodeBig(s)
+		if err != nil {
+			return Amount{}, fmt.Errorf("invalid wei amount %q: %v", s, err)
+		}
+		return Amount{wei}, nil
+	}
+	return ParseERB(s)
+}
+
+// Wei returns the amount in wei.
+func (a Amount) Wei() *big.Int {
+	if a.wei == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(a.wei)
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of a.
+func (a Amount) Sign() int {
+	if a.wei == nil {
+		return 0
+	}
+	return a.wei.Sign()
+}
+
+// Cmp compares a and b and returns -1, 0 or +1.
+func (a Amount) Cmp(b Amount) int {
+	return a.value().Cmp(b.value())
+}
+
+// Add returns a + b.
+func (a Amount) Add(b Amount) Amount {
+	return Amount{new(big.Int).Add(a.value(), b.value())}
+}
+
+// Sub returns a - b.
+func (a Amount) Sub(b Amount) Amount {
+	return Amount{new(big.Int).Sub(a.value(), b.value())}
+}
+
+func (a Amount) value() *big.Int {
+	if a.wei == nil {
+		return new(big.Int)
+	}
+	return a.wei
+}
+
+// Hex returns the amount in wei as a hex string, the format signed by buyer and seller orders.
+func (a Amount) Hex() string {
+	return hexutil.EncodeBig(a.value())
+}
+
+// String returns the amount as an exact decimal number of ERB.
+func (a Amount) String() string {
+	wei := a.value()
+	quo, rem := new(big.Int).QuoRem(new(big.Int).Abs(wei), weiPerERB, new(big.Int))
+	s := quo.String()
+	if rem.Sign() != 0 {
+		s += "." + strings.TrimRight(fmt.Sprintf("%018s", rem.String()), "0")
+	}
+	if wei.Sign() < 0 {
+		s = "-" + s
+	}
+	return s
+}
+
+// MarshalText encodes the amount as a decimal number of ERB.
+func (a Amount) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalText decodes an amount in the formats of ParseAmount.
+func (a *Amount) UnmarshalText(text []byte) error {
+	amount, err := ParseAmount(string(text))
+	if err != nil {
+		return err
+	}
+	*a = amount
+	return nil
+}
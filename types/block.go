@@ -0,0 +1,58 @@
+package types
+
+import (
+	"encoding/json"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Header holds the block header fields needed for chain analysis.
+// Unlike core/types.Header it does not require the full set of consensus fields to decode.
+type Header struct {
+	Number     uint64
+	Hash       common.Hash
+	ParentHash common.Hash
+	Miner      common.Address
+	Time       uint64
+	GasUsed    uint64
+	GasLimit   uint64
+}
+
+type headerMarshaling struct {
+	Number     hexutil.Uint64 `json:"number"`
+	Hash       common.Hash    `json:"hash"`
+	ParentHash common.Hash    `json:"parentHash"`
+	Miner      common.Address `json:"miner"`
+	Time       hexutil.Uint64 `json:"timestamp"`
+	GasUsed    hexutil.Uint64 `json:"gasUsed"`
+	GasLimit   hexutil.Uint64 `json:"gasLimit"`
+}
+
+func (h *Header) UnmarshalJSON(input []byte) error {
+	var dec headerMarshaling
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	*h = Header{
+		Number:     uint64(dec.Number),
+		Hash:       dec.Hash,
+		ParentHash: dec.ParentHash,
+		Miner:      dec.Miner,
+		Time:       uint64(dec.Time),
+		GasUsed:    uint64(dec.GasUsed),
+		GasLimit:   uint64(dec.GasLimit),
+	}
+	return nil
+}
+
+func (h Header) MarshalJSON() ([]byte, error) {
+	return json.Marshal(headerMarshaling{
+		Number:     hexutil.Uint64(h.Number),
+		Hash:       h.Hash,
+		ParentHash: h.ParentHash,
+		Miner:      h.Miner,
+		Time:       hexutil.Uint64(h.Time),
+		GasUsed:    hexutil.Uint64(h.GasUsed),
+		GasLimit:   hexutil.Uint64(h.GasLimit),
+	})
+}
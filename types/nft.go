@@ -1,110 +1,161 @@
 package types
 
 import (
+	"fmt"
 	"github.com/ethereum/go-ethereum/common"
 	"math/big"
 )
 
+// The JSON names of the account and miner types are the field names in lowerCamelCase. The node
+// encodes them with the field names, which still decode as JSON keys match case-insensitively.
+
 type Account struct {
-	Nonce   uint64
-	Balance *big.Int
+	Nonce   uint64   `json:"nonce"`
+	Balance *big.Int `json:"balance"`
 	// *** modify to support nft transaction 20211220 begin ***
 	//NFTCount uint64		// number of nft who account have
 	// *** modify to support nft transaction 20211220 end ***
-	Root     common.Hash // merkle root of the storage trie
-	CodeHash []byte
-	Worm     *WormholesExtension
+	Root     common.Hash         `json:"root"` // merkle root of the storage trie
+	CodeHash []byte              `json:"codeHash"`
+	Worm     *WormholesExtension `json:"worm"`
 	// NFTBalance is the nft number that the account have
-	Nft AccountNFT
+	Nft AccountNFT `json:"nft"`
+}
+
+// String formats the nonce and balance of the account and its wormholes state.
+func (a Account) String() string {
+	return fmt.Sprintf("Account{Nonce: %d, Balance: %s ERB, Worm: %v, Nft: %v}", a.Nonce, Wei(a.Balance), a.Worm, a.Nft)
 }
 
 type WormholesExtension struct {
-	PledgedBalance     *big.Int
-	PledgedBlockNumber *big.Int
+	PledgedBalance     *big.Int `json:"pledgedBalance"`
+	PledgedBlockNumber *big.Int `json:"pledgedBlockNumber"`
 	// *** modify to support nft transaction 20211215 ***
 	//Owner common.Address
 	// whether the account has a NFT exchanger
-	ExchangerFlag      bool
-	BlockNumber        *big.Int
-	ExchangerBalance   *big.Int
-	SNFTAgentRecipient common.Address
-	VoteBlockNumber    *big.Int
-	VoteWeight         *big.Int
-	Coefficient        uint8
+	ExchangerFlag      bool           `json:"exchangerFlag"`
+	BlockNumber        *big.Int       `json:"blockNumber"`
+	ExchangerBalance   *big.Int       `json:"exchangerBalance"`
+	SNFTAgentRecipient common.Address `json:"snftAgentRecipient"`
+	VoteBlockNumber    *big.Int       `json:"voteBlockNumber"`
+	VoteWeight         *big.Int       `json:"voteWeight"`
+	Coefficient        uint8          `json:"coefficient"`
 	// The ratio that exchanger get.
-	FeeRate       uint16
-	ExchangerName string
-	ExchangerURL  string
+	FeeRate       uint16 `json:"feeRate"`
+	ExchangerName string `json:"exchangerName"`
+	ExchangerURL  string `json:"exchangerUrl"`
 	// ApproveAddress have the right to handle all nfts of the account
-	ApproveAddressList []common.Address
+	ApproveAddressList []common.Address `json:"approveAddressList"`
 	// NFTBalance is the nft number that the account have
 	//NFTBalance uint64
 	// Indicates the reward method chosen by the miner
 	//RewardFlag uint8 // 0:SNFT 1:ERB default:1
-	SNFTNoMerge     bool
-	LockSNFTFlag    bool
-	NFTBalance      uint64
-	StakerExtension StakersExtensionList
+	SNFTNoMerge     bool                 `json:"snftNoMerge"`
+	LockSNFTFlag    bool                 `json:"lockSnftFlag"`
+	NFTBalance      uint64               `json:"nftBalance"`
+	StakerExtension StakersExtensionList `json:"stakerExtension"`
+}
+
+// String formats the pledge, the exchanger and the NFT balance of the account, the approved
+// accounts and the stakers are counted.
+func (w WormholesExtension) String() string {
+	return fmt.Sprintf("WormholesExtension{PledgedBalance: %s ERB, Coefficient: %d, ExchangerFlag: %t, ExchangerName: %q, FeeRate: %d, ExchangerBalance: %s ERB, NFTBalance: %d, Approved: %d, Stakers: %d}",
+		Wei(w.PledgedBalance), w.Coefficient, w.ExchangerFlag, w.ExchangerName, w.FeeRate, Wei(w.ExchangerBalance), w.NFTBalance, len(w.ApproveAddressList), len(w.StakerExtension.StakerExtensions))
 }
 
 type AccountNFT struct {
 	//Account
-	Name                  string
-	Symbol                string
-	Price                 *big.Int
-	Direction             uint8 // 0:not traded,1:buyer,2:sell
-	Owner                 common.Address
-	NFTApproveAddressList common.Address
+	Name                  string         `json:"name"`
+	Symbol                string         `json:"symbol"`
+	Price                 *big.Int       `json:"price"`
+	Direction             uint8          `json:"direction"` // 0:not traded,1:buyer,2:sell
+	Owner                 common.Address `json:"owner"`
+	NFTApproveAddressList common.Address `json:"nftApproveAddressList"`
 	//Auctions map[string][]common.Address
 	// MergeLevel is the level of NFT merged
-	MergeLevel uint8
+	MergeLevel uint8 `json:"mergeLevel"`
 
-	Creator   common.Address
-	Royalty   uint32
-	Exchanger common.Address
-	MetaURL   string
+	Creator   common.Address `json:"creator"`
+	Royalty   uint32         `json:"royalty"`
+	Exchanger common.Address `json:"exchanger"`
+	MetaURL   string         `json:"metaUrl"`
+}
+
+// String formats the owner, creator, royalty and metadata of the NFT.
+func (n AccountNFT) String() string {
+	return fmt.Sprintf("AccountNFT{Owner: %s, Creator: %s, Royalty: %d, Exchanger: %s, MergeLevel: %d, MetaURL: %q}", n.Owner, n.Creator, n.Royalty, n.Exchanger, n.MergeLevel, n.MetaURL)
 }
 
 type ValidatorList struct {
-	Validators []*Validator
+	Validators []*Validator `json:"validators"`
 }
 
 type Validator struct {
-	Addr    common.Address
-	Balance *big.Int
-	Proxy   common.Address
-	Weight  []*big.Int
+	Addr    common.Address `json:"addr"`
+	Balance *big.Int       `json:"balance"`
+	Proxy   common.Address `json:"proxy"`
+	Weight  []*big.Int     `json:"weight"`
+}
+
+// String formats the address, pledge and proxy of the validator.
+func (v Validator) String() string {
+	return fmt.Sprintf("Validator{Addr: %s, Balance: %s ERB, Proxy: %s, Weight: %v}", v.Addr, Wei(v.Balance), v.Proxy, v.Weight)
 }
 
 type BeneficiaryAddress struct {
-	Address    common.Address
-	NftAddress common.Address
+	Address    common.Address `json:"address"`
+	NftAddress common.Address `json:"nftAddress"`
 }
 
 type BeneficiaryAddressList []*BeneficiaryAddress
 
 type ActiveMiner struct {
-	Address common.Address
-	Balance *big.Int
-	Height  uint64
+	Address common.Address `json:"address"`
+	Balance *big.Int       `json:"balance"`
+	Height  uint64         `json:"height"`
+}
+
+// String formats the address, pledge and height of the miner.
+func (m ActiveMiner) String() string {
+	return fmt.Sprintf("ActiveMiner{Address: %s, Balance: %s ERB, Height: %d}", m.Address, Wei(m.Balance), m.Height)
 }
 
 type ActiveMinerList struct {
-	ActiveMiners []*ActiveMiner
+	ActiveMiners []*ActiveMiner `json:"activeMiners"`
 }
 
 type MinerProxy struct {
-	Address common.Address
-	Proxy   common.Address
+	Address common.Address `json:"address"`
+	Proxy   common.Address `json:"proxy"`
 }
 
 type MinerProxyList []*MinerProxy
 
 type StakersExtensionList struct {
-	StakerExtensions []*StakerExtension
+	StakerExtensions []*StakerExtension `json:"stakerExtensions"`
 }
 type StakerExtension struct {
-	Addr        common.Address
-	Balance     *big.Int
-	BlockNumber *big.Int
+	Addr        common.Address `json:"addr"`
+	Balance     *big.Int       `json:"balance"`
+	BlockNumber *big.Int       `json:"blockNumber"`
+}
+
+// String formats the address and stake of the staker.
+func (s StakerExtension) String() string {
+	return fmt.Sprintf("StakerExtension{Addr: %s, Balance: %s ERB, BlockNumber: %v}", s.Addr, Wei(s.Balance), s.BlockNumber)
+}
+
+// Exchanger is an account that has opened an NFT exchange.
+type Exchanger struct {
+	Address        common.Address `json:"address"`
+	Name           string         `json:"name"`
+	URL            string         `json:"url"`
+	FeeRate        uint16         `json:"feeRate"`
+	PledgedBalance *big.Int       `json:"pledgedBalance"`
+	BlockNumber    *big.Int       `json:"blockNumber"`
+}
+
+// String formats the exchanger with its fee rate in basis points.
+func (e Exchanger) String() string {
+	return fmt.Sprintf("Exchanger{Address: %s, Name: %q, URL: %q, FeeRate: %d, PledgedBalance: %s ERB}", e.Address, e.Name, e.URL, e.FeeRate, Wei(e.PledgedBalance))
 }
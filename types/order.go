@@ -0,0 +1,401 @@
+package types
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// MaxRoyalty is the largest royalty of an NFT. Royalties are in basis points, ten thousandths
+// of the price, e.g. 250 for 2.5%. Mint transactions carry them as numbers, sell orders of
+// unminted NFTs as hex strings.
+const MaxRoyalty = 10000
+
+// ParseRoyalty parses a royalty in basis points, a decimal number of at most MaxRoyalty such as
+// "250" for 2.5%. A hex number with a 0x prefix, the encoding of sell orders, is accepted too.
+func ParseRoyalty(s string) (uint32, error) {
+	base, digits := 10, s
+	if hasHexPrefix(s) {
+		base, digits = 16, s[2:]
+	}
+	royalty, err := strconv.ParseUint(digits, base, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid royalty %q, want basis points", s)
+	}
+	if royalty > MaxRoyalty {
+		return 0, fmt.Errorf("royalty %s is out of range 0..%d basis points", s, MaxRoyalty)
+	}
+	return uint32(royalty), nil
+}
+
+// ParseAddress parses a hex address of 40 digits with a 0x prefix. A mixed case address must
+// be EIP-55 checksummed, so a mistyped digit is rejected rather than signed; an all lowercase or
+// all uppercase one is accepted. Decimal numbers and other encodings are not addresses.
+func ParseAddress(s string) (common.Address, error) {
+	if !hasHexPrefix(s) || len(s) != 42 || !isHex(s[2:]) {
+		return common.Address{}, fmt.Errorf("invalid address %q, want 0x and 40 hex digits", s)
+	}
+	address := common.HexToAddress(s)
+	digits := s[2:]
+	if digits != strings.ToLower(digits) && digits != strings.ToUpper(digits) && digits != address.Hex()[2:] {
+		return common.Address{}, fmt.Errorf("address %s has an invalid checksum, want %s", s, address.Hex())
+	}
+	return address, nil
+}
+
+// MaxMetaURLLength is the longest NFT metadata URL accepted by the client.
+const MaxMetaURLLength = 256
+
+// The constructors build unsigned orders and authorizations, sign them with the Sign*Order
+// methods of the client wallet. They validate the fields and format them the way the signers
+// do: numbers as lowercase hex, addresses checksummed and NFT addresses in lowercase. Message
+// returns the pre-image the signature is over, SigningHash its hash as tools.SignHash computes
+// it, the digest an external signer signs. Validate checks orders decoded from JSON.
+
+// NewBuyer returns the unsigned order of a buyer paying amount for the NFT nftAddress through
+// exchanger, valid before block blockNumber. An empty nftAddress buys an unminted NFT of seller,
+// seller may be empty.
+func NewBuyer(amount Amount, nftAddress, exchanger string, blockNumber uint64, seller string) (*Buyer, error) {
+	b := &Buyer{
+		Amount:      amount.Hex(),
+		NFTAddress:  normalizeNFTAddress(nftAddress),
+		Exchanger:   normalizeAddress(exchanger),
+		BlockNumber: hexutil.EncodeUint64(blockNumber),
+		Seller:      normalizeAddress(seller),
+	}
+	if err := b.Validate(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Message returns the message signed by the buyer.
+func (b *Buyer) Message() string {
+	return b.Amount + b.NFTAddress + b.Exchanger + b.BlockNumber + b.Seller
+}
+
+// SigningHash returns the hash of Message the buyer signs.
+func (b *Buyer) SigningHash() common.Hash {
+	return signingHash(b.Amount, b.NFTAddress, b.Exchanger, b.BlockNumber, b.Seller)
+}
+
+// Validate checks the fields of the order, not its signature.
+func (b *Buyer) Validate() error {
+	if err := checkHex("buyer price", b.Amount); err != nil {
+		return err
+	}
+	if b.NFTAddress != "" {
+		if err := checkNFTAddress("buyer nft_address", b.NFTAddress); err != nil {
+			return err
+		}
+	}
+	if err := checkOptionalExchanger("buyer exchanger", b.Exchanger); err != nil {
+		return err
+	}
+	if err := checkOptionalAddress("buyer seller", b.Seller); err != nil {
+		return err
+	}
+	return checkHex("buyer block_number", b.BlockNumber)
+}
+
+// NewSeller1 returns the unsigned order of the owner of the minted NFT nftAddress selling it for
+// amount through exchanger, valid before block blockNumber.
+func NewSeller1(amount Amount, nftAddress, exchanger string, blockNumber uint64) (*Seller1, error) {
+	s := &Seller1{
+		Amount:      amount.Hex(),
+		NFTAddress:  normalizeNFTAddress(nftAddress),
+		Exchanger:   normalizeAddress(exchanger),
+		BlockNumber: hexutil.EncodeUint64(blockNumber),
+	}
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Message returns the message signed by the seller.
+func (s *Seller1) Message() string {
+	return s.Amount + s.NFTAddress + s.Exchanger + s.BlockNumber
+}
+
+// SigningHash returns the hash of Message the seller signs.
+func (s *Seller1) SigningHash() common.Hash {
+	return signingHash(s.Amount, s.NFTAddress, s.Exchanger, s.BlockNumber)
+}
+
+// Validate checks the fields of the order, not its signature.
+func (s *Seller1) Validate() error {
+	if err := checkHex("seller1 price", s.Amount); err != nil {
+		return err
+	}
+	if err := checkNFTAddress("seller1 nft_address", s.NFTAddress); err != nil {
+		return err
+	}
+	if err := checkOptionalExchanger("seller1 exchanger", s.Exchanger); err != nil {
+		return err
+	}
+	return checkHex("seller1 block_number", s.BlockNumber)
+}
+
+// NewSeller2 returns the unsigned order of a creator selling the unminted NFT of metaURL for
+// amount through exchanger, valid before block blockNumber. An exclusive NFT can only be traded
+// through the exchanger it is minted with.
+func NewSeller2(amount Amount, royalty uint32, metaURL string, exclusive bool, exchanger string, blockNumber uint64) (*Seller2, error) {
+	s := &Seller2{
+		Amount:        amount.Hex(),
+		Royalty:       hexutil.EncodeUint64(uint64(royalty)),
+		MetaURL:       metaURL,
+		ExclusiveFlag: "0",
+		Exchanger:     normalizeAddress(exchanger),
+		BlockNumber:   hexutil.EncodeUint64(blockNumber),
+	}
+	if exclusive {
+		s.ExclusiveFlag = "1"
+	}
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Message returns the message signed by the seller.
+func (s *Seller2) Message() string {
+	return s.Amount + s.Royalty + s.MetaURL + s.ExclusiveFlag + s.Exchanger + s.BlockNumber
+}
+
+// SigningHash returns the hash of Message the seller signs.
+func (s *Seller2) SigningHash() common.Hash {
+	return signingHash(s.Amount, s.Royalty, s.MetaURL, s.ExclusiveFlag, s.Exchanger, s.BlockNumber)
+}
+
+// Validate checks the fields of the order, not its signature.
+func (s *Seller2) Validate() error {
+	if err := checkHex("seller2 price", s.Amount); err != nil {
+		return err
+	}
+	if err := checkHex("seller2 royalty", s.Royalty); err != nil {
+		return err
+	}
+	if royalty, _ := new(big.Int).SetString(s.Royalty[2:], 16); royalty.Cmp(big.NewInt(MaxRoyalty)) > 0 {
+		return fmt.Errorf("seller2 royalty %s is out of range 0..%d", s.Royalty, MaxRoyalty)
+	}
+	if err := checkMetaURL("seller2 meta_url", s.MetaURL); err != nil {
+		return err
+	}
+	if s.ExclusiveFlag != "0" && s.ExclusiveFlag != "1" {
+		return fmt.Errorf("seller2 exclusive_flag is not the need flag")
+	}
+	if err := checkOptionalExchanger("seller2 exchanger", s.Exchanger); err != nil {
+		return err
+	}
+	return checkHex("seller2 block_number", s.BlockNumber)
+}
+
+// NewExchangerAuth returns the unsigned authorization of the owner of an exchanger for the
+// account to to settle the orders of the exchanger before block blockNumber.
+func NewExchangerAuth(exchangerOwner, to string, blockNumber uint64) (*ExchangerAuth, error) {
+	a := &ExchangerAuth{
+		ExchangerOwner: normalizeAddress(exchangerOwner),
+		To:             normalizeAddress(to),
+		BlockNumber:    hexutil.EncodeUint64(blockNumber),
+	}
+	if err := a.Validate(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Message returns the message signed by the owner of the exchanger.
+func (a *ExchangerAuth) Message() string {
+	return a.ExchangerOwner + a.To + a.BlockNumber
+}
+
+// SigningHash returns the hash of Message the owner of the exchanger signs.
+func (a *ExchangerAuth) SigningHash() common.Hash {
+	return signingHash(a.ExchangerOwner, a.To, a.BlockNumber)
+}
+
+// Validate checks the fields of the authorization, not its signature.
+func (a *ExchangerAuth) Validate() error {
+	if err := checkExchanger("exchanger auth exchanger_owner", a.ExchangerOwner); err != nil {
+		return err
+	}
+	if err := checkAddress("exchanger auth to", a.To); err != nil {
+		return err
+	}
+	return checkHex("exchanger auth block_number", a.BlockNumber)
+}
+
+// Message returns the message signed by the buyer.
+func (a *Buyauth) Message() string {
+	return a.Exchanger + a.BlockNumber
+}
+
+// SigningHash returns the hash of Message the buyer signs.
+func (a *Buyauth) SigningHash() common.Hash {
+	return signingHash(a.Exchanger, a.BlockNumber)
+}
+
+// Validate checks the fields of the authorization, not its signature.
+func (a *Buyauth) Validate() error {
+	if err := checkExchanger("buyer auth exchanger", a.Exchanger); err != nil {
+		return err
+	}
+	return checkHex("buyer auth block_number", a.BlockNumber)
+}
+
+// Message returns the message signed by the seller.
+func (a *Sellerauth) Message() string {
+	return a.Exchanger + a.BlockNumber
+}
+
+// SigningHash returns the hash of Message the seller signs.
+func (a *Sellerauth) SigningHash() common.Hash {
+	return signingHash(a.Exchanger, a.BlockNumber)
+}
+
+// Validate checks the fields of the authorization, not its signature.
+func (a *Sellerauth) Validate() error {
+	if err := checkExchanger("seller auth exchanger", a.Exchanger); err != nil {
+		return err
+	}
+	return checkHex("seller auth block_number", a.BlockNumber)
+}
+
+// signingHashers are reused for the signing hashes of orders, which are computed in bulk.
+var signingHashers = sync.Pool{
+	New: func() interface{} { return &signingHasher{state: crypto.NewKeccakState()} },
+}
+
+type signingHasher struct {
+	state crypto.KeccakState
+	buf   []byte
+}
+
+// signingHash returns the EIP-191 hash of the personal message of the concatenated parts, the
+// hash of tools.SignHash, in a reused buffer rather than by building the message.
+func signingHash(parts ...string) (hash common.Hash) {
+	n := 0
+	for _, part := range parts {
+		n += len(part)
+	}
+	h := signingHashers.Get().(*signingHasher)
+	defer signingHashers.Put(h)
+	h.buf = append(h.buf[:0], "\x19Ethereum Signed Message:\n"...)
+	h.buf = strconv.AppendInt(h.buf, int64(n), 10)
+	for _, part := range parts {
+		h.buf = append(h.buf, part...)
+	}
+	h.state.Reset()
+	h.state.Write(h.buf)
+	h.state.Read(hash[:])
+	return hash
+}
+
+// normalizeAddress checksums an address, other strings are left for Validate to reject.
+func normalizeAddress(address string) string {
+	if hasHexPrefix(address) && len(address) == 42 && isHex(address[2:]) {
+		return common.HexToAddress(address).Hex()
+	}
+	return address
+}
+
+// normalizeNFTAddress formats an NFT address in lowercase.
+func normalizeNFTAddress(address string) string {
+	if hasHexPrefix(address) && isHex(address[2:]) {
+		return strings.ToLower(address)
+	}
+	return address
+}
+
+func checkAddress(name, value string) error {
+	if !hasHexPrefix(value) {
+		return fmt.Errorf("%s is not string of 0x", name)
+	}
+	if len(value) != 42 {
+		return fmt.Errorf("the len of %s must be 42", name)
+	}
+	if !isHex(value[2:]) {
+		return fmt.Errorf("%s is not a hex address: %s", name, value)
+	}
+	return nil
+}
+
+func checkOptionalAddress(name, value string) error {
+	if value == "" {
+		return nil
+	}
+	return checkAddress(name, value)
+}
+
+// checkExchanger checks the address of an exchanger, which is signed as it is written, with
+// ParseAddress.
+func checkExchanger(name, value string) error {
+	if err := checkAddress(name, value); err != nil {
+		return err
+	}
+	if _, err := ParseAddress(value); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+func checkOptionalExchanger(name, value string) error {
+	if value == "" {
+		return nil
+	}
+	return checkExchanger(name, value)
+}
+
+func checkHex(name, value string) error {
+	if !hasHexPrefix(value) {
+		return fmt.Errorf("%s is not string of 0x", name)
+	}
+	if len(value) == 2 || !isHex(value[2:]) {
+		return fmt.Errorf("%s is not a hex number: %q", name, value)
+	}
+	return nil
+}
+
+func checkNFTAddress(name, value string) error {
+	if err := checkHex(name, value); err != nil {
+		return err
+	}
+	if len(value) > 42 {
+		return fmt.Errorf("the len of %s must be at most 42", name)
+	}
+	return nil
+}
+
+func checkMetaURL(name, value string) error {
+	if len(value) > MaxMetaURLLength {
+		return fmt.Errorf("the len of %s must be at most %d", name, MaxMetaURLLength)
+	}
+	for _, r := range value {
+		if r == utf8.RuneError || !unicode.IsPrint(r) {
+			return fmt.Errorf("%s contains an invalid character: %q", name, value)
+		}
+	}
+	return nil
+}
+
+func hasHexPrefix(s string) bool {
+	return strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X")
+}
+
+func isHex(s string) bool {
+	for _, c := range []byte(s) {
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+			return false
+		}
+	}
+	return true
+}
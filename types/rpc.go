@@ -0,0 +1,74 @@
+package types
+
+import (
+	"encoding/json"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"math/big"
+)
+
+// AccountResult is the result of a GetProof operation.
+type AccountResult struct {
+	Address      common.Address  `json:"address"`
+	AccountProof []string        `json:"accountProof"`
+	Balance      *big.Int        `json:"balance"`
+	CodeHash     common.Hash     `json:"codeHash"`
+	Nonce        uint64          `json:"nonce"`
+	StorageHash  common.Hash     `json:"storageHash"`
+	StorageProof []StorageResult `json:"storageProof"`
+}
+
+// StorageResult provides a proof for a key-value pair.
+type StorageResult struct {
+	Key   string   `json:"key"`
+	Value *big.Int `json:"value"`
+	Proof []string `json:"proof"`
+}
+
+// TxPoolStatus is the number of transactions currently held by the node's transaction pool.
+type TxPoolStatus struct {
+	Pending uint64 `json:"pending"`
+	Queued  uint64 `json:"queued"`
+}
+
+// TxPoolContent holds the pooled transactions of a single account keyed by nonce.
+// Queued transactions are waiting for a nonce gap to be filled before they become pending.
+type TxPoolContent struct {
+	Pending map[uint64]*types.Transaction `json:"pending"`
+	Queued  map[uint64]*types.Transaction `json:"queued"`
+}
+
+// TraceConfig holds the options of the debug_trace* calls.
+// When Tracer is empty the node uses its default struct logger.
+type TraceConfig struct {
+	EnableMemory     bool            `json:"enableMemory,omitempty"`
+	DisableStack     bool            `json:"disableStack,omitempty"`
+	DisableStorage   bool            `json:"disableStorage,omitempty"`
+	EnableReturnData bool            `json:"enableReturnData,omitempty"`
+	Tracer           string          `json:"tracer,omitempty"`
+	Timeout          string          `json:"timeout,omitempty"`
+	Reexec           *uint64         `json:"reexec,omitempty"`
+	TracerConfig     json.RawMessage `json:"tracerConfig,omitempty"`
+}
+
+// TxTraceResult is the trace of a single transaction of a traced block.
+type TxTraceResult struct {
+	TxHash common.Hash     `json:"txHash"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// NodeInfo is the information the node reports about itself through admin_nodeInfo.
+type NodeInfo struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Enode string `json:"enode"`
+	ENR   string `json:"enr"`
+	IP    string `json:"ip"`
+	Ports struct {
+		Discovery int `json:"discovery"`
+		Listener  int `json:"listener"`
+	} `json:"ports"`
+	ListenAddr string                     `json:"listenAddr"`
+	Protocols  map[string]json.RawMessage `json:"protocols"`
+}
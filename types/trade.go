@@ -0,0 +1,54 @@
+package types
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"math/big"
+)
+
+// Trade is a settled NFT sale decoded from a mined wormholes transaction.
+type Trade struct {
+	TxHash      common.Hash
+	BlockNumber uint64
+	Type        uint8
+	Buyer       common.Address
+	Seller      common.Address
+	Exchanger   common.Address
+	// NFTAddress is empty for trades of unminted NFTs, which are identified by MetaURL instead.
+	NFTAddress string
+	MetaURL    string
+	Price      *big.Int
+	Creator    common.Address
+	// RoyaltyPaid and FeePaid are the parts of Price paid to the creator and the exchanger.
+	RoyaltyPaid *big.Int
+	FeePaid     *big.Int
+}
+
+// RateDenominator is the denominator of royalties and exchanger fee rates, which are in basis
+// points.
+const RateDenominator = 10000
+
+// tradeTypes maps the transaction types that settle an NFT sale to whether the sender of the
+// transaction is the buyer. For the other settlement types the buyer is the recipient.
+var tradeTypes = map[uint8]bool{
+	TransactionNFT:              false,
+	BuyerInitiatingTransaction:  true,
+	FoundryTradeBuyer:           true,
+	FoundryExchange:             false,
+	NftExchangeMatch:            false,
+	FoundryExchangeInitiated:    false,
+	FtDoesNotAuthorizeExchanges: false,
+	BatchSellTransfer:           false,
+	ForceBuyingTransfer:         false,
+}
+
+// IsTrade reports whether the transactions of txType settle an NFT sale.
+func IsTrade(txType uint8) bool {
+	_, ok := tradeTypes[txType]
+	return ok
+}
+
+// SenderIsBuyer reports whether the sender of a trade of txType is the buyer, the buyer of the
+// other trades is the recipient of the transaction.
+func SenderIsBuyer(txType uint8) bool {
+	return tradeTypes[txType]
+}
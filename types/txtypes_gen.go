@@ -0,0 +1,805 @@
+// Code generated by gentypes from txtypes.json. DO NOT EDIT.
+
+package types
+
+import "fmt"
+
+// The wormholes transaction types, the Type of a Transaction. The codes are defined by the
+// chain, the missing ones are not used.
+const (
+	Mint                               = 0
+	Transfer                           = 1
+	Author                             = 2
+	AuthorRevoke                       = 3
+	AccountAuthor                      = 4
+	AccountAuthorRevoke                = 5
+	SNFTToERB                          = 6
+	SNFTPledge                         = 7
+	SNFTRevokesPledge                  = 8
+	TokenPledge                        = 9
+	TokenRevokesPledge                 = 10
+	TransactionNFT                     = 14
+	BuyerInitiatingTransaction         = 15
+	FoundryTradeBuyer                  = 16
+	FoundryExchange                    = 17
+	NftExchangeMatch                   = 18
+	FoundryExchangeInitiated           = 19
+	FtDoesNotAuthorizeExchanges        = 20
+	AdditionalPledgeAmount             = 21
+	RevokesPledgeAmount                = 22
+	VoteOfficialNFT                    = 23
+	VoteOfficialNFTByApprovedExchanger = 24
+	UnfreezeAccount                    = 25
+	WeightRedemption                   = 26
+	BatchSellTransfer                  = 27
+	ForceBuyingTransfer                = 28
+	ExtractERB                         = 29
+	AccountDelegate                    = 31
+)
+
+var typeNames = map[uint8]string{
+	Mint:                               "Mint",
+	Transfer:                           "Transfer",
+	Author:                             "Author",
+	AuthorRevoke:                       "AuthorRevoke",
+	AccountAuthor:                      "AccountAuthor",
+	AccountAuthorRevoke:                "AccountAuthorRevoke",
+	SNFTToERB:                          "SNFTToERB",
+	SNFTPledge:                         "SNFTPledge",
+	SNFTRevokesPledge:                  "SNFTRevokesPledge",
+	TokenPledge:                        "TokenPledge",
+	TokenRevokesPledge:                 "TokenRevokesPledge",
+	TransactionNFT:                     "TransactionNFT",
+	BuyerInitiatingTransaction:         "BuyerInitiatingTransaction",
+	FoundryTradeBuyer:                  "FoundryTradeBuyer",
+	FoundryExchange:                    "FoundryExchange",
+	NftExchangeMatch:                   "NftExchangeMatch",
+	FoundryExchangeInitiated:           "FoundryExchangeInitiated",
+	FtDoesNotAuthorizeExchanges:        "FtDoesNotAuthorizeExchanges",
+	AdditionalPledgeAmount:             "AdditionalPledgeAmount",
+	RevokesPledgeAmount:                "RevokesPledgeAmount",
+	VoteOfficialNFT:                    "VoteOfficialNFT",
+	VoteOfficialNFTByApprovedExchanger: "VoteOfficialNFTByApprovedExchanger",
+	UnfreezeAccount:                    "UnfreezeAccount",
+	WeightRedemption:                   "WeightRedemption",
+	BatchSellTransfer:                  "BatchSellTransfer",
+	ForceBuyingTransfer:                "ForceBuyingTransfer",
+	ExtractERB:                         "ExtractERB",
+	AccountDelegate:                    "AccountDelegate",
+}
+
+// Transaction is the payload of a wormholes transaction, JSON in the data of the transaction
+// after the wormholes prefix. Each type uses some of the fields, see DecodePayload.
+type Transaction struct {
+	Type          uint8          `json:"type"`
+	Dir           string         `json:"dir,omitempty"`
+	StartIndex    string         `json:"start_index,omitempty"`
+	Number        uint64         `json:"number,omitempty"`
+	NFTAddress    string         `json:"nft_address,omitempty"`
+	Royalty       uint32         `json:"royalty,omitempty"`
+	MetaURL       string         `json:"meta_url,omitempty"`
+	Exchanger     string         `json:"exchanger,omitempty"`
+	FeeRate       uint32         `json:"fee_rate,omitempty"`
+	Name          string         `json:"name,omitempty"`
+	Url           string         `json:"url,omitempty"`
+	Buyer         *Buyer         `json:"buyer,omitempty"`
+	Seller1       *Seller1       `json:"seller1,omitempty"`
+	Seller2       *Seller2       `json:"seller2,omitempty"`
+	BuyerAuth     *Buyauth       `json:"buyer_auth,omitempty"`
+	SellerAuth    *Sellerauth    `json:"seller_auth,omitempty"`
+	ExchangerAuth *ExchangerAuth `json:"exchanger_auth,omitempty"`
+	Creator       string         `json:"creator,omitempty"`
+	RewardFlag    int            `json:"reward_flag,omitempty"`
+	ProxyAddress  string         `json:"proxy_address,omitempty"`
+	ProxySign     string         `json:"proxy_sign,omitempty"`
+	Version       string         `json:"version"`
+}
+
+// Payload is the typed payload of a wormholes transaction, holding the fields of its type.
+type Payload interface {
+	// Type returns the wormholes transaction type.
+	Type() uint8
+	// Transaction returns the payload to send, of the current version.
+	Transaction() *Transaction
+}
+
+// MintPayload is the payload of a Mint transaction, which mints an NFT owned by the sender.
+type MintPayload struct {
+	Royalty   uint32 `json:"royalty,omitempty"`
+	MetaURL   string `json:"meta_url,omitempty"`
+	Exchanger string `json:"exchanger,omitempty"`
+}
+
+// Type returns Mint.
+func (p *MintPayload) Type() uint8 {
+	return Mint
+}
+
+// Transaction returns the payload to send.
+func (p *MintPayload) Transaction() *Transaction {
+	return &Transaction{
+		Type:      Mint,
+		Royalty:   p.Royalty,
+		MetaURL:   p.MetaURL,
+		Exchanger: p.Exchanger,
+		Version:   WormHolesVersion,
+	}
+}
+
+// TransferPayload is the payload of a Transfer transaction, which transfers an NFT to the recipient of the transaction.
+type TransferPayload struct {
+	NFTAddress string `json:"nft_address,omitempty"`
+}
+
+// Type returns Transfer.
+func (p *TransferPayload) Type() uint8 {
+	return Transfer
+}
+
+// Transaction returns the payload to send.
+func (p *TransferPayload) Transaction() *Transaction {
+	return &Transaction{
+		Type:       Transfer,
+		NFTAddress: p.NFTAddress,
+		Version:    WormHolesVersion,
+	}
+}
+
+// AuthorPayload is the payload of a Author transaction, which approves the recipient to transfer an NFT.
+type AuthorPayload struct {
+	NFTAddress string `json:"nft_address,omitempty"`
+}
+
+// Type returns Author.
+func (p *AuthorPayload) Type() uint8 {
+	return Author
+}
+
+// Transaction returns the payload to send.
+func (p *AuthorPayload) Transaction() *Transaction {
+	return &Transaction{
+		Type:       Author,
+		NFTAddress: p.NFTAddress,
+		Version:    WormHolesVersion,
+	}
+}
+
+// AuthorRevokePayload is the payload of a AuthorRevoke transaction, which revokes the approval of an NFT.
+type AuthorRevokePayload struct {
+	NFTAddress string `json:"nft_address,omitempty"`
+}
+
+// Type returns AuthorRevoke.
+func (p *AuthorRevokePayload) Type() uint8 {
+	return AuthorRevoke
+}
+
+// Transaction returns the payload to send.
+func (p *AuthorRevokePayload) Transaction() *Transaction {
+	return &Transaction{
+		Type:       AuthorRevoke,
+		NFTAddress: p.NFTAddress,
+		Version:    WormHolesVersion,
+	}
+}
+
+// AccountAuthorPayload is the payload of a AccountAuthor transaction, which approves the recipient to transfer all NFTs of the sender.
+type AccountAuthorPayload struct {
+}
+
+// Type returns AccountAuthor.
+func (p *AccountAuthorPayload) Type() uint8 {
+	return AccountAuthor
+}
+
+// Transaction returns the payload to send.
+func (p *AccountAuthorPayload) Transaction() *Transaction {
+	return &Transaction{
+		Type:    AccountAuthor,
+		Version: WormHolesVersion,
+	}
+}
+
+// AccountAuthorRevokePayload is the payload of a AccountAuthorRevoke transaction, which revokes the approval of all NFTs.
+type AccountAuthorRevokePayload struct {
+}
+
+// Type returns AccountAuthorRevoke.
+func (p *AccountAuthorRevokePayload) Type() uint8 {
+	return AccountAuthorRevoke
+}
+
+// Transaction returns the payload to send.
+func (p *AccountAuthorRevokePayload) Transaction() *Transaction {
+	return &Transaction{
+		Type:    AccountAuthorRevoke,
+		Version: WormHolesVersion,
+	}
+}
+
+// SNFTToERBPayload is the payload of a SNFTToERB transaction, which exchanges an SNFT for ERB.
+type SNFTToERBPayload struct {
+	NFTAddress string `json:"nft_address,omitempty"`
+}
+
+// Type returns SNFTToERB.
+func (p *SNFTToERBPayload) Type() uint8 {
+	return SNFTToERB
+}
+
+// Transaction returns the payload to send.
+func (p *SNFTToERBPayload) Transaction() *Transaction {
+	return &Transaction{
+		Type:       SNFTToERB,
+		NFTAddress: p.NFTAddress,
+		Version:    WormHolesVersion,
+	}
+}
+
+// SNFTPledgePayload is the payload of a SNFTPledge transaction, which pledges an SNFT.
+type SNFTPledgePayload struct {
+	NFTAddress string `json:"nft_address,omitempty"`
+}
+
+// Type returns SNFTPledge.
+func (p *SNFTPledgePayload) Type() uint8 {
+	return SNFTPledge
+}
+
+// Transaction returns the payload to send.
+func (p *SNFTPledgePayload) Transaction() *Transaction {
+	return &Transaction{
+		Type:       SNFTPledge,
+		NFTAddress: p.NFTAddress,
+		Version:    WormHolesVersion,
+	}
+}
+
+// SNFTRevokesPledgePayload is the payload of a SNFTRevokesPledge transaction, which revokes the pledge of an SNFT.
+type SNFTRevokesPledgePayload struct {
+	NFTAddress string `json:"nft_address,omitempty"`
+}
+
+// Type returns SNFTRevokesPledge.
+func (p *SNFTRevokesPledgePayload) Type() uint8 {
+	return SNFTRevokesPledge
+}
+
+// Transaction returns the payload to send.
+func (p *SNFTRevokesPledgePayload) Transaction() *Transaction {
+	return &Transaction{
+		Type:       SNFTRevokesPledge,
+		NFTAddress: p.NFTAddress,
+		Version:    WormHolesVersion,
+	}
+}
+
+// TokenPledgePayload is the payload of a TokenPledge transaction, which pledges ERB to become a validator or an exchanger.
+type TokenPledgePayload struct {
+	ProxyAddress string `json:"proxy_address,omitempty"`
+	FeeRate      uint32 `json:"fee_rate,omitempty"`
+	Name         string `json:"name,omitempty"`
+	Url          string `json:"url,omitempty"`
+}
+
+// Type returns TokenPledge.
+func (p *TokenPledgePayload) Type() uint8 {
+	return TokenPledge
+}
+
+// Transaction returns the payload to send.
+func (p *TokenPledgePayload) Transaction() *Transaction {
+	return &Transaction{
+		Type:         TokenPledge,
+		ProxyAddress: p.ProxyAddress,
+		FeeRate:      p.FeeRate,
+		Name:         p.Name,
+		Url:          p.Url,
+		Version:      WormHolesVersion,
+	}
+}
+
+// TokenRevokesPledgePayload is the payload of a TokenRevokesPledge transaction, which revokes a pledge of ERB.
+type TokenRevokesPledgePayload struct {
+}
+
+// Type returns TokenRevokesPledge.
+func (p *TokenRevokesPledgePayload) Type() uint8 {
+	return TokenRevokesPledge
+}
+
+// Transaction returns the payload to send.
+func (p *TokenRevokesPledgePayload) Transaction() *Transaction {
+	return &Transaction{
+		Type:    TokenRevokesPledge,
+		Version: WormHolesVersion,
+	}
+}
+
+// TransactionNFTPayload is the payload of a TransactionNFT transaction, which sells an NFT to the signer of a buy order.
+type TransactionNFTPayload struct {
+	Buyer *Buyer `json:"buyer,omitempty"`
+}
+
+// Type returns TransactionNFT.
+func (p *TransactionNFTPayload) Type() uint8 {
+	return TransactionNFT
+}
+
+// Transaction returns the payload to send.
+func (p *TransactionNFTPayload) Transaction() *Transaction {
+	return &Transaction{
+		Type:    TransactionNFT,
+		Buyer:   p.Buyer,
+		Version: WormHolesVersion,
+	}
+}
+
+// BuyerInitiatingTransactionPayload is the payload of a BuyerInitiatingTransaction transaction, which buys a minted NFT with the sell order of its owner.
+type BuyerInitiatingTransactionPayload struct {
+	Seller1 *Seller1 `json:"seller1,omitempty"`
+}
+
+// Type returns BuyerInitiatingTransaction.
+func (p *BuyerInitiatingTransactionPayload) Type() uint8 {
+	return BuyerInitiatingTransaction
+}
+
+// Transaction returns the payload to send.
+func (p *BuyerInitiatingTransactionPayload) Transaction() *Transaction {
+	return &Transaction{
+		Type:    BuyerInitiatingTransaction,
+		Seller1: p.Seller1,
+		Version: WormHolesVersion,
+	}
+}
+
+// FoundryTradeBuyerPayload is the payload of a FoundryTradeBuyer transaction, which buys an unminted NFT with the sell order of its creator.
+type FoundryTradeBuyerPayload struct {
+	Seller2 *Seller2 `json:"seller2,omitempty"`
+}
+
+// Type returns FoundryTradeBuyer.
+func (p *FoundryTradeBuyerPayload) Type() uint8 {
+	return FoundryTradeBuyer
+}
+
+// Transaction returns the payload to send.
+func (p *FoundryTradeBuyerPayload) Transaction() *Transaction {
+	return &Transaction{
+		Type:    FoundryTradeBuyer,
+		Seller2: p.Seller2,
+		Version: WormHolesVersion,
+	}
+}
+
+// FoundryExchangePayload is the payload of a FoundryExchange transaction, which matches a buy order and a sell order of an unminted NFT.
+type FoundryExchangePayload struct {
+	Buyer   *Buyer   `json:"buyer,omitempty"`
+	Seller2 *Seller2 `json:"seller2,omitempty"`
+}
+
+// Type returns FoundryExchange.
+func (p *FoundryExchangePayload) Type() uint8 {
+	return FoundryExchange
+}
+
+// Transaction returns the payload to send.
+func (p *FoundryExchangePayload) Transaction() *Transaction {
+	return &Transaction{
+		Type:    FoundryExchange,
+		Buyer:   p.Buyer,
+		Seller2: p.Seller2,
+		Version: WormHolesVersion,
+	}
+}
+
+// NftExchangeMatchPayload is the payload of a NftExchangeMatch transaction, which matches the orders of a minted NFT for an exchanger.
+type NftExchangeMatchPayload struct {
+	Buyer         *Buyer         `json:"buyer,omitempty"`
+	Seller1       *Seller1       `json:"seller1,omitempty"`
+	ExchangerAuth *ExchangerAuth `json:"exchanger_auth,omitempty"`
+}
+
+// Type returns NftExchangeMatch.
+func (p *NftExchangeMatchPayload) Type() uint8 {
+	return NftExchangeMatch
+}
+
+// Transaction returns the payload to send.
+func (p *NftExchangeMatchPayload) Transaction() *Transaction {
+	return &Transaction{
+		Type:          NftExchangeMatch,
+		Buyer:         p.Buyer,
+		Seller1:       p.Seller1,
+		ExchangerAuth: p.ExchangerAuth,
+		Version:       WormHolesVersion,
+	}
+}
+
+// FoundryExchangeInitiatedPayload is the payload of a FoundryExchangeInitiated transaction, which matches the orders of an unminted NFT for an exchanger.
+type FoundryExchangeInitiatedPayload struct {
+	Buyer         *Buyer         `json:"buyer,omitempty"`
+	Seller2       *Seller2       `json:"seller2,omitempty"`
+	ExchangerAuth *ExchangerAuth `json:"exchanger_auth,omitempty"`
+}
+
+// Type returns FoundryExchangeInitiated.
+func (p *FoundryExchangeInitiatedPayload) Type() uint8 {
+	return FoundryExchangeInitiated
+}
+
+// Transaction returns the payload to send.
+func (p *FoundryExchangeInitiatedPayload) Transaction() *Transaction {
+	return &Transaction{
+		Type:          FoundryExchangeInitiated,
+		Buyer:         p.Buyer,
+		Seller2:       p.Seller2,
+		ExchangerAuth: p.ExchangerAuth,
+		Version:       WormHolesVersion,
+	}
+}
+
+// FtDoesNotAuthorizeExchangesPayload is the payload of a FtDoesNotAuthorizeExchanges transaction, which matches the orders of a minted NFT without an exchanger authorization.
+type FtDoesNotAuthorizeExchangesPayload struct {
+	Buyer   *Buyer   `json:"buyer,omitempty"`
+	Seller1 *Seller1 `json:"seller1,omitempty"`
+}
+
+// Type returns FtDoesNotAuthorizeExchanges.
+func (p *FtDoesNotAuthorizeExchangesPayload) Type() uint8 {
+	return FtDoesNotAuthorizeExchanges
+}
+
+// Transaction returns the payload to send.
+func (p *FtDoesNotAuthorizeExchangesPayload) Transaction() *Transaction {
+	return &Transaction{
+		Type:    FtDoesNotAuthorizeExchanges,
+		Buyer:   p.Buyer,
+		Seller1: p.Seller1,
+		Version: WormHolesVersion,
+	}
+}
+
+// AdditionalPledgeAmountPayload is the payload of a AdditionalPledgeAmount transaction, which adds ERB to a pledge.
+type AdditionalPledgeAmountPayload struct {
+}
+
+// Type returns AdditionalPledgeAmount.
+func (p *AdditionalPledgeAmountPayload) Type() uint8 {
+	return AdditionalPledgeAmount
+}
+
+// Transaction returns the payload to send.
+func (p *AdditionalPledgeAmountPayload) Transaction() *Transaction {
+	return &Transaction{
+		Type:    AdditionalPledgeAmount,
+		Version: WormHolesVersion,
+	}
+}
+
+// RevokesPledgeAmountPayload is the payload of a RevokesPledgeAmount transaction, which withdraws ERB from a pledge.
+type RevokesPledgeAmountPayload struct {
+}
+
+// Type returns RevokesPledgeAmount.
+func (p *RevokesPledgeAmountPayload) Type() uint8 {
+	return RevokesPledgeAmount
+}
+
+// Transaction returns the payload to send.
+func (p *RevokesPledgeAmountPayload) Transaction() *Transaction {
+	return &Transaction{
+		Type:    RevokesPledgeAmount,
+		Version: WormHolesVersion,
+	}
+}
+
+// VoteOfficialNFTPayload is the payload of a VoteOfficialNFT transaction, which votes for the official NFTs of a creator.
+type VoteOfficialNFTPayload struct {
+	Dir        string `json:"dir,omitempty"`
+	StartIndex string `json:"start_index,omitempty"`
+	Number     uint64 `json:"number,omitempty"`
+	Royalty    uint32 `json:"royalty,omitempty"`
+	Creator    string `json:"creator,omitempty"`
+}
+
+// Type returns VoteOfficialNFT.
+func (p *VoteOfficialNFTPayload) Type() uint8 {
+	return VoteOfficialNFT
+}
+
+// Transaction returns the payload to send.
+func (p *VoteOfficialNFTPayload) Transaction() *Transaction {
+	return &Transaction{
+		Type:       VoteOfficialNFT,
+		Dir:        p.Dir,
+		StartIndex: p.StartIndex,
+		Number:     p.Number,
+		Royalty:    p.Royalty,
+		Creator:    p.Creator,
+		Version:    WormHolesVersion,
+	}
+}
+
+// VoteOfficialNFTByApprovedExchangerPayload is the payload of a VoteOfficialNFTByApprovedExchanger transaction, which votes for official NFTs through an approved exchanger.
+type VoteOfficialNFTByApprovedExchangerPayload struct {
+	Dir           string         `json:"dir,omitempty"`
+	StartIndex    string         `json:"start_index,omitempty"`
+	Number        uint64         `json:"number,omitempty"`
+	Royalty       uint32         `json:"royalty,omitempty"`
+	Creator       string         `json:"creator,omitempty"`
+	ExchangerAuth *ExchangerAuth `json:"exchanger_auth,omitempty"`
+}
+
+// Type returns VoteOfficialNFTByApprovedExchanger.
+func (p *VoteOfficialNFTByApprovedExchangerPayload) Type() uint8 {
+	return VoteOfficialNFTByApprovedExchanger
+}
+
+// Transaction returns the payload to send.
+func (p *VoteOfficialNFTByApprovedExchangerPayload) Transaction() *Transaction {
+	return &Transaction{
+		Type:          VoteOfficialNFTByApprovedExchanger,
+		Dir:           p.Dir,
+		StartIndex:    p.StartIndex,
+		Number:        p.Number,
+		Royalty:       p.Royalty,
+		Creator:       p.Creator,
+		ExchangerAuth: p.ExchangerAuth,
+		Version:       WormHolesVersion,
+	}
+}
+
+// UnfreezeAccountPayload is the payload of a UnfreezeAccount transaction, which unfreezes the account of a punished validator.
+type UnfreezeAccountPayload struct {
+}
+
+// Type returns UnfreezeAccount.
+func (p *UnfreezeAccountPayload) Type() uint8 {
+	return UnfreezeAccount
+}
+
+// Transaction returns the payload to send.
+func (p *UnfreezeAccountPayload) Transaction() *Transaction {
+	return &Transaction{
+		Type:    UnfreezeAccount,
+		Version: WormHolesVersion,
+	}
+}
+
+// WeightRedemptionPayload is the payload of a WeightRedemption transaction, which redeems the weight of a validator.
+type WeightRedemptionPayload struct {
+}
+
+// Type returns WeightRedemption.
+func (p *WeightRedemptionPayload) Type() uint8 {
+	return WeightRedemption
+}
+
+// Transaction returns the payload to send.
+func (p *WeightRedemptionPayload) Transaction() *Transaction {
+	return &Transaction{
+		Type:    WeightRedemption,
+		Version: WormHolesVersion,
+	}
+}
+
+// BatchSellTransferPayload is the payload of a BatchSellTransfer transaction, which sells NFTs in bulk with authorizations of the buyer and the seller.
+type BatchSellTransferPayload struct {
+	Buyer         *Buyer         `json:"buyer,omitempty"`
+	BuyerAuth     *Buyauth       `json:"buyer_auth,omitempty"`
+	SellerAuth    *Sellerauth    `json:"seller_auth,omitempty"`
+	Seller1       *Seller1       `json:"seller1,omitempty"`
+	ExchangerAuth *ExchangerAuth `json:"exchanger_auth,omitempty"`
+}
+
+// Type returns BatchSellTransfer.
+func (p *BatchSellTransferPayload) Type() uint8 {
+	return BatchSellTransfer
+}
+
+// Transaction returns the payload to send.
+func (p *BatchSellTransferPayload) Transaction() *Transaction {
+	return &Transaction{
+		Type:          BatchSellTransfer,
+		Buyer:         p.Buyer,
+		BuyerAuth:     p.BuyerAuth,
+		SellerAuth:    p.SellerAuth,
+		Seller1:       p.Seller1,
+		ExchangerAuth: p.ExchangerAuth,
+		Version:       WormHolesVersion,
+	}
+}
+
+// ForceBuyingTransferPayload is the payload of a ForceBuyingTransfer transaction, which buys an NFT with the authorization of the buyer.
+type ForceBuyingTransferPayload struct {
+	Buyer         *Buyer         `json:"buyer,omitempty"`
+	BuyerAuth     *Buyauth       `json:"buyer_auth,omitempty"`
+	ExchangerAuth *ExchangerAuth `json:"exchanger_auth,omitempty"`
+}
+
+// Type returns ForceBuyingTransfer.
+func (p *ForceBuyingTransferPayload) Type() uint8 {
+	return ForceBuyingTransfer
+}
+
+// Transaction returns the payload to send.
+func (p *ForceBuyingTransferPayload) Transaction() *Transaction {
+	return &Transaction{
+		Type:          ForceBuyingTransfer,
+		Buyer:         p.Buyer,
+		BuyerAuth:     p.BuyerAuth,
+		ExchangerAuth: p.ExchangerAuth,
+		Version:       WormHolesVersion,
+	}
+}
+
+// ExtractERBPayload is the payload of a ExtractERB transaction, which extracts the ERB of an exchanger.
+type ExtractERBPayload struct {
+}
+
+// Type returns ExtractERB.
+func (p *ExtractERBPayload) Type() uint8 {
+	return ExtractERB
+}
+
+// Transaction returns the payload to send.
+func (p *ExtractERBPayload) Transaction() *Transaction {
+	return &Transaction{
+		Type:    ExtractERB,
+		Version: WormHolesVersion,
+	}
+}
+
+// AccountDelegatePayload is the payload of a AccountDelegate transaction, which delegates the validation of the sender to a proxy.
+type AccountDelegatePayload struct {
+	ProxyAddress string `json:"proxy_address,omitempty"`
+	ProxySign    string `json:"proxy_sign,omitempty"`
+}
+
+// Type returns AccountDelegate.
+func (p *AccountDelegatePayload) Type() uint8 {
+	return AccountDelegate
+}
+
+// Transaction returns the payload to send.
+func (p *AccountDelegatePayload) Transaction() *Transaction {
+	return &Transaction{
+		Type:         AccountDelegate,
+		ProxyAddress: p.ProxyAddress,
+		ProxySign:    p.ProxySign,
+		Version:      WormHolesVersion,
+	}
+}
+
+// DecodePayload returns the typed payload of t with the fields of its type, the others are
+// ignored.
+func DecodePayload(t *Transaction) (Payload, error) {
+	switch t.Type {
+	case Mint:
+		return &MintPayload{
+			Royalty:   t.Royalty,
+			MetaURL:   t.MetaURL,
+			Exchanger: t.Exchanger,
+		}, nil
+	case Transfer:
+		return &TransferPayload{
+			NFTAddress: t.NFTAddress,
+		}, nil
+	case Author:
+		return &AuthorPayload{
+			NFTAddress: t.NFTAddress,
+		}, nil
+	case AuthorRevoke:
+		return &AuthorRevokePayload{
+			NFTAddress: t.NFTAddress,
+		}, nil
+	case AccountAuthor:
+		return &AccountAuthorPayload{}, nil
+	case AccountAuthorRevoke:
+		return &AccountAuthorRevokePayload{}, nil
+	case SNFTToERB:
+		return &SNFTToERBPayload{
+			NFTAddress: t.NFTAddress,
+		}, nil
+	case SNFTPledge:
+		return &SNFTPledgePayload{
+			NFTAddress: t.NFTAddress,
+		}, nil
+	case SNFTRevokesPledge:
+		return &SNFTRevokesPledgePayload{
+			NFTAddress: t.NFTAddress,
+		}, nil
+	case TokenPledge:
+		return &TokenPledgePayload{
+			ProxyAddress: t.ProxyAddress,
+			FeeRate:      t.FeeRate,
+			Name:         t.Name,
+			Url:          t.Url,
+		}, nil
+	case TokenRevokesPledge:
+		return &TokenRevokesPledgePayload{}, nil
+	case TransactionNFT:
+		return &TransactionNFTPayload{
+			Buyer: t.Buyer,
+		}, nil
+	case BuyerInitiatingTransaction:
+		return &BuyerInitiatingTransactionPayload{
+			Seller1: t.Seller1,
+		}, nil
+	case FoundryTradeBuyer:
+		return &FoundryTradeBuyerPayload{
+			Seller2: t.Seller2,
+		}, nil
+	case FoundryExchange:
+		return &FoundryExchangePayload{
+			Buyer:   t.Buyer,
+			Seller2: t.Seller2,
+		}, nil
+	case NftExchangeMatch:
+		return &NftExchangeMatchPayload{
+			Buyer:         t.Buyer,
+			Seller1:       t.Seller1,
+			ExchangerAuth: t.ExchangerAuth,
+		}, nil
+	case FoundryExchangeInitiated:
+		return &FoundryExchangeInitiatedPayload{
+			Buyer:         t.Buyer,
+			Seller2:       t.Seller2,
+			ExchangerAuth: t.ExchangerAuth,
+		}, nil
+	case FtDoesNotAuthorizeExchanges:
+		return &FtDoesNotAuthorizeExchangesPayload{
+			Buyer:   t.Buyer,
+			Seller1: t.Seller1,
+		}, nil
+	case AdditionalPledgeAmount:
+		return &AdditionalPledgeAmountPayload{}, nil
+	case RevokesPledgeAmount:
+		return &RevokesPledgeAmountPayload{}, nil
+	case VoteOfficialNFT:
+		return &VoteOfficialNFTPayload{
+			Dir:        t.Dir,
+			StartIndex: t.StartIndex,
+			Number:     t.Number,
+			Royalty:    t.Royalty,
+			Creator:    t.Creator,
+		}, nil
+	case VoteOfficialNFTByApprovedExchanger:
+		return &VoteOfficialNFTByApprovedExchangerPayload{
+			Dir:           t.Dir,
+			StartIndex:    t.StartIndex,
+			Number:        t.Number,
+			Royalty:       t.Royalty,
+			Creator:       t.Creator,
+			ExchangerAuth: t.ExchangerAuth,
+		}, nil
+	case UnfreezeAccount:
+		return &UnfreezeAccountPayload{}, nil
+	case WeightRedemption:
+		return &WeightRedemptionPayload{}, nil
+	case BatchSellTransfer:
+		return &BatchSellTransferPayload{
+			Buyer:         t.Buyer,
+			BuyerAuth:     t.BuyerAuth,
+			SellerAuth:    t.SellerAuth,
+			Seller1:       t.Seller1,
+			ExchangerAuth: t.ExchangerAuth,
+		}, nil
+	case ForceBuyingTransfer:
+		return &ForceBuyingTransferPayload{
+			Buyer:         t.Buyer,
+			BuyerAuth:     t.BuyerAuth,
+			ExchangerAuth: t.ExchangerAuth,
+		}, nil
+	case ExtractERB:
+		return &ExtractERBPayload{}, nil
+	case AccountDelegate:
+		return &AccountDelegatePayload{
+			ProxyAddress: t.ProxyAddress,
+			ProxySign:    t.ProxySign,
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown wormholes transaction type %d", t.Type)
+}
@@ -1,69 +1,59 @@
 package types
 
-import "github.com/ethereum/go-ethereum/common"
+import (
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
 
 const WormHolesVersion = "v0.0.1"
 
-const (
-	Mint = iota
-	Transfer
-	Author
-	AuthorRevoke
-	AccountAuthor
-	AccountAuthorRevoke
-	SNFTToERB
-	SNFTPledge
-	SNFTRevokesPledge
-	TokenPledge
-	TokenRevokesPledge
-	_
-	_
-	_
-	TransactionNFT
-	BuyerInitiatingTransaction
-	FoundryTradeBuyer
-	FoundryExchange
-	NftExchangeMatch
-	FoundryExchangeInitiated
-	FtDoesNotAuthorizeExchanges
-	AdditionalPledgeAmount
-	RevokesPledgeAmount
-	VoteOfficialNFT
-	VoteOfficialNFTByApprovedExchanger
-	UnforzenAccount
-	WeightRedemption
-	BatchSellTransfer
-	ForceBuyingTransfer
-	ExtractERB
-	_
-	AccountDelegate
-)
+// The transaction types, the Transaction payload and the typed payloads are generated from
+// txtypes.json, add new types of the chain there.
+//
+//go:generate go run ../internal/gentypes -schema txtypes.json -out txtypes_gen.go
+
+// UnforzenAccount is the former name of UnfreezeAccount.
+//
+// Deprecated: use UnfreezeAccount.
+const UnforzenAccount = UnfreezeAccount
+
+// TypeName returns the name of a wormholes transaction type, or "Unknown" for an unknown type.
+func TypeName(t uint8) string {
+	if name, ok := typeNames[t]; ok {
+		return name
+	}
+	return "Unknown"
+}
+
+// formerTypeNames are the names of renamed types, still accepted by TypeByName.
+var formerTypeNames = map[string]uint8{
+	"unforzenaccount": UnfreezeAccount,
+}
+
+// TypeByName returns the wormholes transaction type of a name returned by TypeName, ignoring case.
+// The former names of renamed types are accepted too.
+func TypeByName(name string) (uint8, bool) {
+	if t, ok := formerTypeNames[strings.ToLower(name)]; ok {
+		return t, true
+	}
+	for t, n := range typeNames {
+		if strings.EqualFold(n, name) {
+			return t, true
+		}
+	}
+	return 0, false
+}
 
-// Transaction struct for handling NFT transactions
-type Transaction struct {
-	Type       uint8  `json:"type"`
-	Dir        string `json:"dir,omitempty"`
-	StartIndex string `json:"start_index,omitempty"`
-	Number     uint64 `json:"number,omitempty"`
-	NFTAddress string `json:"nft_address,omitempty"`
-	Royalty    uint32 `json:"royalty,omitempty"`
-	MetaURL    string `json:"meta_url,omitempty"`
-	Exchanger  string `json:"exchanger,omitempty"`
-	//ApproveAddress string		`json:"approve_address"`
-	FeeRate       uint32         `json:"fee_rate,omitempty"`
-	Name          string         `json:"name,omitempty"`
-	Url           string         `json:"url,omitempty"`
-	Buyer         *Buyer         `json:"buyer,omitempty"`
-	Seller1       *Seller1       `json:"seller1,omitempty"`
-	Seller2       *Seller2       `json:"seller2,omitempty"`
-	BuyerAuth     *Buyauth       `json:"buyer_auth,omitempty"`
-	SellerAuth    *Sellerauth    `json:"seller_auth,omitempty"`
-	ExchangerAuth *ExchangerAuth `json:"exchanger_auth,omitempty"`
-	Creator       string         `json:"creator,omitempty"`
-	RewardFlag    int            `json:"reward_flag,omitempty"`
-	ProxyAddress  string         `json:"proxy_address,omitempty"`
-	ProxySign     string         `json:"proxy_sign,omitempty"`
-	Version       string         `json:"version"`
+// Types returns the wormholes transaction types in ascending order.
+func Types() []uint8 {
+	types := make([]uint8, 0, len(typeNames))
+	for t := range typeNames {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	return types
 }
 
 type Buyauth struct {
@@ -0,0 +1,139 @@
+// Package vault reads signing keys from the KV secrets engine of HashiCorp Vault, so exchanger
+// and validator keys are only held in the memory of the process using them instead of being
+// stored on disk or passed in environment variables.
+//
+// The transit engine of Vault has no secp256k1 key type and can not sign wormholes
+// transactions, so keys are stored as KV secrets and fetched when the client is created.
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/erbieio/erb-client/v2/client"
+	"github.com/erbieio/erb-client/v2/tools"
+	"golang.org/x/xerrors"
+)
+
+const (
+	defaultMount = "secret"
+	defaultField = "private_key"
+)
+
+// Config configures a KeyProvider. Zero values select the defaults.
+type Config struct {
+	// Address is the address of the Vault server, VAULT_ADDR by default.
+	Address string
+	// Token authenticates the requests, VAULT_TOKEN by default.
+	Token string
+	// Mount is the mount path of the KV engine, "secret" by default.
+	Mount string
+	// KVVersion is the version of the KV engine, 1 or 2, 2 by default.
+	KVVersion int
+	// Field is the field of the secret holding the hex private key, "private_key" by default.
+	Field string
+	// HTTPClient sends the requests, http.DefaultClient by default.
+	HTTPClient *http.Client
+}
+
+// KeyProvider fetches private keys from Vault.
+type KeyProvider struct {
+	cfg Config
+}
+
+// New creates a key provider.
+func New(cfg Config) (*KeyProvider, error) {
+	if cfg.Address == "" {
+		cfg.Address = os.Getenv("VAULT_ADDR")
+	}
+	if cfg.Token == "" {
+		cfg.Token = os.Getenv("VAULT_TOKEN")
+	}
+	if cfg.Address == "" {
+		return nil, xerrors.New("vault: no server address, set Address or VAULT_ADDR")
+	}
+	if cfg.Token == "" {
+		return nil, xerrors.New("vault: no token, set Token or VAULT_TOKEN")
+	}
+	if cfg.Mount == "" {
+		cfg.Mount = defaultMount
+	}
+	if cfg.KVVersion == 0 {
+		cfg.KVVersion = 2
+	}
+	if cfg.KVVersion != 1 && cfg.KVVersion != 2 {
+		return nil, xerrors.Errorf("vault: unsupported kv version %d", cfg.KVVersion)
+	}
+	if cfg.Field == "" {
+		cfg.Field = defaultField
+	}
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = http.DefaultClient
+	}
+	return &KeyProvider{cfg: cfg}, nil
+}
+
+// PriKey returns the private key stored in the secret at path, in the format taken by client.NewClient.
+func (p *KeyProvider) PriKey(ctx context.Context, path string) (string, error) {
+	secretURL := strings.TrimRight(p.cfg.Address, "/") + "/v1/" + strings.Trim(p.cfg.Mount, "/")
+	if p.cfg.KVVersion == 2 {
+		secretURL += "/data"
+	}
+	secretURL += "/" + strings.Trim(path, "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, secretURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", p.cfg.Token)
+	resp, err := p.cfg.HTTPClient.Do(req)
+	if err != nil {
+		return "", xerrors.Errorf("vault: read %s: %v", path, err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []string        `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil && resp.StatusCode == http.StatusOK {
+		return "", xerrors.Errorf("vault: read %s: %v", path, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", xerrors.Errorf("vault: read %s: %s %s", path, resp.Status, strings.Join(body.Errors, "; "))
+	}
+	data := body.Data
+	if p.cfg.KVVersion == 2 {
+		var versioned struct {
+			Data json.RawMessage `json:"data"`
+		}
+		if err := json.Unmarshal(data, &versioned); err != nil {
+			return "", xerrors.Errorf("vault: read %s: %v", path, err)
+		}
+		data = versioned.Data
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return "", xerrors.Errorf("vault: read %s: %v", path, err)
+	}
+	priKey, ok := fields[p.cfg.Field].(string)
+	if !ok || priKey == "" {
+		return "", xerrors.Errorf("vault: secret %s has no field %s", path, p.cfg.Field)
+	}
+	priKey, err = tools.ParsePriKey(priKey)
+	if err != nil {
+		return "", xerrors.Errorf("vault: secret %s holds an invalid private key", path)
+	}
+	return priKey, nil
+}
+
+// Client fetches the private key stored at path and connects a client signing with it to rawurl.
+func (p *KeyProvider) Client(ctx context.Context, path, rawurl string) (*client.Wormholes, error) {
+	priKey, err := p.PriKey(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return client.NewClient(priKey, rawurl), nil
+}
@@ -0,0 +1,300 @@
+// Package vectors produces the canonical test vectors of the signed orders of the client, so
+// other implementations of wormholes orders and auditors can check they sign and verify the
+// same bytes without reading the client. A vector holds a private key, an unsigned order, the
+// message and hash signed, the signature and its signer.
+//
+// The keys are derived from fixed seeds and the signatures are deterministic (RFC 6979), so
+// Generate returns the same vectors every time; publish them as JSON:
+//
+//	vs, err := vectors.Generate()
+//	data, err := json.MarshalIndent(vs, "", "  ")
+//
+// and check an implementation, or vectors read back, with Verify.
+package vectors
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+
+	"github.com/erbieio/erb-client/v2/client"
+	"github.com/erbieio/erb-client/v2/tools"
+	types2 "github.com/erbieio/erb-client/v2/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/xerrors"
+)
+
+// The kinds of orders of a Vector, the kinds of client.ExportOrder and the authorizations of
+// bulk sales.
+const (
+	KindBuyer         = client.OrderBuyer
+	KindSeller1       = client.OrderSeller1
+	KindSeller2       = client.OrderSeller2
+	KindExchangerAuth = client.OrderExchangerAuth
+	KindBuyerAuth     = "buyer_auth"
+	KindSellerAuth    = "seller_auth"
+)
+
+// Vector is a canonical signature of an order.
+type Vector struct {
+	// Name identifies the vector, e.g. "buyer/minted".
+	Name string `json:"name"`
+	// Kind is the kind of Order, one of the Kind constants.
+	Kind string `json:"kind"`
+	// PrivateKey is the hex key of the signer, without 0x.
+	PrivateKey string `json:"private_key"`
+	// Order is the order without its signature, as JSON.
+	Order json.RawMessage `json:"order"`
+	// Message is the concatenation of the fields of Order that is signed.
+	Message string `json:"message"`
+	// Hash is the EIP-191 personal message hash of Message.
+	Hash common.Hash `json:"hash"`
+	// Signature is the signature of Hash, r, s and V of 27 or 28, as in the sig field.
+	Signature string `json:"signature"`
+	// Signer is the address of PrivateKey, recovered from Signature.
+	Signer common.Address `json:"signer"`
+	// Signed is Order with its signature, as sent to the chain.
+	Signed json.RawMessage `json:"signed"`
+}
+
+// order is an order or an authorization of a Vector.
+type order interface {
+	Message() string
+	SigningHash() common.Hash
+	Validate() error
+}
+
+// The roles signing the vectors.
+const (
+	roleBuyer     = "buyer"
+	roleSeller    = "seller"
+	roleExchanger = "exchanger"
+)
+
+// key returns the private key of a role, the keccak256 hash of "erb-client/vectors/<role>".
+func key(role string) string {
+	return common.Bytes2Hex(crypto.Keccak256([]byte("erb-client/vectors/" + role)))
+}
+
+// address returns the address of the key of a role.
+func address(role string) string {
+	account, _, _ := tools.PriKeyToAddress(key(role))
+	return account.Hex()
+}
+
+// nftAddress is the NFT traded by the vectors.
+const nftAddress = "0x0000000000000000000000000000000000000001"
+
+// Generate returns the vectors of every kind of order, with the edge cases of each kind:
+// buyers of minted and unminted NFTs, with and without an exchanger, and inclusive and
+// exclusive sellers of unminted NFTs.
+func Generate() ([]Vector, error) {
+	oneERB := types2.ERB(1)
+	exchanger := address(roleExchanger)
+	type source struct {
+		name, kind, role string
+		order            func() (order, error)
+	}
+	sources := []source{
+		{"buyer/minted", KindBuyer, roleBuyer, func() (order, error) {
+			return types2.NewBuyer(oneERB, nftAddress, exchanger, 100, "")
+		}},
+		{"buyer/minted-no-exchanger", KindBuyer, roleBuyer, func() (order, error) {
+			return types2.NewBuyer(types2.Wei(big.NewInt(1)), nftAddress, "", 0x1000, "")
+		}},
+		{"buyer/unminted", KindBuyer, roleBuyer, func() (order, error) {
+			return types2.NewBuyer(oneERB, "", exchanger, 100, address(roleSeller))
+		}},
+		{"seller1/minted", KindSeller1, roleSeller, func() (order, error) {
+			return types2.NewSeller1(oneERB, nftAddress, exchanger, 100)
+		}},
+		{"seller2/inclusive", KindSeller2, roleSeller, func() (order, error) {
+			return types2.NewSeller2(oneERB, 250, "ipfs://QmVectorMetadata", false, exchanger, 100)
+		}},
+		{"seller2/exclusive", KindSeller2, roleSeller, func() (order, error) {
+			return types2.NewSeller2(oneERB, types2.MaxRoyalty, "https://example.com/nft/1.json", true, exchanger, 100)
+		}},
+		{"exchanger_auth", KindExchangerAuth, roleExchanger, func() (order, error) {
+			return types2.NewExchangerAuth(exchanger, address(roleBuyer), 100)
+		}},
+		{"buyer_auth", KindBuyerAuth, roleBuyer, func() (order, error) {
+			a := &types2.Buyauth{Exchanger: exchanger, BlockNumber: hexutil.EncodeUint64(100)}
+			return a, a.Validate()
+		}},
+		{"seller_auth", KindSellerAuth, roleSeller, func() (order, error) {
+			a := &types2.Sellerauth{Exchanger: exchanger, BlockNumber: hexutil.EncodeUint64(100)}
+			return a, a.Validate()
+		}},
+	}
+	vs := make([]Vector, 0, len(sources))
+	for _, s := range sources {
+		o, err := s.order()
+		if err != nil {
+			return nil, xerrors.Errorf("vector %s: %w", s.name, err)
+		}
+		v, err := sign(s.name, s.kind, key(s.role), o)
+		if err != nil {
+			return nil, xerrors.Errorf("vector %s: %w", s.name, err)
+		}
+		vs = append(vs, *v)
+	}
+	return vs, nil
+}
+
+// sign signs o with the wallet of the client, the way applications sign orders.
+func sign(name, kind, priKey string, o order) (*Vector, error) {
+	unsigned, err := json.Marshal(o)
+	if err != nil {
+		return nil, err
+	}
+	w := client.NewWallet(priKey)
+	var signed []byte
+	switch o := o.(type) {
+	case *types2.Buyer:
+		signed, err = w.SignBuyerOrder(o)
+	case *types2.Seller1:
+		signed, err = w.SignSeller1Order(o)
+	case *types2.Seller2:
+		signed, err = w.SignSeller2Order(o)
+	case *types2.ExchangerAuth:
+		signed, err = w.SignExchangerAuth(o)
+	case *types2.Buyauth:
+		signed, err = w.SignBuyerAuth(o.Exchanger, o.BlockNumber)
+	case *types2.Sellerauth:
+		signed, err = w.SignSellerAuth(o.Exchanger, o.BlockNumber)
+	}
+	if err != nil {
+		return nil, err
+	}
+	signer, _, err := tools.PriKeyToAddress(priKey)
+	if err != nil {
+		return nil, err
+	}
+	return &Vector{
+		Name:       name,
+		Kind:       kind,
+		PrivateKey: priKey,
+		Order:      unsigned,
+		Message:    o.Message(),
+		Hash:       o.SigningHash(),
+		Signature:  sigOf(signed),
+		Signer:     signer,
+		Signed:     signed,
+	}, nil
+}
+
+// secp256k1halfN is half the order of the curve, the largest s of a canonical signature.
+var secp256k1halfN = new(big.Int).Rsh(crypto.S256().Params().N, 1)
+
+// Verify checks every field of v against its order: the order is valid and unsigned, Message
+// and Hash are of the order, Signer is the address of PrivateKey, Signature is the canonical
+// signature of Hash by PrivateKey with a low s and V of 27 or 28, and Signed is the order with
+// Signature.
+func Verify(v *Vector) error {
+	o, err := decode(v.Kind, v.Order)
+	if err != nil {
+		return xerrors.Errorf("vector %s: %w", v.Name, err)
+	}
+	if sigOf(v.Order) != "" {
+		return xerrors.Errorf("vector %s: order is signed", v.Name)
+	}
+	if message := o.Message(); v.Message != message {
+		return xerrors.Errorf("vector %s: message %q, want %q", v.Name, v.Message, message)
+	}
+	hash := common.BytesToHash(tools.SignHash([]byte(v.Message)))
+	if v.Hash != hash || o.SigningHash() != hash {
+		return xerrors.Errorf("vector %s: hash %s, want %s", v.Name, v.Hash.Hex(), hash.Hex())
+	}
+	account, priKey, err := tools.PriKeyToAddress(v.PrivateKey)
+	if err != nil {
+		return xerrors.Errorf("vector %s: invalid private key: %w", v.Name, err)
+	}
+	if account != v.Signer {
+		return xerrors.Errorf("vector %s: signer %s is not the address %s of the key", v.Name, v.Signer.Hex(), account.Hex())
+	}
+	sig, err := hexutil.Decode(v.Signature)
+	if err != nil || len(sig) != crypto.SignatureLength {
+		return xerrors.Errorf("vector %s: signature %q is not 65 bytes of hex", v.Name, v.Signature)
+	}
+	if sig[64] != tools.SignatureVOffset && sig[64] != tools.SignatureVOffset+1 {
+		return xerrors.Errorf("vector %s: signature V %d, want 27 or 28", v.Name, sig[64])
+	}
+	if new(big.Int).SetBytes(sig[32:64]).Cmp(secp256k1halfN) > 0 {
+		return xerrors.Errorf("vector %s: signature s is not in the lower half of the curve order", v.Name)
+	}
+	signer, err := tools.RecoverAddress(v.Message, v.Signature)
+	if err != nil {
+		return xerrors.Errorf("vector %s: %w", v.Name, err)
+	}
+	if signer != v.Signer {
+		return xerrors.Errorf("vector %s: signature recovers %s, want %s", v.Name, signer.Hex(), v.Signer.Hex())
+	}
+	canonical, err := crypto.Sign(hash[:], priKey)
+	if err != nil {
+		return xerrors.Errorf("vector %s: %w", v.Name, err)
+	}
+	canonical[64] += tools.SignatureVOffset
+	if !bytes.Equal(sig, canonical) {
+		return xerrors.Errorf("vector %s: signature is not the deterministic signature %s", v.Name, hexutil.Encode(canonical))
+	}
+	signed, err := decode(v.Kind, v.Signed)
+	if err != nil {
+		return xerrors.Errorf("vector %s: signed: %w", v.Name, err)
+	}
+	if signed.Message() != v.Message {
+		return xerrors.Errorf("vector %s: signed order differs from the order", v.Name)
+	}
+	if sig := sigOf(v.Signed); sig != v.Signature {
+		return xerrors.Errorf("vector %s: signed order has sig %q, want the signature", v.Name, sig)
+	}
+	return nil
+}
+
+// VerifyAll verifies vs and returns the error of the first invalid vector.
+func VerifyAll(vs []Vector) error {
+	for i := range vs {
+		if err := Verify(&vs[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sigOf returns the sig field of an order as JSON.
+func sigOf(data []byte) string {
+	var o struct {
+		Sig string `json:"sig"`
+	}
+	_ = json.Unmarshal(data, &o)
+	return o.Sig
+}
+
+// decode decodes and validates an order of kind, ignoring its signature.
+func decode(kind string, data []byte) (order, error) {
+	var o order
+	switch kind {
+	case KindBuyer:
+		o = new(types2.Buyer)
+	case KindSeller1:
+		o = new(types2.Seller1)
+	case KindSeller2:
+		o = new(types2.Seller2)
+	case KindExchangerAuth:
+		o = new(types2.ExchangerAuth)
+	case KindBuyerAuth:
+		o = new(types2.Buyauth)
+	case KindSellerAuth:
+		o = new(types2.Sellerauth)
+	default:
+		return nil, xerrors.Errorf("unknown order kind %q", kind)
+	}
+	if err := json.Unmarshal(data, o); err != nil {
+		return nil, xerrors.Errorf("invalid %s order: %w", kind, err)
+	}
+	if err := o.Validate(); err != nil {
+		return nil, err
+	}
+	return o, nil
+}